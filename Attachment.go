@@ -0,0 +1,49 @@
+package msgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Types of an Attachment.
+const (
+	ODataTypeFileAttachment      = "#microsoft.graph.fileAttachment"
+	ODataTypeItemAttachment      = "#microsoft.graph.itemAttachment"
+	ODataTypeReferenceAttachment = "#microsoft.graph.referenceAttachment"
+)
+
+// Attachment represents a file attached to a Post, see Post.ListAttachments. Attachments are
+// added to a new post with Group.CreateConversation, ConversationThread.Reply or Post.Reply,
+// only file attachments with their ContentBytes can be added.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/fileattachment
+type Attachment struct {
+	ODataType            string    `json:"@odata.type"` // one of the ODataType*Attachment constants, defaults to ODataTypeFileAttachment
+	ID                   string    `json:"id,omitempty"`
+	Name                 string    `json:"name"`                  // file name of the attachment
+	ContentType          string    `json:"contentType,omitempty"` // e.g. application/pdf
+	Size                 int       `json:"size,omitempty"`        // read-only, size in bytes
+	IsInline             bool      `json:"isInline"`              // true if the attachment is embedded in the body, see ContentID
+	ContentID            string    `json:"contentId,omitempty"`   // ID of an inline attachment, referenced in the body as cid:{ContentID}
+	LastModifiedDateTime time.Time `json:"lastModifiedDateTime"`  // read-only
+	ContentBytes         []byte    `json:"contentBytes,omitempty"`
+}
+
+func (a Attachment) String() string {
+	return fmt.Sprintf("Attachment(ID: \"%v\", ODataType: \"%v\", Name: \"%v\", ContentType: \"%v\", Size: %v, IsInline: %v, LastModifiedDateTime: \"%v\")",
+		a.ID, a.ODataType, a.Name, a.ContentType, a.Size, a.IsInline, a.LastModifiedDateTime)
+}
+
+// MarshalJSON implements the json marshal to be used by the json-library. The @odata.type
+// defaults to ODataTypeFileAttachment, the read-only LastModifiedDateTime is omitted if zero.
+func (a Attachment) MarshalJSON() ([]byte, error) {
+	type attachment Attachment // prevents the recursion into MarshalJSON
+	if a.ODataType == "" {
+		a.ODataType = ODataTypeFileAttachment
+	}
+	return json.Marshal(struct {
+		attachment
+		LastModifiedDateTime *time.Time `json:"lastModifiedDateTime,omitempty"`
+	}{attachment(a), optionalTime(a.LastModifiedDateTime)})
+}
@@ -0,0 +1,16 @@
+package msgraph
+
+import (
+	"strings"
+)
+
+// Attachments represents multiple Attachment-instances.
+type Attachments []Attachment
+
+func (a Attachments) String() string {
+	var attachments = make([]string, len(a))
+	for i, attachment := range a {
+		attachments[i] = attachment.String()
+	}
+	return "Attachments(" + strings.Join(attachments, " | ") + ")"
+}
@@ -0,0 +1,182 @@
+package msgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Types of authentication methods, hence the collections below /users/{id}/authentication.
+// Use them with User.RemoveAuthenticationMethod.
+const (
+	AuthenticationMethodTypePhone                  = "phoneMethods"
+	AuthenticationMethodTypeMicrosoftAuthenticator = "microsoftAuthenticatorMethods"
+	AuthenticationMethodTypeFido2                  = "fido2Methods"
+	AuthenticationMethodTypeEmail                  = "emailMethods"
+	AuthenticationMethodTypeSoftwareOath           = "softwareOathMethods"
+	AuthenticationMethodTypeTemporaryAccessPass    = "temporaryAccessPassMethods"
+	AuthenticationMethodTypePassword               = "passwordMethods"
+)
+
+// Phone types of a PhoneAuthenticationMethod, every user can have one phone method per type.
+const (
+	PhoneTypeMobile          = "mobile"
+	PhoneTypeAlternateMobile = "alternateMobile"
+	PhoneTypeOffice          = "office"
+)
+
+// PasswordAuthenticationMethodID is the well-known ID of the password authentication method of every user.
+const PasswordAuthenticationMethodID = "28c10230-6103-485e-b985-444c60001490"
+
+// PhoneAuthenticationMethod represents a phone number registered for SMS or voice based MFA.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/phoneauthenticationmethod
+type PhoneAuthenticationMethod struct {
+	ID             string `json:"id,omitempty"`
+	PhoneNumber    string `json:"phoneNumber,omitempty"`    // e.g. "+1 2065555555"
+	PhoneType      string `json:"phoneType,omitempty"`      // PhoneTypeMobile, PhoneTypeAlternateMobile or PhoneTypeOffice
+	SmsSignInState string `json:"smsSignInState,omitempty"` // e.g. ready, notEnabled, notSupported
+}
+
+// MicrosoftAuthenticatorAuthenticationMethod represents a device with the Microsoft Authenticator app.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/microsoftauthenticatorauthenticationmethod
+type MicrosoftAuthenticatorAuthenticationMethod struct {
+	ID              string    `json:"id,omitempty"`
+	CreatedDateTime time.Time `json:"createdDateTime,omitempty"`
+	DisplayName     string    `json:"displayName,omitempty"` // name of the device
+	DeviceTag       string    `json:"deviceTag,omitempty"`
+	PhoneAppVersion string    `json:"phoneAppVersion,omitempty"`
+}
+
+// Fido2AuthenticationMethod represents a FIDO2 security key.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/fido2authenticationmethod
+type Fido2AuthenticationMethod struct {
+	ID                      string    `json:"id,omitempty"`
+	CreatedDateTime         time.Time `json:"createdDateTime,omitempty"`
+	DisplayName             string    `json:"displayName,omitempty"`
+	Model                   string    `json:"model,omitempty"`
+	AAGuid                  string    `json:"aaGuid,omitempty"`
+	AttestationLevel        string    `json:"attestationLevel,omitempty"` // attested or notAttested
+	AttestationCertificates []string  `json:"attestationCertificates,omitempty"`
+}
+
+// EmailAuthenticationMethod represents an email address registered for self-service password reset.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/emailauthenticationmethod
+type EmailAuthenticationMethod struct {
+	ID           string `json:"id,omitempty"`
+	EmailAddress string `json:"emailAddress,omitempty"`
+}
+
+// SoftwareOathAuthenticationMethod represents a software OATH token, e.g. of a third-party authenticator app.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/softwareoathauthenticationmethod
+type SoftwareOathAuthenticationMethod struct {
+	ID        string `json:"id,omitempty"`
+	SecretKey string `json:"secretKey,omitempty"` // only returned for tokens that have not been activated yet
+}
+
+// TemporaryAccessPassAuthenticationMethod represents a Temporary Access Pass, a time-limited
+// passcode that allows the user to sign in and register other authentication methods.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/temporaryaccesspassauthenticationmethod
+type TemporaryAccessPassAuthenticationMethod struct {
+	ID                    string    `json:"id,omitempty"`
+	TemporaryAccessPass   string    `json:"temporaryAccessPass,omitempty"` // only returned when the pass is created
+	CreatedDateTime       time.Time `json:"createdDateTime,omitempty"`
+	StartDateTime         time.Time `json:"startDateTime,omitempty"`
+	LifetimeInMinutes     int       `json:"lifetimeInMinutes,omitempty"`
+	IsUsableOnce          bool      `json:"isUsableOnce,omitempty"`
+	IsUsable              bool      `json:"isUsable,omitempty"`
+	MethodUsabilityReason string    `json:"methodUsabilityReason,omitempty"` // e.g. EnabledByPolicy, NotYetValid, Expired
+}
+
+// PasswordAuthenticationMethod represents the password of a user, see PasswordAuthenticationMethodID.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/passwordauthenticationmethod
+type PasswordAuthenticationMethod struct {
+	ID              string    `json:"id,omitempty"`
+	CreatedDateTime time.Time `json:"createdDateTime,omitempty"`
+}
+
+// AuthenticationMethods contains the authentication methods of a user grouped by their type,
+// see User.ListAuthenticationMethods.
+type AuthenticationMethods struct {
+	Phone                  []PhoneAuthenticationMethod
+	MicrosoftAuthenticator []MicrosoftAuthenticatorAuthenticationMethod
+	Fido2                  []Fido2AuthenticationMethod
+	Email                  []EmailAuthenticationMethod
+	SoftwareOath           []SoftwareOathAuthenticationMethod
+	TemporaryAccessPass    []TemporaryAccessPassAuthenticationMethod
+	Password               []PasswordAuthenticationMethod
+	Other                  []json.RawMessage // methods of types not listed above, e.g. Windows Hello for Business
+}
+
+func (a AuthenticationMethods) String() string {
+	return fmt.Sprintf("AuthenticationMethods(Phone: %v, MicrosoftAuthenticator: %v, Fido2: %v, Email: %v, SoftwareOath: %v, "+
+		"TemporaryAccessPass: %v, Password: %v, Other: %v)", len(a.Phone), len(a.MicrosoftAuthenticator), len(a.Fido2), len(a.Email),
+		len(a.SoftwareOath), len(a.TemporaryAccessPass), len(a.Password), len(a.Other))
+}
+
+// HasStrongMethod returns true if the user has registered any authentication method besides
+// the password, hence is able to perform multi-factor authentication.
+func (a AuthenticationMethods) HasStrongMethod() bool {
+	return len(a.Phone)+len(a.MicrosoftAuthenticator)+len(a.Fido2)+len(a.SoftwareOath)+len(a.TemporaryAccessPass)+len(a.Other) > 0
+}
+
+// UnmarshalJSON implements the json unmarshal to be used by the json-library. The collection
+// of polymorphic authentication methods is sorted into the typed slices by their @odata.type.
+func (a *AuthenticationMethods) UnmarshalJSON(data []byte) error {
+	var methods []json.RawMessage
+	if err := json.Unmarshal(data, &methods); err != nil {
+		return err
+	}
+	for _, method := range methods {
+		var typed struct {
+			ODataType string `json:"@odata.type"`
+		}
+		if err := json.Unmarshal(method, &typed); err != nil {
+			return err
+		}
+		var err error
+		switch strings.TrimPrefix(typed.ODataType, "#microsoft.graph.") {
+		case "phoneAuthenticationMethod":
+			var m PhoneAuthenticationMethod
+			err = json.Unmarshal(method, &m)
+			a.Phone = append(a.Phone, m)
+		case "microsoftAuthenticatorAuthenticationMethod":
+			var m MicrosoftAuthenticatorAuthenticationMethod
+			err = json.Unmarshal(method, &m)
+			a.MicrosoftAuthenticator = append(a.MicrosoftAuthenticator, m)
+		case "fido2AuthenticationMethod":
+			var m Fido2AuthenticationMethod
+			err = json.Unmarshal(method, &m)
+			a.Fido2 = append(a.Fido2, m)
+		case "emailAuthenticationMethod":
+			var m EmailAuthenticationMethod
+			err = json.Unmarshal(method, &m)
+			a.Email = append(a.Email, m)
+		case "softwareOathAuthenticationMethod":
+			var m SoftwareOathAuthenticationMethod
+			err = json.Unmarshal(method, &m)
+			a.SoftwareOath = append(a.SoftwareOath, m)
+		case "temporaryAccessPassAuthenticationMethod":
+			var m TemporaryAccessPassAuthenticationMethod
+			err = json.Unmarshal(method, &m)
+			a.TemporaryAccessPass = append(a.TemporaryAccessPass, m)
+		case "passwordAuthenticationMethod":
+			var m PasswordAuthenticationMethod
+			err = json.Unmarshal(method, &m)
+			a.Password = append(a.Password, m)
+		default:
+			a.Other = append(a.Other, method)
+		}
+		if err != nil {
+			return fmt.Errorf("cannot unmarshal %v: %v", typed.ODataType, err)
+		}
+	}
+	return nil
+}
@@ -183,10 +183,18 @@ func parseTimeAndLocation(timeToParse, locationToParse string) (time.Time, error
 	return parsedTime.In(parsedTimeZone), nil
 }
 
-// mapTimeZoneStrings maps various Timezones used by Microsoft to go-understandable timezones or returns the source-zone if no mapping is found
+// mapTimeZoneStrings maps various Timezones used by Microsoft to go-understandable timezones. Windows
+// time zone names are resolved by the supported time zones if already loaded, else by windowsTimeZones.
 func mapTimeZoneStrings(timeZone string) (*time.Location, error) {
 	if timeZone == "tzone://Microsoft/Custom" {
 		return FullDayEventTimeZone, nil
 	}
-	return globalSupportedTimeZones.GetTimeZoneByAlias(timeZone)
+	if location, err := globalSupportedTimeZones.GetTimeZoneByAlias(timeZone); err == nil {
+		return location, nil
+	}
+	ianaName, ok := windowsTimeZones[timeZone]
+	if !ok {
+		return nil, fmt.Errorf("could not find given time.Location for Alias %v", timeZone)
+	}
+	return time.LoadLocation(ianaName)
 }
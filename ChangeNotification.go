@@ -0,0 +1,66 @@
+package msgraph
+
+import (
+	"fmt"
+	"time"
+)
+
+// Lifecycle events of a ChangeNotification, sent to the LifecycleNotificationURL of a Subscription.
+//
+// See https://docs.microsoft.com/en-us/graph/webhooks-lifecycle
+const (
+	// LifecycleEventReauthorizationRequired - the access token of the subscription is about to
+	// expire, renew the subscription with Subscription.RenewSubscription
+	LifecycleEventReauthorizationRequired = "reauthorizationRequired"
+	// LifecycleEventSubscriptionRemoved - the subscription has been removed by Microsoft Graph and must be recreated
+	LifecycleEventSubscriptionRemoved = "subscriptionRemoved"
+	// LifecycleEventMissed - some notifications have not been delivered, resync the resource
+	LifecycleEventMissed = "missed"
+)
+
+// ChangeNotification represents a change notification or a lifecycle notification sent by
+// Microsoft Graph for a Subscription.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/changenotification?view=graph-rest-1.0
+type ChangeNotification struct {
+	ID                             string                         `json:"id"`
+	SubscriptionID                 string                         `json:"subscriptionId"`
+	SubscriptionExpirationDateTime time.Time                      `json:"subscriptionExpirationDateTime"`
+	ClientState                    string                         `json:"clientState"`
+	ChangeType                     string                         `json:"changeType"` // one of ChangeTypeCreated, ChangeTypeUpdated, ChangeTypeDeleted, empty for lifecycle notifications
+	Resource                       string                         `json:"resource"`   // e.g. Users/{id}
+	TenantID                       string                         `json:"tenantId"`
+	ResourceData                   ChangeNotificationResourceData `json:"resourceData"`
+	LifecycleEvent                 string                         `json:"lifecycleEvent"` // one of LifecycleEventReauthorizationRequired, LifecycleEventSubscriptionRemoved, LifecycleEventMissed, empty for change notifications
+	// EncryptedContent contains the changed resource if the Subscription has been created with
+	// IncludeResourceData, nil otherwise. See ChangeNotification.DecryptResourceData
+	EncryptedContent *ChangeNotificationEncryptedContent `json:"encryptedContent,omitempty"`
+}
+
+// ChangeNotificationResourceData identifies the changed resource of a ChangeNotification.
+type ChangeNotificationResourceData struct {
+	ID        string `json:"id"`
+	ODataType string `json:"@odata.type"` // e.g. #Microsoft.Graph.User
+	ODataID   string `json:"@odata.id"`   // e.g. Users/{id}
+	ODataEtag string `json:"@odata.etag"`
+}
+
+// ChangeNotificationCollection is the body of a POST request sent by Microsoft Graph to the
+// notification URL of a Subscription.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/changenotificationcollection?view=graph-rest-1.0
+type ChangeNotificationCollection struct {
+	Value            []ChangeNotification `json:"value"`
+	ValidationTokens []string             `json:"validationTokens"` // only set for subscriptions with IncludeResourceData
+}
+
+func (c ChangeNotification) String() string {
+	return fmt.Sprintf("ChangeNotification(SubscriptionID: \"%v\", ChangeType: \"%v\", Resource: \"%v\", ResourceID: \"%v\", LifecycleEvent: \"%v\", TenantID: \"%v\")",
+		c.SubscriptionID, c.ChangeType, c.Resource, c.ResourceData.ID, c.LifecycleEvent, c.TenantID)
+}
+
+// IsLifecycleNotification returns true if this is a lifecycle notification, e.g.
+// LifecycleEventReauthorizationRequired, instead of a change of the resource.
+func (c ChangeNotification) IsLifecycleNotification() bool {
+	return c.LifecycleEvent != ""
+}
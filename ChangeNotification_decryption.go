@@ -0,0 +1,124 @@
+package msgraph
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrNoEncryptedContent is returned when decrypting a ChangeNotification without resource data,
+// e.g. because the Subscription has not been created with IncludeResourceData.
+var ErrNoEncryptedContent = errors.New("change notification has no encrypted content")
+
+// ChangeNotificationEncryptedContent contains the encrypted resource data of a ChangeNotification
+// of a Subscription with IncludeResourceData.
+//
+// See https://docs.microsoft.com/en-us/graph/webhooks-with-resource-data#decrypting-resource-data-from-change-notifications
+type ChangeNotificationEncryptedContent struct {
+	Data                            string `json:"data"`                            // base64-encoded resource data, encrypted with AES-CBC
+	DataSignature                   string `json:"dataSignature"`                   // base64-encoded HMAC-SHA256 of Data
+	DataKey                         string `json:"dataKey"`                         // base64-encoded symmetric key, encrypted with the public key of the EncryptionCertificate
+	EncryptionCertificateID         string `json:"encryptionCertificateId"`         // EncryptionCertificateID of the Subscription
+	EncryptionCertificateThumbprint string `json:"encryptionCertificateThumbprint"` // thumbprint of the EncryptionCertificate
+}
+
+// Decrypt decrypts the symmetric key with the RSA private key of the EncryptionCertificate of
+// the Subscription, verifies the signature of the data and returns the decrypted resource data,
+// hence the JSON representation of the changed resource.
+func (e ChangeNotificationEncryptedContent) Decrypt(privateKey *rsa.PrivateKey) ([]byte, error) {
+	if privateKey == nil {
+		return nil, fmt.Errorf("private key is nil")
+	}
+	dataKey, err := base64.StdEncoding.DecodeString(e.DataKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot base64-decode dataKey: %v", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(e.Data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot base64-decode data: %v", err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(e.DataSignature)
+	if err != nil {
+		return nil, fmt.Errorf("cannot base64-decode dataSignature: %v", err)
+	}
+
+	// the symmetric key is encrypted with RSA-OAEP with SHA-1
+	symmetricKey, err := rsa.DecryptOAEP(sha1.New(), rand.Reader, privateKey, dataKey, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt dataKey with private key of certificate %v: %v", e.EncryptionCertificateID, err)
+	}
+
+	mac := hmac.New(sha256.New, symmetricKey)
+	mac.Write(data)
+	if !hmac.Equal(mac.Sum(nil), signature) {
+		return nil, fmt.Errorf("dataSignature does not match, the encrypted content has been tampered with")
+	}
+
+	// AES-CBC with PKCS7 padding, the initialization vector are the first 16 bytes of the symmetric key
+	block, err := aes.NewCipher(symmetricKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid symmetric key: %v", err)
+	}
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("invalid length %v of encrypted data", len(data))
+	}
+	decrypted := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, symmetricKey[:aes.BlockSize]).CryptBlocks(decrypted, data)
+	padding := int(decrypted[len(decrypted)-1])
+	if padding == 0 || padding > aes.BlockSize || padding > len(decrypted) {
+		return nil, fmt.Errorf("invalid padding of decrypted data")
+	}
+	for _, b := range decrypted[len(decrypted)-padding:] {
+		if int(b) != padding {
+			return nil, fmt.Errorf("invalid padding of decrypted data")
+		}
+	}
+	return decrypted[:len(decrypted)-padding], nil
+}
+
+// DecryptResourceData decrypts the EncryptedContent of the notification and json-unmarshals
+// it into v, see ChangeNotificationEncryptedContent.Decrypt.
+func (c ChangeNotification) DecryptResourceData(privateKey *rsa.PrivateKey, v interface{}) error {
+	if c.EncryptedContent == nil {
+		return ErrNoEncryptedContent
+	}
+	data, err := c.EncryptedContent.Decrypt(privateKey)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("cannot json.Unmarshal decrypted resource data to %T: %v", v, err)
+	}
+	return nil
+}
+
+// DecryptCalendarEvent decrypts the resource data of a notification of an event subscription,
+// e.g. users/{id}/events. Like for User.ListCalendarView, the Windows time zones of the event
+// are mapped to IANA time zones, the supported time zones do not need to be loaded.
+func (c ChangeNotification) DecryptCalendarEvent(privateKey *rsa.PrivateKey) (CalendarEvent, error) {
+	var event CalendarEvent
+	err := c.DecryptResourceData(privateKey, &event)
+	return event, err
+}
+
+// DecryptUser decrypts the resource data of a notification of a user subscription.
+func (c ChangeNotification) DecryptUser(privateKey *rsa.PrivateKey) (User, error) {
+	var user User
+	err := c.DecryptResourceData(privateKey, &user)
+	return user, err
+}
+
+// DecryptGroup decrypts the resource data of a notification of a group subscription.
+func (c ChangeNotification) DecryptGroup(privateKey *rsa.PrivateKey) (Group, error) {
+	var group Group
+	err := c.DecryptResourceData(privateKey, &group)
+	return group, err
+}
@@ -0,0 +1,119 @@
+package msgraph
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+// encryptTestContent encrypts the data like Microsoft Graph encrypts resource data of change notifications.
+func encryptTestContent(t *testing.T, publicKey *rsa.PublicKey, data []byte) *ChangeNotificationEncryptedContent {
+	t.Helper()
+	symmetricKey := make([]byte, 32)
+	if _, err := rand.Read(symmetricKey); err != nil {
+		t.Fatalf("rand.Read() error = %v", err)
+	}
+	padding := aes.BlockSize - len(data)%aes.BlockSize
+	for i := 0; i < padding; i++ {
+		data = append(data, byte(padding))
+	}
+	block, err := aes.NewCipher(symmetricKey)
+	if err != nil {
+		t.Fatalf("aes.NewCipher() error = %v", err)
+	}
+	encrypted := make([]byte, len(data))
+	cipher.NewCBCEncrypter(block, symmetricKey[:aes.BlockSize]).CryptBlocks(encrypted, data)
+	mac := hmac.New(sha256.New, symmetricKey)
+	mac.Write(encrypted)
+	dataKey, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, publicKey, symmetricKey, nil)
+	if err != nil {
+		t.Fatalf("rsa.EncryptOAEP() error = %v", err)
+	}
+	return &ChangeNotificationEncryptedContent{
+		Data:                    base64.StdEncoding.EncodeToString(encrypted),
+		DataSignature:           base64.StdEncoding.EncodeToString(mac.Sum(nil)),
+		DataKey:                 base64.StdEncoding.EncodeToString(dataKey),
+		EncryptionCertificateID: "go-msgraph-unit-test",
+	}
+}
+
+func TestChangeNotification_DecryptResourceData(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+
+	event := ChangeNotification{EncryptedContent: encryptTestContent(t, &privateKey.PublicKey, []byte(`{
+		"id": "event-1",
+		"subject": "Rich notification",
+		"createdDateTime": "2021-06-01T10:00:00Z",
+		"lastModifiedDateTime": "2021-06-01T10:00:00Z",
+		"originalStartTimeZone": "tzone://Microsoft/Custom",
+		"originalEndTimeZone": "tzone://Microsoft/Custom",
+		"start": {"dateTime": "2021-06-02T10:00:00.0000000", "timeZone": "UTC"},
+		"end": {"dateTime": "2021-06-02T11:00:00.0000000", "timeZone": "UTC"}
+	}`))}
+	gotEvent, err := event.DecryptCalendarEvent(privateKey)
+	if err != nil {
+		t.Fatalf("ChangeNotification.DecryptCalendarEvent() error = %v", err)
+	}
+	if gotEvent.ID != "event-1" || gotEvent.Subject != "Rich notification" || !gotEvent.StartTime.Equal(time.Date(2021, 6, 2, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("ChangeNotification.DecryptCalendarEvent() = %v", gotEvent)
+	}
+
+	// Windows time zones are resolved without loading the supported time zones
+	defer func(loaded supportedTimeZones) { globalSupportedTimeZones = loaded }(globalSupportedTimeZones)
+	globalSupportedTimeZones = supportedTimeZones{}
+	windowsEvent := ChangeNotification{EncryptedContent: encryptTestContent(t, &privateKey.PublicKey, []byte(`{
+		"id": "event-2",
+		"subject": "Windows time zone",
+		"createdDateTime": "2021-06-01T10:00:00Z",
+		"lastModifiedDateTime": "2021-06-01T10:00:00Z",
+		"originalStartTimeZone": "W. Europe Standard Time",
+		"originalEndTimeZone": "W. Europe Standard Time",
+		"start": {"dateTime": "2021-06-02T10:00:00.0000000", "timeZone": "UTC"},
+		"end": {"dateTime": "2021-06-02T11:00:00.0000000", "timeZone": "UTC"}
+	}`))}
+	gotEvent, err = windowsEvent.DecryptCalendarEvent(privateKey)
+	if err != nil {
+		t.Fatalf("ChangeNotification.DecryptCalendarEvent() with Windows time zone error = %v", err)
+	}
+	if gotEvent.OriginalStartTimeZone.String() != "Europe/Berlin" || gotEvent.StartTime.Hour() != 12 {
+		t.Errorf("ChangeNotification.DecryptCalendarEvent() OriginalStartTimeZone = %v, StartTime = %v, want Europe/Berlin and 12:00", gotEvent.OriginalStartTimeZone, gotEvent.StartTime)
+	}
+
+	user := ChangeNotification{EncryptedContent: encryptTestContent(t, &privateKey.PublicKey, []byte(`{"id": "user-1", "displayName": "Alice Smith", "accountEnabled": true}`))}
+	gotUser, err := user.DecryptUser(privateKey)
+	if err != nil || gotUser.ID != "user-1" || gotUser.DisplayName != "Alice Smith" || !gotUser.AccountEnabled {
+		t.Errorf("ChangeNotification.DecryptUser() = %v, error = %v", gotUser, err)
+	}
+
+	group := ChangeNotification{EncryptedContent: encryptTestContent(t, &privateKey.PublicKey, []byte(`{"id": "group-1", "displayName": "technicians", "securityEnabled": true}`))}
+	gotGroup, err := group.DecryptGroup(privateKey)
+	if err != nil || gotGroup.ID != "group-1" || gotGroup.DisplayName != "technicians" || !gotGroup.SecurityEnabled {
+		t.Errorf("ChangeNotification.DecryptGroup() = %v, error = %v", gotGroup, err)
+	}
+
+	if _, err := user.DecryptUser(otherKey); err == nil {
+		t.Errorf("ChangeNotification.DecryptUser() with wrong private key error = nil, want error")
+	}
+	tampered := *user.EncryptedContent
+	tampered.DataSignature = group.EncryptedContent.DataSignature
+	if _, err := (ChangeNotification{EncryptedContent: &tampered}).DecryptUser(privateKey); err == nil {
+		t.Errorf("ChangeNotification.DecryptUser() with wrong dataSignature error = nil, want error")
+	}
+	if _, err := (ChangeNotification{}).DecryptUser(privateKey); err != ErrNoEncryptedContent {
+		t.Errorf("ChangeNotification.DecryptUser() without content error = %v, want %v", err, ErrNoEncryptedContent)
+	}
+}
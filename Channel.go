@@ -0,0 +1,152 @@
+package msgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Membership types of a Channel.
+const (
+	ChannelMembershipTypeStandard = "standard" // all members of the team are members of the channel
+	ChannelMembershipTypePrivate  = "private"  // only the members of the channel, who must be members of the team
+	ChannelMembershipTypeShared   = "shared"   // the members of the channel, who may be members of other teams
+)
+
+// Channel represents a channel of a Team, see Team.ListChannels.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/channel
+type Channel struct {
+	ID              string    `json:"id"`
+	DisplayName     string    `json:"displayName"`
+	Description     string    `json:"description"`
+	MembershipType  string    `json:"membershipType"` // one of the ChannelMembershipType* constants
+	Email           string    `json:"email"`          // read-only, address to send email to the channel
+	WebURL          string    `json:"webUrl"`         // read-only, link to the channel in the Microsoft Teams client
+	CreatedDateTime time.Time `json:"createdDateTime"`
+
+	teamID      string       // the ID of the team of the channel, part of the resource path of its API-calls
+	graphClient *GraphClient // the graphClient that called the channel
+}
+
+func (c Channel) String() string {
+	return fmt.Sprintf("Channel(ID: \"%v\", DisplayName: \"%v\", Description: \"%v\", MembershipType: \"%v\", Email: \"%v\", CreatedDateTime: \"%v\", DirectAPIConnection: %v)",
+		c.ID, c.DisplayName, c.Description, c.MembershipType, c.Email, c.CreatedDateTime, c.graphClient != nil)
+}
+
+// setTeam sets the graphClient and the ID of the team of the channel.
+func (c *Channel) setTeam(gC *GraphClient, teamID string) {
+	c.graphClient = gC
+	c.teamID = teamID
+}
+
+// UpdateChannel patches the DisplayName and Description of the channel, empty values are not
+// changed. The General channel cannot be renamed.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/channel-patch
+func (c Channel) UpdateChannel(channelInput Channel, opts ...UpdateQueryOption) error {
+	if c.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/teams/%v/channels/%v", c.teamID, c.ID)
+
+	patch := make(map[string]string)
+	if channelInput.DisplayName != "" {
+		patch["displayName"] = channelInput.DisplayName
+	}
+	if channelInput.Description != "" {
+		patch["description"] = channelInput.Description
+	}
+	bodyBytes, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+
+	reader := bytes.NewReader(bodyBytes)
+	// Hint: API-call body does not return any data / no json object.
+	return c.graphClient.makePATCHAPICall(resource, compileUpdateQueryOptions(opts), reader, nil)
+}
+
+// DeleteChannel deletes the channel, it can be restored within 30 days in the Microsoft Teams
+// client. The General channel cannot be deleted.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/channel-delete
+func (c Channel) DeleteChannel(opts ...DeleteQueryOption) error {
+	if c.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/teams/%v/channels/%v", c.teamID, c.ID)
+
+	return c.graphClient.makeDELETEAPICall(resource, compileDeleteQueryOptions(opts), nil)
+}
+
+// ListMembers returns the members of the channel including their roles. The members of a
+// standard channel are the members of the team.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/channel-list-members
+func (c Channel) ListMembers(opts ...ListQueryOption) (ConversationMembers, error) {
+	if c.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	return c.graphClient.listConversationMembers(fmt.Sprintf("/teams/%v/channels/%v/members", c.teamID, c.ID), opts)
+}
+
+// AddMember adds the user with the given ID to the private or shared channel, with roles
+// []string{TeamRoleOwner} as owner or with no roles as member. Members of a private channel
+// must be members of the team.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/channel-post-members
+func (c Channel) AddMember(userID string, roles []string, opts ...CreateQueryOption) (ConversationMember, error) {
+	if c.graphClient == nil {
+		return ConversationMember{}, ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/teams/%v/channels/%v/members", c.teamID, c.ID)
+
+	bodyBytes, err := json.Marshal(c.graphClient.conversationMemberBody(userID, roles))
+	if err != nil {
+		return ConversationMember{}, err
+	}
+
+	reader := bytes.NewReader(bodyBytes)
+	var member ConversationMember
+	err = c.graphClient.makePOSTAPICall(resource, compileCreateQueryOptions(opts), reader, &member)
+	return member, err
+}
+
+// UpdateMemberRoles replaces the roles of the membership with the given ID, e.g. to promote
+// a member to owner. Note, membershipID is the ID of the ConversationMember, not of the user.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/channel-update-members
+func (c Channel) UpdateMemberRoles(membershipID string, roles []string, opts ...UpdateQueryOption) error {
+	if c.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/teams/%v/channels/%v/members/%v", c.teamID, c.ID, membershipID)
+
+	if roles == nil {
+		roles = []string{}
+	}
+	bodyBytes, err := json.Marshal(map[string]interface{}{"@odata.type": ODataTypeAadUserConversationMember, "roles": roles})
+	if err != nil {
+		return err
+	}
+
+	reader := bytes.NewReader(bodyBytes)
+	// Hint: API-call body does not return any data / no json object.
+	return c.graphClient.makePATCHAPICall(resource, compileUpdateQueryOptions(opts), reader, nil)
+}
+
+// RemoveMember removes the membership with the given ID from the private or shared channel.
+// Note, membershipID is the ID of the ConversationMember, not of the user.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/channel-delete-members
+func (c Channel) RemoveMember(membershipID string, opts ...DeleteQueryOption) error {
+	if c.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/teams/%v/channels/%v/members/%v", c.teamID, c.ID, membershipID)
+
+	return c.graphClient.makeDELETEAPICall(resource, compileDeleteQueryOptions(opts), nil)
+}
@@ -0,0 +1,35 @@
+package msgraph
+
+import (
+	"strings"
+)
+
+// Channels represents multiple Channel-instances and provides funcs to work with them.
+type Channels []Channel
+
+func (c Channels) String() string {
+	var channels = make([]string, len(c))
+	for i, channel := range c {
+		channels[i] = channel.String()
+	}
+	return "Channels(" + strings.Join(channels, " | ") + ")"
+}
+
+// setTeam sets the graphClient and the ID of the team in all channels.
+func (c Channels) setTeam(gC *GraphClient, teamID string) Channels {
+	for i := range c {
+		c[i].setTeam(gC, teamID)
+	}
+	return c
+}
+
+// GetByDisplayName returns the channel with the given display name, compared
+// case-insensitively like Microsoft Teams does. Returns ErrFindChannel if there is none.
+func (c Channels) GetByDisplayName(displayName string) (Channel, error) {
+	for _, channel := range c {
+		if strings.EqualFold(channel.DisplayName, displayName) {
+			return channel, nil
+		}
+	}
+	return Channel{}, ErrFindChannel
+}
@@ -0,0 +1,44 @@
+package msgraph
+
+import (
+	"fmt"
+	"time"
+)
+
+// Conversation represents a conversation of a Microsoft 365 group, hence a topic with one or
+// more ConversationThreads, see Group.ListConversations.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/conversation
+type Conversation struct {
+	ID                    string    `json:"id"`
+	Topic                 string    `json:"topic"`
+	HasAttachments        bool      `json:"hasAttachments"`        // true if any post of the conversation has attachments
+	LastDeliveredDateTime time.Time `json:"lastDeliveredDateTime"` // time of the latest post
+	UniqueSenders         []string  `json:"uniqueSenders"`         // display names of all senders of the conversation
+	Preview               string    `json:"preview"`               // short summary of the body of the latest post
+
+	groupID     string       // the ID of the group of the conversation, part of the resource path of its API-calls
+	graphClient *GraphClient // the graphClient that called the conversation
+}
+
+func (c Conversation) String() string {
+	return fmt.Sprintf("Conversation(ID: \"%v\", Topic: \"%v\", HasAttachments: %v, LastDeliveredDateTime: \"%v\", UniqueSenders: %v, DirectAPIConnection: %v)",
+		c.ID, c.Topic, c.HasAttachments, c.LastDeliveredDateTime, c.UniqueSenders, c.graphClient != nil)
+}
+
+// setGroup sets the graphClient and the ID of the group of the conversation.
+func (c *Conversation) setGroup(gC *GraphClient, groupID string) {
+	c.graphClient = gC
+	c.groupID = groupID
+}
+
+// ListThreads returns the threads of the conversation.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/conversation-list-threads
+func (c Conversation) ListThreads(opts ...ListQueryOption) (ConversationThreads, error) {
+	if c.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	return c.graphClient.listConversationThreads(c.groupID, fmt.Sprintf("/groups/%v/conversations/%v/threads", c.groupID, c.ID), opts)
+}
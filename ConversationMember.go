@@ -0,0 +1,45 @@
+package msgraph
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Roles of a ConversationMember, members without a role are regular members.
+const (
+	TeamRoleOwner = "owner"
+	TeamRoleGuest = "guest"
+)
+
+// ODataTypeAadUserConversationMember is the @odata.type of a ConversationMember that is a user.
+const ODataTypeAadUserConversationMember = "#microsoft.graph.aadUserConversationMember"
+
+// ConversationMember represents the membership of a user in a Team or Channel, see
+// Team.ListMembers and Channel.ListMembers.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/aaduserconversationmember
+type ConversationMember struct {
+	ODataType                   string    `json:"@odata.type"`
+	ID                          string    `json:"id"` // ID of the membership, not of the user, see UserID
+	DisplayName                 string    `json:"displayName"`
+	Roles                       []string  `json:"roles"` // TeamRoleOwner, TeamRoleGuest or none for members
+	UserID                      string    `json:"userId"`
+	Email                       string    `json:"email"`
+	VisibleHistoryStartDateTime time.Time `json:"visibleHistoryStartDateTime"` // messages before are not visible to the member
+}
+
+func (c ConversationMember) String() string {
+	return fmt.Sprintf("ConversationMember(ID: \"%v\", DisplayName: \"%v\", Roles: %v, UserID: \"%v\", Email: \"%v\")",
+		c.ID, c.DisplayName, c.Roles, c.UserID, c.Email)
+}
+
+// HasRole returns true if the member has the given role, e.g. TeamRoleOwner.
+func (c ConversationMember) HasRole(role string) bool {
+	for _, r := range c.Roles {
+		if strings.EqualFold(r, role) {
+			return true
+		}
+	}
+	return false
+}
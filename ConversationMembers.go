@@ -0,0 +1,38 @@
+package msgraph
+
+import (
+	"strings"
+)
+
+// ConversationMembers represents multiple ConversationMember-instances and provides funcs to work with them.
+type ConversationMembers []ConversationMember
+
+func (c ConversationMembers) String() string {
+	var members = make([]string, len(c))
+	for i, member := range c {
+		members[i] = member.String()
+	}
+	return "ConversationMembers(" + strings.Join(members, " | ") + ")"
+}
+
+// Owners returns all members with the role TeamRoleOwner.
+func (c ConversationMembers) Owners() ConversationMembers {
+	var owners ConversationMembers
+	for _, member := range c {
+		if member.HasRole(TeamRoleOwner) {
+			owners = append(owners, member)
+		}
+	}
+	return owners
+}
+
+// GetByUserID returns the membership of the user with the given ID, compared
+// case-insensitively. Returns ErrFindConversationMember if there is none.
+func (c ConversationMembers) GetByUserID(userID string) (ConversationMember, error) {
+	for _, member := range c {
+		if strings.EqualFold(member.UserID, userID) {
+			return member, nil
+		}
+	}
+	return ConversationMember{}, ErrFindConversationMember
+}
@@ -0,0 +1,90 @@
+package msgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ConversationThread represents a thread of a Conversation of a Microsoft 365 group, hence
+// a collection of Posts that are replies to each other, see Group.ListThreads.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/conversationthread
+type ConversationThread struct {
+	ID                    string      `json:"id"`
+	Topic                 string      `json:"topic"`
+	HasAttachments        bool        `json:"hasAttachments"` // true if any post of the thread has attachments
+	LastDeliveredDateTime time.Time   `json:"lastDeliveredDateTime"`
+	UniqueSenders         []string    `json:"uniqueSenders"` // display names of all senders of the thread
+	ToRecipients          []Recipient `json:"toRecipients"`
+	CcRecipients          []Recipient `json:"ccRecipients"`
+	Preview               string      `json:"preview"`  // short summary of the body of the latest post
+	IsLocked              bool        `json:"isLocked"` // locked threads cannot be replied to
+
+	groupID     string       // the ID of the group of the thread, part of the resource path of its API-calls
+	graphClient *GraphClient // the graphClient that called the thread
+}
+
+func (t ConversationThread) String() string {
+	return fmt.Sprintf("ConversationThread(ID: \"%v\", Topic: \"%v\", HasAttachments: %v, LastDeliveredDateTime: \"%v\", UniqueSenders: %v, IsLocked: %v, DirectAPIConnection: %v)",
+		t.ID, t.Topic, t.HasAttachments, t.LastDeliveredDateTime, t.UniqueSenders, t.IsLocked, t.graphClient != nil)
+}
+
+// setGroup sets the graphClient and the ID of the group of the thread.
+func (t *ConversationThread) setGroup(gC *GraphClient, groupID string) {
+	t.graphClient = gC
+	t.groupID = groupID
+}
+
+// ListPosts returns the posts of the thread including their body, the oldest post first.
+// The attachments of a post are listed with Post.ListAttachments.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/conversationthread-list-posts
+func (t ConversationThread) ListPosts(opts ...ListQueryOption) (Posts, error) {
+	if t.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/groups/%v/threads/%v/posts", t.groupID, t.ID)
+
+	var marsh struct {
+		Posts Posts `json:"value"`
+	}
+	err := t.graphClient.makeGETAPICall(resource, compileListQueryOptions(opts), &marsh)
+	marsh.Posts.setThread(t.graphClient, t.groupID, t.ID)
+	return marsh.Posts, err
+}
+
+// Reply adds the post to the thread, only its Body, NewParticipants and Attachments are used.
+// Microsoft Graph does not return the created post, use ConversationThread.ListPosts to get it.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/conversationthread-reply
+func (t ConversationThread) Reply(post Post, opts ...CreateQueryOption) error {
+	if t.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	return t.graphClient.replyToPost(fmt.Sprintf("/groups/%v/threads/%v/reply", t.groupID, t.ID), post, opts)
+}
+
+// listConversationThreads returns the threads of the given resource, e.g. /groups/{id}/threads.
+func (g *GraphClient) listConversationThreads(groupID, resource string, opts []ListQueryOption) (ConversationThreads, error) {
+	var marsh struct {
+		Threads ConversationThreads `json:"value"`
+	}
+	err := g.makeGETAPICall(resource, compileListQueryOptions(opts), &marsh)
+	marsh.Threads.setGroup(g, groupID)
+	return marsh.Threads, err
+}
+
+// replyToPost sends the post to the given reply action of a thread or post.
+func (g *GraphClient) replyToPost(resource string, post Post, opts []CreateQueryOption) error {
+	bodyBytes, err := json.Marshal(map[string]interface{}{"post": post.requestBody()})
+	if err != nil {
+		return err
+	}
+
+	reader := bytes.NewReader(bodyBytes)
+	// Hint: API-call body does not return any data / no json object.
+	return g.makePOSTAPICall(resource, compileCreateQueryOptions(opts), reader, nil)
+}
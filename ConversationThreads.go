@@ -0,0 +1,24 @@
+package msgraph
+
+import (
+	"strings"
+)
+
+// ConversationThreads represents multiple ConversationThread-instances.
+type ConversationThreads []ConversationThread
+
+func (t ConversationThreads) String() string {
+	var threads = make([]string, len(t))
+	for i, thread := range t {
+		threads[i] = thread.String()
+	}
+	return "ConversationThreads(" + strings.Join(threads, " | ") + ")"
+}
+
+// setGroup sets the graphClient and the ID of the group in all threads.
+func (t ConversationThreads) setGroup(gC *GraphClient, groupID string) ConversationThreads {
+	for i := range t {
+		t[i].setGroup(gC, groupID)
+	}
+	return t
+}
@@ -0,0 +1,35 @@
+package msgraph
+
+import (
+	"strings"
+)
+
+// Conversations represents multiple Conversation-instances and provides funcs to work with them.
+type Conversations []Conversation
+
+func (c Conversations) String() string {
+	var conversations = make([]string, len(c))
+	for i, conversation := range c {
+		conversations[i] = conversation.String()
+	}
+	return "Conversations(" + strings.Join(conversations, " | ") + ")"
+}
+
+// setGroup sets the graphClient and the ID of the group in all conversations.
+func (c Conversations) setGroup(gC *GraphClient, groupID string) Conversations {
+	for i := range c {
+		c[i].setGroup(gC, groupID)
+	}
+	return c
+}
+
+// GetByTopic returns all conversations with the given topic, compared case-insensitively.
+func (c Conversations) GetByTopic(topic string) Conversations {
+	var conversations Conversations
+	for _, conversation := range c {
+		if strings.EqualFold(conversation.Topic, topic) {
+			conversations = append(conversations, conversation)
+		}
+	}
+	return conversations
+}
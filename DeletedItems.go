@@ -0,0 +1,85 @@
+package msgraph
+
+import (
+	"fmt"
+)
+
+// ListDeletedUsers returns all users that have been deleted within the last 30 days and can
+// still be restored, see User.Restore.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/directory-deleteditems-list
+func (g *GraphClient) ListDeletedUsers(opts ...ListQueryOption) (Users, error) {
+	resource := "/directory/deletedItems/microsoft.graph.user"
+
+	var marsh struct {
+		Users Users `json:"value"`
+	}
+	err := g.makeGETAPICall(resource, compileListQueryOptions(opts), &marsh)
+	marsh.Users.setGraphClient(g)
+	return marsh.Users, err
+}
+
+// ListDeletedGroups returns all Microsoft 365 groups that have been deleted within the last
+// 30 days and can still be restored, see Group.Restore. Deleted security groups are deleted
+// permanently right away.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/directory-deleteditems-list
+func (g *GraphClient) ListDeletedGroups(opts ...ListQueryOption) (Groups, error) {
+	resource := "/directory/deletedItems/microsoft.graph.group"
+
+	var marsh struct {
+		Groups Groups `json:"value"`
+	}
+	err := g.makeGETAPICall(resource, compileListQueryOptions(opts), &marsh)
+	marsh.Groups.setGraphClient(g)
+	return marsh.Groups, err
+}
+
+// RestoreDeletedItem restores the deleted user or group with the given ID, including its group
+// memberships. Use User.Restore or Group.Restore to get the restored object.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/directory-deleteditems-restore
+func (g *GraphClient) RestoreDeletedItem(id string, opts ...UpdateQueryOption) error {
+	return g.restoreDeletedItem(id, opts, nil)
+}
+
+// restoreDeletedItem restores the deleted item with the given ID into v.
+func (g *GraphClient) restoreDeletedItem(id string, opts []UpdateQueryOption, v interface{}) error {
+	resource := fmt.Sprintf("/directory/deletedItems/%v/restore", id)
+	return g.makePOSTAPICall(resource, compileUpdateQueryOptions(opts), nil, v)
+}
+
+// PermanentlyDeleteItem permanently deletes the deleted user or group with the given ID, it
+// cannot be restored afterwards. Use with caution.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/directory-deleteditems-delete
+func (g *GraphClient) PermanentlyDeleteItem(id string, opts ...DeleteQueryOption) error {
+	resource := fmt.Sprintf("/directory/deletedItems/%v", id)
+	return g.makeDELETEAPICall(resource, compileDeleteQueryOptions(opts), nil)
+}
+
+// Restore restores the deleted user, see GraphClient.ListDeletedUsers, and returns the restored user.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/directory-deleteditems-restore
+func (u User) Restore(opts ...UpdateQueryOption) (User, error) {
+	if u.graphClient == nil {
+		return User{}, ErrNotGraphClientSourced
+	}
+	restored := User{graphClient: u.graphClient}
+	err := u.graphClient.restoreDeletedItem(u.ID, opts, &restored)
+	return restored, err
+}
+
+// Restore restores the deleted group, see GraphClient.ListDeletedGroups, and returns the restored group.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/directory-deleteditems-restore
+func (g Group) Restore(opts ...UpdateQueryOption) (Group, error) {
+	if g.graphClient == nil {
+		return Group{}, ErrNotGraphClientSourced
+	}
+	restored := Group{graphClient: g.graphClient}
+	err := g.graphClient.restoreDeletedItem(g.ID, opts, &restored)
+	return restored, err
+}
@@ -0,0 +1,103 @@
+package msgraph
+
+import (
+	"testing"
+)
+
+func TestGraphClient_DeletedUsers(t *testing.T) {
+	user := createUnitTestUser(t)
+	if err := user.DeleteUser(); err != nil {
+		t.Fatalf("User.DeleteUser() error = %v", err)
+	}
+	defer graphClient.PermanentlyDeleteItem(user.ID)
+
+	deleted, err := graphClient.ListDeletedUsers()
+	if err != nil {
+		t.Fatalf("GraphClient.ListDeletedUsers() error = %v", err)
+	}
+	deletedUser, ok := findUserByID(deleted, user.ID)
+	if !ok {
+		t.Fatalf("GraphClient.ListDeletedUsers() = %v, want the deleted user %v", deleted, user.ID)
+	}
+	if deletedUser.DeletedDateTime.IsZero() {
+		t.Errorf("GraphClient.ListDeletedUsers() DeletedDateTime is zero, want the time of deletion")
+	}
+
+	restored, err := deletedUser.Restore()
+	if err != nil || restored.ID != user.ID || restored.graphClient == nil {
+		t.Fatalf("User.Restore() = %v, error = %v, want %v", restored, err, user.ID)
+	}
+	if _, err := graphClient.GetUser(user.ID); err != nil {
+		t.Errorf("GraphClient.GetUser() of the restored user error = %v", err)
+	}
+
+	if err := restored.DeleteUser(); err != nil {
+		t.Fatalf("User.DeleteUser() of the restored user error = %v", err)
+	}
+	if err := graphClient.PermanentlyDeleteItem(user.ID); err != nil {
+		t.Fatalf("GraphClient.PermanentlyDeleteItem() error = %v", err)
+	}
+	if deleted, _ := graphClient.ListDeletedUsers(); len(deleted) > 0 {
+		if _, ok := findUserByID(deleted, user.ID); ok {
+			t.Errorf("GraphClient.ListDeletedUsers() still contains the permanently deleted user %v", user.ID)
+		}
+	}
+	if err := graphClient.RestoreDeletedItem(user.ID); err == nil {
+		t.Errorf("GraphClient.RestoreDeletedItem() of a permanently deleted user error = nil, want error")
+	}
+	if _, err := (User{}).Restore(); err != ErrNotGraphClientSourced {
+		t.Errorf("User.Restore() without GraphClient error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+}
+
+func TestGraphClient_DeletedGroups(t *testing.T) {
+	if offlineServer == nil {
+		t.Skip("the unit tests must not delete groups of the tenant, only tested offline")
+	}
+	group := offlineServer.AddGroup(map[string]interface{}{"displayName": "deleted group", "mailNickname": "deleted-group", "groupTypes": []string{"Unified"}})
+	parent := offlineServer.AddGroup(map[string]interface{}{"displayName": "parent group", "mailNickname": "parent-group"})
+	user := createUnitTestUser(t)
+	defer user.DeleteUser()
+	offlineServer.AddGroupMember(group.ID(), user.ID)
+	offlineServer.AddGroupMember(parent.ID(), group.ID())
+
+	if err := (Group{ID: group.ID(), graphClient: graphClient}).DeleteGroup(); err != nil {
+		t.Fatalf("Group.DeleteGroup() error = %v", err)
+	}
+	if len(offlineServer.GroupMembers(parent.ID())) != 0 {
+		t.Fatalf("msgraphtest.Server.GroupMembers() of the parent = %v, want the deleted group removed", offlineServer.GroupMembers(parent.ID()))
+	}
+
+	deleted, err := graphClient.ListDeletedGroups()
+	if err != nil {
+		t.Fatalf("GraphClient.ListDeletedGroups() error = %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].ID != group.ID() || deleted[0].DeletedDateTime.IsZero() {
+		t.Fatalf("GraphClient.ListDeletedGroups() = %v, want the deleted group %v", deleted, group.ID())
+	}
+	restored, err := deleted[0].Restore()
+	if err != nil || restored.ID != group.ID() || restored.DisplayName != "deleted group" {
+		t.Fatalf("Group.Restore() = %v, error = %v, want %v", restored, err, group.ID())
+	}
+	if members := offlineServer.GroupMembers(group.ID()); len(members) != 1 || members[0] != user.ID {
+		t.Errorf("msgraphtest.Server.GroupMembers() of the restored group = %v, want %v", members, user.ID)
+	}
+	if members := offlineServer.GroupMembers(parent.ID()); len(members) != 1 || members[0] != group.ID() {
+		t.Errorf("msgraphtest.Server.GroupMembers() of the parent = %v, want the restored group %v", members, group.ID())
+	}
+	if deleted, _ := graphClient.ListDeletedGroups(); len(deleted) != 0 {
+		t.Errorf("GraphClient.ListDeletedGroups() after restore = %v, want none", deleted)
+	}
+	if err := graphClient.PermanentlyDeleteItem(group.ID()); err == nil {
+		t.Errorf("GraphClient.PermanentlyDeleteItem() of a restored group error = nil, want error")
+	}
+}
+
+func findUserByID(users Users, id string) (User, bool) {
+	for _, user := range users {
+		if user.ID == id {
+			return user, true
+		}
+	}
+	return User{}, false
+}
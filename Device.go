@@ -0,0 +1,26 @@
+package msgraph
+
+import (
+	"fmt"
+)
+
+// Device represents a device registered in Azure AD, e.g. a member of a group.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/device
+type Device struct {
+	ID                     string   `json:"id"`
+	DeviceID               string   `json:"deviceId"`
+	DisplayName            string   `json:"displayName"`
+	AccountEnabled         bool     `json:"accountEnabled"`
+	OperatingSystem        string   `json:"operatingSystem"`
+	OperatingSystemVersion string   `json:"operatingSystemVersion"`
+	TrustType              string   `json:"trustType"` // e.g. "AzureAd", "ServerAd" or "Workplace"
+	IsCompliant            bool     `json:"isCompliant"`
+	IsManaged              bool     `json:"isManaged"`
+	SystemLabels           []string `json:"systemLabels"`
+}
+
+func (d Device) String() string {
+	return fmt.Sprintf("Device(ID: \"%v\", DeviceID: \"%v\", DisplayName: \"%v\", OperatingSystem: \"%v\", TrustType: \"%v\")",
+		d.ID, d.DeviceID, d.DisplayName, d.OperatingSystem, d.TrustType)
+}
@@ -0,0 +1,146 @@
+package msgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// @odata.type of the directory objects decoded by DirectoryObject, e.g. for
+// Group.ListMembersOfType.
+const (
+	ODataTypeUser             = "#microsoft.graph.user"
+	ODataTypeGroup            = "#microsoft.graph.group"
+	ODataTypeDevice           = "#microsoft.graph.device"
+	ODataTypeServicePrincipal = "#microsoft.graph.servicePrincipal"
+	ODataTypeOrgContact       = "#microsoft.graph.orgContact"
+)
+
+// DirectoryObject represents any object of the directory, e.g. a member of a group. It is
+// decoded by its ODataType, hence exactly one of User, Group, Device, ServicePrincipal and
+// OrgContact is set. Other types, e.g. applications, only have the ODataType, ID and
+// DisplayName.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/directoryobject
+type DirectoryObject struct {
+	ODataType   string // one of the ODataType* constants, e.g. ODataTypeUser
+	ID          string
+	DisplayName string
+
+	User             *User
+	Group            *Group
+	Device           *Device
+	ServicePrincipal *ServicePrincipal
+	OrgContact       *OrgContact
+}
+
+func (d DirectoryObject) String() string {
+	return fmt.Sprintf("DirectoryObject(ODataType: \"%v\", ID: \"%v\", DisplayName: \"%v\")", d.ODataType, d.ID, d.DisplayName)
+}
+
+// UnmarshalJSON implements the json unmarshal to be used by the json-library. The object is
+// decoded into the type given by its @odata.type.
+func (d *DirectoryObject) UnmarshalJSON(data []byte) error {
+	return d.unmarshalJSONOfType(data, "")
+}
+
+// unmarshalJSONOfType decodes the object into the type given by its @odata.type, or into the
+// given odataType if it has none, e.g. objects of a type cast.
+func (d *DirectoryObject) unmarshalJSONOfType(data []byte, odataType string) error {
+	tmp := struct {
+		ODataType   string `json:"@odata.type"`
+		ID          string `json:"id"`
+		DisplayName string `json:"displayName"`
+	}{}
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return err
+	}
+	if tmp.ODataType == "" {
+		tmp.ODataType = odataType
+	}
+	*d = DirectoryObject{ODataType: tmp.ODataType, ID: tmp.ID, DisplayName: tmp.DisplayName}
+
+	var v interface{}
+	switch tmp.ODataType {
+	case ODataTypeUser:
+		d.User = &User{}
+		v = d.User
+	case ODataTypeGroup:
+		d.Group = &Group{}
+		v = d.Group
+	case ODataTypeDevice:
+		d.Device = &Device{}
+		v = d.Device
+	case ODataTypeServicePrincipal:
+		d.ServicePrincipal = &ServicePrincipal{}
+		v = d.ServicePrincipal
+	case ODataTypeOrgContact:
+		d.OrgContact = &OrgContact{}
+		v = d.OrgContact
+	default:
+		return nil
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("cannot unmarshal directory object %v of type %v: %v", tmp.ID, tmp.ODataType, err)
+	}
+	return nil
+}
+
+// setGraphClient sets the graphClient instance in this instance and all child-instances (if any)
+func (d *DirectoryObject) setGraphClient(gC *GraphClient) {
+	if d.User != nil {
+		d.User.setGraphClient(gC)
+	}
+	if d.Group != nil {
+		d.Group.setGraphClient(gC)
+	}
+}
+
+// castSegment returns the path segment of the type cast to the given @odata.type, e.g.
+// microsoft.graph.user for ODataTypeUser.
+func castSegment(odataType string) string {
+	return strings.TrimPrefix(odataType, "#")
+}
+
+// compileTransitiveCastListQueryOptions compiles the options of a transitive list request with
+// a type cast, e.g. /transitiveMembers/microsoft.graph.user. Type casts of transitive lists
+// require advanced query capabilities, hence $count and the ConsistencyLevel header. Type casts
+// of direct lists like /members work without them, hence they are not eventually consistent.
+func compileTransitiveCastListQueryOptions(opts []ListQueryOption) *listQueryOptions {
+	reqOpt := compileListQueryOptions(opts)
+	reqOpt.queryHeaders.Set("ConsistencyLevel", "eventual")
+	reqOpt.queryValues.Set("$count", "true")
+	return reqOpt
+}
+
+// listDirectoryObjects returns the directory objects of the given resource, e.g. /groups/{id}/members.
+func (g *GraphClient) listDirectoryObjects(resource string, opts []ListQueryOption) (DirectoryObjects, error) {
+	var marsh struct {
+		DirectoryObjects DirectoryObjects `json:"value"`
+	}
+	err := g.makeGETAPICall(resource, compileListQueryOptions(opts), &marsh)
+	marsh.DirectoryObjects.setGraphClient(g)
+	return marsh.DirectoryObjects, err
+}
+
+// listDirectoryObjectsOfType returns the directory objects of the resource, e.g.
+// /groups/{id}/members, filtered by a type cast to the given @odata.type. Microsoft Graph omits
+// the @odata.type of casted objects, hence they are decoded as the given type. The resource must
+// be a direct list, see compileTransitiveCastListQueryOptions.
+func (g *GraphClient) listDirectoryObjectsOfType(resource, odataType string, opts []ListQueryOption) (DirectoryObjects, error) {
+	var marsh struct {
+		Values []json.RawMessage `json:"value"`
+	}
+	resource = fmt.Sprintf("%v/%v", resource, castSegment(odataType))
+	if err := g.makeGETAPICall(resource, compileListQueryOptions(opts), &marsh); err != nil {
+		return nil, err
+	}
+	objects := make(DirectoryObjects, len(marsh.Values))
+	for i, data := range marsh.Values {
+		if err := objects[i].unmarshalJSONOfType(data, "#"+castSegment(odataType)); err != nil {
+			return nil, err
+		}
+	}
+	objects.setGraphClient(g)
+	return objects, nil
+}
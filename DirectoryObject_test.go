@@ -0,0 +1,121 @@
+package msgraph
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDirectoryObject_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want func(DirectoryObject) bool
+	}{
+		{name: "User", json: `{"@odata.type":"#microsoft.graph.user","id":"1","displayName":"Alice","userPrincipalName":"alice@contoso.com"}`,
+			want: func(d DirectoryObject) bool {
+				return d.User != nil && d.User.UserPrincipalName == "alice@contoso.com" && d.Group == nil
+			}},
+		{name: "Group", json: `{"@odata.type":"#microsoft.graph.group","id":"2","displayName":"technicians","securityEnabled":true}`,
+			want: func(d DirectoryObject) bool { return d.Group != nil && d.Group.SecurityEnabled && d.User == nil }},
+		{name: "Device", json: `{"@odata.type":"#microsoft.graph.device","id":"3","displayName":"laptop","operatingSystem":"Windows"}`,
+			want: func(d DirectoryObject) bool { return d.Device != nil && d.Device.OperatingSystem == "Windows" }},
+		{name: "ServicePrincipal", json: `{"@odata.type":"#microsoft.graph.servicePrincipal","id":"4","displayName":"app","appId":"5"}`,
+			want: func(d DirectoryObject) bool { return d.ServicePrincipal != nil && d.ServicePrincipal.AppID == "5" }},
+		{name: "OrgContact", json: `{"@odata.type":"#microsoft.graph.orgContact","id":"6","displayName":"partner","mail":"partner@example.com"}`,
+			want: func(d DirectoryObject) bool { return d.OrgContact != nil && d.OrgContact.Mail == "partner@example.com" }},
+		{name: "Unknown type", json: `{"@odata.type":"#microsoft.graph.application","id":"7","displayName":"app registration"}`,
+			want: func(d DirectoryObject) bool {
+				return d.User == nil && d.Group == nil && d.Device == nil && d.ServicePrincipal == nil && d.OrgContact == nil
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got DirectoryObject
+			if err := json.Unmarshal([]byte(tt.json), &got); err != nil {
+				t.Fatalf("DirectoryObject.UnmarshalJSON() error = %v", err)
+			}
+			if got.ID == "" || got.DisplayName == "" || got.ODataType == "" || !tt.want(got) {
+				t.Errorf("DirectoryObject.UnmarshalJSON() = %+v, want a %v", got, tt.name)
+			}
+		})
+	}
+}
+
+func TestGroup_ListMemberObjects(t *testing.T) {
+	if offlineServer == nil {
+		t.Skip("the unit tests must not add devices, service principals and contacts to the tenant, only tested offline")
+	}
+	group := createUnitTestGroup(t)
+	defer group.DeleteGroup()
+	user := createUnitTestUser(t)
+	defer user.DeleteUser()
+	nested := createUnitTestGroup(t)
+	defer nested.DeleteGroup()
+	device := offlineServer.Add("/devices", map[string]interface{}{"displayName": "laptop", "operatingSystem": "Windows"})
+	servicePrincipal := offlineServer.Add("/servicePrincipals", map[string]interface{}{"displayName": "app", "appId": "00000000-0000-0000-0000-000000000001"})
+	contact := offlineServer.Add("/contacts", map[string]interface{}{"displayName": "partner", "mail": "partner@example.com"})
+	nestedUser := createUnitTestUser(t)
+	defer nestedUser.DeleteUser()
+	if err := group.AddMembers([]string{user.ID, nested.ID, device.ID(), servicePrincipal.ID(), contact.ID()}); err != nil {
+		t.Fatalf("Group.AddMembers() error = %v", err)
+	}
+	if err := nested.AddMember(nestedUser.ID); err != nil {
+		t.Fatalf("Group.AddMember() error = %v", err)
+	}
+
+	members, err := group.ListMemberObjects()
+	if err != nil {
+		t.Fatalf("Group.ListMemberObjects() error = %v", err)
+	}
+	if len(members) != 5 || len(members.Users()) != 1 || len(members.Groups()) != 1 || len(members.Devices()) != 1 ||
+		len(members.ServicePrincipals()) != 1 || len(members.OrgContacts()) != 1 {
+		t.Fatalf("Group.ListMemberObjects() = %v, want one member of each type", members)
+	}
+	if nestedGroup, err := members.GetByID(nested.ID); err != nil || nestedGroup.Group.graphClient == nil {
+		t.Errorf("DirectoryObjects.GetByID() = %v, error = %v, want the GraphClient sourced nested group", nestedGroup, err)
+	}
+	if _, err := members.GetByID("unknown"); err != ErrFindDirectoryObject {
+		t.Errorf("DirectoryObjects.GetByID() error = %v, want %v", err, ErrFindDirectoryObject)
+	}
+
+	offlineServer.ResetRequests()
+	users, err := group.ListMembers()
+	if err != nil || len(users) != 1 || users[0].ID != user.ID || users[0].graphClient == nil {
+		t.Errorf("Group.ListMembers() = %v, error = %v, want only the GraphClient sourced user %v", users, err, user.ID)
+	}
+	// type casts of direct lists are consistent, they do not require advanced query capabilities
+	if requests := offlineServer.Requests(); len(requests) != 1 || requests[0].Header.Get("ConsistencyLevel") != "" || requests[0].Query.Get("$count") != "" {
+		t.Errorf("Group.ListMembers() requests = %v, want a request without advanced query capabilities", requests)
+	}
+	groups, err := group.ListMembersOfType(ODataTypeGroup)
+	if err != nil || len(groups) != 1 || groups[0].ID != nested.ID {
+		t.Errorf("Group.ListMembersOfType(ODataTypeGroup) = %v, error = %v, want %v", groups, err, nested.ID)
+	} else if groups[0].ODataType != ODataTypeGroup || groups[0].Group == nil || groups[0].Group.graphClient == nil {
+		t.Errorf("Group.ListMembersOfType(ODataTypeGroup) = %v, want the GraphClient sourced group decoded from the type cast", groups[0])
+	}
+	if _, err := group.ListMembersOfType("#microsoft.graph.unknown"); err == nil {
+		t.Errorf("Group.ListMembersOfType() of an unknown type error = nil, want error")
+	}
+
+	transitive, err := group.ListTransitiveMemberObjects()
+	if err != nil || len(transitive) != 6 || len(transitive.OfType(ODataTypeUser)) != 2 {
+		t.Errorf("Group.ListTransitiveMemberObjects() = %v, error = %v, want the 5 members and the user of the nested group", transitive, err)
+	}
+	offlineServer.ResetRequests()
+	if users, err := group.ListTransitiveMembers(); err != nil || len(users) != 2 {
+		t.Errorf("Group.ListTransitiveMembers() = %v, error = %v, want both users", users, err)
+	}
+	if requests := offlineServer.Requests(); len(requests) != 1 || requests[0].Header.Get("ConsistencyLevel") != "eventual" || requests[0].Query.Get("$count") != "true" {
+		t.Errorf("Group.ListTransitiveMembers() requests = %v, want a request with advanced query capabilities", requests)
+	}
+
+	if err := group.AddOwner(servicePrincipal.ID()); err != nil {
+		t.Fatalf("Group.AddOwner() of a service principal error = %v", err)
+	}
+	if owners, err := group.ListOwnerObjects(); err != nil || len(owners.ServicePrincipals()) != 1 {
+		t.Errorf("Group.ListOwnerObjects() = %v, error = %v, want the service principal", owners, err)
+	}
+	if owners, err := group.ListOwners(); err != nil || len(owners) != 0 {
+		t.Errorf("Group.ListOwners() = %v, error = %v, want no users", owners, err)
+	}
+}
@@ -0,0 +1,101 @@
+package msgraph
+
+import (
+	"strings"
+)
+
+// DirectoryObjects represents multiple DirectoryObject-instances and provides funcs to work with them.
+type DirectoryObjects []DirectoryObject
+
+func (d DirectoryObjects) String() string {
+	var objects = make([]string, len(d))
+	for i, object := range d {
+		objects[i] = object.String()
+	}
+	return "DirectoryObjects(" + strings.Join(objects, " | ") + ")"
+}
+
+// setGraphClient sets the GraphClient within that particular instance. Hence it's directly created by GraphClient
+func (d DirectoryObjects) setGraphClient(gC *GraphClient) DirectoryObjects {
+	for i := range d {
+		d[i].setGraphClient(gC)
+	}
+	return d
+}
+
+// GetByID returns the DirectoryObject with the given ID. Returns ErrFindDirectoryObject if
+// there is none.
+func (d DirectoryObjects) GetByID(id string) (DirectoryObject, error) {
+	for _, object := range d {
+		if object.ID == id {
+			return object, nil
+		}
+	}
+	return DirectoryObject{}, ErrFindDirectoryObject
+}
+
+// OfType returns all DirectoryObjects of the given @odata.type, one of the ODataType* constants.
+func (d DirectoryObjects) OfType(odataType string) DirectoryObjects {
+	var objects DirectoryObjects
+	for _, object := range d {
+		if object.ODataType == odataType {
+			objects = append(objects, object)
+		}
+	}
+	return objects
+}
+
+// Users returns all users of the DirectoryObjects.
+func (d DirectoryObjects) Users() Users {
+	var users Users
+	for _, object := range d {
+		if object.User != nil {
+			users = append(users, *object.User)
+		}
+	}
+	return users
+}
+
+// Groups returns all groups of the DirectoryObjects.
+func (d DirectoryObjects) Groups() Groups {
+	var groups Groups
+	for _, object := range d {
+		if object.Group != nil {
+			groups = append(groups, *object.Group)
+		}
+	}
+	return groups
+}
+
+// Devices returns all devices of the DirectoryObjects.
+func (d DirectoryObjects) Devices() []Device {
+	var devices []Device
+	for _, object := range d {
+		if object.Device != nil {
+			devices = append(devices, *object.Device)
+		}
+	}
+	return devices
+}
+
+// ServicePrincipals returns all service principals of the DirectoryObjects.
+func (d DirectoryObjects) ServicePrincipals() []ServicePrincipal {
+	var servicePrincipals []ServicePrincipal
+	for _, object := range d {
+		if object.ServicePrincipal != nil {
+			servicePrincipals = append(servicePrincipals, *object.ServicePrincipal)
+		}
+	}
+	return servicePrincipals
+}
+
+// OrgContacts returns all org contacts of the DirectoryObjects.
+func (d DirectoryObjects) OrgContacts() []OrgContact {
+	var orgContacts []OrgContact
+	for _, object := range d {
+		if object.OrgContact != nil {
+			orgContacts = append(orgContacts, *object.OrgContact)
+		}
+	}
+	return orgContacts
+}
@@ -0,0 +1,202 @@
+package msgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// OpenExtension represents an open extension of a user or group, hence untyped custom data
+// identified by its ExtensionName, e.g. com.contoso.costCenter.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/opentypeextension
+type OpenExtension struct {
+	ExtensionName string                 // unique name in reverse domain name notation, e.g. com.contoso.costCenter
+	Properties    map[string]interface{} // custom data, e.g. {"code": "CC-4711"}
+}
+
+func (e OpenExtension) String() string {
+	names := make([]string, 0, len(e.Properties))
+	for name := range e.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Sprintf("OpenExtension(ExtensionName: \"%v\", Properties: %v)", e.ExtensionName, names)
+}
+
+// Decode maps the Properties of the open extension to v, a pointer to a user-defined struct
+// with json tags.
+func (e OpenExtension) Decode(v interface{}) error {
+	data, err := json.Marshal(e.Properties)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// MarshalJSON implements the json marshal to be used by the json-library.
+func (e OpenExtension) MarshalJSON() ([]byte, error) {
+	properties := make(map[string]interface{}, len(e.Properties)+2)
+	for name, value := range e.Properties {
+		properties[name] = value
+	}
+	properties["@odata.type"] = "microsoft.graph.openTypeExtension"
+	properties["extensionName"] = e.ExtensionName
+	return json.Marshal(properties)
+}
+
+// UnmarshalJSON implements the json unmarshal to be used by the json-library.
+func (e *OpenExtension) UnmarshalJSON(data []byte) error {
+	var properties map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // keep large numbers exact until they are decoded into their final type
+	if err := decoder.Decode(&properties); err != nil {
+		return err
+	}
+	e.ExtensionName, _ = properties["extensionName"].(string)
+	for name := range properties {
+		if name == "id" || name == "extensionName" || strings.Contains(name, "@") {
+			delete(properties, name)
+		}
+	}
+	e.Properties = properties
+	return nil
+}
+
+// OpenExtensions represents multiple OpenExtension-instances and provides funcs to work with them.
+type OpenExtensions []OpenExtension
+
+func (o OpenExtensions) String() string {
+	var extensions = make([]string, len(o))
+	for i, extension := range o {
+		extensions[i] = extension.String()
+	}
+	return "OpenExtensions(" + strings.Join(extensions, " | ") + ")"
+}
+
+// GetByName returns the OpenExtension with the given ExtensionName, compared case-insensitively.
+// Returns ErrFindExtension if there is none.
+func (o OpenExtensions) GetByName(extensionName string) (OpenExtension, error) {
+	for _, extension := range o {
+		if strings.EqualFold(extension.ExtensionName, extensionName) {
+			return extension, nil
+		}
+	}
+	return OpenExtension{}, ErrFindExtension
+}
+
+// isExtensionProperty returns true if the json name is the name of a schema extension, e.g.
+// extkvbmkofy_costCenter, or of a directory extension, e.g. extension_{appId}_costCenter.
+// Properties of Microsoft Graph itself never contain underscores.
+func isExtensionProperty(name string) bool {
+	return strings.Contains(name, "_") && !strings.Contains(name, "@")
+}
+
+// extensionProperties returns all schema and directory extension properties of the json object.
+func extensionProperties(data []byte) (map[string]json.RawMessage, error) {
+	var properties map[string]json.RawMessage
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return nil, err
+	}
+	var extensions map[string]json.RawMessage
+	for name, value := range properties {
+		if !isExtensionProperty(name) {
+			continue
+		}
+		if extensions == nil {
+			extensions = make(map[string]json.RawMessage)
+		}
+		extensions[name] = value
+	}
+	return extensions, nil
+}
+
+// decodeSchemaExtension maps the value of the schema extension with the given ID to v. Returns
+// ErrFindExtension if the extension has not been selected or has no value.
+func decodeSchemaExtension(properties map[string]json.RawMessage, extensionID string, v interface{}) error {
+	value, ok := properties[extensionID]
+	if !ok || string(value) == "null" {
+		return ErrFindExtension
+	}
+	return json.Unmarshal(value, v)
+}
+
+// selectSchemaExtensions adds the schema extensions requested via GetWithSchemaExtensions or
+// ListWithSchemaExtensions to $select. Microsoft Graph only returns the selected properties,
+// hence the defaultSelect of the resource is selected too if nothing else has been selected.
+func (g *getQueryOptions) selectSchemaExtensions(defaultSelect string) {
+	if len(g.schemaExtensions) == 0 {
+		return
+	}
+	properties := g.queryValues[odataSelectParamKey]
+	if len(properties) == 0 {
+		properties = []string{defaultSelect}
+	}
+	properties = append(properties, g.schemaExtensions...)
+	g.queryValues.Set(odataSelectParamKey, strings.Join(properties, ","))
+}
+
+// listExtensions returns all open extensions of the given resource, e.g. /users/{id}.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/opentypeextension-get
+func (g *GraphClient) listExtensions(resource string, opts []ListQueryOption) (OpenExtensions, error) {
+	var marsh struct {
+		Extensions OpenExtensions `json:"value"`
+	}
+	err := g.makeGETAPICall(resource+"/extensions", compileListQueryOptions(opts), &marsh)
+	return marsh.Extensions, err
+}
+
+// getExtension returns the open extension with the given name of the given resource.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/opentypeextension-get
+func (g *GraphClient) getExtension(resource, extensionName string, opts []GetQueryOption) (OpenExtension, error) {
+	var extension OpenExtension
+	err := g.makeGETAPICall(resource+"/extensions/"+extensionName, compileGetQueryOptions(opts), &extension)
+	return extension, err
+}
+
+// createExtension creates the open extension for the given resource.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/opentypeextension-post-opentypeextension
+func (g *GraphClient) createExtension(resource string, extension OpenExtension, opts []CreateQueryOption) (OpenExtension, error) {
+	if extension.ExtensionName == "" {
+		return OpenExtension{}, fmt.Errorf("ExtensionName of the open extension is required")
+	}
+	bodyBytes, err := json.Marshal(extension)
+	if err != nil {
+		return OpenExtension{}, err
+	}
+
+	reader := bytes.NewReader(bodyBytes)
+	var created OpenExtension
+	err = g.makePOSTAPICall(resource+"/extensions", compileCreateQueryOptions(opts), reader, &created)
+	return created, err
+}
+
+// updateExtension updates the properties of the open extension of the given resource. Properties
+// that are not set are removed from the extension.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/opentypeextension-update
+func (g *GraphClient) updateExtension(resource string, extension OpenExtension, opts []UpdateQueryOption) error {
+	if extension.ExtensionName == "" {
+		return fmt.Errorf("ExtensionName of the open extension is required")
+	}
+	bodyBytes, err := json.Marshal(extension)
+	if err != nil {
+		return err
+	}
+
+	reader := bytes.NewReader(bodyBytes)
+	// Hint: API-call body does not return any data / no json object.
+	return g.makePATCHAPICall(resource+"/extensions/"+extension.ExtensionName, compileUpdateQueryOptions(opts), reader, nil)
+}
+
+// deleteExtension deletes the open extension with the given name of the given resource.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/opentypeextension-delete
+func (g *GraphClient) deleteExtension(resource, extensionName string, opts []DeleteQueryOption) error {
+	return g.makeDELETEAPICall(resource+"/extensions/"+extensionName, compileDeleteQueryOptions(opts), nil)
+}
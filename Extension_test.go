@@ -0,0 +1,93 @@
+package msgraph
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestOpenExtension_JSON(t *testing.T) {
+	extension := OpenExtension{ExtensionName: "com.contoso.costCenter", Properties: map[string]interface{}{"code": "CC-4711", "budget": 12000}}
+	data, err := json.Marshal(extension)
+	if err != nil {
+		t.Fatalf("OpenExtension.MarshalJSON() error = %v", err)
+	}
+	var properties map[string]interface{}
+	json.Unmarshal(data, &properties)
+	if properties["@odata.type"] != "microsoft.graph.openTypeExtension" || properties["extensionName"] != extension.ExtensionName || properties["code"] != "CC-4711" {
+		t.Errorf("OpenExtension.MarshalJSON() = %v", string(data))
+	}
+
+	var got OpenExtension
+	response := `{"@odata.type": "#microsoft.graph.openTypeExtension", "id": "com.contoso.costCenter", "extensionName": "com.contoso.costCenter", "code": "CC-4711", "budget": 9007199254740993}`
+	if err := json.Unmarshal([]byte(response), &got); err != nil {
+		t.Fatalf("OpenExtension.UnmarshalJSON() error = %v", err)
+	}
+	if got.ExtensionName != extension.ExtensionName || len(got.Properties) != 2 {
+		t.Errorf("OpenExtension.UnmarshalJSON() = %v, want only the custom properties", got)
+	}
+	var costCenter struct {
+		Code   string `json:"code"`
+		Budget int64  `json:"budget"`
+	}
+	if err := got.Decode(&costCenter); err != nil || costCenter.Code != "CC-4711" || costCenter.Budget != 9007199254740993 {
+		t.Errorf("OpenExtension.Decode() = %+v, error = %v", costCenter, err)
+	}
+}
+
+func TestExtensionProperties(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want map[string]json.RawMessage
+	}{
+		{name: "none", data: `{"id": "1", "displayName": "Alice", "manager@odata.bind": "x"}`, want: nil},
+		{
+			name: "schema and directory extensions",
+			data: `{"id": "1", "extkvbmkofy_costCenter": {"code": "CC-4711"}, "extension_8a7e4c0c8a6d4d0e_employeeNumber": "4711"}`,
+			want: map[string]json.RawMessage{
+				"extkvbmkofy_costCenter":                    json.RawMessage(`{"code": "CC-4711"}`),
+				"extension_8a7e4c0c8a6d4d0e_employeeNumber": json.RawMessage(`"4711"`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var user User
+			if err := json.Unmarshal([]byte(tt.data), &user); err != nil {
+				t.Fatalf("User.UnmarshalJSON() error = %v", err)
+			}
+			if !reflect.DeepEqual(user.ExtensionProperties, tt.want) {
+				t.Errorf("User.ExtensionProperties = %v, want %v", user.ExtensionProperties, tt.want)
+			}
+			var group Group
+			if err := json.Unmarshal([]byte(tt.data), &group); err != nil {
+				t.Fatalf("Group.UnmarshalJSON() error = %v", err)
+			}
+			if !reflect.DeepEqual(group.ExtensionProperties, tt.want) {
+				t.Errorf("Group.ExtensionProperties = %v, want %v", group.ExtensionProperties, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetQueryOptions_selectSchemaExtensions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []GetQueryOption
+		want string
+	}{
+		{name: "none", opts: nil, want: ""},
+		{name: "default", opts: []GetQueryOption{GetWithSchemaExtensions("extkvbmkofy_costCenter")}, want: userDefaultSelect + ",extkvbmkofy_costCenter"},
+		{name: "selected", opts: []GetQueryOption{GetWithSelect("id,mail"), GetWithSchemaExtensions("extkvbmkofy_costCenter", "extkvbmkofy_site")}, want: "id,mail,extkvbmkofy_costCenter,extkvbmkofy_site"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqOpt := compileGetQueryOptions(tt.opts)
+			reqOpt.selectSchemaExtensions(userDefaultSelect)
+			if got := reqOpt.Values().Get(odataSelectParamKey); got != tt.want {
+				t.Errorf("getQueryOptions.selectSchemaExtensions() $select = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
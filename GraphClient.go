@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -39,6 +40,12 @@ type GraphClient struct {
 	azureADAuthEndpoint string
 	// serviceRootEndpoint is the basic API-url used for this instance of GraphClient, namely Microsoft Graph service root endpoints. For available endpoints see https://docs.microsoft.com/en-us/graph/deployments#microsoft-graph-and-graph-explorer-service-root-endpoints.
 	serviceRootEndpoint string
+	// httpClient is used to perform all requests of this instance of GraphClient, see WithHTTPClient. If nil, a http.Client with a timeout of 10 seconds is used, streamed response bodies are only limited by the context.
+	httpClient *http.Client
+	// dryRun prevents POST, PATCH and DELETE API-calls from being sent, they are added to plan instead. See WithDryRun.
+	dryRun bool
+	// plan holds all API-calls that have not been sent because of dry-run mode, see Plan.
+	plan []PlannedOperation
 }
 
 func (g *GraphClient) String() string {
@@ -56,8 +63,10 @@ func (g *GraphClient) String() string {
 // default ms graph API global endpoint is used.
 //
 // This method does not have to be used to create a new GraphClient. If not used, the default global ms Graph API endpoint is used.
-func NewGraphClient(tenantID, applicationID, clientSecret string) (*GraphClient, error) {
-	return NewGraphClientWithCustomEndpoint(tenantID, applicationID, clientSecret, AzureADAuthEndpointGlobal, ServiceRootEndpointGlobal)
+//
+// Supports optional GraphClientOptions, e.g. msgraph.WithHTTPClient
+func NewGraphClient(tenantID, applicationID, clientSecret string, opts ...GraphClientOption) (*GraphClient, error) {
+	return NewGraphClientWithCustomEndpoint(tenantID, applicationID, clientSecret, AzureADAuthEndpointGlobal, ServiceRootEndpointGlobal, opts...)
 }
 
 // NewGraphClientCustomEndpoint creates a new GraphClient instance with the
@@ -70,7 +79,9 @@ func NewGraphClient(tenantID, applicationID, clientSecret string) (*GraphClient,
 //
 // Returns an error if the token cannot be initialized. This func does not have
 // to be used to create a new GraphClient.
-func NewGraphClientWithCustomEndpoint(tenantID, applicationID, clientSecret string, azureADAuthEndpoint string, serviceRootEndpoint string) (*GraphClient, error) {
+//
+// Supports optional GraphClientOptions, e.g. msgraph.WithHTTPClient
+func NewGraphClientWithCustomEndpoint(tenantID, applicationID, clientSecret string, azureADAuthEndpoint string, serviceRootEndpoint string, opts ...GraphClientOption) (*GraphClient, error) {
 	g := GraphClient{
 		TenantID:            tenantID,
 		ApplicationID:       applicationID,
@@ -78,6 +89,9 @@ func NewGraphClientWithCustomEndpoint(tenantID, applicationID, clientSecret stri
 		azureADAuthEndpoint: azureADAuthEndpoint,
 		serviceRootEndpoint: serviceRootEndpoint,
 	}
+	for idx := range opts {
+		opts[idx](&g)
+	}
 	g.apiCall.Lock()         // lock because we will refresh the token
 	defer g.apiCall.Unlock() // unlock after token refresh
 	return &g, g.refreshToken()
@@ -95,6 +109,30 @@ func (g *GraphClient) makeSureURLsAreSet() {
 	}
 }
 
+// directoryObjectURL returns the absolute URL of the directory object with the given ID, as
+// used by @odata.id and @odata.bind references.
+func (g *GraphClient) directoryObjectURL(id string) string {
+	g.makeSureURLsAreSet()
+	return fmt.Sprintf("%v/%v/directoryObjects/%v", g.serviceRootEndpoint, APIVersion, id)
+}
+
+// resourceURL returns the absolute URL of the given resource, e.g. groups('{id}'), as used
+// by @odata.bind references to objects that are no directory objects.
+func (g *GraphClient) resourceURL(resource string) string {
+	g.makeSureURLsAreSet()
+	return fmt.Sprintf("%v/%v/%v", g.serviceRootEndpoint, APIVersion, resource)
+}
+
+// directoryObjectURLs returns the absolute URLs of the directory objects with the given IDs,
+// see directoryObjectURL.
+func (g *GraphClient) directoryObjectURLs(ids []string) []string {
+	urls := make([]string, len(ids))
+	for i, id := range ids {
+		urls[i] = g.directoryObjectURL(id)
+	}
+	return urls
+}
+
 // refreshToken refreshes the current Token. Grabs a new one and saves it within the GraphClient instance
 func (g *GraphClient) refreshToken() error {
 	g.makeSureURLsAreSet()
@@ -142,24 +180,47 @@ func (g *GraphClient) makeGETAPICall(apiCall string, reqParams getRequestParams,
 	return g.makeAPICall(apiCall, http.MethodGet, reqParams, nil, v)
 }
 
-// makePOSTAPICall performs an API-Call to the msgraph API.
+// makePOSTAPICall performs an API-Call to the msgraph API. In dry-run mode the API-call is added to the plan instead.
 func (g *GraphClient) makePOSTAPICall(apiCall string, reqParams getRequestParams, body io.Reader, v interface{}) error {
+	if g.isDryRun(reqParams) {
+		return g.planAPICall(apiCall, http.MethodPost, reqParams, body)
+	}
+	return g.makeAPICall(apiCall, http.MethodPost, reqParams, body, v)
+}
+
+// makeReadOnlyPOSTAPICall performs an API-Call to the msgraph API that does not modify any data,
+// e.g. getMemberGroups. Hence it is performed in dry-run mode too.
+func (g *GraphClient) makeReadOnlyPOSTAPICall(apiCall string, reqParams getRequestParams, body io.Reader, v interface{}) error {
 	return g.makeAPICall(apiCall, http.MethodPost, reqParams, body, v)
 }
 
-// makePATCHAPICall performs an API-Call to the msgraph API.
+// makePATCHAPICall performs an API-Call to the msgraph API. In dry-run mode the API-call is added to the plan instead.
 func (g *GraphClient) makePATCHAPICall(apiCall string, reqParams getRequestParams, body io.Reader, v interface{}) error {
+	if g.isDryRun(reqParams) {
+		return g.planAPICall(apiCall, http.MethodPatch, reqParams, body)
+	}
 	return g.makeAPICall(apiCall, http.MethodPatch, reqParams, body, v)
 }
 
-// makeDELETEAPICall performs an API-Call to the msgraph API.
+// makePUTAPICall performs an API-Call to the msgraph API. In dry-run mode the API-call is added to the plan instead.
+func (g *GraphClient) makePUTAPICall(apiCall string, reqParams getRequestParams, body io.Reader, v interface{}) error {
+	if g.isDryRun(reqParams) {
+		return g.planAPICall(apiCall, http.MethodPut, reqParams, body)
+	}
+	return g.makeAPICall(apiCall, http.MethodPut, reqParams, body, v)
+}
+
+// makeDELETEAPICall performs an API-Call to the msgraph API. In dry-run mode the API-call is added to the plan instead.
 func (g *GraphClient) makeDELETEAPICall(apiCall string, reqParams getRequestParams, v interface{}) error {
+	if g.isDryRun(reqParams) {
+		return g.planAPICall(apiCall, http.MethodDelete, reqParams, nil)
+	}
 	return g.makeAPICall(apiCall, http.MethodDelete, reqParams, nil, v)
 }
 
 // makeAPICall performs an API-Call to the msgraph API. This func uses sync.Mutex to synchronize all API-calls.
 //
-// Parameter httpMethod may be http.MethodGet, http.MethodPost or http.MethodPatch
+// Parameter httpMethod may be http.MethodGet, http.MethodPost, http.MethodPatch, http.MethodPut or http.MethodDelete
 //
 // Parameter body may be nil to not provide any content - e.g. when using a http GET request.
 func (g *GraphClient) makeAPICall(apiCall string, httpMethod string, reqParams getRequestParams, body io.Reader, v interface{}) error {
@@ -182,12 +243,17 @@ func (g *GraphClient) makeAPICall(apiCall string, httpMethod string, reqParams g
 	// Add Version to API-Call, the leading slash is always added by the calling func
 	reqURL.Path = "/" + APIVersion + apiCall
 
+	contentType := "application/json"
+	if binary, ok := body.(*binaryBody); ok {
+		contentType = binary.contentType
+		body = binary.Reader // *bytes.Reader, hence the Content-Length is set instead of a chunked body
+	}
+
 	req, err := http.NewRequestWithContext(reqParams.Context(), httpMethod, reqURL.String(), body)
 	if err != nil {
 		return fmt.Errorf("HTTP request error: %v", err)
 	}
-
-	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Content-Type", contentType)
 	req.Header.Add("Authorization", g.token.GetAccessToken())
 
 	for key, vals := range reqParams.Headers() {
@@ -232,14 +298,34 @@ func (g *GraphClient) makeSkipTokenApiCall(httpMethod string, v interface{}, ski
 	return g.performSkipTokenRequest(req, v)
 }
 
-// performSkipTokenRequest performs a pre-prepared http.Request and does the proper error-handling for it.
-// does a json.Unmarshal into the v interface{} and returns the error of it if everything went well so far.
-func (g *GraphClient) performSkipTokenRequest(req *http.Request, v interface{}) error {
-	httpClient := &http.Client{
+// getHTTPClient returns the http.Client configured via WithHTTPClient or a new
+// http.Client with a timeout of 10 seconds.
+func (g *GraphClient) getHTTPClient() *http.Client {
+	if g.httpClient != nil {
+		return g.httpClient
+	}
+	return &http.Client{
 		Timeout: time.Second * 10,
 	}
+}
 
-	resp, err := httpClient.Do(req)
+// getStreamHTTPClient returns the http.Client to receive a streamResponse, hence the
+// http.Client configured via WithHTTPClient or a new http.Client that waits 10 seconds at most
+// for the response headers. Unlike the timeout of getHTTPClient, reading the streamed body is
+// not limited, it is cancelled via the context of the request.
+func (g *GraphClient) getStreamHTTPClient() *http.Client {
+	if g.httpClient != nil {
+		return g.httpClient
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = time.Second * 10
+	return &http.Client{Transport: transport}
+}
+
+// performSkipTokenRequest performs a pre-prepared http.Request and does the proper error-handling for it.
+// does a json.Unmarshal into the v interface{} and returns the error of it if everything went well so far.
+func (g *GraphClient) performSkipTokenRequest(req *http.Request, v interface{}) error {
+	resp, err := g.getHTTPClient().Do(req)
 	if err != nil {
 		return fmt.Errorf("HTTP response error: %v of http.Request: %v", err, req.URL)
 	}
@@ -249,7 +335,7 @@ func (g *GraphClient) performSkipTokenRequest(req *http.Request, v interface{})
 	if resp.StatusCode < 200 || resp.StatusCode > 299 {
 		// Hint: this will mostly be the case if the tenant ID cannot be found, the Application ID cannot be found or the clientSecret is incorrect.
 		// The cause will be described in the body, hence we have to return the body too for proper error-analysis
-		return fmt.Errorf("StatusCode is not OK: %v. Body: %v ", resp.StatusCode, string(body))
+		return &statusError{statusCode: resp.StatusCode, body: string(body)}
 	}
 
 	// fmt.Println("Body: ", string(body))
@@ -261,48 +347,114 @@ func (g *GraphClient) performSkipTokenRequest(req *http.Request, v interface{})
 	return json.Unmarshal(body, &v) // return the error of the json unmarshal
 }
 
+// statusError is returned for responses with a status code other than 2xx. The body contains
+// the error of Microsoft Graph, e.g. {"error": {"code": "Request_ResourceNotFound", ...}}.
+type statusError struct {
+	statusCode int
+	body       string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("StatusCode is not OK: %v. Body: %v ", e.statusCode, e.body)
+}
+
+// isStatusError returns true if err is a statusError with the given status code whose body
+// contains the given message, e.g. to treat a 404 on removing a reference as success.
+func isStatusError(err error, statusCode int, message string) bool {
+	statusErr, ok := err.(*statusError)
+	return ok && statusErr.statusCode == statusCode && strings.Contains(statusErr.body, message)
+}
+
+// binaryBody is a non-JSON request body, e.g. an image uploaded by User.SetPhoto. It is sent
+// with its contentType instead of application/json.
+type binaryBody struct {
+	*bytes.Reader
+	contentType string
+}
+
+// rawResponse receives a non-JSON response body, e.g. the image returned by User.GetPhoto,
+// instead of unmarshalling it.
+type rawResponse struct {
+	contentType string
+	location    string // Location header, e.g. of a long-running operation accepted with 202
+	body        []byte
+}
+
+// streamResponse receives a non-JSON response body as stream, e.g. the image returned by
+// User.OpenPhoto, instead of reading it. The caller must close the body.
+type streamResponse struct {
+	contentType   string
+	contentLength int64 // -1 if unknown
+	body          io.ReadCloser
+}
+
 // performRequest performs a pre-prepared http.Request and does the proper error-handling for it.
 // does a json.Unmarshal into the v interface{} and returns the error of it if everything went well so far.
+// If v is a *rawResponse the body is returned as is, if v is a *streamResponse it is not read at all.
 func (g *GraphClient) performRequest(req *http.Request, v interface{}) error {
-	httpClient := &http.Client{
-		Timeout: time.Second * 10,
+	httpClient := g.getHTTPClient()
+	if _, ok := v.(*streamResponse); ok {
+		httpClient = g.getStreamHTTPClient()
 	}
 	resp, err := httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("HTTP response error: %v of http.Request: %v", err, req.URL)
 	}
+	// binary content is streamed to the caller, e.g. by User.OpenPhoto(), hence the body is closed by the caller
+	if stream, ok := v.(*streamResponse); ok && resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		stream.contentType = resp.Header.Get("Content-Type")
+		stream.contentLength = resp.ContentLength
+		stream.body = resp.Body
+		return nil
+	}
 	defer resp.Body.Close() // close body when func returns
 
 	body, err := ioutil.ReadAll(resp.Body) // read body first to append it to the error (if any)
 	if resp.StatusCode < 200 || resp.StatusCode > 299 {
 		// Hint: this will mostly be the case if the tenant ID cannot be found, the Application ID cannot be found or the clientSecret is incorrect.
 		// The cause will be described in the body, hence we have to return the body too for proper error-analysis
-		return fmt.Errorf("StatusCode is not OK: %v. Body: %v ", resp.StatusCode, string(body))
+		return &statusError{statusCode: resp.StatusCode, body: string(body)}
 	}
 
 	if err != nil {
 		return fmt.Errorf("HTTP response read error: %v of http.Request: %v", err, req.URL)
 	}
 
-	// no content returned when http PATCH or DELETE is used, e.g. User.DeleteUser()
-	if req.Method == http.MethodDelete || req.Method == http.MethodPatch {
+	// binary content is returned as is, e.g. by User.GetPhoto()
+	if raw, ok := v.(*rawResponse); ok {
+		raw.contentType = resp.Header.Get("Content-Type")
+		raw.location = resp.Header.Get("Location")
+		raw.body = body
+		return nil
+	}
+
+	// no content returned when http PATCH or DELETE is used, e.g. User.DeleteUser(), or for
+	// 204 No Content and empty bodies in general, e.g. when adding a reference with User.SetManager()
+	// or for long-running operations accepted with 202, e.g. User.ResetPassword()
+	if req.Method == http.MethodDelete || req.Method == http.MethodPatch || resp.StatusCode == http.StatusNoContent || len(body) == 0 {
 		return nil
 	}
 	type skipTokenCallData struct {
 		Data      []json.RawMessage `json:"value"`
 		SkipToken string            `json:"@odata.nextLink"`
 	}
-	res := skipTokenCallData{}
+	// only paged collections have a nextLink, the value of e.g. an action may also be a single value
+	var nextLink struct {
+		SkipToken string `json:"@odata.nextLink"`
+	}
+	if err := json.Unmarshal(body, &nextLink); err != nil {
+		return err
+	}
+	if nextLink.SkipToken == "" {
+		return json.Unmarshal(body, &v) // return the error of the json unmarshal
+	}
 
+	res := skipTokenCallData{}
 	err = json.Unmarshal(body, &res)
 	if err != nil {
 		return err
 	}
 
-	if res.SkipToken == "" {
-		return json.Unmarshal(body, &v) // return the error of the json unmarshal
-	}
-
 	data := res.Data
 	for res.SkipToken != "" {
 		skipToken := res.SkipToken
@@ -331,20 +483,24 @@ func (g *GraphClient) performRequest(req *http.Request, v interface{}) error {
 }
 
 // ListUsers returns a list of all users
+// Supports schema extensions, see ListWithSchemaExtensions
 // Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
 //
 // Reference: https://developer.microsoft.com/en-us/graph/docs/api-reference/v1.0/api/user_list
 func (g *GraphClient) ListUsers(opts ...ListQueryOption) (Users, error) {
 	resource := "/users"
+	reqParams := compileListQueryOptions(opts)
+	reqParams.selectSchemaExtensions(userDefaultSelect)
 	var marsh struct {
 		Users Users `json:"value"`
 	}
-	err := g.makeGETAPICall(resource, compileListQueryOptions(opts), &marsh)
+	err := g.makeGETAPICall(resource, reqParams, &marsh)
 	marsh.Users.setGraphClient(g)
 	return marsh.Users, err
 }
 
 // ListGroups returns a list of all groups
+// Supports schema extensions, see ListWithSchemaExtensions
 // Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
 //
 // Reference: https://developer.microsoft.com/en-us/graph/docs/api-reference/v1.0/api/group_list
@@ -352,6 +508,7 @@ func (g *GraphClient) ListGroups(opts ...ListQueryOption) (Groups, error) {
 	resource := "/groups"
 
 	var reqParams = compileListQueryOptions(opts)
+	reqParams.selectSchemaExtensions(groupDefaultSelect)
 
 	var marsh struct {
 		Groups Groups `json:"value"`
@@ -380,30 +537,36 @@ func (g *GraphClient) getMemberGroups(identifier string, securityEnabledOnly boo
 	}
 	body := bytes.NewReader(bodyBytes)
 
-	err = g.makePOSTAPICall(resource, compileGetQueryOptions(opts), body, &marsh)
+	err = g.makeReadOnlyPOSTAPICall(resource, compileGetQueryOptions(opts), body, &marsh)
 	return marsh.Groups, err
 }
 
 // GetUser returns the user object associated to the given user identified by either
 // the given ID or userPrincipalName
+// Supports schema extensions, see GetWithSchemaExtensions
 // Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
 //
 // Reference: https://developer.microsoft.com/en-us/graph/docs/api-reference/v1.0/api/user_get
 func (g *GraphClient) GetUser(identifier string, opts ...GetQueryOption) (User, error) {
 	resource := fmt.Sprintf("/users/%v", identifier)
 	user := User{graphClient: g}
-	err := g.makeGETAPICall(resource, compileGetQueryOptions(opts), &user)
+	reqParams := compileGetQueryOptions(opts)
+	reqParams.selectSchemaExtensions(userDefaultSelect)
+	err := g.makeGETAPICall(resource, reqParams, &user)
 	return user, err
 }
 
 // GetGroup returns the group object identified by the given groupID.
+// Supports schema extensions, see GetWithSchemaExtensions
 // Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
 //
 // Reference: https://developer.microsoft.com/en-us/graph/docs/api-reference/v1.0/api/group_get
 func (g *GraphClient) GetGroup(groupID string, opts ...GetQueryOption) (Group, error) {
 	resource := fmt.Sprintf("/groups/%v", groupID)
 	group := Group{graphClient: g}
-	err := g.makeGETAPICall(resource, compileGetQueryOptions(opts), &group)
+	reqParams := compileGetQueryOptions(opts)
+	reqParams.selectSchemaExtensions(groupDefaultSelect)
+	err := g.makeGETAPICall(resource, reqParams, &group)
 	return group, err
 }
 
@@ -422,6 +585,63 @@ func (g *GraphClient) CreateUser(userInput User, opts ...CreateQueryOption) (Use
 	return user, err
 }
 
+// CreateGroup creates a new group given a group object and returns the created group. The
+// DisplayName and MailNickname are required, MailEnabled and SecurityEnabled are always sent.
+// Create a Microsoft 365 group with GroupTypes GroupTypeUnified and MailEnabled, a security
+// group with SecurityEnabled. The users or service principals with the ownerIDs and the
+// directory objects with the memberIDs are added on creation, up to 20 in total. Add further
+// members afterwards. A group with GroupTypeDynamicMembership requires a MembershipRule and
+// cannot have memberIDs. The rule is validated by Microsoft Graph, see ParseMembershipRule.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/group-post-groups
+func (g *GraphClient) CreateGroup(groupInput Group, ownerIDs, memberIDs []string, opts ...CreateQueryOption) (Group, error) {
+	group := Group{graphClient: g}
+	if groupInput.DisplayName == "" || groupInput.MailNickname == "" {
+		return group, fmt.Errorf("DisplayName and MailNickname of the group are required")
+	}
+	if len(ownerIDs)+len(memberIDs) > maxGroupBindsOnCreate {
+		return group, fmt.Errorf("at most %v owners and members can be added on creation, got %v", maxGroupBindsOnCreate, len(ownerIDs)+len(memberIDs))
+	}
+	if groupInput.IsDynamicMembership() || groupInput.MembershipRule != "" {
+		if !groupInput.IsDynamicMembership() {
+			return group, fmt.Errorf("a MembershipRule requires the GroupType %v", GroupTypeDynamicMembership)
+		}
+		if strings.TrimSpace(groupInput.MembershipRule) == "" {
+			return group, fmt.Errorf("a group with the GroupType %v requires a MembershipRule", GroupTypeDynamicMembership)
+		}
+		if len(memberIDs) > 0 {
+			return group, fmt.Errorf("members of a group with dynamic membership are determined by its MembershipRule, they cannot be added")
+		}
+	}
+	bodyBytes, err := json.Marshal(groupInput)
+	if err != nil {
+		return group, err
+	}
+	var properties map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(bodyBytes))
+	decoder.UseNumber() // keep numbers of extension properties exact
+	if err := decoder.Decode(&properties); err != nil {
+		return group, err
+	}
+	properties["mailEnabled"] = groupInput.MailEnabled
+	properties["securityEnabled"] = groupInput.SecurityEnabled
+	if len(ownerIDs) > 0 {
+		properties["owners@odata.bind"] = g.directoryObjectURLs(ownerIDs)
+	}
+	if len(memberIDs) > 0 {
+		properties["members@odata.bind"] = g.directoryObjectURLs(memberIDs)
+	}
+	bodyBytes, err = json.Marshal(properties)
+	if err != nil {
+		return group, err
+	}
+
+	reader := bytes.NewReader(bodyBytes)
+	err = g.makePOSTAPICall("/groups", compileCreateQueryOptions(opts), reader, &group)
+
+	return group, err
+}
+
 // UnmarshalJSON implements the json unmarshal to be used by the json-library.
 // This method additionally to loading the TenantID, ApplicationID and ClientSecret
 // immediately gets a Token from msgraph (hence initialize this GraphAPI instance)
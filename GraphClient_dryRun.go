@@ -0,0 +1,87 @@
+package msgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// PlannedOperation is a POST, PATCH or DELETE API-call that has not been sent to
+// Microsoft Graph because of dry-run mode, see WithDryRun and GraphClient.Plan.
+type PlannedOperation struct {
+	Method string          `json:"method"`         // HTTP method, e.g. PATCH
+	Path   string          `json:"path"`           // resource path without API version including query parameters, e.g. /users/{id}
+	Body   json.RawMessage `json:"body,omitempty"` // JSON body that would have been sent, nil if none
+}
+
+func (p PlannedOperation) String() string {
+	if len(p.Body) == 0 {
+		return fmt.Sprintf("%v %v", p.Method, p.Path)
+	}
+	return fmt.Sprintf("%v %v %v", p.Method, p.Path, string(p.Body))
+}
+
+// SetDryRun enables or disables dry-run mode of the GraphClient, see WithDryRun.
+// The plan is kept when disabling dry-run mode, use ResetPlan to clear it.
+func (g *GraphClient) SetDryRun(dryRun bool) {
+	g.apiCall.Lock()
+	defer g.apiCall.Unlock()
+	g.dryRun = dryRun
+}
+
+// DryRun returns true if dry-run mode is enabled for the GraphClient, see WithDryRun.
+func (g *GraphClient) DryRun() bool {
+	g.apiCall.Lock()
+	defer g.apiCall.Unlock()
+	return g.dryRun
+}
+
+// Plan returns a copy of all operations that have not been sent to Microsoft Graph
+// because of dry-run mode, in the order they were requested.
+func (g *GraphClient) Plan() []PlannedOperation {
+	g.apiCall.Lock()
+	defer g.apiCall.Unlock()
+	return append([]PlannedOperation(nil), g.plan...)
+}
+
+// ResetPlan removes all planned operations from the GraphClient.
+func (g *GraphClient) ResetPlan() {
+	g.apiCall.Lock()
+	defer g.apiCall.Unlock()
+	g.plan = nil
+}
+
+// isDryRun returns true if the API-call must not be sent but planned, either because the
+// GraphClient or the request is in dry-run mode.
+func (g *GraphClient) isDryRun(reqParams getRequestParams) bool {
+	g.apiCall.Lock()
+	defer g.apiCall.Unlock()
+	return g.dryRun || reqParams.DryRun()
+}
+
+// planAPICall adds the API-call to the plan instead of sending it.
+func (g *GraphClient) planAPICall(apiCall string, httpMethod string, reqParams getRequestParams, body io.Reader) error {
+	operation := PlannedOperation{Method: httpMethod, Path: apiCall}
+	if query := reqParams.Values().Encode(); query != "" {
+		operation.Path += "?" + query
+	}
+	// binary bodies, e.g. of User.SetPhoto, are not part of the plan as they are no JSON
+	if _, binary := body.(*binaryBody); body != nil && !binary {
+		bodyBytes, err := ioutil.ReadAll(body)
+		if err != nil {
+			return fmt.Errorf("cannot read body of planned %v %v: %v", httpMethod, apiCall, err)
+		}
+		if len(bodyBytes) > 0 {
+			operation.Body = json.RawMessage(bodyBytes)
+		}
+	}
+	if err := reqParams.Context().Err(); err != nil {
+		return err
+	}
+
+	g.apiCall.Lock()
+	defer g.apiCall.Unlock()
+	g.plan = append(g.plan, operation)
+	return nil
+}
@@ -0,0 +1,123 @@
+package msgraph
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestGraphClient_DryRun(t *testing.T) {
+	g, err := NewGraphClientWithCustomEndpoint(msGraphTenantID, msGraphApplicationID, msGraphClientSecret, msGraphAzureADAuthEndpoint, msGraphServiceRootEndpoint, WithDryRun())
+	if err != nil {
+		t.Fatalf("Cannot initialize a new GraphClient with dry-run mode: %v", err)
+	}
+	if !g.DryRun() {
+		t.Errorf("GraphClient.DryRun() = false, want true")
+	}
+
+	// GETs and read-only POSTs are still sent
+	user, err := g.GetUser(msGraphExistingUserPrincipalInGroup)
+	if err != nil {
+		t.Fatalf("GraphClient.GetUser() error = %v", err)
+	}
+	if _, err := user.GetMemberGroupsAsStrings(false); err != nil {
+		t.Errorf("User.GetMemberGroupsAsStrings() error = %v", err)
+	}
+
+	rndstring := randomString(32)
+	newUser := User{
+		AccountEnabled:    true,
+		DisplayName:       "go-msgraph unit-test dry-run user " + rndstring,
+		MailNickname:      "go-msgraph.unit-test.dry-run." + rndstring,
+		UserPrincipalName: "go-msgraph.unit-test.dry-run." + rndstring + "@" + msGraphDomainNameForCreateTests,
+		PasswordProfile:   PasswordProfile{Password: randomString(32)},
+	}
+	if _, err := g.CreateUser(newUser); err != nil {
+		t.Errorf("GraphClient.CreateUser() error = %v", err)
+	}
+	if err := user.UpdateUser(User{JobTitle: "dry-run " + rndstring}); err != nil {
+		t.Errorf("User.UpdateUser() error = %v", err)
+	}
+	if err := user.DisableAccount(); err != nil {
+		t.Errorf("User.DisableAccount() error = %v", err)
+	}
+	if err := user.DeleteUser(); err != nil {
+		t.Errorf("User.DeleteUser() error = %v", err)
+	}
+
+	want := []struct {
+		method string
+		path   string
+	}{
+		{method: http.MethodPost, path: "/users"},
+		{method: http.MethodPatch, path: "/users/" + user.ID},
+		{method: http.MethodPatch, path: "/users/" + user.ID},
+		{method: http.MethodDelete, path: "/users/" + user.ID},
+	}
+	plan := g.Plan()
+	if len(plan) != len(want) {
+		t.Fatalf("GraphClient.Plan() = %v, want %v operations", plan, len(want))
+	}
+	for i := range want {
+		if plan[i].Method != want[i].method || plan[i].Path != want[i].path {
+			t.Errorf("GraphClient.Plan()[%v] = %v, want %v %v", i, plan[i], want[i].method, want[i].path)
+		}
+	}
+	var planned User
+	if err := json.Unmarshal(plan[0].Body, &planned); err != nil || planned.UserPrincipalName != newUser.UserPrincipalName {
+		t.Errorf("GraphClient.Plan()[0].Body = %v, want the new user", string(plan[0].Body))
+	}
+	if string(plan[2].Body) != `{"accountEnabled":false}` {
+		t.Errorf("GraphClient.Plan()[2].Body = %v, want {\"accountEnabled\":false}", string(plan[2].Body))
+	}
+	if plan[3].Body != nil {
+		t.Errorf("GraphClient.Plan()[3].Body = %v, want nil", string(plan[3].Body))
+	}
+
+	// nothing has been changed
+	if _, err := graphClient.GetUser(newUser.UserPrincipalName); err == nil {
+		t.Errorf("GraphClient.CreateUser() created the user in dry-run mode")
+	}
+	got, err := graphClient.GetUser(user.ID)
+	if err != nil {
+		t.Fatalf("GraphClient.GetUser() error = %v, user was deleted in dry-run mode", err)
+	}
+	if !got.AccountEnabled || got.JobTitle == "dry-run "+rndstring {
+		t.Errorf("User was updated in dry-run mode: %v", got)
+	}
+
+	g.ResetPlan()
+	if plan := g.Plan(); len(plan) != 0 {
+		t.Errorf("GraphClient.Plan() after ResetPlan() = %v, want none", plan)
+	}
+	g.SetDryRun(false)
+	if g.DryRun() {
+		t.Errorf("GraphClient.DryRun() after SetDryRun(false) = true, want false")
+	}
+}
+
+func TestGraphClient_DryRunPerCall(t *testing.T) {
+	g, err := NewGraphClientWithCustomEndpoint(msGraphTenantID, msGraphApplicationID, msGraphClientSecret, msGraphAzureADAuthEndpoint, msGraphServiceRootEndpoint)
+	if err != nil {
+		t.Fatalf("Cannot initialize a new GraphClient: %v", err)
+	}
+	user, err := g.GetUser(msGraphExistingUserPrincipalInGroup)
+	if err != nil {
+		t.Fatalf("GraphClient.GetUser() error = %v", err)
+	}
+	if err := user.DisableAccount(UpdateWithDryRun()); err != nil {
+		t.Errorf("User.DisableAccount() error = %v", err)
+	}
+	if err := user.DeleteUser(DeleteWithDryRun()); err != nil {
+		t.Errorf("User.DeleteUser() error = %v", err)
+	}
+	if _, err := g.CreateUser(User{DisplayName: "dry-run"}, CreateWithDryRun()); err != nil {
+		t.Errorf("GraphClient.CreateUser() error = %v", err)
+	}
+	if plan := g.Plan(); len(plan) != 3 {
+		t.Errorf("GraphClient.Plan() = %v, want 3 operations", plan)
+	}
+	if got, err := g.GetUser(user.ID); err != nil || !got.AccountEnabled {
+		t.Errorf("User was changed with dry-run option: %v, error = %v", got, err)
+	}
+}
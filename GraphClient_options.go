@@ -0,0 +1,25 @@
+package msgraph
+
+import "net/http"
+
+// GraphClientOption configures optional settings of a GraphClient, see NewGraphClient and NewGraphClientWithCustomEndpoint
+type GraphClientOption func(g *GraphClient)
+
+var (
+	// WithHTTPClient - use the given http.Client to perform all requests of the GraphClient, e.g. to
+	// configure a proxy, a custom timeout or a recording http.RoundTripper like msgraphtest.Recorder
+	WithHTTPClient = func(httpClient *http.Client) GraphClientOption {
+		return func(g *GraphClient) {
+			g.httpClient = httpClient
+		}
+	}
+
+	// WithDryRun - do not send POST, PATCH and DELETE requests but add them to the plan of the
+	// GraphClient, see GraphClient.Plan. GET requests are still sent. Objects returned by
+	// planned operations, e.g. by CreateUser, are not populated
+	WithDryRun = func() GraphClientOption {
+		return func(g *GraphClient) {
+			g.dryRun = true
+		}
+	}
+)
@@ -10,6 +10,7 @@ type getRequestParams interface {
 	Context() context.Context
 	Values() url.Values
 	Headers() http.Header
+	DryRun() bool
 }
 
 type GetQueryOption func(opts *getQueryOptions)
@@ -37,6 +38,13 @@ var (
 		}
 	}
 
+	// GetWithSchemaExtensions - $select - Adds the schema extensions with the given IDs to the selected properties of a user or group, see User.GetSchemaExtension
+	GetWithSchemaExtensions = func(extensionIDs ...string) GetQueryOption {
+		return func(opts *getQueryOptions) {
+			opts.schemaExtensions = append(opts.schemaExtensions, extensionIDs...)
+		}
+	}
+
 	// ListWithContext - add a context.Context to the HTTP request e.g. to allow cancellation
 	ListWithContext = func(ctx context.Context) ListQueryOption {
 		return func(opts *listQueryOptions) {
@@ -58,6 +66,13 @@ var (
 		}
 	}
 
+	// ListWithSchemaExtensions - $select - Adds the schema extensions with the given IDs to the selected properties of users or groups, see User.GetSchemaExtension
+	ListWithSchemaExtensions = func(extensionIDs ...string) ListQueryOption {
+		return func(opts *listQueryOptions) {
+			opts.schemaExtensions = append(opts.schemaExtensions, extensionIDs...)
+		}
+	}
+
 	// ListWithSearch - $search - Returns results based on search criteria - https://docs.microsoft.com/en-us/graph/query-parameters#search-parameter
 	ListWithSearch = func(searchParam string) ListQueryOption {
 		return func(opts *listQueryOptions) {
@@ -73,25 +88,49 @@ var (
 		}
 	}
 
+	// CreateWithDryRun - do not send the request but add it to the plan of the GraphClient, see GraphClient.Plan
+	CreateWithDryRun = func() CreateQueryOption {
+		return func(opts *createQueryOptions) {
+			opts.dryRun = true
+		}
+	}
+
 	// UpdateWithContext - add a context.Context to the HTTP request e.g. to allow cancellation
 	UpdateWithContext = func(ctx context.Context) UpdateQueryOption {
 		return func(opts *updateQueryOptions) {
 			opts.ctx = ctx
 		}
 	}
+
+	// UpdateWithDryRun - do not send the request but add it to the plan of the GraphClient, see GraphClient.Plan
+	UpdateWithDryRun = func() UpdateQueryOption {
+		return func(opts *updateQueryOptions) {
+			opts.dryRun = true
+		}
+	}
+
 	// DeleteWithContext - add a context.Context to the HTTP request e.g. to allow cancellation
 	DeleteWithContext = func(ctx context.Context) DeleteQueryOption {
 		return func(opts *deleteQueryOptions) {
 			opts.ctx = ctx
 		}
 	}
+
+	// DeleteWithDryRun - do not send the request but add it to the plan of the GraphClient, see GraphClient.Plan
+	DeleteWithDryRun = func() DeleteQueryOption {
+		return func(opts *deleteQueryOptions) {
+			opts.dryRun = true
+		}
+	}
 )
 
 // getQueryOptions allow to optionally pass OData query options
 // see https://docs.microsoft.com/en-us/graph/query-parameters
 type getQueryOptions struct {
-	ctx         context.Context
-	queryValues url.Values
+	ctx              context.Context
+	queryValues      url.Values
+	dryRun           bool
+	schemaExtensions []string // IDs of schema extensions to add to $select, see selectSchemaExtensions
 }
 
 func (g *getQueryOptions) Context() context.Context {
@@ -109,6 +148,10 @@ func (g getQueryOptions) Headers() http.Header {
 	return http.Header{}
 }
 
+func (g getQueryOptions) DryRun() bool {
+	return g.dryRun
+}
+
 func compileGetQueryOptions(options []GetQueryOption) *getQueryOptions {
 	var opts = &getQueryOptions{
 		queryValues: url.Values{},
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/rand"
+	"net/http"
 	"net/url"
 	"os"
 	"reflect"
@@ -12,6 +13,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/open-networks/go-msgraph/msgraphtest"
 )
 
 // get graph client config from environment
@@ -40,6 +43,10 @@ var (
 	graphClient *GraphClient
 	// marker if the calendar tests should be skipped - set if msGraphExistingCalendarsOfUser is empty
 	skipCalendarTests bool
+	// Optional: a public https URL that answers the validation of change notification subscriptions, e.g. https://webhook.contoso.com/notifications
+	msGraphNotificationURL string
+	// the fake Microsoft Graph API used if the tests run offline, nil otherwise
+	offlineServer *msgraphtest.Server
 )
 
 func getEnvOrPanic(key string) string {
@@ -51,6 +58,11 @@ func getEnvOrPanic(key string) string {
 }
 
 func TestMain(m *testing.M) {
+	if os.Getenv("MSGraphTenantID") == "" {
+		fmt.Println("Running tests offline against msgraphtest.Server due to missing 'MSGraphTenantID' value")
+		offlineServer = newOfflineTestServer()
+	}
+
 	msGraphTenantID = getEnvOrPanic("MSGraphTenantID")
 	msGraphApplicationID = getEnvOrPanic("MSGraphApplicationID")
 	msGraphClientSecret = getEnvOrPanic("MSGraphClientSecret")
@@ -69,6 +81,8 @@ func TestMain(m *testing.M) {
 		skipCalendarTests = true
 	}
 
+	msGraphNotificationURL = os.Getenv("MSGraphNotificationURL")
+
 	var err error
 	msGraphExistingGroupDisplayNameNumRes, err = strconv.ParseUint(os.Getenv("MSGraphExistingGroupDisplayNameNumRes"), 10, 64)
 	if err != nil {
@@ -82,7 +96,98 @@ func TestMain(m *testing.M) {
 
 	rand.Seed(time.Now().UnixNano())
 
-	os.Exit(m.Run())
+	code := m.Run()
+	if offlineServer != nil {
+		offlineServer.Close()
+	}
+	os.Exit(code)
+}
+
+// newOfflineTestServer starts a msgraphtest.Server with fixtures for all tests and sets the
+// environment variables read by TestMain accordingly.
+func newOfflineTestServer() *msgraphtest.Server {
+	srv := msgraphtest.NewServer()
+
+	alice := srv.AddUser(User{
+		AccountEnabled:    true,
+		DisplayName:       "Alice Smith",
+		GivenName:         "Alice",
+		Surname:           "Smith",
+		Mail:              "alice@contoso.com",
+		MobilePhone:       "+1 23456789",
+		UserPrincipalName: "alice@contoso.com",
+	})
+	srv.AddUser(User{
+		AccountEnabled:    true,
+		DisplayName:       "Bob Rabbit",
+		GivenName:         "Bob",
+		Surname:           "Rabbit",
+		Mail:              "bob@contoso.com",
+		BusinessPhones:    []string{"+1 98765432"},
+		UserPrincipalName: "bob@contoso.com",
+	})
+	technicians := srv.AddGroup(map[string]interface{}{
+		"displayName":     "technicians",
+		"mailEnabled":     false,
+		"mailNickname":    "technicians",
+		"securityEnabled": true,
+	})
+	staff := srv.AddGroup(map[string]interface{}{
+		"displayName":     "technicians-and-staff",
+		"mailEnabled":     false,
+		"mailNickname":    "technicians-and-staff",
+		"securityEnabled": true,
+	})
+	srv.AddGroupMember(technicians.ID(), alice.ID())
+	srv.AddGroupMember(staff.ID(), technicians.ID())
+
+	srv.AddSubscribedSku(SubscribedSku{
+		SkuID:            "c42b9cae-ea4f-4ab7-9717-81576235ccac",
+		SkuPartNumber:    "DEVELOPERPACK_E5",
+		AppliesTo:        "User",
+		CapabilityStatus: "Enabled",
+		PrepaidUnits:     LicenseUnitsDetail{Enabled: 25},
+		ServicePlans: []ServicePlanInfo{
+			{ServicePlanID: "efb87545-963c-4e0d-99df-69c6916d9eb0", ServicePlanName: "EXCHANGE_S_ENTERPRISE", AppliesTo: "User"},
+			{ServicePlanID: "57ff2da0-773e-42df-b2af-ffb7a2317929", ServicePlanName: "TEAMS1", AppliesTo: "User"},
+		},
+	})
+	srv.AddSubscribedSku(SubscribedSku{
+		SkuID:            "a403ebcc-fae0-4ca2-8c8c-7a907fd6c235",
+		SkuPartNumber:    "POWER_BI_STANDARD",
+		AppliesTo:        "User",
+		CapabilityStatus: "Warning",
+		ConsumedUnits:    12,
+		PrepaidUnits:     LicenseUnitsDetail{Enabled: 10, Warning: 2},
+	})
+
+	srv.AddCalendar(alice.ID(), map[string]interface{}{"name": "Calendar", "canEdit": true, "canShare": true})
+	srv.AddCalendar(alice.ID(), map[string]interface{}{"name": "Birthdays"})
+	start := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Hour)
+	srv.AddEvent(alice.ID(), map[string]interface{}{
+		"createdDateTime":       start.Add(-48 * time.Hour).Format(time.RFC3339Nano),
+		"lastModifiedDateTime":  start.Add(-48 * time.Hour).Format(time.RFC3339Nano),
+		"originalStartTimeZone": "UTC",
+		"originalEndTimeZone":   "UTC",
+		"subject":               "go-msgraph offline test event",
+		"isOrganizer":           true,
+		"responseStatus":        map[string]string{"response": "organizer", "time": "0001-01-01T00:00:00Z"},
+		"start":                 map[string]string{"dateTime": start.Format("2006-01-02T15:04:05.0000000"), "timeZone": "UTC"},
+		"end":                   map[string]string{"dateTime": start.Add(time.Hour).Format("2006-01-02T15:04:05.0000000"), "timeZone": "UTC"},
+		"organizer":             map[string]interface{}{"emailAddress": map[string]string{"name": "Alice Smith", "address": "alice@contoso.com"}},
+	})
+
+	os.Setenv("MSGraphTenantID", srv.TenantID)
+	os.Setenv("MSGraphApplicationID", srv.ApplicationID)
+	os.Setenv("MSGraphClientSecret", srv.ClientSecret)
+	os.Setenv("MSGraphAzureADAuthEndpoint", srv.URL)
+	os.Setenv("MSGraphServiceRootEndpoint", srv.URL)
+	os.Setenv("MSGraphExistingGroupDisplayName", "technicians")
+	os.Setenv("MSGraphExistingGroupDisplayNameNumRes", "1")
+	os.Setenv("MSGraphExistingUserPrincipalInGroup", "alice@contoso.com")
+	os.Setenv("MSGraphExistingCalendarsOfUser", "Calendar,Birthdays")
+	os.Setenv("MSGraphDomainNameForCreateTests", "contoso.com")
+	return srv
 }
 
 func randomString(n int) string {
@@ -502,7 +607,11 @@ func isZeroValue(v interface{}, prop string, m map[string]interface{}) bool {
 		return zeroable.IsZero()
 	}
 
-	return reflect.ValueOf(m[prop]).IsZero()
+	// properties omitted by a custom MarshalJSON, e.g. of the Group, are not in the json
+	if value, ok := m[prop]; ok {
+		return reflect.ValueOf(value).IsZero()
+	}
+	return underlying.FieldByName(prop).IsZero()
 }
 
 func TestGraphClient_GetUser(t *testing.T) {
@@ -930,3 +1039,14 @@ func TestGraphClient_String(t *testing.T) {
 		t.Errorf("GraphClient.String(): String function failed")
 	}
 }
+
+func TestGraphClient_getStreamHTTPClient(t *testing.T) {
+	// streams are not limited by the timeout of the default http.Client, only the response headers are
+	streamClient := (&GraphClient{}).getStreamHTTPClient()
+	if transport, ok := streamClient.Transport.(*http.Transport); streamClient.Timeout != 0 || !ok || transport.ResponseHeaderTimeout != 10*time.Second {
+		t.Errorf("GraphClient.getStreamHTTPClient() = %v, want no timeout and a response header timeout of 10s", streamClient)
+	}
+	if httpClient := (&http.Client{}); (&GraphClient{httpClient: httpClient}).getStreamHTTPClient() != httpClient {
+		t.Errorf("GraphClient.getStreamHTTPClient() did not return the http.Client configured via WithHTTPClient")
+	}
+}
@@ -1,11 +1,35 @@
 package msgraph
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"time"
 )
 
+// GroupTypes of a Group.
+const (
+	GroupTypeUnified           = "Unified"           // Microsoft 365 group with a shared mailbox, calendar and files
+	GroupTypeDynamicMembership = "DynamicMembership" // members are determined by a membership rule
+)
+
+// MembershipRuleProcessingStates of a Group with dynamic membership.
+const (
+	MembershipRuleProcessingStateOn     = "On"     // the membership rule is evaluated and the members are updated
+	MembershipRuleProcessingStatePaused = "Paused" // the members are kept as they are
+)
+
+// Visibility of a Microsoft 365 Group.
+const (
+	GroupVisibilityPublic           = "Public"
+	GroupVisibilityPrivate          = "Private"
+	GroupVisibilityHiddenMembership = "HiddenMembership" // can only be set on creation
+)
+
+// maxGroupBindsOnCreate is the maximum number of owners and members that can be added by
+// GraphClient.CreateGroup.
+const maxGroupBindsOnCreate = 20
+
 // Group represents one group of ms graph
 //
 // See: https://developer.microsoft.com/en-us/graph/docs/api-reference/v1.0/api/group_get
@@ -14,6 +38,7 @@ type Group struct {
 	Description                  string
 	DisplayName                  string
 	CreatedDateTime              time.Time
+	DeletedDateTime              time.Time // only set for deleted groups, see GraphClient.ListDeletedGroups
 	GroupTypes                   []string
 	Mail                         string
 	MailEnabled                  bool
@@ -25,6 +50,16 @@ type Group struct {
 	SecurityEnabled              bool
 	Visibility                   string
 
+	// MembershipRule determines the members of a group with GroupTypeDynamicMembership, e.g.
+	// user.department -eq "Sales", see ParseMembershipRule.
+	MembershipRule                string
+	MembershipRuleProcessingState string // one of the MembershipRuleProcessingState* constants
+
+	// ExtensionProperties contains the schema and directory extension properties of the group by
+	// their name, e.g. extkvbmkofy_costCenter. Only selected extensions are returned, see
+	// GetWithSchemaExtensions and Group.GetSchemaExtension.
+	ExtensionProperties map[string]json.RawMessage
+
 	graphClient *GraphClient // the graphClient that called the group
 }
 
@@ -40,7 +75,7 @@ func (g *Group) setGraphClient(gC *GraphClient) {
 
 // ListMembers - Get a list of the group's direct members. A group can have users,
 // contacts, and other groups as members. This operation is not transitive. This
-// method will currently ONLY return User-instances of members
+// method ONLY returns the users, see Group.ListMemberObjects for all members.
 // Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
 //
 // See https://developer.microsoft.com/en-us/graph/docs/api-reference/v1.0/api/group_list_members
@@ -48,18 +83,19 @@ func (g Group) ListMembers(opts ...ListQueryOption) (Users, error) {
 	if g.graphClient == nil {
 		return nil, ErrNotGraphClientSourced
 	}
-	resource := fmt.Sprintf("/groups/%v/members", g.ID)
+	resource := fmt.Sprintf("/groups/%v/members/%v", g.ID, castSegment(ODataTypeUser))
 
 	var marsh struct {
 		Users Users `json:"value"`
 	}
+	err := g.graphClient.makeGETAPICall(resource, compileListQueryOptions(opts), &marsh)
 	marsh.Users.setGraphClient(g.graphClient)
-	return marsh.Users, g.graphClient.makeGETAPICall(resource, compileListQueryOptions(opts), &marsh)
+	return marsh.Users, err
 }
 
 // Get a list of the group's members. A group can have users, devices, organizational contacts, and other groups as members.
 // This operation is transitive and returns a flat list of all nested members.
-// This method will currently ONLY return User-instances of members
+// This method ONLY returns the users, see Group.ListTransitiveMemberObjects for all members.
 // Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
 //
 // See https://docs.microsoft.com/en-us/graph/api/group-list-transitivemembers?view=graph-rest-1.0&tabs=http
@@ -67,13 +103,52 @@ func (g Group) ListTransitiveMembers(opts ...ListQueryOption) (Users, error) {
 	if g.graphClient == nil {
 		return nil, ErrNotGraphClientSourced
 	}
-	resource := fmt.Sprintf("/groups/%v/transitiveMembers", g.ID)
+	resource := fmt.Sprintf("/groups/%v/transitiveMembers/%v", g.ID, castSegment(ODataTypeUser))
 
 	var marsh struct {
 		Users Users `json:"value"`
 	}
+	err := g.graphClient.makeGETAPICall(resource, compileTransitiveCastListQueryOptions(opts), &marsh)
 	marsh.Users.setGraphClient(g.graphClient)
-	return marsh.Users, g.graphClient.makeGETAPICall(resource, compileListQueryOptions(opts), &marsh)
+	return marsh.Users, err
+}
+
+// ListMemberObjects returns all direct members of the group, hence users, groups, devices,
+// service principals and org contacts, see DirectoryObject.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/group-list-members
+func (g Group) ListMemberObjects(opts ...ListQueryOption) (DirectoryObjects, error) {
+	if g.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	return g.graphClient.listDirectoryObjects(fmt.Sprintf("/groups/%v/members", g.ID), opts)
+}
+
+// ListTransitiveMemberObjects returns all direct and nested members of the group, including
+// the nested groups themselves, see DirectoryObject.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/group-list-transitivemembers
+func (g Group) ListTransitiveMemberObjects(opts ...ListQueryOption) (DirectoryObjects, error) {
+	if g.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	return g.graphClient.listDirectoryObjects(fmt.Sprintf("/groups/%v/transitiveMembers", g.ID), opts)
+}
+
+// ListMembersOfType returns the direct members of the group of the given @odata.type, one of
+// the ODataType* constants, e.g. ODataTypeGroup for the nested groups. The members are
+// filtered by Microsoft Graph with a type cast, e.g. /members/microsoft.graph.group, and
+// decoded as the given type.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/group-list-members
+func (g Group) ListMembersOfType(odataType string, opts ...ListQueryOption) (DirectoryObjects, error) {
+	if g.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	return g.graphClient.listDirectoryObjectsOfType(fmt.Sprintf("/groups/%v/members", g.ID), odataType, opts)
 }
 
 // GetMemberGroupsAsStrings returns a list of all group IDs the user is a member of.
@@ -88,23 +163,142 @@ func (g Group) GetMemberGroupsAsStrings(opts ...GetQueryOption) ([]string, error
 	return g.graphClient.getMemberGroups(g.ID, false, opts...) // securityEnabledOnly is not supported for Groups, see documentation / API-reference
 }
 
+// removeGroupMember removes the directory object with the given ID from the direct members of the group.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/group-delete-members
+func (g *GraphClient) removeGroupMember(groupID, memberID string, opts ...DeleteQueryOption) error {
+	resource := fmt.Sprintf("/groups/%v/members/%v/$ref", groupID, memberID)
+	return g.makeDELETEAPICall(resource, compileDeleteQueryOptions(opts), nil)
+}
+
+// UpdateGroup patches this group object. Note, only set the fields that should be changed.
+//
+// IMPORTANT: like User.UpdateUser, false booleans and empty strings are not sent, hence e.g.
+// the Description cannot be cleared this way.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/group-update
+func (g Group) UpdateGroup(groupInput Group, opts ...UpdateQueryOption) error {
+	if g.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/groups/%v", g.ID)
+
+	bodyBytes, err := json.Marshal(groupInput)
+	if err != nil {
+		return err
+	}
+
+	reader := bytes.NewReader(bodyBytes)
+	// Hint: API-call body does not return any data / no json object.
+	return g.graphClient.makePATCHAPICall(resource, compileUpdateQueryOptions(opts), reader, nil)
+}
+
+// DeleteGroup deletes this group. Microsoft 365 groups can be restored within 30 days, see
+// GraphClient.ListDeletedGroups, security groups are deleted permanently right away.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/group-delete
+func (g Group) DeleteGroup(opts ...DeleteQueryOption) error {
+	if g.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/groups/%v", g.ID)
+
+	return g.graphClient.makeDELETEAPICall(resource, compileDeleteQueryOptions(opts), nil)
+}
+
+// groupDefaultSelect are the properties of the Group that are unmarshalled, see Group.UnmarshalJSON.
+const groupDefaultSelect = "id,description,displayName,createdDateTime,groupTypes,mail,mailEnabled,mailNickname," +
+	"onPremisesLastSyncDateTime,onPremisesSecurityIdentifier,onPremisesSyncEnabled,proxyAddresses,securityEnabled,visibility," +
+	"membershipRule,membershipRuleProcessingState"
+
+// MarshalJSON implements the json marshal to be used by the json-library, it is symmetric to
+// Group.UnmarshalJSON. Like for the User, unset properties, false booleans and unset
+// time.Time properties are omitted, hence read-only properties are only sent if set.
+// Schema and directory extension properties are merged in.
+func (g Group) MarshalJSON() ([]byte, error) {
+	tmp := struct {
+		ID                            string     `json:"id,omitempty"`
+		Description                   string     `json:"description,omitempty"`
+		DisplayName                   string     `json:"displayName,omitempty"`
+		CreatedDateTime               *time.Time `json:"createdDateTime,omitempty"`
+		DeletedDateTime               *time.Time `json:"deletedDateTime,omitempty"`
+		GroupTypes                    []string   `json:"groupTypes,omitempty"`
+		Mail                          string     `json:"mail,omitempty"`
+		MailEnabled                   bool       `json:"mailEnabled,omitempty"`
+		MailNickname                  string     `json:"mailNickname,omitempty"`
+		OnPremisesLastSyncDateTime    *time.Time `json:"onPremisesLastSyncDateTime,omitempty"`
+		OnPremisesSecurityIdentifier  string     `json:"onPremisesSecurityIdentifier,omitempty"`
+		OnPremisesSyncEnabled         bool       `json:"onPremisesSyncEnabled,omitempty"`
+		ProxyAddresses                []string   `json:"proxyAddresses,omitempty"`
+		SecurityEnabled               bool       `json:"securityEnabled,omitempty"`
+		Visibility                    string     `json:"visibility,omitempty"`
+		MembershipRule                string     `json:"membershipRule,omitempty"`
+		MembershipRuleProcessingState string     `json:"membershipRuleProcessingState,omitempty"`
+	}{
+		ID:                            g.ID,
+		Description:                   g.Description,
+		DisplayName:                   g.DisplayName,
+		CreatedDateTime:               optionalTime(g.CreatedDateTime),
+		DeletedDateTime:               optionalTime(g.DeletedDateTime),
+		GroupTypes:                    g.GroupTypes,
+		Mail:                          g.Mail,
+		MailEnabled:                   g.MailEnabled,
+		MailNickname:                  g.MailNickname,
+		OnPremisesLastSyncDateTime:    optionalTime(g.OnPremisesLastSyncDateTime),
+		OnPremisesSecurityIdentifier:  g.OnPremisesSecurityIdentifier,
+		OnPremisesSyncEnabled:         g.OnPremisesSyncEnabled,
+		ProxyAddresses:                g.ProxyAddresses,
+		SecurityEnabled:               g.SecurityEnabled,
+		Visibility:                    g.Visibility,
+		MembershipRule:                g.MembershipRule,
+		MembershipRuleProcessingState: g.MembershipRuleProcessingState,
+	}
+	if len(g.ExtensionProperties) == 0 {
+		return json.Marshal(tmp)
+	}
+
+	data, err := json.Marshal(tmp)
+	if err != nil {
+		return nil, err
+	}
+	var properties map[string]json.RawMessage
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return nil, err
+	}
+	for name, value := range g.ExtensionProperties {
+		properties[name] = value
+	}
+	return json.Marshal(properties)
+}
+
+// optionalTime returns nil for the zero time, e.g. to omit unset time.Time properties.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
 // UnmarshalJSON implements the json unmarshal to be used by the json-library
 func (g *Group) UnmarshalJSON(data []byte) error {
 	tmp := struct {
-		ID                           string   `json:"id"`
-		Description                  string   `json:"description"`
-		DisplayName                  string   `json:"displayName"`
-		CreatedDateTime              string   `json:"createdDateTime"`
-		GroupTypes                   []string `json:"groupTypes"`
-		Mail                         string   `json:"mail"`
-		MailEnabled                  bool     `json:"mailEnabled"`
-		MailNickname                 string   `json:"mailNickname"`
-		OnPremisesLastSyncDateTime   string   `json:"onPremisesLastSyncDateTime"`
-		OnPremisesSecurityIdentifier string   `json:"onPremisesSecurityIdentifier"`
-		OnPremisesSyncEnabled        bool     `json:"onPremisesSyncEnabled"`
-		ProxyAddresses               []string `json:"proxyAddresses"`
-		SecurityEnabled              bool     `json:"securityEnabled"`
-		Visibility                   string   `json:"visibility"`
+		ID                            string   `json:"id"`
+		Description                   string   `json:"description"`
+		DisplayName                   string   `json:"displayName"`
+		CreatedDateTime               string   `json:"createdDateTime"`
+		DeletedDateTime               string   `json:"deletedDateTime"`
+		GroupTypes                    []string `json:"groupTypes"`
+		Mail                          string   `json:"mail"`
+		MailEnabled                   bool     `json:"mailEnabled"`
+		MailNickname                  string   `json:"mailNickname"`
+		OnPremisesLastSyncDateTime    string   `json:"onPremisesLastSyncDateTime"`
+		OnPremisesSecurityIdentifier  string   `json:"onPremisesSecurityIdentifier"`
+		OnPremisesSyncEnabled         bool     `json:"onPremisesSyncEnabled"`
+		ProxyAddresses                []string `json:"proxyAddresses"`
+		SecurityEnabled               bool     `json:"securityEnabled"`
+		Visibility                    string   `json:"visibility"`
+		MembershipRule                string   `json:"membershipRule"`
+		MembershipRuleProcessingState string   `json:"membershipRuleProcessingState"`
 	}{}
 
 	err := json.Unmarshal(data, &tmp)
@@ -119,6 +313,10 @@ func (g *Group) UnmarshalJSON(data []byte) error {
 	if err != nil && tmp.CreatedDateTime != "" {
 		return fmt.Errorf("cannot parse CreatedDateTime %v with RFC3339: %v", tmp.CreatedDateTime, err)
 	}
+	g.DeletedDateTime, err = time.Parse(time.RFC3339, tmp.DeletedDateTime)
+	if err != nil && tmp.DeletedDateTime != "" {
+		return fmt.Errorf("cannot parse DeletedDateTime %v with RFC3339: %v", tmp.DeletedDateTime, err)
+	}
 	g.GroupTypes = tmp.GroupTypes
 	g.Mail = tmp.Mail
 	g.MailEnabled = tmp.MailEnabled
@@ -132,6 +330,9 @@ func (g *Group) UnmarshalJSON(data []byte) error {
 	g.ProxyAddresses = tmp.ProxyAddresses
 	g.SecurityEnabled = tmp.SecurityEnabled
 	g.Visibility = tmp.Visibility
+	g.MembershipRule = tmp.MembershipRule
+	g.MembershipRuleProcessingState = tmp.MembershipRuleProcessingState
+	g.ExtensionProperties, err = extensionProperties(data)
 
-	return nil
+	return err
 }
@@ -0,0 +1,156 @@
+package msgraph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// GroupNestingGraph is the nesting of groups below a set of root groups, see
+// GraphClient.GetGroupNestingGraph. Members of a group inherit the access of all groups it is
+// nested in, hence the graph shows where access is inherited from. The graph can be marshalled
+// with encoding/json or rendered with Graphviz, see GroupNestingGraph.DOT.
+type GroupNestingGraph struct {
+	Groups []GroupNestingNode `json:"groups"`           // all groups of the graph, starting with the root groups
+	Edges  []GroupNestingEdge `json:"edges"`            // all nestings of a group in another group of the graph
+	Cycles [][]string         `json:"cycles,omitempty"` // IDs of groups that are nested in each other, e.g. [A B] if B is a member of A and A a member of B
+}
+
+// GroupNestingNode is a group of a GroupNestingGraph.
+type GroupNestingNode struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	Root        bool   `json:"root,omitempty"` // true if the group is one of the groups the graph has been built for
+}
+
+// GroupNestingEdge is a group that is a direct member of another group.
+type GroupNestingEdge struct {
+	GroupID  string `json:"groupId"`  // ID of the group that contains the member
+	MemberID string `json:"memberId"` // ID of the group that is a member, hence inherits the access of the group
+}
+
+func (n GroupNestingGraph) String() string {
+	return fmt.Sprintf("GroupNestingGraph(Groups: %v, Edges: %v, Cycles: %v)", len(n.Groups), len(n.Edges), n.Cycles)
+}
+
+// GetGroupNestingGraph returns the graph of all groups nested directly or transitively in the
+// groups with the given IDs. Every group is listed once, even if the nesting contains cycles,
+// see GroupNestingGraph.Cycles. Members that are not groups, e.g. users, are not part of the
+// graph. One API-call is made per group of the graph.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/group-list-members
+func (g *GraphClient) GetGroupNestingGraph(ctx context.Context, groupIDs []string) (GroupNestingGraph, error) {
+	var graph GroupNestingGraph
+	var queue []string
+	visited := make(map[string]bool)
+	for _, groupID := range groupIDs {
+		group, err := g.GetGroup(groupID, GetWithContext(ctx), GetWithSelect("id,displayName"))
+		if err != nil {
+			return GroupNestingGraph{}, err
+		}
+		if !visited[group.ID] {
+			visited[group.ID] = true
+			graph.Groups = append(graph.Groups, GroupNestingNode{ID: group.ID, DisplayName: group.DisplayName, Root: true})
+			queue = append(queue, group.ID)
+		}
+	}
+
+	for len(queue) > 0 {
+		group := Group{ID: queue[0], graphClient: g}
+		queue = queue[1:]
+		members, err := group.ListMembersOfType(ODataTypeGroup, ListWithContext(ctx), ListWithSelect("id,displayName"))
+		if err != nil {
+			return GroupNestingGraph{}, err
+		}
+		for _, member := range members {
+			graph.Edges = append(graph.Edges, GroupNestingEdge{GroupID: group.ID, MemberID: member.ID})
+			if !visited[member.ID] {
+				visited[member.ID] = true
+				graph.Groups = append(graph.Groups, GroupNestingNode{ID: member.ID, DisplayName: member.DisplayName})
+				queue = append(queue, member.ID)
+			}
+		}
+	}
+	graph.Cycles = graph.findCycles()
+	return graph, nil
+}
+
+// findCycles returns the groups of every cycle found with a depth-first search starting at
+// each group of the graph, in the order of the nesting.
+func (n GroupNestingGraph) findCycles() [][]string {
+	members := make(map[string][]string)
+	for _, edge := range n.Edges {
+		members[edge.GroupID] = append(members[edge.GroupID], edge.MemberID)
+	}
+	const (
+		unvisited = iota
+		onPath
+		done
+	)
+	state := make(map[string]int)
+	var path []string
+	var cycles [][]string
+	var visit func(groupID string)
+	visit = func(groupID string) {
+		state[groupID] = onPath
+		path = append(path, groupID)
+		for _, memberID := range members[groupID] {
+			switch state[memberID] {
+			case unvisited:
+				visit(memberID)
+			case onPath:
+				// the member contains the group, the cycle is the path from the member to the group
+				for i := len(path) - 1; i >= 0; i-- {
+					if path[i] == memberID {
+						cycles = append(cycles, append([]string(nil), path[i:]...))
+						break
+					}
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[groupID] = done
+	}
+	for _, group := range n.Groups {
+		if state[group.ID] == unvisited {
+			visit(group.ID)
+		}
+	}
+	return cycles
+}
+
+// DOT returns the graph in the DOT language of Graphviz, e.g. to render it with
+// `dot -Tsvg`. Every group points to its nested groups, root groups are drawn bold and
+// nestings that are part of a cycle red.
+func (n GroupNestingGraph) DOT() string {
+	inCycle := make(map[GroupNestingEdge]bool)
+	for _, cycle := range n.Cycles {
+		for i := range cycle {
+			inCycle[GroupNestingEdge{GroupID: cycle[i], MemberID: cycle[(i+1)%len(cycle)]}] = true
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph groups {\n")
+	for _, group := range n.Groups {
+		attributes := "label=" + dotQuote(group.DisplayName)
+		if group.Root {
+			attributes += ", style=bold"
+		}
+		fmt.Fprintf(&b, "\t%v [%v];\n", dotQuote(group.ID), attributes)
+	}
+	for _, edge := range n.Edges {
+		fmt.Fprintf(&b, "\t%v -> %v", dotQuote(edge.GroupID), dotQuote(edge.MemberID))
+		if inCycle[edge] {
+			b.WriteString(" [color=red]")
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotQuote returns the string as quoted ID of the DOT language.
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,102 @@
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestGraphClient_GetGroupNestingGraph(t *testing.T) {
+	user := createUnitTestUser(t)
+	defer user.DeleteUser()
+	// root contains a and b, both contain c
+	root, a, b, c := createUnitTestGroup(t), createUnitTestGroup(t), createUnitTestGroup(t), createUnitTestGroup(t)
+	for _, group := range []Group{root, a, b, c} {
+		defer group.DeleteGroup()
+	}
+	for _, nesting := range []struct{ group, member string }{{root.ID, a.ID}, {root.ID, b.ID}, {a.ID, c.ID}, {b.ID, c.ID}, {c.ID, user.ID}} {
+		if err := (Group{ID: nesting.group, graphClient: graphClient}).AddMember(nesting.member); err != nil {
+			t.Fatalf("Group.AddMember() error = %v", err)
+		}
+	}
+	wantCycles := 0
+	if offlineServer != nil {
+		// Azure AD may refuse cyclic nestings, hence they are only tested offline
+		if err := c.AddMember(a.ID); err != nil {
+			t.Fatalf("Group.AddMember() error = %v", err)
+		}
+		wantCycles = 1
+	}
+
+	graph, err := graphClient.GetGroupNestingGraph(context.Background(), []string{root.ID, root.ID})
+	if err != nil {
+		t.Fatalf("GraphClient.GetGroupNestingGraph() error = %v", err)
+	}
+	if len(graph.Groups) != 4 || !graph.Groups[0].Root || graph.Groups[0].ID != root.ID || graph.Groups[0].DisplayName != root.DisplayName || graph.Groups[1].Root {
+		t.Errorf("GraphClient.GetGroupNestingGraph().Groups = %v, want the root group and its 3 nested groups", graph.Groups)
+	}
+	if len(graph.Edges) != 4+wantCycles || len(graph.Cycles) != wantCycles {
+		t.Errorf("GraphClient.GetGroupNestingGraph() = %v, want %v edges and %v cycles", graph, 4+wantCycles, wantCycles)
+	}
+	if wantCycles > 0 && strings.Join(graph.Cycles[0], ",") != a.ID+","+c.ID {
+		t.Errorf("GraphClient.GetGroupNestingGraph().Cycles = %v, want [[%v %v]]", graph.Cycles, a.ID, c.ID)
+	}
+
+	if _, err := graphClient.GetGroupNestingGraph(context.Background(), []string{"00000000-0000-0000-0000-000000000000"}); err == nil {
+		t.Errorf("GraphClient.GetGroupNestingGraph() of an unknown group error = nil, want error")
+	}
+}
+
+func TestGroupNestingGraph_findCycles(t *testing.T) {
+	tests := []struct {
+		name  string
+		edges string // comma separated nestings group>member
+		want  string
+	}{
+		{name: "No edges", edges: "", want: "[]"},
+		{name: "Diamond", edges: "r>a,r>b,a>c,b>c", want: "[]"},
+		{name: "Two nested in each other", edges: "r>a,a>b,b>a", want: "[[a b]]"},
+		{name: "Long cycle", edges: "r>a,a>b,b>c,c>r", want: "[[r a b c]]"},
+		{name: "Two cycles", edges: "r>a,a>r,r>b,b>c,c>b", want: "[[r a] [b c]]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := GroupNestingGraph{Groups: []GroupNestingNode{{ID: "r", Root: true}}}
+			for _, edge := range strings.Split(tt.edges, ",") {
+				if edge != "" {
+					ids := strings.Split(edge, ">")
+					graph.Edges = append(graph.Edges, GroupNestingEdge{GroupID: ids[0], MemberID: ids[1]})
+				}
+			}
+			if got := fmt.Sprint(graph.findCycles()); got != tt.want {
+				t.Errorf("GroupNestingGraph.findCycles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupNestingGraph_export(t *testing.T) {
+	graph := GroupNestingGraph{
+		Groups: []GroupNestingNode{{ID: "1", DisplayName: `all "staff"`, Root: true}, {ID: "2", DisplayName: "sales"}},
+		Edges:  []GroupNestingEdge{{GroupID: "1", MemberID: "2"}, {GroupID: "2", MemberID: "1"}},
+		Cycles: [][]string{{"1", "2"}},
+	}
+	want := `digraph groups {
+	"1" [label="all \"staff\"", style=bold];
+	"2" [label="sales"];
+	"1" -> "2" [color=red];
+	"2" -> "1" [color=red];
+}
+`
+	if got := graph.DOT(); got != want {
+		t.Errorf("GroupNestingGraph.DOT() = %v, want %v", got, want)
+	}
+
+	got, err := json.Marshal(graph)
+	wantJSON := `{"groups":[{"id":"1","displayName":"all \"staff\"","root":true},{"id":"2","displayName":"sales"}],"edges":[{"groupId":"1","memberId":"2"},{"groupId":"2","memberId":"1"}],"cycles":[["1","2"]]}`
+	if err != nil || string(got) != wantJSON {
+		t.Errorf("json.Marshal(GroupNestingGraph) = %s, error = %v, want %v", got, err, wantJSON)
+	}
+}
@@ -0,0 +1,97 @@
+package msgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// ListConversations returns the conversations of the Microsoft 365 group. Microsoft Graph
+// responds with an error for groups that are not Microsoft 365 groups.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/group-list-conversations
+func (g Group) ListConversations(opts ...ListQueryOption) (Conversations, error) {
+	if g.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/groups/%v/conversations", g.ID)
+
+	var marsh struct {
+		Conversations Conversations `json:"value"`
+	}
+	err := g.graphClient.makeGETAPICall(resource, compileListQueryOptions(opts), &marsh)
+	marsh.Conversations.setGroup(g.graphClient, g.ID)
+	return marsh.Conversations, err
+}
+
+// GetConversation returns the conversation with the given ID of the Microsoft 365 group.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/group-get-conversation
+func (g Group) GetConversation(conversationID string, opts ...GetQueryOption) (Conversation, error) {
+	if g.graphClient == nil {
+		return Conversation{}, ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/groups/%v/conversations/%v", g.ID, conversationID)
+
+	var conversation Conversation
+	err := g.graphClient.makeGETAPICall(resource, compileGetQueryOptions(opts), &conversation)
+	conversation.setGroup(g.graphClient, g.ID)
+	return conversation, err
+}
+
+// CreateConversation starts a new conversation with the given topic in the Microsoft 365
+// group, its first thread contains the given post. Only Body, NewParticipants and Attachments
+// of the post are used. The post is sent to all members of the group that follow the group
+// in their inbox.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/group-post-conversations
+func (g Group) CreateConversation(topic string, post Post, opts ...CreateQueryOption) (Conversation, error) {
+	if g.graphClient == nil {
+		return Conversation{}, ErrNotGraphClientSourced
+	}
+	if topic == "" {
+		return Conversation{}, fmt.Errorf("the topic of the conversation is required")
+	}
+	resource := fmt.Sprintf("/groups/%v/conversations", g.ID)
+
+	bodyBytes, err := json.Marshal(map[string]interface{}{
+		"topic":   topic,
+		"threads": []interface{}{map[string]interface{}{"posts": []interface{}{post.requestBody()}}},
+	})
+	if err != nil {
+		return Conversation{}, err
+	}
+
+	reader := bytes.NewReader(bodyBytes)
+	var conversation Conversation
+	err = g.graphClient.makePOSTAPICall(resource, compileCreateQueryOptions(opts), reader, &conversation)
+	conversation.setGroup(g.graphClient, g.ID)
+	return conversation, err
+}
+
+// ListThreads returns the threads of all conversations of the Microsoft 365 group.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/group-list-threads
+func (g Group) ListThreads(opts ...ListQueryOption) (ConversationThreads, error) {
+	if g.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	return g.graphClient.listConversationThreads(g.ID, fmt.Sprintf("/groups/%v/threads", g.ID), opts)
+}
+
+// GetThread returns the thread with the given ID of the Microsoft 365 group.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/group-get-thread
+func (g Group) GetThread(threadID string, opts ...GetQueryOption) (ConversationThread, error) {
+	if g.graphClient == nil {
+		return ConversationThread{}, ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/groups/%v/threads/%v", g.ID, threadID)
+
+	var thread ConversationThread
+	err := g.graphClient.makeGETAPICall(resource, compileGetQueryOptions(opts), &thread)
+	thread.setGroup(g.graphClient, g.ID)
+	return thread, err
+}
@@ -0,0 +1,122 @@
+package msgraph
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGroup_CreateConversation(t *testing.T) {
+	if _, err := (Group{}).ListConversations(); err != ErrNotGraphClientSourced {
+		t.Errorf("Group.ListConversations() without GraphClient error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+	if err := (ConversationThread{}).Reply(Post{}); err != ErrNotGraphClientSourced {
+		t.Errorf("ConversationThread.Reply() without GraphClient error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+	if offlineServer == nil {
+		t.Skip("Microsoft 365 groups are only deleted permanently after 30 days, only tested offline")
+	}
+	group, err := graphClient.CreateGroup(Group{
+		DisplayName:  "go-msgraph unit-test conversations",
+		MailNickname: "go-msgraph.unit-test.conversations",
+		GroupTypes:   []string{GroupTypeUnified},
+		MailEnabled:  true,
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("GraphClient.CreateGroup() error = %v", err)
+	}
+	defer func() {
+		group.DeleteGroup()
+		graphClient.PermanentlyDeleteItem(group.ID)
+	}()
+	securityGroup := createUnitTestGroup(t)
+	defer securityGroup.DeleteGroup()
+	if _, err := securityGroup.ListConversations(); err == nil {
+		t.Errorf("Group.ListConversations() of a security group error = nil, want error")
+	}
+
+	offlineServer.ResetRequests()
+	if _, err := group.CreateConversation("", Post{Body: ItemBody{Content: "no topic"}}); err == nil || len(offlineServer.Requests()) != 0 {
+		t.Errorf("Group.CreateConversation() without topic error = %v, want error without API-call", err)
+	}
+	conversation, err := group.CreateConversation("Ticket 4711: printer", Post{
+		Body:        ItemBody{ContentType: BodyContentTypeHTML, Content: "<p>The printer is jammed.</p>"},
+		Attachments: Attachments{{Name: "log.txt", ContentType: "text/plain", ContentBytes: []byte("paper jam")}},
+	})
+	if err != nil || conversation.ID == "" || conversation.Topic != "Ticket 4711: printer" || !conversation.HasAttachments {
+		t.Fatalf("Group.CreateConversation() = %v, error = %v, want a conversation with attachments", conversation, err)
+	}
+	if _, err := group.CreateConversation("Ticket 4712: coffee machine", Post{Body: ItemBody{ContentType: BodyContentTypeText, Content: "Empty again."}}); err != nil {
+		t.Fatalf("Group.CreateConversation() error = %v", err)
+	}
+
+	conversations, err := group.ListConversations()
+	if err != nil || len(conversations) != 2 || len(conversations.GetByTopic("ticket 4711: PRINTER")) != 1 {
+		t.Errorf("Group.ListConversations() = %v, error = %v, want 2 conversations", conversations, err)
+	}
+	if got, err := group.GetConversation(conversation.ID); err != nil || got.Topic != conversation.Topic {
+		t.Errorf("Group.GetConversation() = %v, error = %v, want %v", got, err, conversation)
+	}
+	if threads, err := group.ListThreads(); err != nil || len(threads) != 2 {
+		t.Errorf("Group.ListThreads() = %v, error = %v, want 2 threads", threads, err)
+	}
+	threads, err := conversation.ListThreads()
+	if err != nil || len(threads) != 1 {
+		t.Fatalf("Conversation.ListThreads() = %v, error = %v, want 1 thread", threads, err)
+	}
+	thread, err := group.GetThread(threads[0].ID)
+	if err != nil || thread.Topic != conversation.Topic {
+		t.Fatalf("Group.GetThread() = %v, error = %v, want the thread of %v", thread, err, conversation)
+	}
+
+	posts, err := thread.ListPosts()
+	if err != nil || len(posts) != 1 {
+		t.Fatalf("ConversationThread.ListPosts() = %v, error = %v, want 1 post", posts, err)
+	}
+	if post := posts[0]; post.Body.Content != "<p>The printer is jammed.</p>" || !post.HasAttachments || post.ConversationID != conversation.ID || post.From.EmailAddress.Address != group.Mail {
+		t.Errorf("ConversationThread.ListPosts()[0] = %v, want the first post sent as the group", post)
+	}
+	attachments, err := posts[0].ListAttachments()
+	if err != nil || len(attachments) != 1 || string(attachments[0].ContentBytes) != "paper jam" || attachments[0].Size != 9 || attachments[0].ODataType != ODataTypeFileAttachment {
+		t.Errorf("Post.ListAttachments() = %v, error = %v, want log.txt", attachments, err)
+	}
+
+	offlineServer.ResetRequests()
+	if err := thread.Reply(Post{ID: "ignored", ReceivedDateTime: time.Now(), Body: ItemBody{ContentType: BodyContentTypeText, Content: "A technician is on the way."}}); err != nil {
+		t.Fatalf("ConversationThread.Reply() error = %v", err)
+	}
+	if requests := offlineServer.Requests(); len(requests) != 1 || string(requests[0].Body) != `{"post":{"body":{"contentType":"text","content":"A technician is on the way."}}}` {
+		t.Errorf("ConversationThread.Reply() sent %v, want only the body of the post", requests)
+	}
+	if err := posts[0].Reply(Post{Body: ItemBody{ContentType: BodyContentTypeText, Content: "Fixed."}}); err != nil {
+		t.Fatalf("Post.Reply() error = %v", err)
+	}
+	if posts, err := thread.ListPosts(); err != nil || len(posts) != 3 || posts[2].Body.Content != "Fixed." {
+		t.Errorf("ConversationThread.ListPosts() after replying = %v, error = %v, want 3 posts", posts, err)
+	}
+	if thread, err := group.GetThread(thread.ID); err != nil || !strings.Contains(thread.Preview, "Fixed.") || !thread.LastDeliveredDateTime.After(time.Time{}) {
+		t.Errorf("Group.GetThread() after replying = %v, error = %v, want the preview of the latest post", thread, err)
+	}
+}
+
+func TestAttachment_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		attachment Attachment
+		want       string
+	}{
+		{name: "File attachment", attachment: Attachment{Name: "a.txt", ContentType: "text/plain", ContentBytes: []byte("abc")},
+			want: `{"@odata.type":"#microsoft.graph.fileAttachment","name":"a.txt","contentType":"text/plain","isInline":false,"contentBytes":"YWJj"}`},
+		{name: "Read attachment", attachment: Attachment{ODataType: ODataTypeReferenceAttachment, ID: "1", Name: "b", Size: 5, LastModifiedDateTime: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)},
+			want: `{"@odata.type":"#microsoft.graph.referenceAttachment","id":"1","name":"b","size":5,"isInline":false,"lastModifiedDateTime":"2021-03-04T05:06:07Z"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.attachment)
+			if err != nil || string(got) != tt.want {
+				t.Errorf("Attachment.MarshalJSON() = %s, error = %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
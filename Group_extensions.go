@@ -0,0 +1,92 @@
+package msgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// ListExtensions returns all open extensions of the group.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/opentypeextension-get
+func (g Group) ListExtensions(opts ...ListQueryOption) (OpenExtensions, error) {
+	if g.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	return g.graphClient.listExtensions(fmt.Sprintf("/groups/%v", g.ID), opts)
+}
+
+// GetExtension returns the open extension of the group with the given name, e.g.
+// com.contoso.costCenter. Use OpenExtension.Decode to map it to a user-defined struct.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/opentypeextension-get
+func (g Group) GetExtension(extensionName string, opts ...GetQueryOption) (OpenExtension, error) {
+	if g.graphClient == nil {
+		return OpenExtension{}, ErrNotGraphClientSourced
+	}
+	return g.graphClient.getExtension(fmt.Sprintf("/groups/%v", g.ID), extensionName, opts)
+}
+
+// CreateExtension creates the open extension for the group. Microsoft Graph responds with 409
+// if the group already has an extension with the same name, use UpdateExtension instead.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/opentypeextension-post-opentypeextension
+func (g Group) CreateExtension(extension OpenExtension, opts ...CreateQueryOption) (OpenExtension, error) {
+	if g.graphClient == nil {
+		return OpenExtension{}, ErrNotGraphClientSourced
+	}
+	return g.graphClient.createExtension(fmt.Sprintf("/groups/%v", g.ID), extension, opts)
+}
+
+// UpdateExtension replaces the properties of the open extension of the group, properties that
+// are not set are removed from the extension.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/opentypeextension-update
+func (g Group) UpdateExtension(extension OpenExtension, opts ...UpdateQueryOption) error {
+	if g.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	return g.graphClient.updateExtension(fmt.Sprintf("/groups/%v", g.ID), extension, opts)
+}
+
+// DeleteExtension deletes the open extension of the group with the given name.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/opentypeextension-delete
+func (g Group) DeleteExtension(extensionName string, opts ...DeleteQueryOption) error {
+	if g.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	return g.graphClient.deleteExtension(fmt.Sprintf("/groups/%v", g.ID), extensionName, opts)
+}
+
+// GetSchemaExtension maps the value of the schema or directory extension with the given ID,
+// e.g. extkvbmkofy_costCenter, to v, a pointer to a user-defined struct with json tags. The
+// extension must have been selected, e.g. with GetWithSchemaExtensions. Returns
+// ErrFindExtension if the group has no value for the extension, see User.GetSchemaExtension.
+func (g Group) GetSchemaExtension(extensionID string, v interface{}) error {
+	return decodeSchemaExtension(g.ExtensionProperties, extensionID, v)
+}
+
+// SetSchemaExtension sets the schema or directory extension with the given ID to v, e.g. a
+// user-defined struct with json tags. A nil v clears the extension.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/extensibility-schema-groups
+func (g Group) SetSchemaExtension(extensionID string, v interface{}, opts ...UpdateQueryOption) error {
+	if g.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	if !isExtensionProperty(extensionID) {
+		return fmt.Errorf("%q is not the ID of a schema or directory extension", extensionID)
+	}
+	resource := fmt.Sprintf("/groups/%v", g.ID)
+
+	bodyBytes, err := json.Marshal(map[string]interface{}{extensionID: v})
+	if err != nil {
+		return err
+	}
+
+	reader := bytes.NewReader(bodyBytes)
+	// Hint: API-call body does not return any data / no json object.
+	return g.graphClient.makePATCHAPICall(resource, compileUpdateQueryOptions(opts), reader, nil)
+}
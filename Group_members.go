@@ -0,0 +1,225 @@
+package msgraph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// maxGroupBindsPerRequest is the maximum number of members Microsoft Graph adds with one
+// members@odata.bind PATCH request.
+const maxGroupBindsPerRequest = 20
+
+// errAlreadyExists is part of the error Microsoft Graph returns when adding a member or owner
+// that has been added before.
+const errAlreadyExists = "added object references already exist"
+
+// AddMember adds the user, group, device, service principal or org contact with the given ID
+// as direct member of the group. Adding an existing member succeeds.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/group-post-members
+func (g Group) AddMember(memberID string, opts ...UpdateQueryOption) error {
+	if g.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	return g.graphClient.addGroupReference(g.ID, "members", memberID, opts)
+}
+
+// AddMembers adds the directory objects with the given IDs as direct members of the group.
+// The members are added with one request per 20 members. If a request fails because one of
+// its members has been added before, its members are added one by one, hence adding existing
+// members succeeds. Stops at the first error, members of previous requests stay added.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/group-post-members
+func (g Group) AddMembers(memberIDs []string, opts ...UpdateQueryOption) error {
+	if g.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	return g.addMembers(memberIDs, opts, func() error { return nil })
+}
+
+// addMembers adds the members like Group.AddMembers and calls wait before every API-call,
+// e.g. to space them with a requestLimiter. Returns the error of wait.
+func (g Group) addMembers(memberIDs []string, opts []UpdateQueryOption, wait func() error) error {
+	resource := fmt.Sprintf("/groups/%v", g.ID)
+
+	var distinct []string
+	for _, memberID := range memberIDs {
+		if !containsString(distinct, memberID) {
+			distinct = append(distinct, memberID)
+		}
+	}
+	for start := 0; start < len(distinct); start += maxGroupBindsPerRequest {
+		end := start + maxGroupBindsPerRequest
+		if end > len(distinct) {
+			end = len(distinct)
+		}
+		batch := distinct[start:end]
+
+		bodyBytes, err := json.Marshal(map[string][]string{
+			"members@odata.bind": g.graphClient.directoryObjectURLs(batch),
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := wait(); err != nil {
+			return err
+		}
+		reader := bytes.NewReader(bodyBytes)
+		// Hint: API-call body does not return any data / no json object.
+		err = g.graphClient.makePATCHAPICall(resource, compileUpdateQueryOptions(opts), reader, nil)
+		if isStatusError(err, http.StatusBadRequest, errAlreadyExists) {
+			for _, memberID := range batch {
+				if err := wait(); err != nil {
+					return err
+				}
+				if err := g.AddMember(memberID, opts...); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveMember removes the directory object with the given ID from the direct members of
+// the group. Removing an object that is not a member succeeds, removing it from a group that
+// does not exist fails.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/group-delete-members
+func (g Group) RemoveMember(memberID string, opts ...DeleteQueryOption) error {
+	if g.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	err := g.graphClient.removeGroupMember(g.ID, memberID, opts...)
+	if g.graphClient.isMissingGroupReference(compileDeleteQueryOptions(opts).Context(), err, g.ID) {
+		return nil
+	}
+	return err
+}
+
+// ListMemberOf returns the groups and administrative units the group is a direct member of,
+// see DirectoryObject. Use DirectoryObjects.Groups to get the groups only.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/group-list-memberof
+func (g Group) ListMemberOf(opts ...ListQueryOption) (DirectoryObjects, error) {
+	if g.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	return g.graphClient.listDirectoryObjects(fmt.Sprintf("/groups/%v/memberOf", g.ID), opts)
+}
+
+// ListTransitiveMemberOf returns the groups and administrative units the group is a direct or
+// nested member of, hence all groups its members inherit access from.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/group-list-transitivememberof
+func (g Group) ListTransitiveMemberOf(opts ...ListQueryOption) (DirectoryObjects, error) {
+	if g.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	return g.graphClient.listDirectoryObjects(fmt.Sprintf("/groups/%v/transitiveMemberOf", g.ID), opts)
+}
+
+// ListOwners returns the owners of the group. Like Group.ListMembers this method ONLY
+// returns the users, owners may also be service principals, see Group.ListOwnerObjects.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/group-list-owners
+func (g Group) ListOwners(opts ...ListQueryOption) (Users, error) {
+	if g.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/groups/%v/owners/%v", g.ID, castSegment(ODataTypeUser))
+
+	var marsh struct {
+		Users Users `json:"value"`
+	}
+	err := g.graphClient.makeGETAPICall(resource, compileListQueryOptions(opts), &marsh)
+	marsh.Users.setGraphClient(g.graphClient)
+	return marsh.Users, err
+}
+
+// ListOwnerObjects returns all owners of the group, hence users and service principals, see
+// DirectoryObject.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/group-list-owners
+func (g Group) ListOwnerObjects(opts ...ListQueryOption) (DirectoryObjects, error) {
+	if g.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	return g.graphClient.listDirectoryObjects(fmt.Sprintf("/groups/%v/owners", g.ID), opts)
+}
+
+// AddOwner adds the user or service principal with the given ID as owner of the group.
+// Adding an existing owner succeeds.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/group-post-owners
+func (g Group) AddOwner(ownerID string, opts ...UpdateQueryOption) error {
+	if g.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	return g.graphClient.addGroupReference(g.ID, "owners", ownerID, opts)
+}
+
+// RemoveOwner removes the user or service principal with the given ID from the owners of
+// the group. Removing an object that is not an owner succeeds, removing it from a group that
+// does not exist fails. Microsoft Graph refuses to remove the last owner of a Microsoft 365 group.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/group-delete-owners
+func (g Group) RemoveOwner(ownerID string, opts ...DeleteQueryOption) error {
+	if g.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/groups/%v/owners/%v/$ref", g.ID, ownerID)
+	reqParams := compileDeleteQueryOptions(opts)
+	err := g.graphClient.makeDELETEAPICall(resource, reqParams, nil)
+	if g.graphClient.isMissingGroupReference(reqParams.Context(), err, g.ID) {
+		return nil
+	}
+	return err
+}
+
+// isMissingGroupReference returns true if err is the 404 of Microsoft Graph for a member or
+// owner reference of the group that does not exist. Microsoft Graph responds with 404 for a
+// group that does not exist too, hence the existence of the group is checked.
+func (g *GraphClient) isMissingGroupReference(ctx context.Context, err error, groupID string) bool {
+	if !isStatusError(err, http.StatusNotFound, "") {
+		return false
+	}
+	_, err = g.GetGroup(groupID, GetWithSelect("id"), GetWithContext(ctx))
+	return err == nil
+}
+
+// addGroupReference adds the directory object with the given ID to the members or owners of
+// the group, an existing reference is no error.
+func (g *GraphClient) addGroupReference(groupID, relation, objectID string, opts []UpdateQueryOption) error {
+	if objectID == "" {
+		return fmt.Errorf("ID of the %v to add must not be empty", relation)
+	}
+	resource := fmt.Sprintf("/groups/%v/%v/$ref", groupID, relation)
+
+	bodyBytes, err := json.Marshal(struct {
+		ODataID string `json:"@odata.id"`
+	}{ODataID: g.directoryObjectURL(objectID)})
+	if err != nil {
+		return err
+	}
+
+	reader := bytes.NewReader(bodyBytes)
+	// Hint: API-call body does not return any data / no json object.
+	err = g.makePOSTAPICall(resource, compileUpdateQueryOptions(opts), reader, nil)
+	if isStatusError(err, http.StatusBadRequest, errAlreadyExists) {
+		return nil
+	}
+	return err
+}
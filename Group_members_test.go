@@ -0,0 +1,143 @@
+package msgraph
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// createUnitTestGroup creates a security group for unit tests, delete it with Group.DeleteGroup.
+func createUnitTestGroup(t *testing.T) Group {
+	t.Helper()
+	rndstring := randomString(32)
+	group, err := graphClient.CreateGroup(Group{
+		DisplayName:     "go-msgraph unit-test generated group - random " + rndstring,
+		MailNickname:    "go-msgraph.unit-test.generated." + rndstring,
+		SecurityEnabled: true,
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("Cannot create a new Group for unit tests: %v", err)
+	}
+	return group
+}
+
+func TestGroup_AddMember(t *testing.T) {
+	group := createUnitTestGroup(t)
+	defer group.DeleteGroup()
+	user := createUnitTestUser(t)
+	defer user.DeleteUser()
+
+	for i := 0; i < 2; i++ { // adding an existing member succeeds
+		if err := group.AddMember(user.ID); err != nil {
+			t.Fatalf("Group.AddMember() #%v error = %v", i+1, err)
+		}
+	}
+	if members, err := group.ListMembers(); err != nil || len(members) != 1 || members[0].ID != user.ID {
+		t.Errorf("Group.ListMembers() = %v, error = %v, want %v", members, err, user.ID)
+	}
+	if err := group.AddMember(""); err == nil {
+		t.Errorf("Group.AddMember() with empty ID error = nil, want error")
+	}
+
+	for i := 0; i < 2; i++ { // removing a non-member succeeds
+		if err := group.RemoveMember(user.ID); err != nil {
+			t.Fatalf("Group.RemoveMember() #%v error = %v", i+1, err)
+		}
+	}
+	// IDs are case-insensitive, the error message of Microsoft Graph is not evaluated
+	if err := group.RemoveMember(strings.ToUpper(user.ID)); err != nil {
+		t.Errorf("Group.RemoveMember() of a non-member in upper case error = %v", err)
+	}
+	if members, err := group.ListMembers(); err != nil || len(members) != 0 {
+		t.Errorf("Group.ListMembers() after Group.RemoveMember() = %v, error = %v, want none", members, err)
+	}
+	missing := group
+	missing.ID = "00000000-0000-0000-0000-000000000000"
+	if err := missing.RemoveMember(user.ID); !isStatusError(err, http.StatusNotFound, "") {
+		t.Errorf("Group.RemoveMember() of a non-existing group error = %v, want 404", err)
+	}
+	if err := (Group{}).AddMember(user.ID); err != ErrNotGraphClientSourced {
+		t.Errorf("Group.AddMember() without GraphClient error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+}
+
+func TestGroup_AddMembers(t *testing.T) {
+	if offlineServer == nil {
+		t.Skip("the unit tests must not create dozens of users in the tenant, only tested offline")
+	}
+	group := createUnitTestGroup(t)
+	defer group.DeleteGroup()
+
+	var memberIDs []string
+	for i := 0; i < 45; i++ {
+		user := offlineServer.AddUser(map[string]interface{}{"displayName": fmt.Sprintf("member %v", i), "userPrincipalName": fmt.Sprintf("member%v.%v@contoso.com", i, randomString(8))})
+		defer (User{ID: user.ID(), graphClient: graphClient}).DeleteUser()
+		memberIDs = append(memberIDs, user.ID())
+	}
+
+	graphClient.ResetPlan()
+	if err := group.AddMembers(memberIDs, UpdateWithDryRun()); err != nil || len(graphClient.Plan()) != 3 {
+		t.Fatalf("Group.AddMembers() with dry-run error = %v, planned %v requests, want 3", err, len(graphClient.Plan()))
+	}
+	graphClient.ResetPlan()
+
+	// the second batch contains an existing member and is added one by one
+	if err := group.AddMember(memberIDs[25]); err != nil {
+		t.Fatalf("Group.AddMember() error = %v", err)
+	}
+	offlineServer.ResetRequests()
+	if err := group.AddMembers(append(memberIDs, memberIDs[0])); err != nil {
+		t.Fatalf("Group.AddMembers() error = %v", err)
+	}
+	if members := offlineServer.GroupMembers(group.ID); len(members) != len(memberIDs) {
+		t.Errorf("msgraphtest.Server.GroupMembers() = %v members, want %v", len(members), len(memberIDs))
+	}
+	if requests := offlineServer.Requests(); len(requests) != 3+20 {
+		t.Errorf("Group.AddMembers() sent %v requests, want 3 batches and 20 single members", len(requests))
+	}
+
+	if err := group.AddMembers(memberIDs[:5]); err != nil {
+		t.Errorf("Group.AddMembers() of existing members error = %v", err)
+	}
+	if err := group.AddMembers([]string{"00000000-0000-0000-0000-000000000000"}); err == nil {
+		t.Errorf("Group.AddMembers() of an unknown member error = nil, want error")
+	}
+}
+
+func TestGroup_Owners(t *testing.T) {
+	group := createUnitTestGroup(t)
+	defer group.DeleteGroup()
+	owner := createUnitTestUser(t)
+	defer owner.DeleteUser()
+
+	for i := 0; i < 2; i++ { // adding an existing owner succeeds
+		if err := group.AddOwner(owner.ID); err != nil {
+			t.Fatalf("Group.AddOwner() #%v error = %v", i+1, err)
+		}
+	}
+	owners, err := group.ListOwners()
+	if err != nil || len(owners) != 1 || owners[0].ID != owner.ID || owners[0].graphClient == nil {
+		t.Errorf("Group.ListOwners() = %v, error = %v, want %v", owners, err, owner.ID)
+	}
+
+	for i := 0; i < 2; i++ { // removing a non-owner succeeds
+		if err := group.RemoveOwner(owner.ID); err != nil {
+			t.Fatalf("Group.RemoveOwner() #%v error = %v", i+1, err)
+		}
+	}
+	if err := group.RemoveOwner(strings.ToUpper(owner.ID)); err != nil {
+		t.Errorf("Group.RemoveOwner() of a non-owner in upper case error = %v", err)
+	}
+	if owners, err := group.ListOwners(); err != nil || len(owners) != 0 {
+		t.Errorf("Group.ListOwners() after Group.RemoveOwner() = %v, error = %v, want none", owners, err)
+	}
+	missing := group
+	missing.ID = "00000000-0000-0000-0000-000000000000"
+	if err := missing.RemoveOwner(owner.ID); !isStatusError(err, http.StatusNotFound, "") {
+		t.Errorf("Group.RemoveOwner() of a non-existing group error = %v, want 404", err)
+	}
+	if _, err := (Group{}).ListOwners(); err != ErrNotGraphClientSourced {
+		t.Errorf("Group.ListOwners() without GraphClient error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+}
@@ -0,0 +1,79 @@
+package msgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// IsDynamicMembership returns true if the members of the group are determined by its
+// MembershipRule, hence members cannot be added or removed.
+func (g Group) IsDynamicMembership() bool {
+	return containsString(g.GroupTypes, GroupTypeDynamicMembership)
+}
+
+// UpdateMembershipRule sets the membership rule of the group, the rule is validated by
+// Microsoft Graph. A group with assigned members is converted to a group with dynamic
+// membership, its members are replaced by the users matching the rule then. Microsoft Graph
+// processes the rule asynchronously, the members are updated within minutes.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/group-update
+func (g Group) UpdateMembershipRule(rule string, opts ...UpdateQueryOption) error {
+	if g.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/groups/%v", g.ID)
+
+	// the current group types are read, as updating them replaces all of them
+	current, err := g.graphClient.GetGroup(g.ID, GetWithSelect("id,groupTypes"))
+	if err != nil {
+		return err
+	}
+	patch := map[string]interface{}{"membershipRule": rule}
+	if !current.IsDynamicMembership() {
+		patch["groupTypes"] = append(append([]string{}, current.GroupTypes...), GroupTypeDynamicMembership)
+		patch["membershipRuleProcessingState"] = MembershipRuleProcessingStateOn
+	}
+	bodyBytes, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+
+	reader := bytes.NewReader(bodyBytes)
+	// Hint: API-call body does not return any data / no json object.
+	return g.graphClient.makePATCHAPICall(resource, compileUpdateQueryOptions(opts), reader, nil)
+}
+
+// PauseMembershipRuleProcessing stops updating the members of the group with dynamic
+// membership, e.g. while changing the user properties the rule depends on. The current
+// members are kept.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/group-update
+func (g Group) PauseMembershipRuleProcessing(opts ...UpdateQueryOption) error {
+	return g.setMembershipRuleProcessingState(MembershipRuleProcessingStatePaused, opts)
+}
+
+// ResumeMembershipRuleProcessing resumes updating the members of the group with dynamic
+// membership, see Group.PauseMembershipRuleProcessing.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/group-update
+func (g Group) ResumeMembershipRuleProcessing(opts ...UpdateQueryOption) error {
+	return g.setMembershipRuleProcessingState(MembershipRuleProcessingStateOn, opts)
+}
+
+// setMembershipRuleProcessingState sets the membershipRuleProcessingState of the group.
+func (g Group) setMembershipRuleProcessingState(state string, opts []UpdateQueryOption) error {
+	if g.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/groups/%v", g.ID)
+
+	bodyBytes, err := json.Marshal(map[string]string{"membershipRuleProcessingState": state})
+	if err != nil {
+		return err
+	}
+
+	reader := bytes.NewReader(bodyBytes)
+	// Hint: API-call body does not return any data / no json object.
+	return g.graphClient.makePATCHAPICall(resource, compileUpdateQueryOptions(opts), reader, nil)
+}
@@ -0,0 +1,103 @@
+package msgraph
+
+import (
+	"testing"
+)
+
+func TestGroup_UpdateMembershipRule(t *testing.T) {
+	if offlineServer == nil {
+		t.Skip("groups with dynamic membership require an Azure AD Premium license, only tested offline")
+	}
+	user := createUnitTestUser(t)
+	defer user.DeleteUser()
+
+	tests := []struct {
+		name      string
+		group     Group
+		memberIDs []string
+	}{
+		{name: "Rule without group type", group: Group{MembershipRule: `user.department -eq "Sales"`}},
+		{name: "Dynamic group without rule", group: Group{GroupTypes: []string{GroupTypeDynamicMembership}}},
+		{name: "Members of a dynamic group", group: Group{GroupTypes: []string{GroupTypeDynamicMembership}, MembershipRule: `user.department -eq "Sales"`}, memberIDs: []string{user.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.group.DisplayName, tt.group.MailNickname, tt.group.SecurityEnabled = "dynamic", "dynamic", true
+			offlineServer.ResetRequests()
+			if _, err := graphClient.CreateGroup(tt.group, nil, tt.memberIDs); err == nil {
+				t.Errorf("GraphClient.CreateGroup() error = nil, want error")
+			}
+			if requests := offlineServer.Requests(); len(requests) != 0 {
+				t.Errorf("GraphClient.CreateGroup() sent %v requests, want the group to be validated locally", len(requests))
+			}
+		})
+	}
+
+	group, err := graphClient.CreateGroup(Group{
+		DisplayName:     "go-msgraph unit-test dynamic group",
+		MailNickname:    "go-msgraph.unit-test.dynamic",
+		SecurityEnabled: true,
+		GroupTypes:      []string{GroupTypeDynamicMembership},
+		MembershipRule:  `user.department -eq "Sales"`,
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("GraphClient.CreateGroup() error = %v", err)
+	}
+	defer group.DeleteGroup()
+	if !group.IsDynamicMembership() || group.MembershipRule != `user.department -eq "Sales"` || group.MembershipRuleProcessingState != MembershipRuleProcessingStateOn {
+		t.Errorf("GraphClient.CreateGroup() = %v, want a group with dynamic membership", group)
+	}
+	if err := group.AddMember(user.ID); err == nil {
+		t.Errorf("Group.AddMember() to a group with dynamic membership error = nil, want error")
+	}
+
+	for _, pause := range []bool{true, false} {
+		update, want := group.ResumeMembershipRuleProcessing, MembershipRuleProcessingStateOn
+		if pause {
+			update, want = group.PauseMembershipRuleProcessing, MembershipRuleProcessingStatePaused
+		}
+		if err := update(); err != nil {
+			t.Fatalf("Group.PauseMembershipRuleProcessing() or Group.ResumeMembershipRuleProcessing() error = %v", err)
+		}
+		if got, err := graphClient.GetGroup(group.ID); err != nil || got.MembershipRuleProcessingState != want {
+			t.Errorf("GraphClient.GetGroup().MembershipRuleProcessingState = %v, error = %v, want %v", got.MembershipRuleProcessingState, err, want)
+		}
+	}
+
+	// rules are validated by Microsoft Graph, also those that cannot be evaluated locally
+	for _, rule := range []string{`user.memberof -any (group.objectId -in ['a','b'])`, `device.deviceOSType -eq "Windows"`} {
+		if err := group.UpdateMembershipRule(rule); err != nil {
+			t.Errorf("Group.UpdateMembershipRule(%v) error = %v", rule, err)
+		}
+	}
+	if err := group.UpdateMembershipRule(`user.department -in ["Sales", "Marketing"]`); err != nil {
+		t.Fatalf("Group.UpdateMembershipRule() error = %v", err)
+	}
+	if got, err := graphClient.GetGroup(group.ID); err != nil || got.MembershipRule != `user.department -in ["Sales", "Marketing"]` || len(got.GroupTypes) != 1 {
+		t.Errorf("GraphClient.GetGroup() after Group.UpdateMembershipRule() = %v, error = %v, want the updated rule", got, err)
+	}
+
+	// a Microsoft 365 group with assigned members is converted and keeps its group types
+	unified, err := graphClient.CreateGroup(Group{DisplayName: "unified", MailNickname: "unified", MailEnabled: true, GroupTypes: []string{GroupTypeUnified}}, nil, nil)
+	if err != nil {
+		t.Fatalf("GraphClient.CreateGroup() error = %v", err)
+	}
+	defer func() {
+		// deleted Microsoft 365 groups are kept as deleted items
+		unified.DeleteGroup()
+		graphClient.PermanentlyDeleteItem(unified.ID)
+	}()
+	if unified.IsDynamicMembership() {
+		t.Errorf("Group.IsDynamicMembership() of a group with assigned members = true, want false")
+	}
+	if err := (Group{ID: unified.ID, graphClient: graphClient}).UpdateMembershipRule(`user.accountEnabled -eq true`); err != nil {
+		t.Fatalf("Group.UpdateMembershipRule() error = %v", err)
+	}
+	got, err := graphClient.GetGroup(unified.ID)
+	if err != nil || !got.IsDynamicMembership() || !containsString(got.GroupTypes, GroupTypeUnified) || got.MembershipRuleProcessingState != MembershipRuleProcessingStateOn {
+		t.Errorf("GraphClient.GetGroup() after converting = %v, error = %v, want a Microsoft 365 group with dynamic membership", got, err)
+	}
+	if err := (Group{}).PauseMembershipRuleProcessing(); err != ErrNotGraphClientSourced {
+		t.Errorf("Group.PauseMembershipRuleProcessing() without GraphClient error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+}
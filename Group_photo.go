@@ -0,0 +1,89 @@
+package msgraph
+
+import (
+	"fmt"
+	"io"
+)
+
+// GetPhoto returns the binary content of the profile photo of the group in the largest
+// available size, see Photo. Microsoft Graph responds with 404 if the group has no photo.
+// Profile photos are only supported for Microsoft 365 groups, not for security groups.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/profilephoto-get
+func (g Group) GetPhoto(opts ...GetQueryOption) (Photo, error) {
+	if g.graphClient == nil {
+		return Photo{}, ErrNotGraphClientSourced
+	}
+	return g.graphClient.getPhoto(fmt.Sprintf("/groups/%v/photo", g.ID), opts)
+}
+
+// GetPhotoOfSize returns the binary content of the profile photo of the group in the given
+// size, one of the PhotoSize* constants, e.g. PhotoSize96x96 for thumbnails.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/profilephoto-get
+func (g Group) GetPhotoOfSize(size string, opts ...GetQueryOption) (Photo, error) {
+	if g.graphClient == nil {
+		return Photo{}, ErrNotGraphClientSourced
+	}
+	return g.graphClient.getPhoto(fmt.Sprintf("/groups/%v/photos/%v", g.ID, size), opts)
+}
+
+// OpenPhoto returns the binary content of the profile photo of the group in the largest
+// available size as stream, without reading it into memory like Group.GetPhoto. The caller
+// must close the PhotoStream.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/profilephoto-get
+func (g Group) OpenPhoto(opts ...GetQueryOption) (PhotoStream, error) {
+	if g.graphClient == nil {
+		return PhotoStream{}, ErrNotGraphClientSourced
+	}
+	return g.graphClient.openPhoto(fmt.Sprintf("/groups/%v/photo", g.ID), opts)
+}
+
+// OpenPhotoOfSize returns the binary content of the profile photo of the group in the given
+// size as stream, see Group.OpenPhoto and Group.GetPhotoOfSize.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/profilephoto-get
+func (g Group) OpenPhotoOfSize(size string, opts ...GetQueryOption) (PhotoStream, error) {
+	if g.graphClient == nil {
+		return PhotoStream{}, ErrNotGraphClientSourced
+	}
+	return g.graphClient.openPhoto(fmt.Sprintf("/groups/%v/photos/%v", g.ID, size), opts)
+}
+
+// GetPhotoMetadata returns the metadata of the profile photo of the group in the largest
+// available size, e.g. to compare its ETag with a cached photo.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/profilephoto-get
+func (g Group) GetPhotoMetadata(opts ...GetQueryOption) (ProfilePhoto, error) {
+	if g.graphClient == nil {
+		return ProfilePhoto{}, ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/groups/%v/photo", g.ID)
+
+	var photo ProfilePhoto
+	err := g.graphClient.makeGETAPICall(resource, compileGetQueryOptions(opts), &photo)
+	return photo, err
+}
+
+// ListPhotos returns the metadata of all available sizes of the profile photo of the group.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/profilephoto-get
+func (g Group) ListPhotos(opts ...ListQueryOption) (ProfilePhotos, error) {
+	if g.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	return g.graphClient.listPhotos(fmt.Sprintf("/groups/%v/photos", g.ID), opts)
+}
+
+// SetPhoto uploads the profile photo of the group, replacing the current photo. The contentType,
+// e.g. image/jpeg, is detected from the photo if empty. Microsoft Graph accepts photos of up
+// to 4 MB and generates the smaller sizes from it.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/profilephoto-update
+func (g Group) SetPhoto(photo io.Reader, contentType string, opts ...UpdateQueryOption) error {
+	if g.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	return g.graphClient.setPhoto(fmt.Sprintf("/groups/%v/photo", g.ID), photo, contentType, opts)
+}
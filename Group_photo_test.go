@@ -0,0 +1,43 @@
+package msgraph
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+func TestGroup_Photo(t *testing.T) {
+	if err := (Group{ID: "none"}).SetPhoto(bytes.NewReader(nil), ""); err != ErrNotGraphClientSourced {
+		t.Errorf("Group.SetPhoto() error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+	if offlineServer == nil {
+		t.Skip("the unit tests must not change the photos of existing groups, only tested offline")
+	}
+	group, err := graphClient.GetGroup(offlineServer.List("/groups")[0].ID())
+	if err != nil {
+		t.Fatalf("GraphClient.GetGroup() error = %v", err)
+	}
+
+	content := createUnitTestPhoto(t, 300, 250)
+	if err := group.SetPhoto(bytes.NewReader(content), "image/png"); err != nil {
+		t.Fatalf("Group.SetPhoto() error = %v", err)
+	}
+	if contentType, got, ok := offlineServer.Photo(group.ID); !ok || contentType != "image/png" || !bytes.Equal(got, content) {
+		t.Errorf("Group.SetPhoto() did not upload the photo: %v, %v bytes", contentType, len(got))
+	}
+	photo, err := group.GetPhotoOfSize(PhotoSize240x240)
+	if err != nil || !bytes.Equal(photo.Content, content) {
+		t.Errorf("Group.GetPhotoOfSize() = %v, error = %v", photo, err)
+	}
+	stream, err := group.OpenPhotoOfSize(PhotoSize240x240)
+	if err != nil {
+		t.Fatalf("Group.OpenPhotoOfSize() error = %v", err)
+	}
+	defer stream.Close()
+	if got, err := ioutil.ReadAll(stream); err != nil || !bytes.Equal(got, content) {
+		t.Errorf("Group.OpenPhotoOfSize() returned %v bytes, error = %v, want %v bytes", len(got), err, len(content))
+	}
+	if metadata, err := group.GetPhotoMetadata(); err != nil || metadata.ID != "240X240" {
+		t.Errorf("Group.GetPhotoMetadata() = %v, error = %v, want 240X240", metadata, err)
+	}
+}
@@ -0,0 +1,152 @@
+package msgraph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// teamsAsyncOperationPollInterval is the interval the status of a TeamsAsyncOperation is polled in.
+var teamsAsyncOperationPollInterval = 5 * time.Second
+
+// teamCreationRetries is the number of times the creation of a team is retried if Microsoft
+// Graph responds with 404, as a recently created group is not replicated yet.
+var teamCreationRetries = 3
+
+// teamCreationRetryInterval is the delay before the creation of a team is retried.
+var teamCreationRetryInterval = 10 * time.Second
+
+// teamsAsyncOperationLocation matches the Location header of a TeamsAsyncOperation,
+// e.g. /teams('{teamId}')/operations('{operationId}').
+var teamsAsyncOperationLocation = regexp.MustCompile(`teams\('([^']+)'\)/operations\('([^']+)'\)`)
+
+// CreateTeam creates the Microsoft Teams team of the Microsoft 365 group and waits until
+// Microsoft Graph has provisioned it, which may take several minutes. Only the Description and
+// the settings of the given team are used. The status of the creation is polled every few
+// seconds, use CreateWithContext to limit the waiting time. Microsoft Graph may respond with
+// 404 if the group has been created within the last 15 minutes, as it is not replicated yet,
+// the creation is retried 3 times with a delay of 10 seconds then as recommended.
+//
+// In dry-run mode the creation is planned and the returned Team only has its ID set, the ID of
+// the group.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/team-post
+func (g Group) CreateTeam(team Team, opts ...CreateQueryOption) (Team, error) {
+	if g.graphClient == nil {
+		return Team{}, ErrNotGraphClientSourced
+	}
+	post := map[string]interface{}{
+		"template@odata.bind": g.graphClient.resourceURL("teamsTemplates('standard')"),
+		"group@odata.bind":    g.graphClient.resourceURL(fmt.Sprintf("groups('%v')", g.ID)),
+	}
+	if team.Description != "" {
+		post["description"] = team.Description
+	}
+	if team.MemberSettings != nil {
+		post["memberSettings"] = team.MemberSettings
+	}
+	if team.GuestSettings != nil {
+		post["guestSettings"] = team.GuestSettings
+	}
+	if team.MessagingSettings != nil {
+		post["messagingSettings"] = team.MessagingSettings
+	}
+	bodyBytes, err := json.Marshal(post)
+	if err != nil {
+		return Team{}, err
+	}
+
+	reqParams := compileCreateQueryOptions(opts)
+	// Hint: the team is created asynchronously, the response only contains the Location of the operation.
+	var raw rawResponse
+	for retries := 0; ; retries++ {
+		err = g.graphClient.makePOSTAPICall("/teams", reqParams, bytes.NewReader(bodyBytes), &raw)
+		if err == nil {
+			break
+		}
+		if retries >= teamCreationRetries || !isStatusError(err, http.StatusNotFound, "") {
+			return Team{}, err
+		}
+
+		timer := time.NewTimer(teamCreationRetryInterval)
+		select {
+		case <-reqParams.Context().Done():
+			timer.Stop()
+			return Team{}, reqParams.Context().Err()
+		case <-timer.C:
+		}
+	}
+	if g.graphClient.isDryRun(reqParams) {
+		return Team{ID: g.ID, graphClient: g.graphClient}, nil
+	}
+
+	operation, err := g.graphClient.waitForTeamsAsyncOperation(reqParams.Context(), raw.location)
+	if err != nil {
+		return Team{}, err
+	}
+	teamID := operation.TargetResourceID
+	if teamID == "" {
+		teamID = g.ID
+	}
+	return g.graphClient.getTeam(teamID, GetWithContext(reqParams.Context()))
+}
+
+// GetTeam returns the team of the Microsoft 365 group. Microsoft Graph responds with 404 if
+// the group has no team, see Group.CreateTeam.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/team-get
+func (g Group) GetTeam(opts ...GetQueryOption) (Team, error) {
+	if g.graphClient == nil {
+		return Team{}, ErrNotGraphClientSourced
+	}
+	return g.graphClient.getTeam(g.ID, opts...)
+}
+
+// getTeam returns the team with the given ID.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/team-get
+func (g *GraphClient) getTeam(teamID string, opts ...GetQueryOption) (Team, error) {
+	resource := fmt.Sprintf("/teams/%v", teamID)
+
+	var team Team
+	err := g.makeGETAPICall(resource, compileGetQueryOptions(opts), &team)
+	team.setGraphClient(g)
+	return team, err
+}
+
+// waitForTeamsAsyncOperation polls the operation with the given Location header until it has
+// succeeded, failed or the context is done. Returns an error if the operation failed.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/teamsasyncoperation-get
+func (g *GraphClient) waitForTeamsAsyncOperation(ctx context.Context, location string) (TeamsAsyncOperation, error) {
+	match := teamsAsyncOperationLocation.FindStringSubmatch(location)
+	if match == nil {
+		return TeamsAsyncOperation{}, fmt.Errorf("unexpected Location header of a teams async operation: %q", location)
+	}
+	resource := fmt.Sprintf("/teams/%v/operations/%v", match[1], match[2])
+
+	for {
+		var operation TeamsAsyncOperation
+		if err := g.makeGETAPICall(resource, compileGetQueryOptions([]GetQueryOption{GetWithContext(ctx)}), &operation); err != nil {
+			return TeamsAsyncOperation{}, err
+		}
+		if operation.Status == TeamsAsyncOperationStatusFailed {
+			return operation, fmt.Errorf("teams async operation %v failed after %v attempts: %v", operation.ID, operation.AttemptsCount, operation.Error)
+		}
+		if operation.Done() {
+			return operation, nil
+		}
+
+		timer := time.NewTimer(teamsAsyncOperationPollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return operation, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package msgraph
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-networks/go-msgraph/msgraphtest"
+)
+
+// createUnitTestTeam creates a Microsoft 365 group with a team for unit tests with owner as
+// owner and member as member of the team, delete it with Group.DeleteGroup.
+func createUnitTestTeam(t *testing.T, owner, member User) (Group, Team) {
+	t.Helper()
+	group, err := graphClient.CreateGroup(Group{
+		DisplayName:  "go-msgraph unit-test team " + randomString(8),
+		MailNickname: "go-msgraph.unit-test.team." + randomString(8),
+		GroupTypes:   []string{GroupTypeUnified},
+		MailEnabled:  true,
+		Visibility:   GroupVisibilityPrivate,
+	}, []string{owner.ID}, []string{owner.ID, member.ID})
+	if err != nil {
+		t.Fatalf("GraphClient.CreateGroup() error = %v", err)
+	}
+	team, err := group.CreateTeam(Team{Description: "go-msgraph unit-test"})
+	if err != nil {
+		group.DeleteGroup()
+		t.Fatalf("Group.CreateTeam() error = %v", err)
+	}
+	return group, team
+}
+
+func TestGroup_CreateTeam(t *testing.T) {
+	if _, err := (Group{}).CreateTeam(Team{}); err != ErrNotGraphClientSourced {
+		t.Errorf("Group.CreateTeam() without GraphClient error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+	if offlineServer == nil {
+		t.Skip("Microsoft 365 groups are only deleted permanently after 30 days, only tested offline")
+	}
+	pollInterval, retryInterval := teamsAsyncOperationPollInterval, teamCreationRetryInterval
+	teamsAsyncOperationPollInterval, teamCreationRetryInterval = time.Millisecond, time.Millisecond
+	defer func() { teamsAsyncOperationPollInterval, teamCreationRetryInterval = pollInterval, retryInterval }()
+
+	owner, member := createUnitTestUser(t), createUnitTestUser(t)
+	defer owner.DeleteUser()
+	defer member.DeleteUser()
+	group, err := graphClient.CreateGroup(Group{
+		DisplayName:  "go-msgraph unit-test team",
+		MailNickname: "go-msgraph.unit-test.team",
+		GroupTypes:   []string{GroupTypeUnified},
+		MailEnabled:  true,
+		Visibility:   GroupVisibilityPrivate,
+	}, []string{owner.ID}, []string{member.ID})
+	if err != nil {
+		t.Fatalf("GraphClient.CreateGroup() error = %v", err)
+	}
+	defer func() {
+		group.DeleteGroup()
+		graphClient.PermanentlyDeleteItem(group.ID)
+	}()
+
+	// dry-run plans the creation without waiting for it
+	offlineServer.ResetRequests()
+	if team, err := group.CreateTeam(Team{}, CreateWithDryRun()); err != nil || team.ID != group.ID {
+		t.Errorf("Group.CreateTeam() in dry-run mode = %v, error = %v, want the ID of the group", team, err)
+	}
+	if plan := graphClient.Plan(); len(plan) != 1 || plan[0].Method != http.MethodPost || plan[0].Path != "/teams" || len(offlineServer.Requests()) != 0 {
+		t.Errorf("GraphClient.Plan() after Group.CreateTeam() = %v, want only the planned POST /teams", plan)
+	}
+	graphClient.ResetPlan()
+
+	// the group is not replicated yet, hence the creation is retried
+	offlineServer.InjectFault(msgraphtest.Fault{Method: http.MethodPost, Path: "/teams", StatusCode: http.StatusNotFound, Times: 2})
+	team, err := group.CreateTeam(Team{Description: "Project Apollo", MemberSettings: &TeamMemberSettings{AllowCreateUpdateChannels: true}})
+	if err != nil || team.ID != group.ID || team.DisplayName != group.DisplayName || team.Visibility != GroupVisibilityPrivate {
+		t.Fatalf("Group.CreateTeam() = %v, error = %v, want the team of %v", team, err, group)
+	}
+	var posts, polls int
+	for _, request := range offlineServer.Requests() {
+		if request.Method == http.MethodPost && request.Path == "/teams" {
+			posts++
+		}
+		if request.Method == http.MethodPost && request.Path == "/teams" && !strings.Contains(string(request.Body), `groups('`+group.ID+`')`) {
+			t.Errorf("Group.CreateTeam() sent %s, want the group bound by group@odata.bind", request.Body)
+		}
+		if strings.Contains(request.Path, "/operations/") {
+			polls++
+		}
+	}
+	if posts != 3 || polls != 2 {
+		t.Errorf("Group.CreateTeam() sent %v POST /teams and polled the operation %v times, want 3 and 2", posts, polls)
+	}
+	if got, err := group.GetTeam(); err != nil || got.Description != "Project Apollo" || got.MemberSettings == nil || got.MemberSettings.AllowDeleteChannels {
+		t.Errorf("Group.GetTeam() = %v, error = %v, want the created team and its member settings", got, err)
+	}
+	if _, err := group.CreateTeam(Team{}); !isStatusError(err, http.StatusConflict, "") {
+		t.Errorf("Group.CreateTeam() of a group with team error = %v, want %v", err, http.StatusConflict)
+	}
+
+	members, err := team.ListMembers()
+	if err != nil || len(members) != 2 || len(members.Owners()) != 1 || members.Owners()[0].UserID != owner.ID {
+		t.Errorf("Team.ListMembers() = %v, error = %v, want 2 members with %v as owner", members, err, owner.ID)
+	}
+	if got, err := members.GetByUserID(strings.ToUpper(member.ID)); err != nil || len(got.Roles) != 0 {
+		t.Errorf("ConversationMembers.GetByUserID() = %v, error = %v, want a member without roles", got, err)
+	}
+	if _, err := members.GetByUserID("unknown"); err != ErrFindConversationMember {
+		t.Errorf("ConversationMembers.GetByUserID() of an unknown user error = %v, want %v", err, ErrFindConversationMember)
+	}
+
+	// the creation fails after the retries, or once the context is done
+	offlineServer.ResetRequests()
+	offlineServer.InjectFault(msgraphtest.Fault{Method: http.MethodPost, Path: "/teams", StatusCode: http.StatusNotFound})
+	if _, err := group.CreateTeam(Team{}); !isStatusError(err, http.StatusNotFound, "") || len(offlineServer.Requests()) != teamCreationRetries+1 {
+		t.Errorf("Group.CreateTeam() of a missing group error = %v after %v requests, want %v after %v requests", err, len(offlineServer.Requests()), http.StatusNotFound, teamCreationRetries+1)
+	}
+	teamCreationRetryInterval = time.Hour
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := group.CreateTeam(Team{}, CreateWithContext(ctx)); err != context.DeadlineExceeded {
+		t.Errorf("Group.CreateTeam() with a done context error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	offlineServer.ClearFaults()
+
+	securityGroup := createUnitTestGroup(t)
+	defer securityGroup.DeleteGroup()
+	if _, err := securityGroup.CreateTeam(Team{}); err == nil || !strings.Contains(err.Error(), "failed") {
+		t.Errorf("Group.CreateTeam() of a security group error = %v, want the failed operation", err)
+	}
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, err := graphClient.waitForTeamsAsyncOperation(ctx, "/teams/"+group.ID); err == nil {
+		t.Errorf("GraphClient.waitForTeamsAsyncOperation() of an invalid Location error = nil, want error")
+	}
+}
+
+func TestTeam_CreateChannel(t *testing.T) {
+	if _, err := (Team{}).CreateChannel(Channel{DisplayName: "channel"}, nil); err != ErrNotGraphClientSourced {
+		t.Errorf("Team.CreateChannel() without GraphClient error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+	if offlineServer == nil {
+		t.Skip("Microsoft 365 groups are only deleted permanently after 30 days, only tested offline")
+	}
+	pollInterval := teamsAsyncOperationPollInterval
+	teamsAsyncOperationPollInterval = time.Millisecond
+	defer func() { teamsAsyncOperationPollInterval = pollInterval }()
+
+	owner, member := createUnitTestUser(t), createUnitTestUser(t)
+	defer owner.DeleteUser()
+	defer member.DeleteUser()
+	group, team := createUnitTestTeam(t, owner, member)
+	defer func() {
+		group.DeleteGroup()
+		graphClient.PermanentlyDeleteItem(group.ID)
+	}()
+
+	channels, err := team.ListChannels()
+	if err != nil || len(channels) != 1 {
+		t.Fatalf("Team.ListChannels() = %v, error = %v, want the General channel", channels, err)
+	}
+	general, err := channels.GetByDisplayName("general")
+	if err != nil {
+		t.Fatalf("Channels.GetByDisplayName() error = %v", err)
+	}
+	if err := general.UpdateChannel(Channel{DisplayName: "Renamed"}); err == nil {
+		t.Errorf("Channel.UpdateChannel() of the General channel error = nil, want error")
+	}
+	if err := general.DeleteChannel(); err == nil {
+		t.Errorf("Channel.DeleteChannel() of the General channel error = nil, want error")
+	}
+
+	offlineServer.ResetRequests()
+	tests := []struct {
+		name     string
+		channel  Channel
+		ownerIDs []string
+	}{
+		{name: "Without displayName", channel: Channel{Description: "no name"}},
+		{name: "Standard channel with owners", channel: Channel{DisplayName: "standard"}, ownerIDs: []string{owner.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := team.CreateChannel(tt.channel, tt.ownerIDs); err == nil {
+				t.Errorf("Team.CreateChannel() error = nil, want error")
+			}
+		})
+	}
+	if requests := offlineServer.Requests(); len(requests) != 0 {
+		t.Errorf("Team.CreateChannel() of invalid channels sent %v requests, want the channels to be validated locally", len(requests))
+	}
+
+	planning, err := team.CreateChannel(Channel{DisplayName: "Planning", Description: "Milestones"}, nil)
+	if err != nil || planning.MembershipType != ChannelMembershipTypeStandard || !strings.HasPrefix(planning.ID, "19:") {
+		t.Fatalf("Team.CreateChannel() = %v, error = %v, want a standard channel", planning, err)
+	}
+	if _, err := team.CreateChannel(Channel{DisplayName: "PLANNING"}, nil); err == nil {
+		t.Errorf("Team.CreateChannel() with an existing displayName error = nil, want error")
+	}
+	if err := planning.UpdateChannel(Channel{Description: "Milestones and deadlines"}); err != nil {
+		t.Fatalf("Channel.UpdateChannel() error = %v", err)
+	}
+	if got, err := team.GetChannel(planning.ID); err != nil || got.DisplayName != "Planning" || got.Description != "Milestones and deadlines" {
+		t.Errorf("Team.GetChannel() after Channel.UpdateChannel() = %v, error = %v, want the new description", got, err)
+	}
+	if members, err := planning.ListMembers(); err != nil || len(members) != 2 {
+		t.Errorf("Channel.ListMembers() of a standard channel = %v, error = %v, want the 2 members of the team", members, err)
+	}
+	if _, err := planning.AddMember(member.ID, nil); err == nil {
+		t.Errorf("Channel.AddMember() to a standard channel error = nil, want error")
+	}
+
+	leads, err := team.CreateChannel(Channel{DisplayName: "Leads", MembershipType: ChannelMembershipTypePrivate}, []string{owner.ID})
+	if err != nil {
+		t.Fatalf("Team.CreateChannel() of a private channel error = %v", err)
+	}
+	membership, err := leads.AddMember(member.ID, nil)
+	if err != nil || membership.UserID != member.ID || membership.HasRole(TeamRoleOwner) {
+		t.Fatalf("Channel.AddMember() = %v, error = %v, want %v as member", membership, err, member.ID)
+	}
+	if err := leads.UpdateMemberRoles(membership.ID, []string{TeamRoleOwner}); err != nil {
+		t.Fatalf("Channel.UpdateMemberRoles() error = %v", err)
+	}
+	if members, err := leads.ListMembers(); err != nil || len(members) != 2 || len(members.Owners()) != 2 {
+		t.Errorf("Channel.ListMembers() after Channel.UpdateMemberRoles() = %v, error = %v, want 2 owners", members, err)
+	}
+	if err := leads.RemoveMember(membership.ID); err != nil {
+		t.Fatalf("Channel.RemoveMember() error = %v", err)
+	}
+	if members, err := leads.ListMembers(); err != nil || len(members) != 1 || members[0].UserID != owner.ID {
+		t.Errorf("Channel.ListMembers() after Channel.RemoveMember() = %v, error = %v, want only %v", members, err, owner.ID)
+	}
+
+	if err := planning.DeleteChannel(); err != nil {
+		t.Fatalf("Channel.DeleteChannel() error = %v", err)
+	}
+	channels, err = team.ListChannels()
+	if err != nil || len(channels) != 2 {
+		t.Errorf("Team.ListChannels() after Channel.DeleteChannel() = %v, error = %v, want General and Leads", channels, err)
+	}
+	if _, err := channels.GetByDisplayName("Planning"); err != ErrFindChannel {
+		t.Errorf("Channels.GetByDisplayName() of the deleted channel error = %v, want %v", err, ErrFindChannel)
+	}
+}
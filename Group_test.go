@@ -1,8 +1,10 @@
 package msgraph
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 )
 
 func GetTestGroup(t *testing.T) Group {
@@ -151,3 +153,124 @@ func TestGroup_GetMemberGroupsAsStrings(t *testing.T) {
 		})
 	}
 }
+
+func TestGroup_MarshalJSON(t *testing.T) {
+	created := time.Date(2021, 6, 1, 8, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		group Group
+		want  string
+	}{
+		{name: "Empty", group: Group{}, want: `{}`},
+		{name: "Security group", group: Group{DisplayName: "technicians", MailNickname: "technicians", SecurityEnabled: true},
+			want: `{"displayName":"technicians","mailNickname":"technicians","securityEnabled":true}`},
+		{name: "Read-only properties", group: Group{ID: "1", CreatedDateTime: created, GroupTypes: []string{GroupTypeUnified}, MailEnabled: true},
+			want: `{"id":"1","createdDateTime":"2021-06-01T08:30:00Z","groupTypes":["Unified"],"mailEnabled":true}`},
+		{name: "Dynamic membership", group: Group{GroupTypes: []string{GroupTypeDynamicMembership}, MembershipRule: `user.city -eq "Vienna"`, MembershipRuleProcessingState: MembershipRuleProcessingStatePaused},
+			want: `{"groupTypes":["DynamicMembership"],"membershipRule":"user.city -eq \"Vienna\"","membershipRuleProcessingState":"Paused"}`},
+		{name: "Extension properties", group: Group{DisplayName: "sales", ExtensionProperties: map[string]json.RawMessage{"extkvbmkofy_costCenter": json.RawMessage(`{"code":"CC-4711"}`)}},
+			want: `{"displayName":"sales","extkvbmkofy_costCenter":{"code":"CC-4711"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.group)
+			if err != nil {
+				t.Fatalf("Group.MarshalJSON() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Group.MarshalJSON() = %s, want %s", got, tt.want)
+			}
+			var unmarshalled Group
+			if err := json.Unmarshal(got, &unmarshalled); err != nil {
+				t.Fatalf("Group.UnmarshalJSON() error = %v", err)
+			}
+			if again, _ := json.Marshal(unmarshalled); string(again) != tt.want {
+				t.Errorf("Group.MarshalJSON() after Group.UnmarshalJSON() = %s, want %s", again, tt.want)
+			}
+		})
+	}
+}
+
+func TestGraphClient_CreateGroup(t *testing.T) {
+	owner := createUnitTestUser(t)
+	defer owner.DeleteUser()
+	member := createUnitTestUser(t)
+	defer member.DeleteUser()
+
+	if _, err := graphClient.CreateGroup(Group{DisplayName: "no mailNickname"}, nil, nil); err == nil {
+		t.Errorf("GraphClient.CreateGroup() without MailNickname error = nil, want error")
+	}
+	if _, err := graphClient.CreateGroup(Group{DisplayName: "too many", MailNickname: "too-many"}, make([]string, 10), make([]string, 11)); err == nil {
+		t.Errorf("GraphClient.CreateGroup() with 21 owners and members error = nil, want error")
+	}
+
+	rndstring := randomString(32)
+	group, err := graphClient.CreateGroup(Group{
+		DisplayName:     "go-msgraph unit-test generated group - random " + rndstring,
+		Description:     "go-msgraph unit-test",
+		MailNickname:    "go-msgraph.unit-test.generated." + rndstring,
+		SecurityEnabled: true,
+	}, []string{owner.ID}, []string{member.ID})
+	if err != nil {
+		t.Fatalf("GraphClient.CreateGroup() error = %v", err)
+	}
+	defer group.DeleteGroup()
+	if group.ID == "" || group.graphClient == nil || !group.SecurityEnabled || group.MailEnabled {
+		t.Fatalf("GraphClient.CreateGroup() = %v, want a GraphClient sourced security group", group)
+	}
+	if members, err := group.ListMembers(); err != nil || len(members) != 1 || members[0].ID != member.ID {
+		t.Errorf("Group.ListMembers() of the created group = %v, error = %v, want %v", members, err, member.ID)
+	}
+	if offlineServer != nil {
+		if owners := offlineServer.GroupOwners(group.ID); len(owners) != 1 || owners[0] != owner.ID {
+			t.Errorf("msgraphtest.Server.GroupOwners() of the created group = %v, want %v", owners, owner.ID)
+		}
+	}
+
+	if err := group.UpdateGroup(Group{Description: "go-msgraph unit-test updated"}); err != nil {
+		t.Fatalf("Group.UpdateGroup() error = %v", err)
+	}
+	got, err := graphClient.GetGroup(group.ID)
+	if err != nil || got.Description != "go-msgraph unit-test updated" || got.DisplayName != group.DisplayName {
+		t.Errorf("GraphClient.GetGroup() after Group.UpdateGroup() = %v, error = %v, want the updated description", got, err)
+	}
+
+	if err := group.DeleteGroup(); err != nil {
+		t.Fatalf("Group.DeleteGroup() error = %v", err)
+	}
+	if _, err := graphClient.GetGroup(group.ID); err == nil {
+		t.Errorf("GraphClient.GetGroup() of the deleted group error = nil, want error")
+	}
+	if err := (Group{}).DeleteGroup(); err != ErrNotGraphClientSourced {
+		t.Errorf("Group.DeleteGroup() without GraphClient error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+}
+
+func TestGraphClient_CreateGroup_unified(t *testing.T) {
+	if offlineServer == nil {
+		t.Skip("Microsoft 365 groups are only deleted permanently after 30 days, only tested offline")
+	}
+	group, err := graphClient.CreateGroup(Group{
+		DisplayName:  "go-msgraph unit-test Microsoft 365 group",
+		MailNickname: "go-msgraph.unit-test.unified",
+		GroupTypes:   []string{GroupTypeUnified},
+		MailEnabled:  true,
+		Visibility:   GroupVisibilityPrivate,
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("GraphClient.CreateGroup() error = %v", err)
+	}
+	if group.Mail == "" || group.Visibility != GroupVisibilityPrivate || group.SecurityEnabled {
+		t.Errorf("GraphClient.CreateGroup() = %v, want a private, mail-enabled Microsoft 365 group", group)
+	}
+	if err := group.DeleteGroup(); err != nil {
+		t.Fatalf("Group.DeleteGroup() error = %v", err)
+	}
+	deleted, err := graphClient.ListDeletedGroups()
+	if err != nil || len(deleted) != 1 || deleted[0].ID != group.ID {
+		t.Fatalf("GraphClient.ListDeletedGroups() = %v, error = %v, want the deleted Microsoft 365 group", deleted, err)
+	}
+	if err := graphClient.PermanentlyDeleteItem(group.ID); err != nil {
+		t.Errorf("GraphClient.PermanentlyDeleteItem() error = %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package msgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Invitation represents an invitation of an external user to the organization, see GraphClient.InviteGuest.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/invitation
+type Invitation struct {
+	ID                      string                  `json:"id,omitempty"`                     // read-only
+	InvitedUserDisplayName  string                  `json:"invitedUserDisplayName,omitempty"` // display name of the guest, defaults to the email address
+	InvitedUserEmailAddress string                  `json:"invitedUserEmailAddress"`          // required
+	InvitedUserType         string                  `json:"invitedUserType,omitempty"`        // Guest (default) or Member
+	InviteRedirectURL       string                  `json:"inviteRedirectUrl"`                // required, the URL the guest is redirected to after redeeming the invitation
+	InviteRedeemURL         string                  `json:"inviteRedeemUrl,omitempty"`        // read-only, the URL the guest can use to redeem the invitation
+	SendInvitationMessage   bool                    `json:"sendInvitationMessage"`            // send an invitation email to the guest
+	InvitedUserMessageInfo  *InvitedUserMessageInfo `json:"invitedUserMessageInfo,omitempty"` // customizes the invitation email
+	ResetRedemption         bool                    `json:"resetRedemption,omitempty"`        // reset the redemption status of an existing guest
+	Status                  string                  `json:"status,omitempty"`                 // read-only, PendingAcceptance, Completed, InProgress or Error
+	InvitedUser             *User                   `json:"invitedUser,omitempty"`            // read-only, the created guest
+}
+
+func (i Invitation) String() string {
+	invitedUserID := ""
+	if i.InvitedUser != nil {
+		invitedUserID = i.InvitedUser.ID
+	}
+	return fmt.Sprintf("Invitation(ID: \"%v\", InvitedUserDisplayName: \"%v\", InvitedUserEmailAddress: \"%v\", InvitedUserType: \"%v\", "+
+		"InviteRedirectURL: \"%v\", InviteRedeemURL: \"%v\", SendInvitationMessage: %v, Status: \"%v\", InvitedUserID: \"%v\")",
+		i.ID, i.InvitedUserDisplayName, i.InvitedUserEmailAddress, i.InvitedUserType, i.InviteRedirectURL, i.InviteRedeemURL,
+		i.SendInvitationMessage, i.Status, invitedUserID)
+}
+
+// setGraphClient sets the graphClient instance in this instance and all child-instances (if any)
+func (i *Invitation) setGraphClient(gC *GraphClient) {
+	if i.InvitedUser != nil {
+		i.InvitedUser.setGraphClient(gC)
+	}
+}
+
+// InvitedUserMessageInfo customizes the invitation email of an Invitation.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/invitedusermessageinfo
+type InvitedUserMessageInfo struct {
+	CustomizedMessageBody string `json:"customizedMessageBody,omitempty"`
+	MessageLanguage       string `json:"messageLanguage,omitempty"` // e.g. en-US, defaults to en-US
+}
+
+// InviteGuest invites the external user to the organization. InvitedUserEmailAddress and
+// InviteRedirectURL of the invitation are required. The guest is created immediately, it is
+// returned as InvitedUser of the created Invitation and can be e.g. added to groups before the
+// invitation has been redeemed. Inviting an existing guest again returns the existing guest.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/invitation-post
+func (g *GraphClient) InviteGuest(invitation Invitation, opts ...CreateQueryOption) (Invitation, error) {
+	if invitation.InvitedUserEmailAddress == "" || invitation.InviteRedirectURL == "" {
+		return Invitation{}, fmt.Errorf("invitedUserEmailAddress and inviteRedirectUrl of the invitation are required")
+	}
+	invitation.ID, invitation.InviteRedeemURL, invitation.Status, invitation.InvitedUser = "", "", "", nil
+
+	bodyBytes, err := json.Marshal(invitation)
+	if err != nil {
+		return Invitation{}, err
+	}
+
+	reader := bytes.NewReader(bodyBytes)
+	var created Invitation
+	err = g.makePOSTAPICall("/invitations", compileCreateQueryOptions(opts), reader, &created)
+	created.setGraphClient(g)
+	return created, err
+}
+
+// unredeemedGuestProperties are the properties of a User that Users.UnredeemedGuests evaluates.
+const unredeemedGuestProperties = "userType,createdDateTime,externalUserState,externalUserStateChangeDateTime"
+
+// ListUnredeemedGuests returns all guests that have not redeemed their invitation yet and have
+// been invited before invitedBefore, see Users.UnredeemedGuests. Use a zero invitedBefore to
+// get all unredeemed guests.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters,
+// a $filter is combined with the filter of the unredeemed guests by "and" and the properties
+// required to filter the guests are always added to a $select.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/user-list
+func (g *GraphClient) ListUnredeemedGuests(invitedBefore time.Time, opts ...ListQueryOption) (Users, error) {
+	reqOpt := compileListQueryOptions(opts)
+	filter := "userType eq 'Guest' and externalUserState eq 'PendingAcceptance'"
+	if callerFilter := reqOpt.queryValues.Get(odataFilterParamKey); callerFilter != "" {
+		filter = fmt.Sprintf("(%v) and %v", callerFilter, filter)
+	}
+	reqOpt.queryValues.Set(odataFilterParamKey, filter)
+	if selected := reqOpt.queryValues[odataSelectParamKey]; len(selected) == 0 {
+		reqOpt.queryValues.Set(odataSelectParamKey, "id,displayName,mail,userPrincipalName,"+unredeemedGuestProperties)
+	} else {
+		// the properties of Users.UnredeemedGuests are always selected, as the guests are filtered by them
+		reqOpt.queryValues.Set(odataSelectParamKey, strings.Join(selected, ",")+","+unredeemedGuestProperties)
+	}
+
+	var marsh struct {
+		Users Users `json:"value"`
+	}
+	if err := g.makeGETAPICall("/users", reqOpt, &marsh); err != nil {
+		return nil, err
+	}
+	marsh.Users.setGraphClient(g)
+	return marsh.Users.UnredeemedGuests(invitedBefore), nil
+}
+
+// DeleteUnredeemedGuests deletes all guests that have not redeemed their invitation and have
+// been invited before invitedBefore, e.g. time.Now().AddDate(0, -3, 0) to clean up invitations
+// older than three months. Deleted guests can be restored for 30 days. All guests are deleted
+// even if deleting some of them fails, the guests that have been deleted - or planned to be
+// deleted in dry-run mode - are returned together with an error summarizing the failures.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/user-delete
+func (g *GraphClient) DeleteUnredeemedGuests(invitedBefore time.Time, opts ...DeleteQueryOption) (Users, error) {
+	if invitedBefore.IsZero() {
+		return nil, fmt.Errorf("invitedBefore must not be zero")
+	}
+	guests, err := g.ListUnredeemedGuests(invitedBefore, ListWithContext(compileDeleteQueryOptions(opts).Context()))
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted Users
+	var failures []string
+	for _, guest := range guests {
+		if err := guest.DeleteUser(opts...); err != nil {
+			failures = append(failures, fmt.Sprintf("%v: %v", guest.UserPrincipalName, err))
+			continue
+		}
+		deleted = append(deleted, guest)
+	}
+	if len(failures) > 0 {
+		return deleted, fmt.Errorf("cannot delete %v of %v unredeemed guests: %v", len(failures), len(guests), strings.Join(failures, "; "))
+	}
+	return deleted, nil
+}
@@ -0,0 +1,105 @@
+package msgraph
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestGraphClient_InviteGuest(t *testing.T) {
+	if _, err := graphClient.InviteGuest(Invitation{InvitedUserEmailAddress: "guest@example.com"}); err == nil {
+		t.Errorf("GraphClient.InviteGuest() without InviteRedirectURL error = nil, want error")
+	}
+
+	email := "go-msgraph.unit-test.generated." + randomString(32) + "@example.com"
+	invitation, err := graphClient.InviteGuest(Invitation{
+		InvitedUserEmailAddress: email,
+		InvitedUserDisplayName:  "go-msgraph unit-test guest",
+		InviteRedirectURL:       "https://myapps.microsoft.com",
+		InvitedUserMessageInfo:  &InvitedUserMessageInfo{CustomizedMessageBody: "Welcome to the partner portal."},
+	})
+	if err != nil {
+		t.Fatalf("GraphClient.InviteGuest() error = %v", err)
+	}
+	if invitation.InvitedUser == nil || invitation.InvitedUser.ID == "" || invitation.InviteRedeemURL == "" || invitation.Status != "PendingAcceptance" {
+		t.Fatalf("GraphClient.InviteGuest() = %v, want a pending invitation with the invited user", invitation)
+	}
+	guest := *invitation.InvitedUser
+	defer guest.DeleteUser()
+
+	got, err := guest.graphClient.GetUser(guest.ID, GetWithSelect("id,mail,userType,externalUserState"))
+	if err != nil {
+		t.Fatalf("GraphClient.GetUser() of the guest error = %v", err)
+	}
+	if got.UserType != "Guest" || got.ExternalUserState != "PendingAcceptance" {
+		t.Errorf("GraphClient.GetUser() of the guest = %v, want a guest pending acceptance", got)
+	}
+
+	guests, err := graphClient.ListUnredeemedGuests(time.Time{})
+	if err != nil {
+		t.Fatalf("GraphClient.ListUnredeemedGuests() error = %v", err)
+	}
+	if _, err := guests.GetUserByMail(email); err != nil {
+		t.Errorf("GraphClient.ListUnredeemedGuests() = %v, want the invited guest %v", guests, email)
+	}
+	filtered, err := graphClient.ListUnredeemedGuests(time.Time{}, ListWithFilter(fmt.Sprintf("mail eq '%v'", email)))
+	if err != nil {
+		t.Fatalf("GraphClient.ListUnredeemedGuests() with filter error = %v", err)
+	}
+	if len(filtered) != 1 || filtered[0].Mail != email {
+		t.Errorf("GraphClient.ListUnredeemedGuests() with filter = %v, want only %v", filtered, email)
+	}
+	selected, err := graphClient.ListUnredeemedGuests(time.Time{}, ListWithFilter(fmt.Sprintf("mail eq '%v'", email)), ListWithSelect("id,displayName"))
+	if err != nil {
+		t.Fatalf("GraphClient.ListUnredeemedGuests() with select error = %v", err)
+	}
+	if len(selected) != 1 || selected[0].ID != guest.ID || selected[0].UserType != "Guest" {
+		t.Errorf("GraphClient.ListUnredeemedGuests() with select = %v, want only %v", selected, guest.ID)
+	}
+	if guests, _ := graphClient.ListUnredeemedGuests(time.Now().AddDate(0, 0, -1)); len(guests.UnredeemedGuests(time.Time{})) != len(guests) {
+		t.Errorf("GraphClient.ListUnredeemedGuests() returned redeemed users: %v", guests)
+	}
+	if _, err := graphClient.DeleteUnredeemedGuests(time.Time{}); err == nil {
+		t.Errorf("GraphClient.DeleteUnredeemedGuests() with zero invitedBefore error = nil, want error")
+	}
+}
+
+func TestGraphClient_DeleteUnredeemedGuests(t *testing.T) {
+	if offlineServer == nil {
+		t.Skip("the unit tests must not delete the unredeemed guests of the tenant, only tested offline")
+	}
+	old := time.Now().AddDate(0, -6, 0).UTC().Format(time.RFC3339)
+	stale := offlineServer.AddUser(map[string]interface{}{"displayName": "stale", "userPrincipalName": "stale_example.com#EXT#@contoso.com", "mail": "stale@example.com",
+		"userType": "Guest", "externalUserState": "PendingAcceptance", "externalUserStateChangeDateTime": old})
+	redeemed := offlineServer.AddUser(map[string]interface{}{"displayName": "redeemed", "userPrincipalName": "redeemed_example.com#EXT#@contoso.com", "mail": "redeemed@example.com",
+		"userType": "Guest", "externalUserState": "PendingAcceptance", "externalUserStateChangeDateTime": old})
+	offlineServer.RedeemInvitation(redeemed.ID())
+	invitation, err := graphClient.InviteGuest(Invitation{InvitedUserEmailAddress: "recent@example.com", InviteRedirectURL: "https://myapps.microsoft.com"})
+	if err != nil {
+		t.Fatalf("GraphClient.InviteGuest() error = %v", err)
+	}
+	defer invitation.InvitedUser.DeleteUser()
+
+	invitedBefore := time.Now().AddDate(0, -3, 0)
+	graphClient.ResetPlan()
+	planned, err := graphClient.DeleteUnredeemedGuests(invitedBefore, DeleteWithDryRun())
+	if err != nil || len(planned) != 1 || planned[0].ID != stale.ID() || len(graphClient.Plan()) != 1 {
+		t.Fatalf("GraphClient.DeleteUnredeemedGuests() with dry-run = %v, error = %v, want %v planned", planned, err, stale.ID())
+	}
+	graphClient.ResetPlan()
+
+	deleted, err := graphClient.DeleteUnredeemedGuests(invitedBefore)
+	if err != nil || len(deleted) != 1 || deleted[0].ID != stale.ID() {
+		t.Fatalf("GraphClient.DeleteUnredeemedGuests() = %v, error = %v, want %v", deleted, err, stale.ID())
+	}
+	if _, err := graphClient.GetUser(stale.ID()); err == nil {
+		t.Errorf("GraphClient.DeleteUnredeemedGuests() did not delete %v", stale.ID())
+	}
+	for _, id := range []string{redeemed.ID(), invitation.InvitedUser.ID} {
+		if user, err := graphClient.GetUser(id); err != nil {
+			t.Errorf("GraphClient.DeleteUnredeemedGuests() deleted %v: %v", id, err)
+		} else if id == redeemed.ID() {
+			user.DeleteUser()
+		}
+	}
+}
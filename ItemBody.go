@@ -0,0 +1,21 @@
+package msgraph
+
+import "fmt"
+
+// Content types of an ItemBody.
+const (
+	BodyContentTypeText = "text"
+	BodyContentTypeHTML = "html"
+)
+
+// ItemBody represents the body of an item, e.g. of a Post.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/itembody
+type ItemBody struct {
+	ContentType string `json:"contentType"` // BodyContentTypeText or BodyContentTypeHTML
+	Content     string `json:"content"`
+}
+
+func (i ItemBody) String() string {
+	return fmt.Sprintf("ItemBody(ContentType: \"%v\", Content: %v characters)", i.ContentType, len(i.Content))
+}
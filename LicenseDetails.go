@@ -0,0 +1,27 @@
+package msgraph
+
+import (
+	"fmt"
+)
+
+// LicenseDetails represents a license assigned to a user, including the provisioning status
+// of its service plans.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/licensedetails
+type LicenseDetails struct {
+	ID            string            `json:"id"`
+	SkuID         string            `json:"skuId"`
+	SkuPartNumber string            `json:"skuPartNumber"` // e.g. ENTERPRISEPACK
+	ServicePlans  []ServicePlanInfo `json:"servicePlans"`
+
+	graphClient *GraphClient // the graphClient that listed the license details
+}
+
+func (l LicenseDetails) String() string {
+	return fmt.Sprintf("LicenseDetails(SkuID: \"%v\", SkuPartNumber: \"%v\", ServicePlans: %v)", l.SkuID, l.SkuPartNumber, len(l.ServicePlans))
+}
+
+// setGraphClient sets the graphClient instance in this instance and all child-instances (if any)
+func (l *LicenseDetails) setGraphClient(gC *GraphClient) {
+	l.graphClient = gC
+}
@@ -0,0 +1,35 @@
+package msgraph
+
+import (
+	"strings"
+)
+
+// LicenseDetailsList represents multiple LicenseDetails-instances and provides funcs to work with them.
+type LicenseDetailsList []LicenseDetails
+
+func (l LicenseDetailsList) String() string {
+	var details = make([]string, len(l))
+	for i, d := range l {
+		details[i] = d.String()
+	}
+	return "LicenseDetailsList(" + strings.Join(details, " | ") + ")"
+}
+
+// setGraphClient sets the GraphClient within that particular instance. Hence it's directly created by GraphClient
+func (l LicenseDetailsList) setGraphClient(gC *GraphClient) LicenseDetailsList {
+	for i := range l {
+		l[i].setGraphClient(gC)
+	}
+	return l
+}
+
+// GetBySkuPartNumber returns the LicenseDetails with the given SkuPartNumber, e.g. ENTERPRISEPACK,
+// compared case-insensitively. Returns ErrFindLicenseDetails if there is none.
+func (l LicenseDetailsList) GetBySkuPartNumber(skuPartNumber string) (LicenseDetails, error) {
+	for _, d := range l {
+		if strings.EqualFold(d.SkuPartNumber, skuPartNumber) {
+			return d, nil
+		}
+	}
+	return LicenseDetails{}, ErrFindLicenseDetails
+}
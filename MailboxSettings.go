@@ -0,0 +1,155 @@
+package msgraph
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Status of the AutomaticRepliesSetting.
+const (
+	AutomaticRepliesDisabled      = "disabled"
+	AutomaticRepliesAlwaysEnabled = "alwaysEnabled"
+	AutomaticRepliesScheduled     = "scheduled"
+)
+
+// ExternalAudience of the AutomaticRepliesSetting.
+const (
+	ExternalAudienceNone         = "none"
+	ExternalAudienceContactsOnly = "contactsOnly"
+	ExternalAudienceAll          = "all"
+)
+
+// MailboxSettings represents the settings of the primary mailbox of a user, see
+// User.GetMailboxSettings and User.UpdateMailboxSettings.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/mailboxsettings
+type MailboxSettings struct {
+	AutomaticRepliesSetting               AutomaticRepliesSetting `json:"automaticRepliesSetting"`
+	WorkingHours                          WorkingHours            `json:"workingHours"`
+	TimeZone                              string                  `json:"timeZone"`   // e.g. "W. Europe Standard Time" or "Europe/Berlin"
+	Language                              LocaleInfo              `json:"language"`   // preferred language of the mailbox
+	DateFormat                            string                  `json:"dateFormat"` // e.g. "dd.MM.yyyy"
+	TimeFormat                            string                  `json:"timeFormat"` // e.g. "HH:mm"
+	DelegateMeetingMessageDeliveryOptions string                  `json:"delegateMeetingMessageDeliveryOptions"`
+	ArchiveFolder                         string                  `json:"archiveFolder"`
+	UserPurpose                           string                  `json:"userPurpose"` // read-only, e.g. "user" or "shared"
+}
+
+func (m MailboxSettings) String() string {
+	return fmt.Sprintf("MailboxSettings(AutomaticRepliesSetting: %v, WorkingHours: %v, TimeZone: \"%v\", Language: \"%v\", "+
+		"DateFormat: \"%v\", TimeFormat: \"%v\")",
+		m.AutomaticRepliesSetting, m.WorkingHours, m.TimeZone, m.Language.Locale, m.DateFormat, m.TimeFormat)
+}
+
+// AutomaticRepliesSetting configures the automatic replies, also known as out-of-office
+// messages, of a mailbox.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/automaticrepliessetting
+type AutomaticRepliesSetting struct {
+	Status                 string            `json:"status,omitempty"`                 // one of the AutomaticReplies* constants
+	ExternalAudience       string            `json:"externalAudience,omitempty"`       // one of the ExternalAudience* constants
+	ScheduledStartDateTime *DateTimeTimeZone `json:"scheduledStartDateTime,omitempty"` // required if Status is AutomaticRepliesScheduled
+	ScheduledEndDateTime   *DateTimeTimeZone `json:"scheduledEndDateTime,omitempty"`   // required if Status is AutomaticRepliesScheduled
+	InternalReplyMessage   string            `json:"internalReplyMessage"`             // HTML or plain text for senders of the organization
+	ExternalReplyMessage   string            `json:"externalReplyMessage"`             // HTML or plain text for senders of the ExternalAudience
+}
+
+func (a AutomaticRepliesSetting) String() string {
+	return fmt.Sprintf("AutomaticRepliesSetting(Status: \"%v\", ExternalAudience: \"%v\", ScheduledStartDateTime: %v, ScheduledEndDateTime: %v)",
+		a.Status, a.ExternalAudience, a.ScheduledStartDateTime, a.ScheduledEndDateTime)
+}
+
+// IsActive returns true if the automatic replies are sent at the given time, hence they are
+// always enabled or scheduled for a period that contains t.
+func (a AutomaticRepliesSetting) IsActive(t time.Time) bool {
+	switch a.Status {
+	case AutomaticRepliesAlwaysEnabled:
+		return true
+	case AutomaticRepliesScheduled:
+		if a.ScheduledStartDateTime == nil || a.ScheduledEndDateTime == nil {
+			return false
+		}
+		start, err := a.ScheduledStartDateTime.Time()
+		if err != nil {
+			return false
+		}
+		end, err := a.ScheduledEndDateTime.Time()
+		if err != nil {
+			return false
+		}
+		return !t.Before(start) && t.Before(end)
+	}
+	return false
+}
+
+// DateTimeTimeZone represents a point in time in the given time zone as used by Microsoft
+// Graph, e.g. for the scheduled automatic replies.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/datetimetimezone
+type DateTimeTimeZone struct {
+	DateTime string `json:"dateTime"` // without offset, e.g. "2021-03-01T08:00:00.0000000"
+	TimeZone string `json:"timeZone"` // IANA or Windows name, e.g. "UTC" or "W. Europe Standard Time"
+}
+
+// NewDateTimeTimeZone returns the DateTimeTimeZone of the time in UTC.
+func NewDateTimeTimeZone(t time.Time) *DateTimeTimeZone {
+	return &DateTimeTimeZone{DateTime: t.UTC().Format("2006-01-02T15:04:05.0000000"), TimeZone: "UTC"}
+}
+
+func (d DateTimeTimeZone) String() string {
+	return fmt.Sprintf("%v %v", d.DateTime, d.TimeZone)
+}
+
+// Time returns the DateTimeTimeZone as time.Time. Windows time zone names, e.g. "W. Europe
+// Standard Time", are mapped to IANA time zones.
+func (d DateTimeTimeZone) Time() (time.Time, error) {
+	location, err := time.LoadLocation(d.TimeZone)
+	if err != nil {
+		if location, err = mapTimeZoneStrings(d.TimeZone); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return time.ParseInLocation("2006-01-02T15:04:05.999999999", d.DateTime, location)
+}
+
+// WorkingHours are the days of the week and the hours of the day a user works, e.g. used to
+// suggest meeting times.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/workinghours
+type WorkingHours struct {
+	DaysOfWeek []string     `json:"daysOfWeek"` // lower-case English weekdays, e.g. "monday"
+	StartTime  string       `json:"startTime"`  // e.g. "08:00:00.0000000"
+	EndTime    string       `json:"endTime"`    // e.g. "17:00:00.0000000"
+	TimeZone   TimeZoneBase `json:"timeZone"`
+}
+
+func (w WorkingHours) String() string {
+	return fmt.Sprintf("WorkingHours(DaysOfWeek: [%v], StartTime: \"%v\", EndTime: \"%v\", TimeZone: \"%v\")",
+		strings.Join(w.DaysOfWeek, ", "), w.StartTime, w.EndTime, w.TimeZone.Name)
+}
+
+// IsWorkingDay returns true if the weekday is one of the DaysOfWeek.
+func (w WorkingHours) IsWorkingDay(weekday time.Weekday) bool {
+	for _, day := range w.DaysOfWeek {
+		if strings.EqualFold(day, weekday.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+// TimeZoneBase represents a time zone by its name, e.g. "Pacific Standard Time".
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/timezonebase
+type TimeZoneBase struct {
+	Name string `json:"name"`
+}
+
+// LocaleInfo represents a locale, e.g. the language of a mailbox.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/localeinfo
+type LocaleInfo struct {
+	Locale      string `json:"locale"`                // e.g. "en-US"
+	DisplayName string `json:"displayName,omitempty"` // read-only, e.g. "English (United States)"
+}
@@ -0,0 +1,110 @@
+package msgraph
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// MailboxSettingsPatch is a builder for the mailbox settings to update with
+// User.UpdateMailboxSettings. Like the UserPatch it tracks which settings have been set
+// explicitly and sends exactly those - including empty and null values. Settings that are
+// not set keep their current value.
+//
+// Example:
+//
+//	patch := msgraph.NewMailboxSettingsPatch().
+//		ScheduleAutomaticReplies(start, end, "I am on vacation.", "I am on vacation.").
+//		SetTimeZone("W. Europe Standard Time")
+//	err := user.UpdateMailboxSettings(patch)
+type MailboxSettingsPatch struct {
+	patch
+}
+
+// NewMailboxSettingsPatch returns a new, empty MailboxSettingsPatch.
+func NewMailboxSettingsPatch() *MailboxSettingsPatch {
+	return &MailboxSettingsPatch{patch: newPatch("MailboxSettings", mailboxSettingsProperties)}
+}
+
+// mailboxSettingsProperties are the json names of all mailbox settings that can be updated.
+var mailboxSettingsProperties = jsonPropertyNames(reflect.TypeOf(MailboxSettings{}), "userPurpose")
+
+// Set sets the setting with the given json name, e.g. "dateFormat", to the value. A nil value
+// clears the setting, see Clear. Setting an unknown or read-only setting fails the
+// MailboxSettingsPatch with an error.
+func (p *MailboxSettingsPatch) Set(property string, value interface{}) *MailboxSettingsPatch {
+	p.set(property, value)
+	return p
+}
+
+// Clear sets the setting with the given json name to null, e.g. Clear("archiveFolder").
+func (p *MailboxSettingsPatch) Clear(property string) *MailboxSettingsPatch {
+	return p.Set(property, nil)
+}
+
+// SetAutomaticRepliesSetting sets automaticRepliesSetting. Both reply messages are always
+// sent, an empty message removes the current one.
+func (p *MailboxSettingsPatch) SetAutomaticRepliesSetting(setting AutomaticRepliesSetting) *MailboxSettingsPatch {
+	return p.Set("automaticRepliesSetting", setting)
+}
+
+// EnableAutomaticReplies enables the automatic replies until they are disabled again. The
+// externalMessage is sent to all external senders.
+func (p *MailboxSettingsPatch) EnableAutomaticReplies(internalMessage, externalMessage string) *MailboxSettingsPatch {
+	return p.SetAutomaticRepliesSetting(AutomaticRepliesSetting{
+		Status:               AutomaticRepliesAlwaysEnabled,
+		ExternalAudience:     ExternalAudienceAll,
+		InternalReplyMessage: internalMessage,
+		ExternalReplyMessage: externalMessage,
+	})
+}
+
+// ScheduleAutomaticReplies enables the automatic replies from start until end. The
+// externalMessage is sent to all external senders.
+func (p *MailboxSettingsPatch) ScheduleAutomaticReplies(start, end time.Time, internalMessage, externalMessage string) *MailboxSettingsPatch {
+	if !end.After(start) {
+		p.fail(fmt.Errorf("end %v of the automatic replies must be after start %v", end, start))
+		return p
+	}
+	return p.SetAutomaticRepliesSetting(AutomaticRepliesSetting{
+		Status:                 AutomaticRepliesScheduled,
+		ExternalAudience:       ExternalAudienceAll,
+		ScheduledStartDateTime: NewDateTimeTimeZone(start),
+		ScheduledEndDateTime:   NewDateTimeTimeZone(end),
+		InternalReplyMessage:   internalMessage,
+		ExternalReplyMessage:   externalMessage,
+	})
+}
+
+// DisableAutomaticReplies disables the automatic replies, the reply messages are kept.
+func (p *MailboxSettingsPatch) DisableAutomaticReplies() *MailboxSettingsPatch {
+	return p.Set("automaticRepliesSetting", map[string]string{"status": AutomaticRepliesDisabled})
+}
+
+// SetWorkingHours sets workingHours.
+func (p *MailboxSettingsPatch) SetWorkingHours(workingHours WorkingHours) *MailboxSettingsPatch {
+	if workingHours.DaysOfWeek == nil {
+		workingHours.DaysOfWeek = []string{}
+	}
+	return p.Set("workingHours", workingHours)
+}
+
+// SetTimeZone sets timeZone, e.g. "W. Europe Standard Time" or "Europe/Berlin".
+func (p *MailboxSettingsPatch) SetTimeZone(timeZone string) *MailboxSettingsPatch {
+	return p.Set("timeZone", timeZone)
+}
+
+// SetLanguage sets the locale of language, e.g. "en-US".
+func (p *MailboxSettingsPatch) SetLanguage(locale string) *MailboxSettingsPatch {
+	return p.Set("language", LocaleInfo{Locale: locale})
+}
+
+// SetDateFormat sets dateFormat, e.g. "dd.MM.yyyy".
+func (p *MailboxSettingsPatch) SetDateFormat(dateFormat string) *MailboxSettingsPatch {
+	return p.Set("dateFormat", dateFormat)
+}
+
+// SetTimeFormat sets timeFormat, e.g. "HH:mm".
+func (p *MailboxSettingsPatch) SetTimeFormat(timeFormat string) *MailboxSettingsPatch {
+	return p.Set("timeFormat", timeFormat)
+}
@@ -0,0 +1,82 @@
+package msgraph
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMailboxSettingsPatch_MarshalJSON(t *testing.T) {
+	start := time.Date(2021, 7, 1, 8, 0, 0, 0, time.UTC)
+	end := time.Date(2021, 7, 15, 17, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		patch   *MailboxSettingsPatch
+		want    string
+		wantErr bool
+	}{
+		{name: "Empty", patch: NewMailboxSettingsPatch(), want: `{}`},
+		{name: "Enable automatic replies with empty external message", patch: NewMailboxSettingsPatch().EnableAutomaticReplies("Out of office", ""),
+			want: `{"automaticRepliesSetting":{"status":"alwaysEnabled","externalAudience":"all","internalReplyMessage":"Out of office","externalReplyMessage":""}}`},
+		{name: "Schedule automatic replies", patch: NewMailboxSettingsPatch().ScheduleAutomaticReplies(start, end, "Vacation", "Vacation"),
+			want: `{"automaticRepliesSetting":{"status":"scheduled","externalAudience":"all",` +
+				`"scheduledStartDateTime":{"dateTime":"2021-07-01T08:00:00.0000000","timeZone":"UTC"},` +
+				`"scheduledEndDateTime":{"dateTime":"2021-07-15T17:00:00.0000000","timeZone":"UTC"},` +
+				`"internalReplyMessage":"Vacation","externalReplyMessage":"Vacation"}}`},
+		{name: "Disable automatic replies", patch: NewMailboxSettingsPatch().DisableAutomaticReplies(),
+			want: `{"automaticRepliesSetting":{"status":"disabled"}}`},
+		{name: "Null and empty values", patch: NewMailboxSettingsPatch().Clear("archiveFolder").SetDateFormat(""),
+			want: `{"archiveFolder":null,"dateFormat":""}`},
+		{name: "Nested values", patch: NewMailboxSettingsPatch().SetLanguage("de-AT").SetWorkingHours(WorkingHours{StartTime: "09:00:00.0000000", EndTime: "18:00:00.0000000", TimeZone: TimeZoneBase{Name: "UTC"}}),
+			want: `{"language":{"locale":"de-AT"},"workingHours":{"daysOfWeek":[],"startTime":"09:00:00.0000000","endTime":"18:00:00.0000000","timeZone":{"name":"UTC"}}}`},
+		{name: "End before start", patch: NewMailboxSettingsPatch().ScheduleAutomaticReplies(end, start, "Vacation", "Vacation"), wantErr: true},
+		{name: "Read-only property", patch: NewMailboxSettingsPatch().Set("userPurpose", "shared"), wantErr: true},
+		{name: "Unknown property", patch: NewMailboxSettingsPatch().SetTimeZone("UTC").Set("timezone", "typo"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.patch.MarshalJSON()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MailboxSettingsPatch.MarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("MailboxSettingsPatch.MarshalJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAutomaticRepliesSetting_IsActive(t *testing.T) {
+	start := time.Date(2021, 7, 1, 8, 0, 0, 0, time.UTC)
+	end := time.Date(2021, 7, 15, 17, 0, 0, 0, time.UTC)
+	scheduled := AutomaticRepliesSetting{Status: AutomaticRepliesScheduled, ScheduledStartDateTime: NewDateTimeTimeZone(start), ScheduledEndDateTime: NewDateTimeTimeZone(end)}
+	// 08:00 to 17:00 UTC in W. Europe Standard Time (UTC+2 in summer), as returned for a user's time zone
+	windows := AutomaticRepliesSetting{Status: AutomaticRepliesScheduled,
+		ScheduledStartDateTime: &DateTimeTimeZone{DateTime: "2021-07-01T10:00:00.0000000", TimeZone: "W. Europe Standard Time"},
+		ScheduledEndDateTime:   &DateTimeTimeZone{DateTime: "2021-07-15T19:00:00.0000000", TimeZone: "W. Europe Standard Time"}}
+	defer func(loaded supportedTimeZones) { globalSupportedTimeZones = loaded }(globalSupportedTimeZones)
+	globalSupportedTimeZones = supportedTimeZones{}
+	tests := []struct {
+		name    string
+		setting AutomaticRepliesSetting
+		t       time.Time
+		want    bool
+	}{
+		{name: "Disabled", setting: AutomaticRepliesSetting{Status: AutomaticRepliesDisabled}, t: start, want: false},
+		{name: "Always enabled", setting: AutomaticRepliesSetting{Status: AutomaticRepliesAlwaysEnabled}, t: start, want: true},
+		{name: "Scheduled at start", setting: scheduled, t: start, want: true},
+		{name: "Scheduled in another time zone", setting: scheduled, t: start.In(time.FixedZone("UTC+2", 2*60*60)).Add(time.Hour), want: true},
+		{name: "Scheduled at end", setting: scheduled, t: end, want: false},
+		{name: "Scheduled before start", setting: scheduled, t: start.Add(-time.Minute), want: false},
+		{name: "Scheduled in Windows time zone at start", setting: windows, t: start, want: true},
+		{name: "Scheduled in Windows time zone before start", setting: windows, t: start.Add(-time.Minute), want: false},
+		{name: "Scheduled in Windows time zone at end", setting: windows, t: end, want: false},
+		{name: "Scheduled without period", setting: AutomaticRepliesSetting{Status: AutomaticRepliesScheduled}, t: start, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.setting.IsActive(tt.t); got != tt.want {
+				t.Errorf("AutomaticRepliesSetting.IsActive() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package msgraph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCheckMemberIDsPerRequest is the maximum number of IDs Microsoft Graph checks with one
+// checkMemberGroups or checkMemberObjects request.
+const maxCheckMemberIDsPerRequest = 20
+
+// CheckMemberGroups returns the IDs of the given groups the user is a direct or transitive
+// member of. Unlike GetMemberGroupsAsStrings only the given groups are returned, hence it is
+// well suited for authorization checks. The groups are checked with one request per 20 groups.
+//
+// opts ...GetQueryOption - only msgraph.GetWithContext is supported.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/directoryobject-checkmembergroups
+func (u User) CheckMemberGroups(groupIDs []string, opts ...GetQueryOption) ([]string, error) {
+	if u.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	return u.graphClient.checkMemberGroups(u.ID, groupIDs, opts...)
+}
+
+// CheckMemberObjects returns the IDs of the given groups, directory roles and administrative
+// units the user is a direct or transitive member of. The objects are checked with one
+// request per 20 objects.
+//
+// opts ...GetQueryOption - only msgraph.GetWithContext is supported.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/directoryobject-checkmemberobjects
+func (u User) CheckMemberObjects(ids []string, opts ...GetQueryOption) ([]string, error) {
+	if u.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	return u.graphClient.checkMemberObjects(u.ID, ids, opts...)
+}
+
+// CheckMemberGroups returns the IDs of the given groups the group is a direct or transitive
+// member of, see User.CheckMemberGroups.
+//
+// opts ...GetQueryOption - only msgraph.GetWithContext is supported.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/directoryobject-checkmembergroups
+func (g Group) CheckMemberGroups(groupIDs []string, opts ...GetQueryOption) ([]string, error) {
+	if g.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	return g.graphClient.checkMemberGroups(g.ID, groupIDs, opts...)
+}
+
+// checkMemberGroups returns the IDs of the given groups the directory object with the given ID
+// is a member of.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/directoryobject-checkmembergroups
+func (g *GraphClient) checkMemberGroups(identifier string, groupIDs []string, opts ...GetQueryOption) ([]string, error) {
+	return g.checkMember(identifier, "checkMemberGroups", "groupIds", groupIDs, opts)
+}
+
+// checkMemberObjects returns the IDs of the given groups, directory roles and administrative
+// units the directory object with the given ID is a member of.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/directoryobject-checkmemberobjects
+func (g *GraphClient) checkMemberObjects(identifier string, ids []string, opts ...GetQueryOption) ([]string, error) {
+	return g.checkMember(identifier, "checkMemberObjects", "ids", ids, opts)
+}
+
+// checkMember performs the check action, hence checkMemberGroups or checkMemberObjects, with
+// the IDs in the given property of the request body. The IDs are checked in batches of
+// maxCheckMemberIDsPerRequest, no API-call is made for an empty list.
+func (g *GraphClient) checkMember(identifier, action, property string, ids []string, opts []GetQueryOption) ([]string, error) {
+	resource := fmt.Sprintf("/directoryObjects/%v/%v", identifier, action)
+
+	var distinct []string
+	for _, id := range ids {
+		if !containsFold(distinct, id) {
+			distinct = append(distinct, id)
+		}
+	}
+	var memberOf []string
+	for start := 0; start < len(distinct); start += maxCheckMemberIDsPerRequest {
+		end := start + maxCheckMemberIDsPerRequest
+		if end > len(distinct) {
+			end = len(distinct)
+		}
+		bodyBytes, err := json.Marshal(map[string][]string{property: distinct[start:end]})
+		if err != nil {
+			return nil, err
+		}
+		body := bytes.NewReader(bodyBytes)
+
+		var marsh struct {
+			IDs []string `json:"value"`
+		}
+		if err := g.makeReadOnlyPOSTAPICall(resource, compileGetQueryOptions(opts), body, &marsh); err != nil {
+			return nil, err
+		}
+		memberOf = append(memberOf, marsh.IDs...)
+	}
+	return memberOf, nil
+}
+
+// MembershipCache caches the results of checkMemberGroups and checkMemberObjects for a short
+// time, e.g. for authorization middleware that checks the same users against the same groups
+// on every request. Only the memberships missing in the cache are checked with Microsoft
+// Graph. Changed memberships are noticed when the cached results expire, see TTL.
+// A MembershipCache is safe for concurrent use.
+//
+// Example:
+//
+//	cache := msgraph.NewMembershipCache(graphClient, time.Minute)
+//	allowed, err := cache.IsMemberOfAny(r.Context(), userID, []string{adminGroupID, operatorGroupID})
+type MembershipCache struct {
+	// TTL is the duration a checked membership is cached, defaults to one minute if 0.
+	TTL time.Duration
+
+	graphClient *GraphClient
+	mu          sync.Mutex
+	entries     map[membershipCacheKey]membershipCacheEntry
+	nextSweep   time.Time
+}
+
+type membershipCacheKey struct {
+	action   string // checkMemberGroups or checkMemberObjects
+	objectID string // ID of the checked user or group in lower case
+	id       string // ID of the group, directory role or administrative unit in lower case
+}
+
+type membershipCacheEntry struct {
+	member  bool
+	expires time.Time
+}
+
+// NewMembershipCache returns a new MembershipCache that caches checked memberships for the
+// given duration, see MembershipCache.TTL.
+func NewMembershipCache(graphClient *GraphClient, ttl time.Duration) *MembershipCache {
+	return &MembershipCache{TTL: ttl, graphClient: graphClient}
+}
+
+// CheckMemberGroups returns the IDs of the given groups the user or group with the given ID
+// is a direct or transitive member of, see User.CheckMemberGroups.
+func (c *MembershipCache) CheckMemberGroups(ctx context.Context, objectID string, groupIDs []string) ([]string, error) {
+	return c.check(ctx, "checkMemberGroups", objectID, groupIDs)
+}
+
+// CheckMemberObjects returns the IDs of the given groups, directory roles and administrative
+// units the user with the given ID is a direct or transitive member of, see
+// User.CheckMemberObjects.
+func (c *MembershipCache) CheckMemberObjects(ctx context.Context, objectID string, ids []string) ([]string, error) {
+	return c.check(ctx, "checkMemberObjects", objectID, ids)
+}
+
+// IsMemberOfAny returns true if the user or group with the given ID is a direct or transitive
+// member of at least one of the given groups.
+func (c *MembershipCache) IsMemberOfAny(ctx context.Context, objectID string, groupIDs []string) (bool, error) {
+	memberOf, err := c.CheckMemberGroups(ctx, objectID, groupIDs)
+	return len(memberOf) > 0, err
+}
+
+// Invalidate removes all cached memberships of the user or group with the given ID, e.g.
+// after changing its memberships.
+func (c *MembershipCache) Invalidate(objectID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if key.objectID == strings.ToLower(objectID) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// Reset removes all cached memberships.
+func (c *MembershipCache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = nil
+}
+
+// ttl returns the duration a checked membership is cached.
+func (c *MembershipCache) ttl() time.Duration {
+	if c.TTL <= 0 {
+		return time.Minute
+	}
+	return c.TTL
+}
+
+// check returns the given IDs the object is a member of, the IDs missing in the cache or
+// expired are checked with the check action. The IDs are returned in the given order.
+func (c *MembershipCache) check(ctx context.Context, action, objectID string, ids []string) ([]string, error) {
+	if c.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	now := time.Now()
+	var member = make(map[string]bool)
+	var unknown []string
+	c.mu.Lock()
+	for _, id := range ids {
+		entry, ok := c.entries[membershipCacheKey{action, strings.ToLower(objectID), strings.ToLower(id)}]
+		if ok && now.Before(entry.expires) {
+			member[strings.ToLower(id)] = entry.member
+		} else {
+			unknown = append(unknown, id)
+		}
+	}
+	c.mu.Unlock()
+
+	if len(unknown) > 0 {
+		check := c.graphClient.checkMemberGroups
+		if action == "checkMemberObjects" {
+			check = c.graphClient.checkMemberObjects
+		}
+		memberOf, err := check(objectID, unknown, GetWithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range unknown {
+			member[strings.ToLower(id)] = containsFold(memberOf, id)
+		}
+		c.store(action, objectID, unknown, memberOf, now.Add(c.ttl()))
+	}
+
+	var result []string
+	for _, id := range ids {
+		if member[strings.ToLower(id)] && !containsFold(result, id) {
+			result = append(result, id)
+		}
+	}
+	return result, nil
+}
+
+// store caches whether the object is a member of each of the checked IDs. Expired entries
+// are removed at most once per TTL, hence the cache does not grow with objects that are not
+// checked again.
+func (c *MembershipCache) store(action, objectID string, checked, memberOf []string, expires time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if c.entries == nil {
+		c.entries = make(map[membershipCacheKey]membershipCacheEntry)
+	}
+	if !now.Before(c.nextSweep) {
+		for key, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, key)
+			}
+		}
+		c.nextSweep = now.Add(c.ttl())
+	}
+	for _, id := range checked {
+		key := membershipCacheKey{action, strings.ToLower(objectID), strings.ToLower(id)}
+		c.entries[key] = membershipCacheEntry{member: containsFold(memberOf, id), expires: expires}
+	}
+}
@@ -0,0 +1,149 @@
+package msgraph
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// createNestedUnitTestGroups creates the groups outer, inner and other for unit tests with
+// inner as member of outer and the user as member of inner, delete them with Group.DeleteGroup.
+func createNestedUnitTestGroups(t *testing.T, user User) (outer, inner, other Group) {
+	t.Helper()
+	outer, inner, other = createUnitTestGroup(t), createUnitTestGroup(t), createUnitTestGroup(t)
+	if err := outer.AddMember(inner.ID); err != nil {
+		t.Fatalf("Group.AddMember() of the inner group error = %v", err)
+	}
+	if err := inner.AddMember(user.ID); err != nil {
+		t.Fatalf("Group.AddMember() of the user error = %v", err)
+	}
+	return outer, inner, other
+}
+
+func TestUser_CheckMemberGroups(t *testing.T) {
+	if _, err := (User{}).CheckMemberGroups([]string{"1"}); err != ErrNotGraphClientSourced {
+		t.Errorf("User.CheckMemberGroups() without GraphClient error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+	user := createUnitTestUser(t)
+	defer user.DeleteUser()
+	outer, inner, other := createNestedUnitTestGroups(t, user)
+	defer outer.DeleteGroup()
+	defer inner.DeleteGroup()
+	defer other.DeleteGroup()
+
+	got, err := user.CheckMemberGroups([]string{outer.ID, other.ID, inner.ID}, GetWithContext(context.Background()))
+	sort.Strings(got)
+	want := []string{outer.ID, inner.ID}
+	sort.Strings(want)
+	if err != nil || strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("User.CheckMemberGroups() = %v, error = %v, want %v", got, err, want)
+	}
+	if got, err := user.CheckMemberObjects([]string{other.ID, inner.ID}); err != nil || len(got) != 1 || got[0] != inner.ID {
+		t.Errorf("User.CheckMemberObjects() = %v, error = %v, want %v", got, err, inner.ID)
+	}
+	if got, err := inner.CheckMemberGroups([]string{outer.ID, other.ID}); err != nil || len(got) != 1 || got[0] != outer.ID {
+		t.Errorf("Group.CheckMemberGroups() = %v, error = %v, want %v", got, err, outer.ID)
+	}
+	if got, err := user.CheckMemberGroups(nil); err != nil || len(got) != 0 {
+		t.Errorf("User.CheckMemberGroups() of no groups = %v, error = %v, want none", got, err)
+	}
+
+	if offlineServer != nil {
+		// more than 20 groups are checked with multiple requests
+		var groupIDs = []string{other.ID, outer.ID}
+		for len(groupIDs) < 25 {
+			groupIDs = append(groupIDs, offlineServer.AddGroup(map[string]interface{}{"displayName": "check " + randomString(8)}).ID())
+		}
+		offlineServer.ResetRequests()
+		if got, err := user.CheckMemberGroups(groupIDs); err != nil || len(got) != 1 || got[0] != outer.ID {
+			t.Errorf("User.CheckMemberGroups() of %v groups = %v, error = %v, want %v", len(groupIDs), got, err, outer.ID)
+		}
+		if requests := offlineServer.Requests(); len(requests) != 2 {
+			t.Errorf("User.CheckMemberGroups() of %v groups sent %v requests, want 2", len(groupIDs), len(requests))
+		}
+	}
+}
+
+func TestMembershipCache(t *testing.T) {
+	if _, err := NewMembershipCache(nil, 0).IsMemberOfAny(context.Background(), "1", []string{"2"}); err != ErrNotGraphClientSourced {
+		t.Errorf("MembershipCache.IsMemberOfAny() without GraphClient error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+	user := createUnitTestUser(t)
+	defer user.DeleteUser()
+	outer, inner, other := createNestedUnitTestGroups(t, user)
+	defer outer.DeleteGroup()
+	defer inner.DeleteGroup()
+	defer other.DeleteGroup()
+
+	cache := NewMembershipCache(graphClient, time.Hour)
+	ctx := context.Background()
+	// requests counts the checkMember* requests sent since the last call, always 0 online
+	requests := func() int {
+		if offlineServer == nil {
+			return 0
+		}
+		var count int
+		for _, request := range offlineServer.Requests() {
+			if strings.Contains(request.Path, "/checkMember") {
+				count++
+			}
+		}
+		offlineServer.ResetRequests()
+		return count
+	}
+	requests()
+
+	tests := []struct {
+		name         string
+		groupIDs     []string
+		want         bool
+		wantRequests int
+	}{
+		{name: "Not cached", groupIDs: []string{other.ID, outer.ID}, want: true, wantRequests: 1},
+		{name: "Cached", groupIDs: []string{outer.ID, other.ID}, want: true, wantRequests: 0},
+		{name: "Cached non-member", groupIDs: []string{strings.ToUpper(other.ID)}, want: false, wantRequests: 0},
+		{name: "Partially cached", groupIDs: []string{other.ID, inner.ID}, want: true, wantRequests: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cache.IsMemberOfAny(ctx, user.ID, tt.groupIDs)
+			if err != nil || got != tt.want {
+				t.Errorf("MembershipCache.IsMemberOfAny() = %v, error = %v, want %v", got, err, tt.want)
+			}
+			if offlineServer != nil {
+				if got := requests(); got != tt.wantRequests {
+					t.Errorf("MembershipCache.IsMemberOfAny() sent %v requests, want %v", got, tt.wantRequests)
+				}
+			}
+		})
+	}
+
+	// memberships are checked again after invalidation and expiration
+	if err := inner.RemoveMember(user.ID); err != nil {
+		t.Fatalf("Group.RemoveMember() error = %v", err)
+	}
+	if got, _ := cache.IsMemberOfAny(ctx, user.ID, []string{outer.ID}); !got {
+		t.Errorf("MembershipCache.IsMemberOfAny() before Invalidate() = false, want the cached true")
+	}
+	cache.Invalidate(user.ID)
+	if got, err := cache.CheckMemberGroups(ctx, user.ID, []string{outer.ID, inner.ID}); err != nil || len(got) != 0 {
+		t.Errorf("MembershipCache.CheckMemberGroups() after Invalidate() = %v, error = %v, want none", got, err)
+	}
+	if err := inner.AddMember(user.ID); err != nil {
+		t.Fatalf("Group.AddMember() error = %v", err)
+	}
+	cache.TTL = time.Nanosecond
+	if got, err := cache.CheckMemberObjects(ctx, user.ID, []string{inner.ID}); err != nil || len(got) != 1 {
+		t.Errorf("MembershipCache.CheckMemberObjects() = %v, error = %v, want %v", got, err, inner.ID)
+	}
+	requests()
+	if got, err := cache.CheckMemberObjects(ctx, user.ID, []string{inner.ID}); err != nil || len(got) != 1 {
+		t.Errorf("MembershipCache.CheckMemberObjects() after expiration = %v, error = %v, want %v", got, err, inner.ID)
+	}
+	if got := requests(); offlineServer != nil && got != 1 {
+		t.Errorf("MembershipCache.CheckMemberObjects() after expiration sent %v requests, want 1", got)
+	}
+	cache.Reset()
+}
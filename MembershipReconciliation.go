@@ -0,0 +1,275 @@
+package msgraph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Actions of a MembershipChange.
+const (
+	MembershipActionAdd    = "add"
+	MembershipActionRemove = "remove"
+)
+
+// MembershipReconciliation configures Group.ReconcileMembers, which makes the direct members of a
+// group match a desired set of members, e.g. kept in a Git repository. Members missing in the
+// group are added with one request per 20 members, members not in the desired set are removed
+// one by one. Members are compared by their ID, hence nested groups, devices and other
+// directory objects are reconciled like users.
+type MembershipReconciliation struct {
+	// Members are the desired direct members of the group, either IDs of any directory object or
+	// userPrincipalNames of users. An empty list removes all members unless KeepUnlisted is set.
+	Members []string
+	// KeepUnlisted only adds the missing members and keeps all members that are not listed in
+	// Members.
+	KeepUnlisted bool
+	// RequestInterval is the minimum time between two POST, PATCH or DELETE API-calls, e.g. to
+	// stay below the throttling limits of Microsoft Graph when reconciling many groups. Every
+	// API-call is sent immediately if zero.
+	RequestInterval time.Duration
+	// DryRun plans all POST, PATCH and DELETE API-calls instead of sending them, see
+	// GraphClient.Plan. The current members are still read and userPrincipalNames resolved.
+	DryRun bool
+}
+
+// MembershipChange is a member added to or removed from the group by Group.ReconcileMembers.
+type MembershipChange struct {
+	Action   string // one of the MembershipAction* constants
+	MemberID string // ID of the directory object, empty if the userPrincipalName could not be resolved
+	Member   string // member as given in MembershipReconciliation.Members, userPrincipalName or display name of removed members
+	Err      error  // nil if the change succeeded or has been planned in dry-run mode
+}
+
+func (c MembershipChange) String() string {
+	member := c.MemberID
+	switch {
+	case member == "":
+		member = c.Member
+	case c.Member != "" && c.Member != member:
+		member = fmt.Sprintf("%v (%v)", c.Member, member)
+	}
+	if c.Err != nil {
+		return fmt.Sprintf("%v %v: failed: %v", c.Action, member, c.Err)
+	}
+	return fmt.Sprintf("%v %v: ok", c.Action, member)
+}
+
+// MembershipReconciliationResult is the report of Group.ReconcileMembers. In dry-run mode the
+// changes are the plan of what would be changed.
+type MembershipReconciliationResult struct {
+	GroupID   string
+	DryRun    bool
+	Changes   []MembershipChange
+	Unchanged int // number of desired members that already were members of the group
+}
+
+func (r MembershipReconciliationResult) String() string {
+	var changes = make([]string, len(r.Changes))
+	for i, change := range r.Changes {
+		changes[i] = change.String()
+	}
+	return fmt.Sprintf("MembershipReconciliationResult(GroupID: \"%v\", DryRun: %v, Unchanged: %v, Changes: [%v])", r.GroupID, r.DryRun, r.Unchanged, strings.Join(changes, ", "))
+}
+
+// Added returns the IDs of all members that have been added successfully.
+func (r MembershipReconciliationResult) Added() []string {
+	return r.succeeded(MembershipActionAdd)
+}
+
+// Removed returns the IDs of all members that have been removed successfully.
+func (r MembershipReconciliationResult) Removed() []string {
+	return r.succeeded(MembershipActionRemove)
+}
+
+func (r MembershipReconciliationResult) succeeded(action string) []string {
+	var ids []string
+	for _, change := range r.Changes {
+		if change.Action == action && change.Err == nil {
+			ids = append(ids, change.MemberID)
+		}
+	}
+	return ids
+}
+
+// Failed returns all failed changes.
+func (r MembershipReconciliationResult) Failed() []MembershipChange {
+	var failed []MembershipChange
+	for _, change := range r.Changes {
+		if change.Err != nil {
+			failed = append(failed, change)
+		}
+	}
+	return failed
+}
+
+// Err returns an error summarizing all failed changes, or nil if all changes succeeded.
+func (r MembershipReconciliationResult) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	var messages = make([]string, len(failed))
+	for i, change := range failed {
+		messages[i] = change.String()
+	}
+	return fmt.Errorf("reconciliation of the members of group %v failed in %v change(s): %v", r.GroupID, len(failed), strings.Join(messages, "; "))
+}
+
+// ReconcileMembers adds and removes direct members of the group until they match the desired
+// members of the MembershipReconciliation and returns a report of every change. Changes are
+// continued after a failed change, use MembershipReconciliationResult.Err to check whether all
+// changes succeeded. Returns an error without any change if the current members cannot be
+// listed. Reconciling again retries the failed changes only, as adding and removing members
+// is idempotent.
+func (g Group) ReconcileMembers(ctx context.Context, r MembershipReconciliation) (MembershipReconciliationResult, error) {
+	result := MembershipReconciliationResult{GroupID: g.ID, DryRun: r.DryRun}
+	if g.graphClient == nil {
+		return result, ErrNotGraphClientSourced
+	}
+	current, err := g.ListMemberObjects(ListWithContext(ctx))
+	if err != nil {
+		return result, err
+	}
+
+	// desired member IDs, userPrincipalNames of current members need no API-call to resolve
+	var desired, desiredLabels []string
+	for _, member := range r.Members {
+		member = strings.TrimSpace(member)
+		if member == "" {
+			continue
+		}
+		id := member
+		if strings.Contains(member, "@") {
+			id = ""
+			for _, object := range current {
+				if object.User != nil && strings.EqualFold(object.User.UserPrincipalName, member) {
+					id = object.ID
+				}
+			}
+			if id == "" {
+				user, err := g.graphClient.GetUser(member, GetWithContext(ctx), GetWithSelect("id,userPrincipalName"))
+				if err != nil {
+					result.Changes = append(result.Changes, MembershipChange{Action: MembershipActionAdd, Member: member, Err: err})
+					continue
+				}
+				id = user.ID
+			}
+		}
+		if !containsFold(desired, id) {
+			desired = append(desired, id)
+			desiredLabels = append(desiredLabels, member)
+		}
+	}
+
+	var currentIDs = make([]string, len(current))
+	for i, object := range current {
+		currentIDs[i] = object.ID
+	}
+	var toAdd []MembershipChange
+	for i, id := range desired {
+		if containsFold(currentIDs, id) {
+			result.Unchanged++
+			continue
+		}
+		toAdd = append(toAdd, MembershipChange{Action: MembershipActionAdd, MemberID: id, Member: desiredLabels[i]})
+	}
+	var toRemove []MembershipChange
+	if !r.KeepUnlisted {
+		for _, object := range current {
+			if containsFold(desired, object.ID) {
+				continue
+			}
+			member := object.DisplayName
+			if object.User != nil {
+				member = object.User.UserPrincipalName
+			}
+			toRemove = append(toRemove, MembershipChange{Action: MembershipActionRemove, MemberID: object.ID, Member: member})
+		}
+	}
+
+	updateOpts := []UpdateQueryOption{UpdateWithContext(ctx)}
+	deleteOpts := []DeleteQueryOption{DeleteWithContext(ctx)}
+	if r.DryRun {
+		updateOpts = append(updateOpts, UpdateWithDryRun())
+		deleteOpts = append(deleteOpts, DeleteWithDryRun())
+	}
+	limiter := requestLimiter{interval: r.RequestInterval}
+	if r.DryRun {
+		limiter.interval = 0
+	}
+
+	// missing members are added first, hence members never lose access while being reconciled
+	for start := 0; start < len(toAdd); start += maxGroupBindsPerRequest {
+		end := start + maxGroupBindsPerRequest
+		if end > len(toAdd) {
+			end = len(toAdd)
+		}
+		batch := toAdd[start:end]
+		var ids = make([]string, len(batch))
+		for i, change := range batch {
+			ids[i] = change.MemberID
+		}
+
+		// members added before are added one by one, every API-call is spaced by the limiter
+		err := g.addMembers(ids, updateOpts, func() error { return limiter.wait(ctx) })
+		if err != nil && len(batch) > 1 && ctx.Err() == nil {
+			// a single member, e.g. a deleted user, fails the whole batch, retry one by one
+			// to report the failed members only
+			for i := range batch {
+				if batch[i].Err = limiter.wait(ctx); batch[i].Err == nil {
+					batch[i].Err = g.AddMember(batch[i].MemberID, updateOpts...)
+				}
+			}
+		} else {
+			for i := range batch {
+				batch[i].Err = err
+			}
+		}
+		result.Changes = append(result.Changes, batch...)
+	}
+	for _, change := range toRemove {
+		if change.Err = limiter.wait(ctx); change.Err == nil {
+			change.Err = g.RemoveMember(change.MemberID, deleteOpts...)
+		}
+		result.Changes = append(result.Changes, change)
+	}
+	return result, nil
+}
+
+// requestLimiter spaces API-calls by a minimum interval.
+type requestLimiter struct {
+	interval time.Duration
+	next     time.Time
+}
+
+// wait blocks until the next API-call may be sent, or returns the error of the context if it
+// is done before.
+func (l *requestLimiter) wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if d := time.Until(l.next); l.interval > 0 && d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	l.next = time.Now().Add(l.interval)
+	return nil
+}
+
+// containsFold returns true if the list contains the value, ignoring the case as IDs of
+// directory objects are case-insensitive.
+func containsFold(list []string, v string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package msgraph
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-networks/go-msgraph/msgraphtest"
+)
+
+func TestGroup_ReconcileMembers(t *testing.T) {
+	if _, err := (Group{}).ReconcileMembers(context.Background(), MembershipReconciliation{}); err != ErrNotGraphClientSourced {
+		t.Errorf("Group.ReconcileMembers() without GraphClient error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+
+	group := createUnitTestGroup(t)
+	defer group.DeleteGroup()
+	stay := createUnitTestUser(t)
+	defer stay.DeleteUser()
+	leave := createUnitTestUser(t)
+	defer leave.DeleteUser()
+	join := createUnitTestUser(t)
+	defer join.DeleteUser()
+	if err := group.AddMembers([]string{stay.ID, leave.ID}); err != nil {
+		t.Fatalf("Group.AddMembers() error = %v", err)
+	}
+	// userPrincipalNames are case-insensitive, duplicates are ignored
+	desired := MembershipReconciliation{Members: []string{strings.ToUpper(stay.UserPrincipalName), join.ID, join.ID}}
+
+	// dry-run plans the changes, but does not change the members
+	planned := len(graphClient.Plan())
+	dryRun := desired
+	dryRun.DryRun = true
+	result, err := group.ReconcileMembers(context.Background(), dryRun)
+	if err != nil || result.Err() != nil {
+		t.Fatalf("Group.ReconcileMembers() with DryRun error = %v, result error = %v", err, result.Err())
+	}
+	if len(graphClient.Plan())-planned != 2 || !result.DryRun || result.Unchanged != 1 {
+		t.Errorf("Group.ReconcileMembers() with DryRun = %v, planned %v operations, want an add and a remove", result, len(graphClient.Plan())-planned)
+	}
+	assertMemberIDs(t, group, stay.ID, leave.ID)
+
+	result, err = group.ReconcileMembers(context.Background(), desired)
+	if err != nil || result.Err() != nil {
+		t.Fatalf("Group.ReconcileMembers() error = %v, result error = %v", err, result.Err())
+	}
+	if added, removed := result.Added(), result.Removed(); len(added) != 1 || added[0] != join.ID || len(removed) != 1 || removed[0] != leave.ID {
+		t.Errorf("Group.ReconcileMembers() = %v, want %v added and %v removed", result, join.ID, leave.ID)
+	}
+	assertMemberIDs(t, group, stay.ID, join.ID)
+
+	// reconciling again changes nothing
+	if result, err = group.ReconcileMembers(context.Background(), desired); err != nil || len(result.Changes) != 0 || result.Unchanged != 2 {
+		t.Errorf("Group.ReconcileMembers() again = %v, error = %v, want no changes", result, err)
+	}
+
+	result, err = group.ReconcileMembers(context.Background(), MembershipReconciliation{Members: []string{leave.ID}, KeepUnlisted: true})
+	if err != nil || result.Err() != nil || len(result.Removed()) != 0 {
+		t.Fatalf("Group.ReconcileMembers() with KeepUnlisted = %v, error = %v, want only an add", result, err)
+	}
+	assertMemberIDs(t, group, stay.ID, leave.ID, join.ID)
+}
+
+func TestGroup_ReconcileMembers_failures(t *testing.T) {
+	if offlineServer == nil {
+		t.Skip("devices cannot be created by the unit tests, only tested offline")
+	}
+	group := createUnitTestGroup(t)
+	defer group.DeleteGroup()
+	device := offlineServer.Add("/devices", map[string]interface{}{"displayName": "laptop", "operatingSystem": "Windows"})
+	const missingID = "00000000-0000-0000-0000-000000000000"
+
+	// the unknown ID fails the batch, the members are retried one by one
+	start := time.Now()
+	result, err := group.ReconcileMembers(context.Background(), MembershipReconciliation{
+		Members:         []string{"nobody@" + msGraphDomainNameForCreateTests, device.ID(), missingID},
+		RequestInterval: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Group.ReconcileMembers() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("Group.ReconcileMembers() took %v for 3 requests, want at least 2 RequestIntervals", elapsed)
+	}
+	failed := result.Failed()
+	if len(failed) != 2 || failed[0].MemberID != "" || failed[1].MemberID != missingID || result.Err() == nil {
+		t.Errorf("Group.ReconcileMembers().Failed() = %v, want the unknown userPrincipalName and ID", failed)
+	}
+	if added := result.Added(); len(added) != 1 || added[0] != device.ID() {
+		t.Errorf("Group.ReconcileMembers().Added() = %v, want %v", added, device.ID())
+	}
+	assertMemberIDs(t, group, device.ID())
+
+	// members added before fall back to adding them one by one, which is spaced too
+	stale := createUnitTestGroup(t)
+	defer stale.DeleteGroup()
+	offlineServer.HandleFunc(http.MethodPatch, "/groups/"+stale.ID, func(w http.ResponseWriter, r *http.Request) {
+		msgraphtest.WriteError(w, http.StatusBadRequest, "Request_BadRequest", "One or more added object references already exist for the following modified properties: 'members'.")
+	})
+	offlineServer.ResetRequests()
+	start = time.Now()
+	result, err = stale.ReconcileMembers(context.Background(), MembershipReconciliation{
+		Members:         []string{device.ID(), group.ID},
+		RequestInterval: 50 * time.Millisecond,
+	})
+	if err != nil || result.Err() != nil || len(result.Added()) != 2 {
+		t.Fatalf("Group.ReconcileMembers() of a stale group = %v, error = %v, want 2 added members", result, err)
+	}
+	if requests, elapsed := len(offlineServer.Requests()), time.Since(start); requests < 4 || elapsed < 100*time.Millisecond {
+		t.Errorf("Group.ReconcileMembers() took %v for %v requests, want at least 2 RequestIntervals for the batch and the fallback", elapsed, requests)
+	}
+	assertMemberIDs(t, stale, device.ID(), group.ID)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := group.ReconcileMembers(ctx, MembershipReconciliation{}); err == nil {
+		t.Errorf("Group.ReconcileMembers() with cancelled context error = nil, want error")
+	}
+	assertMemberIDs(t, group, device.ID())
+}
+
+// assertMemberIDs checks that the direct members of the group are exactly the given IDs.
+func assertMemberIDs(t *testing.T, group Group, ids ...string) {
+	t.Helper()
+	members, err := group.ListMemberObjects()
+	if err != nil {
+		t.Fatalf("Group.ListMemberObjects() error = %v", err)
+	}
+	if len(members) != len(ids) {
+		t.Errorf("Group.ListMemberObjects() = %v, want %v", members, ids)
+		return
+	}
+	for _, id := range ids {
+		if _, err := members.GetByID(id); err != nil {
+			t.Errorf("Group.ListMemberObjects() = %v, want %v", members, ids)
+		}
+	}
+}
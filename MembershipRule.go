@@ -0,0 +1,721 @@
+package msgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// MembershipRule is a parsed membership rule of a group with dynamic membership, see
+// ParseMembershipRule. Use MembershipRule.Evaluate or MembershipRule.Filter to preview the
+// members of a rule before saving it with Group.UpdateMembershipRule.
+type MembershipRule struct {
+	rule string
+	expr ruleExpr
+}
+
+// membershipRuleProperty is a user or device property that can be used in membership rules.
+type membershipRuleProperty struct {
+	name         string // name in the rule without the user prefix, e.g. department
+	userProperty string // JSON path of the property in the User, empty if the User does not contain it
+	multiValued  bool   // true if the property must be compared with -any or -all
+	boolean      bool   // true if the property is a boolean, hence an unset property is false
+	device       bool   // true if the property is a device property, which cannot be evaluated for users
+}
+
+// membershipRuleProperties are the user properties supported by membership rules, except the
+// extension attributes.
+//
+// See https://docs.microsoft.com/en-us/azure/active-directory/enterprise-users/groups-dynamic-membership#supported-properties
+var membershipRuleProperties = []membershipRuleProperty{
+	{name: "accountEnabled", userProperty: "accountEnabled", boolean: true},
+	{name: "dirSyncEnabled", userProperty: "onPremisesSyncEnabled", boolean: true},
+	{name: "city", userProperty: "city"},
+	{name: "country", userProperty: "country"},
+	{name: "companyName", userProperty: "companyName"},
+	{name: "department", userProperty: "department"},
+	{name: "displayName", userProperty: "displayName"},
+	{name: "employeeId", userProperty: "employeeId"},
+	{name: "employeeType", userProperty: "employeeType"},
+	{name: "employeeHireDate"},
+	{name: "facsimileTelephoneNumber", userProperty: "faxNumber"},
+	{name: "givenName", userProperty: "givenName"},
+	{name: "jobTitle", userProperty: "jobTitle"},
+	{name: "mail", userProperty: "mail"},
+	{name: "mailNickname", userProperty: "mailNickname"},
+	{name: "memberOf", multiValued: true},
+	{name: "mobile", userProperty: "mobilePhone"},
+	{name: "objectId", userProperty: "id"},
+	{name: "onPremisesDistinguishedName", userProperty: "onPremisesDistinguishedName"},
+	{name: "onPremisesSecurityIdentifier", userProperty: "onPremisesSecurityIdentifier"},
+	{name: "passwordPolicies", userProperty: "passwordPolicies"},
+	{name: "physicalDeliveryOfficeName", userProperty: "officeLocation"},
+	{name: "postalCode", userProperty: "postalCode"},
+	{name: "preferredLanguage", userProperty: "preferredLanguage"},
+	{name: "sipProxyAddress"},
+	{name: "state", userProperty: "state"},
+	{name: "streetAddress", userProperty: "streetAddress"},
+	{name: "surname", userProperty: "surname"},
+	{name: "telephoneNumber", userProperty: "businessPhones"},
+	{name: "usageLocation", userProperty: "usageLocation"},
+	{name: "userPrincipalName", userProperty: "userPrincipalName"},
+	{name: "userType", userProperty: "userType"},
+	{name: "otherMails", userProperty: "otherMails", multiValued: true},
+	{name: "proxyAddresses", userProperty: "proxyAddresses", multiValued: true},
+	{name: "assignedPlans", multiValued: true},
+}
+
+// extensionAttributePattern matches the on-premises extension attributes extensionAttribute1
+// to extensionAttribute15.
+var extensionAttributePattern = regexp.MustCompile(`(?i)^extensionAttribute([1-9]|1[0-5])$`)
+
+// multiValuedDeviceProperties are the multi-valued device properties supported by membership
+// rules. All other device properties are single-valued.
+//
+// See https://docs.microsoft.com/en-us/azure/active-directory/enterprise-users/groups-dynamic-membership#rules-for-devices
+var multiValuedDeviceProperties = []string{"devicePhysicalIds", "memberOf", "systemLabels"}
+
+// systemNowPattern matches the ISO 8601 durations added to or subtracted from system.now, e.g.
+// p1d or P2DT12H.
+var systemNowPattern = regexp.MustCompile(`(?i)^(p(\d+[ymwd])+(t(\d+[hms])+)?|pt(\d+[hms])+)$`)
+
+// directReportsPattern matches the rule for the direct reports of a manager, e.g.
+// Direct Reports for "62e19b97-8b3d-4d4a-a106-4ce66896a863".
+var directReportsPattern = regexp.MustCompile(`(?i)^\s*direct\s+reports\s+for\s+"([^"]+)"\s*$`)
+
+// findMembershipRuleProperty returns the user property with the given name, which is
+// case-insensitive.
+func findMembershipRuleProperty(name string) (membershipRuleProperty, bool) {
+	for _, property := range membershipRuleProperties {
+		if strings.EqualFold(property.name, name) {
+			return property, true
+		}
+	}
+	if match := extensionAttributePattern.FindStringSubmatch(name); match != nil {
+		return membershipRuleProperty{name: name, userProperty: "onPremisesExtensionAttributes.extensionAttribute" + match[1]}, true
+	}
+	if strings.HasPrefix(strings.ToLower(name), "extension_") {
+		return membershipRuleProperty{name: name, userProperty: name}, true
+	}
+	return membershipRuleProperty{}, false
+}
+
+// ParseMembershipRule parses a membership rule of a group with dynamic membership, e.g.
+//
+//	(user.department -eq 'Sales') -and (user.otherMails -any (_ -contains "contoso"))
+//
+// All comparison operators, -and, -or, -not, parentheses, -any and -all for multi-valued
+// properties, system.now with -plus and -minus, rules for devices and the rule
+// Direct Reports for "<manager ID>" are supported. Operators and comparisons of strings are
+// case-insensitive like in Azure AD, strings are enclosed in double or single quotes and a quote
+// in a string is escaped with a backtick. Returns an error for invalid rules and unknown user
+// properties. Microsoft Graph validates the rule when it is saved, hence parsing is optional.
+//
+// Reference: https://docs.microsoft.com/en-us/azure/active-directory/enterprise-users/groups-dynamic-membership
+func ParseMembershipRule(rule string) (MembershipRule, error) {
+	if match := directReportsPattern.FindStringSubmatch(rule); match != nil {
+		return MembershipRule{rule: strings.TrimSpace(rule), expr: ruleDirectReports{managerID: match[1]}}, nil
+	}
+	tokens, err := tokenizeMembershipRule(rule)
+	if err != nil {
+		return MembershipRule{}, err
+	}
+	if len(tokens) == 0 {
+		return MembershipRule{}, fmt.Errorf("membership rule is empty")
+	}
+	p := ruleParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return MembershipRule{}, err
+	}
+	if p.pos < len(p.tokens) {
+		return MembershipRule{}, p.errorf("unexpected %v", p.tokens[p.pos])
+	}
+	return MembershipRule{rule: strings.TrimSpace(rule), expr: expr}, nil
+}
+
+func (r MembershipRule) String() string {
+	return r.rule
+}
+
+// UserProperties returns the properties of the User the rule is evaluated against, e.g. to
+// retrieve only them with ListWithSelect when previewing the members of the rule.
+func (r MembershipRule) UserProperties() []string {
+	var properties []string
+	if r.expr != nil {
+		r.expr.userProperties(&properties)
+	}
+	return properties
+}
+
+// Evaluate returns true if the user matches the rule, hence would be a member of a group with
+// the rule. Only the properties set in the User are evaluated, unset properties are empty,
+// see MembershipRule.UserProperties. Returns an error if the rule cannot be evaluated locally,
+// as it uses a property the User does not contain, e.g. assignedPlans, memberOf or
+// employeeHireDate, system.now, device properties or the direct reports of a manager.
+func (r MembershipRule) Evaluate(u User) (bool, error) {
+	if r.expr == nil {
+		return false, fmt.Errorf("membership rule is empty")
+	}
+	data, err := json.Marshal(u)
+	if err != nil {
+		return false, err
+	}
+	var properties map[string]interface{}
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return false, err
+	}
+	return r.expr.eval(ruleContext{properties: properties})
+}
+
+// Filter returns the users that match the rule, see MembershipRule.Evaluate.
+func (r MembershipRule) Filter(users Users) (Users, error) {
+	var matching Users
+	for _, user := range users {
+		ok, err := r.Evaluate(user)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matching = append(matching, user)
+		}
+	}
+	return matching, nil
+}
+
+// ruleContext contains the values a rule is evaluated against.
+type ruleContext struct {
+	properties map[string]interface{} // properties of the User by their JSON name
+	element    *string                // current value of a multi-valued property in -any and -all, referenced by _
+}
+
+// values returns the values of the property, hence none if the property is unset, one for
+// single-valued properties and all values of multi-valued properties.
+func (c ruleContext) values(property membershipRuleProperty) ([]string, error) {
+	if property.name == "_" {
+		return []string{*c.element}, nil
+	}
+	if property.device {
+		return nil, fmt.Errorf("device.%v of the membership rule cannot be evaluated locally for users", property.name)
+	}
+	if property.userProperty == "" {
+		return nil, fmt.Errorf("user.%v of the membership rule cannot be evaluated locally", property.name)
+	}
+
+	var value interface{} = c.properties
+	for _, name := range strings.Split(property.userProperty, ".") {
+		object, _ := value.(map[string]interface{})
+		value = nil
+		for key, v := range object {
+			if strings.EqualFold(key, name) {
+				value = v
+			}
+		}
+	}
+	switch v := value.(type) {
+	case nil:
+		if property.boolean {
+			return []string{"false"}, nil
+		}
+		return nil, nil
+	case []interface{}:
+		var values []string
+		for _, element := range v {
+			values = append(values, fmt.Sprint(element))
+		}
+		return values, nil
+	}
+	return []string{fmt.Sprint(value)}, nil
+}
+
+// ruleExpr is an expression of a membership rule.
+type ruleExpr interface {
+	eval(c ruleContext) (bool, error)
+	userProperties(properties *[]string)
+}
+
+type ruleOr struct{ left, right ruleExpr }
+
+func (e ruleOr) eval(c ruleContext) (bool, error) {
+	left, err := e.left.eval(c)
+	if err != nil || left {
+		return left, err
+	}
+	return e.right.eval(c)
+}
+
+func (e ruleOr) userProperties(properties *[]string) {
+	e.left.userProperties(properties)
+	e.right.userProperties(properties)
+}
+
+type ruleAnd struct{ left, right ruleExpr }
+
+func (e ruleAnd) eval(c ruleContext) (bool, error) {
+	left, err := e.left.eval(c)
+	if err != nil || !left {
+		return false, err
+	}
+	return e.right.eval(c)
+}
+
+func (e ruleAnd) userProperties(properties *[]string) {
+	e.left.userProperties(properties)
+	e.right.userProperties(properties)
+}
+
+type ruleNot struct{ expr ruleExpr }
+
+func (e ruleNot) eval(c ruleContext) (bool, error) {
+	ok, err := e.expr.eval(c)
+	return !ok && err == nil, err
+}
+
+func (e ruleNot) userProperties(properties *[]string) {
+	e.expr.userProperties(properties)
+}
+
+// ruleDirectReports is the rule for the direct reports of a manager.
+type ruleDirectReports struct{ managerID string }
+
+func (e ruleDirectReports) eval(c ruleContext) (bool, error) {
+	return false, fmt.Errorf("the direct reports of %v of the membership rule cannot be evaluated locally", e.managerID)
+}
+
+func (e ruleDirectReports) userProperties(properties *[]string) {}
+
+// ruleValue is the value a property is compared with.
+type ruleValue struct {
+	text string   // string, true or false, or the duration added to system.now, e.g. -p1d
+	null bool     // true for null
+	now  bool     // true for system.now
+	list []string // values of -in and -notIn
+}
+
+// ruleComparison compares a single-valued property, or the current value of a multi-valued
+// property, with a value.
+type ruleComparison struct {
+	property membershipRuleProperty
+	operator string // lower case without the leading dash, e.g. startswith
+	value    ruleValue
+	pattern  *regexp.Regexp // compiled value of -match and -notMatch
+}
+
+func (e ruleComparison) eval(c ruleContext) (bool, error) {
+	values, err := c.values(e.property)
+	if err != nil {
+		return false, err
+	}
+	var v string
+	if len(values) > 0 {
+		v = values[0]
+	}
+	lower, value := strings.ToLower(v), strings.ToLower(e.value.text)
+	switch e.operator {
+	case "eq", "ne":
+		equal := strings.EqualFold(v, e.value.text)
+		if e.value.null {
+			equal = v == ""
+		}
+		return equal == (e.operator == "eq"), nil
+	case "startswith", "notstartswith":
+		return strings.HasPrefix(lower, value) == (e.operator == "startswith"), nil
+	case "contains", "notcontains":
+		return strings.Contains(lower, value) == (e.operator == "contains"), nil
+	case "match", "notmatch":
+		return e.pattern.MatchString(v) == (e.operator == "match"), nil
+	case "in", "notin":
+		return containsFold(e.value.list, v) == (e.operator == "in"), nil
+	case "le", "ge", "lt", "gt":
+		return false, fmt.Errorf("-%v of the membership rule cannot be evaluated locally", e.operator)
+	}
+	return false, fmt.Errorf("unsupported operator -%v", e.operator)
+}
+
+func (e ruleComparison) userProperties(properties *[]string) {
+	addRuleUserProperty(properties, e.property)
+}
+
+// ruleCollection evaluates an expression for the values of a multi-valued property with -any
+// or -all.
+type ruleCollection struct {
+	property membershipRuleProperty
+	all      bool
+	expr     ruleExpr
+}
+
+func (e ruleCollection) eval(c ruleContext) (bool, error) {
+	values, err := c.values(e.property)
+	if err != nil {
+		return false, err
+	}
+	for _, value := range values {
+		element := value
+		ok, err := e.expr.eval(ruleContext{properties: c.properties, element: &element})
+		if err != nil {
+			return false, err
+		}
+		if ok != e.all {
+			return ok, nil
+		}
+	}
+	return e.all, nil
+}
+
+func (e ruleCollection) userProperties(properties *[]string) {
+	addRuleUserProperty(properties, e.property)
+}
+
+// addRuleUserProperty adds the top-level property of the User the rule property is evaluated
+// against to the properties, unless it has been added before.
+func addRuleUserProperty(properties *[]string, property membershipRuleProperty) {
+	if property.userProperty == "" || property.name == "_" {
+		return
+	}
+	name := strings.Split(property.userProperty, ".")[0]
+	if !containsString(*properties, name) {
+		*properties = append(*properties, name)
+	}
+}
+
+// Kinds of a ruleToken.
+const (
+	ruleTokenIdentifier  = iota // e.g. user.department, _, true or null
+	ruleTokenOperator           // e.g. -eq, text in lower case without the leading dash
+	ruleTokenString             // text without quotes and escapes
+	ruleTokenPunctuation        // ( ) [ ] or ,
+)
+
+// ruleToken is a token of a membership rule.
+type ruleToken struct {
+	kind int
+	text string
+	pos  int // position of the token in the rule
+}
+
+func (t ruleToken) String() string {
+	switch t.kind {
+	case ruleTokenOperator:
+		return fmt.Sprintf("operator -%v", t.text)
+	case ruleTokenString:
+		return fmt.Sprintf("string %q", t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// tokenizeMembershipRule splits the membership rule into its tokens.
+func tokenizeMembershipRule(rule string) ([]ruleToken, error) {
+	var tokens []ruleToken
+	runes := []rune(rule)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case strings.ContainsRune("()[],", r):
+			tokens = append(tokens, ruleToken{kind: ruleTokenPunctuation, text: string(r), pos: i})
+			i++
+		case r == '"' || r == '\'':
+			var text []rune
+			start := i
+			for i++; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '`' && i+1 < len(runes) {
+					i++
+				}
+				text = append(text, runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("invalid membership rule: unterminated string at position %v", start)
+			}
+			tokens = append(tokens, ruleToken{kind: ruleTokenString, text: string(text), pos: start})
+			i++
+		case r == '-':
+			start := i
+			for i++; i < len(runes) && unicode.IsLetter(runes[i]); i++ {
+			}
+			if i == start+1 {
+				return nil, fmt.Errorf("invalid membership rule: operator expected at position %v", start)
+			}
+			tokens = append(tokens, ruleToken{kind: ruleTokenOperator, text: strings.ToLower(string(runes[start+1 : i])), pos: start})
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			start := i
+			for ; i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.'); i++ {
+			}
+			tokens = append(tokens, ruleToken{kind: ruleTokenIdentifier, text: string(runes[start:i]), pos: start})
+		default:
+			return nil, fmt.Errorf("invalid membership rule: unexpected %q at position %v", r, i)
+		}
+	}
+	return tokens, nil
+}
+
+// ruleParser is a recursive descent parser of the tokens of a membership rule.
+type ruleParser struct {
+	tokens     []ruleToken
+	pos        int
+	collection *membershipRuleProperty // multi-valued property of the enclosing -any or -all
+	device     *bool                   // true if the rule is for devices, nil before the first property
+}
+
+// errorf returns an error at the position of the current token.
+func (p *ruleParser) errorf(format string, a ...interface{}) error {
+	position := "at the end"
+	if p.pos < len(p.tokens) {
+		position = fmt.Sprintf("at position %v", p.tokens[p.pos].pos)
+	}
+	return fmt.Errorf("invalid membership rule: %v %v", fmt.Sprintf(format, a...), position)
+}
+
+// accept consumes the next token and returns true if it is of the given kind and text.
+func (p *ruleParser) accept(kind int, text string) bool {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == kind && p.tokens[p.pos].text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// expect consumes the next token or returns an error if it is not of the given kind and text.
+func (p *ruleParser) expect(kind int, text string) error {
+	if !p.accept(kind, text) {
+		return p.errorf("%q expected", text)
+	}
+	return nil
+}
+
+// next consumes the next token or returns an error if there is none.
+func (p *ruleParser) next(expected string) (ruleToken, error) {
+	if p.pos >= len(p.tokens) {
+		return ruleToken{}, p.errorf("%v expected", expected)
+	}
+	p.pos++
+	return p.tokens[p.pos-1], nil
+}
+
+func (p *ruleParser) parseOr() (ruleExpr, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.accept(ruleTokenOperator, "or") {
+		var right ruleExpr
+		if right, err = p.parseAnd(); err == nil {
+			left = ruleOr{left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *ruleParser) parseAnd() (ruleExpr, error) {
+	left, err := p.parseUnary()
+	for err == nil && p.accept(ruleTokenOperator, "and") {
+		var right ruleExpr
+		if right, err = p.parseUnary(); err == nil {
+			left = ruleAnd{left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *ruleParser) parseUnary() (ruleExpr, error) {
+	if p.accept(ruleTokenOperator, "not") {
+		expr, err := p.parseUnary()
+		return ruleNot{expr: expr}, err
+	}
+	if p.accept(ruleTokenPunctuation, "(") {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return expr, p.expect(ruleTokenPunctuation, ")")
+	}
+	return p.parseComparison()
+}
+
+// parseComparison parses a property compared with an operator and a value, or a multi-valued
+// property with -any or -all.
+func (p *ruleParser) parseComparison() (ruleExpr, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, p.errorf("property expected")
+	}
+	property, err := p.property(p.tokens[p.pos])
+	if err != nil {
+		return nil, err
+	}
+	p.pos++
+	operatorPos := p.pos
+	operator, err := p.next("operator")
+	if err != nil {
+		return nil, err
+	}
+	if operator.kind != ruleTokenOperator {
+		p.pos = operatorPos
+		return nil, p.errorf("operator expected instead of %v", operator)
+	}
+
+	switch operator.text {
+	case "any", "all":
+		if !property.multiValued || property.name == "_" {
+			p.pos = operatorPos
+			return nil, p.errorf("-%v requires a multi-valued property like user.otherMails", operator.text)
+		}
+		if err := p.expect(ruleTokenPunctuation, "("); err != nil {
+			return nil, err
+		}
+		outer := p.collection
+		p.collection = &property
+		expr, err := p.parseOr()
+		p.collection = outer
+		if err != nil {
+			return nil, err
+		}
+		return ruleCollection{property: property, all: operator.text == "all", expr: expr}, p.expect(ruleTokenPunctuation, ")")
+	case "eq", "ne", "startswith", "notstartswith", "contains", "notcontains", "match", "notmatch", "in", "notin", "le", "ge", "lt", "gt":
+	default:
+		p.pos = operatorPos
+		return nil, p.errorf("unsupported operator -%v", operator.text)
+	}
+	if property.multiValued && property.name != "_" {
+		p.pos = operatorPos
+		prefix := "user"
+		if property.device {
+			prefix = "device"
+		}
+		return nil, p.errorf("%v.%v is multi-valued and requires -any or -all", prefix, property.name)
+	}
+
+	comparison := ruleComparison{property: property, operator: operator.text}
+	valuePos := p.pos
+	if comparison.value, err = p.parseValue(); err != nil {
+		return nil, err
+	}
+	isList := operator.text == "in" || operator.text == "notin"
+	switch {
+	case isList && comparison.value.list == nil:
+		p.pos = valuePos
+		return nil, p.errorf("-%v requires a list of values like [\"a\", \"b\"]", operator.text)
+	case !isList && comparison.value.list != nil:
+		p.pos = valuePos
+		return nil, p.errorf("-%v requires a single value", operator.text)
+	case comparison.value.null && operator.text != "eq" && operator.text != "ne":
+		p.pos = valuePos
+		return nil, p.errorf("null can only be compared with -eq and -ne")
+	case comparison.value.now && !containsString([]string{"le", "ge", "lt", "gt"}, operator.text):
+		p.pos = valuePos
+		return nil, p.errorf("system.now can only be compared with -le, -ge, -lt and -gt")
+	case operator.text == "match" || operator.text == "notmatch":
+		if comparison.pattern, err = regexp.Compile("(?i)" + comparison.value.text); err != nil {
+			p.pos = valuePos
+			return nil, p.errorf("invalid regular expression %q", comparison.value.text)
+		}
+	}
+	return comparison, nil
+}
+
+// property returns the property referenced by the token, e.g. user.department or
+// device.deviceOSType, or _ for the values of the multi-valued property of the enclosing -any or
+// -all. User and device properties cannot be mixed in a rule.
+func (p *ruleParser) property(token ruleToken) (membershipRuleProperty, error) {
+	property, err := p.findProperty(token)
+	if err != nil || property.name == "_" || p.collection != nil {
+		return property, err
+	}
+	if p.device != nil && *p.device != property.device {
+		return membershipRuleProperty{}, p.errorf("user and device properties cannot be mixed, found %v", token.text)
+	}
+	p.device = &property.device
+	return property, nil
+}
+
+// findProperty returns the property referenced by the token, see ruleParser.property.
+func (p *ruleParser) findProperty(token ruleToken) (membershipRuleProperty, error) {
+	if token.kind != ruleTokenIdentifier {
+		return membershipRuleProperty{}, p.errorf("property expected instead of %v", token)
+	}
+	if token.text == "_" {
+		if p.collection == nil {
+			return membershipRuleProperty{}, p.errorf("_ can only be used within -any and -all")
+		}
+		return membershipRuleProperty{name: "_", multiValued: true}, nil
+	}
+	if p.collection != nil && strings.EqualFold(p.collection.name, "assignedPlans") && strings.HasPrefix(strings.ToLower(token.text), "assignedplan.") {
+		// the values of assignedPlans are objects, they cannot be evaluated locally
+		return membershipRuleProperty{name: token.text}, nil
+	}
+	if p.collection != nil && strings.EqualFold(p.collection.name, "memberOf") && strings.EqualFold(token.text, "group.objectId") {
+		// the groups of memberOf are not contained in the User
+		return membershipRuleProperty{name: token.text, device: p.collection.device}, nil
+	}
+	segments := strings.SplitN(token.text, ".", 2)
+	switch {
+	case len(segments) == 2 && strings.EqualFold(segments[0], "user"):
+		if property, ok := findMembershipRuleProperty(segments[1]); ok {
+			return property, nil
+		}
+		return membershipRuleProperty{}, p.errorf("unknown property %v", token.text)
+	case len(segments) == 2 && strings.EqualFold(segments[0], "device") && segments[1] != "":
+		property := membershipRuleProperty{name: segments[1], device: true}
+		for _, name := range multiValuedDeviceProperties {
+			if strings.EqualFold(name, segments[1]) {
+				property.name, property.multiValued = name, true
+			}
+		}
+		return property, nil
+	}
+	return membershipRuleProperty{}, p.errorf("property like user.department expected instead of %v", token)
+}
+
+// parseValue parses a string, true, false, null, system.now optionally with -plus or -minus a
+// duration, or a list of strings.
+func (p *ruleParser) parseValue() (ruleValue, error) {
+	token, err := p.next("value")
+	if err != nil {
+		return ruleValue{}, err
+	}
+	switch {
+	case token.kind == ruleTokenString:
+		return ruleValue{text: token.text}, nil
+	case token.kind == ruleTokenIdentifier && (strings.EqualFold(token.text, "true") || strings.EqualFold(token.text, "false")):
+		return ruleValue{text: strings.ToLower(token.text)}, nil
+	case token.kind == ruleTokenIdentifier && strings.EqualFold(token.text, "null"):
+		return ruleValue{null: true}, nil
+	case token.kind == ruleTokenIdentifier && strings.EqualFold(token.text, "system.now"):
+		value := ruleValue{now: true}
+		for _, sign := range []string{"plus", "minus"} {
+			if !p.accept(ruleTokenOperator, sign) {
+				continue
+			}
+			duration, err := p.next("duration")
+			if err != nil {
+				return ruleValue{}, err
+			}
+			if duration.kind != ruleTokenIdentifier || !systemNowPattern.MatchString(duration.text) {
+				p.pos--
+				return ruleValue{}, p.errorf("duration like p1d expected instead of %v", duration)
+			}
+			value.text = "-" + sign + " " + strings.ToLower(duration.text)
+			break
+		}
+		return value, nil
+	case token.kind == ruleTokenPunctuation && token.text == "[":
+		value := ruleValue{list: []string{}}
+		for !p.accept(ruleTokenPunctuation, "]") {
+			if len(value.list) > 0 {
+				if err := p.expect(ruleTokenPunctuation, ","); err != nil {
+					return ruleValue{}, err
+				}
+			}
+			element, err := p.parseValue()
+			if err != nil {
+				return ruleValue{}, err
+			}
+			if element.list != nil || element.null || element.now {
+				p.pos--
+				return ruleValue{}, p.errorf("list elements must be strings")
+			}
+			value.list = append(value.list, element.text)
+		}
+		return value, nil
+	}
+	p.pos--
+	return ruleValue{}, p.errorf("value expected instead of %v", token)
+}
@@ -0,0 +1,158 @@
+package msgraph
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseMembershipRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		wantErr string // part of the error message, empty if the rule is valid
+	}{
+		{name: "Equals", rule: `user.department -eq "Sales"`},
+		{name: "Case-insensitive", rule: `User.Department -EQ "Sales" -AND user.accountEnabled -eq TRUE`},
+		{name: "Parentheses and not", rule: `-not (user.city -eq "Vienna" -or user.city -eq null) -and user.jobTitle -startsWith "Head"`},
+		{name: "Lists", rule: `user.country -in ["AT", "DE"] -or user.usageLocation -notIn []`},
+		{name: "Match", rule: `user.mail -match "^.*@contoso\.com$"`},
+		{name: "Any", rule: `user.otherMails -any (_ -contains "contoso")`},
+		{name: "All plans", rule: `user.assignedPlans -all (assignedPlan.capabilityStatus -eq "Enabled")`},
+		{name: "Escaped quote", rule: "user.displayName -eq \"The `\"A`\" team\""},
+		{name: "Extension attributes", rule: `user.extensionAttribute15 -eq "x" -and user.extension_b0e2_costCenter -ne null`},
+		{name: "Empty", rule: " ", wantErr: "empty"},
+		{name: "Unknown property", rule: `user.departement -eq "Sales"`, wantErr: "unknown property user.departement at position 0"},
+		{name: "Extension attribute 16", rule: `user.extensionAttribute16 -eq "x"`, wantErr: "unknown property"},
+		{name: "Single quotes", rule: `user.department -eq 'Sales' -and user.displayName -ne 'O` + "`" + `'Brien'`},
+		{name: "Member of groups", rule: `user.memberof -any (group.objectId -in ['a','b'])`},
+		{name: "Hire date", rule: `user.employeeHireDate -le system.now -plus p1d -and user.employeeHireDate -gt system.now -minus P1MT12H`},
+		{name: "Hire date string", rule: `user.employeeHireDate -ge "2021-01-01T00:00:00Z"`},
+		{name: "Device rule", rule: `device.deviceOSType -eq "Windows" -and device.systemLabels -any (_ -eq "M365Managed")`},
+		{name: "Direct reports", rule: `Direct Reports for "62e19b97-8b3d-4d4a-a106-4ce66896a863"`},
+		{name: "Unsupported operator", rule: `user.department -endsWith "s"`, wantErr: "unsupported operator -endswith at position 16"},
+		{name: "Mixed user and device", rule: `user.department -eq "Sales" -or device.deviceOSType -eq "Windows"`, wantErr: "cannot be mixed, found device.deviceOSType at position 32"},
+		{name: "Multi-valued device property", rule: `device.devicePhysicalIds -eq "x"`, wantErr: "device.devicePhysicalIds is multi-valued"},
+		{name: "System now with eq", rule: `user.employeeHireDate -eq system.now`, wantErr: "system.now can only be compared"},
+		{name: "Invalid duration", rule: `user.employeeHireDate -le system.now -plus 1d`, wantErr: "duration like p1d expected"},
+		{name: "Group outside member of", rule: `group.objectId -eq "a"`, wantErr: "property like user.department expected"},
+		{name: "Unterminated single quote", rule: `user.department -eq 'Sales"`, wantErr: "unterminated string at position 20"},
+		{name: "Missing value", rule: `user.department -eq`, wantErr: "value expected at the end"},
+		{name: "Unterminated string", rule: `user.department -eq "Sales`, wantErr: "unterminated string at position 20"},
+		{name: "Missing parenthesis", rule: `(user.department -eq "Sales"`, wantErr: `")" expected at the end`},
+		{name: "Trailing tokens", rule: `user.department -eq "Sales" "IT"`, wantErr: `unexpected string "IT" at position 28`},
+		{name: "Multi-valued without any", rule: `user.otherMails -contains "x"`, wantErr: "requires -any or -all"},
+		{name: "Any on single value", rule: `user.department -any (_ -eq "x")`, wantErr: "requires a multi-valued property"},
+		{name: "Underscore outside any", rule: `_ -eq "x"`, wantErr: "only be used within -any and -all"},
+		{name: "In without list", rule: `user.country -in "AT"`, wantErr: "requires a list"},
+		{name: "Null with contains", rule: `user.country -contains null`, wantErr: "null can only be compared"},
+		{name: "Invalid regular expression", rule: `user.mail -match "(["`, wantErr: "invalid regular expression"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseMembershipRule(tt.rule)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ParseMembershipRule() error = %v", err)
+				}
+				if rule.String() != strings.TrimSpace(tt.rule) {
+					t.Errorf("MembershipRule.String() = %v, want %v", rule, tt.rule)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseMembershipRule() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMembershipRule_Evaluate(t *testing.T) {
+	var user User
+	if err := json.Unmarshal([]byte(`{"id":"1","department":"Sales","city":"Vienna","accountEnabled":true,
+		"businessPhones":["+43 1 234"],"otherMails":["alice@contoso.com","alice@example.com"],
+		"onPremisesExtensionAttributes":{"extensionAttribute1":"Contractor"},"extension_b0e2_costCenter":"CC-4711"}`), &user); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	tests := []struct {
+		rule    string
+		want    bool
+		wantErr bool
+	}{
+		{rule: `user.department -eq "sales"`, want: true},
+		{rule: `user.department -eq 'sales' -and user.city -in ['Graz','Vienna']`, want: true},
+		{rule: `user.department -ne "Sales"`, want: false},
+		{rule: `user.jobTitle -eq null`, want: true},
+		{rule: `user.city -ne null -and user.city -startsWith "VIE"`, want: true},
+		{rule: `user.city -notStartsWith "Vie" -or user.department -contains "ale"`, want: true},
+		{rule: `user.city -notContains "enn"`, want: false},
+		{rule: `user.objectId -in ["0", "1"]`, want: true},
+		{rule: `user.country -notIn ["AT"]`, want: true},
+		{rule: `user.accountEnabled -eq true -and user.dirSyncEnabled -eq false`, want: true},
+		{rule: `user.telephoneNumber -match "^\+43"`, want: true},
+		{rule: `user.city -notMatch "^v"`, want: false},
+		{rule: `-not (user.department -eq "Sales")`, want: false},
+		{rule: `user.otherMails -any (_ -endsWith "x")`, wantErr: true},
+		{rule: `user.otherMails -any (_ -contains "example")`, want: true},
+		{rule: `user.otherMails -all (_ -contains "example")`, want: false},
+		{rule: `user.proxyAddresses -all (_ -contains "example")`, want: true},
+		{rule: `user.extensionAttribute1 -eq "contractor" -and user.extension_b0e2_costCenter -eq "CC-4711"`, want: true},
+		{rule: `user.department -eq "IT" -and user.memberOf -any (_ -eq "x")`, want: false},
+		{rule: `user.memberOf -any (_ -eq "x")`, wantErr: true},
+		{rule: `user.assignedPlans -any (assignedPlan.capabilityStatus -eq "Enabled")`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			rule, err := ParseMembershipRule(tt.rule)
+			if err != nil {
+				if !tt.wantErr {
+					t.Fatalf("ParseMembershipRule() error = %v", err)
+				}
+				return
+			}
+			got, err := rule.Evaluate(user)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MembershipRule.Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("MembershipRule.Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	for _, rule := range []string{
+		`user.memberof -any (group.objectId -in ['a','b'])`,
+		`user.employeeHireDate -le system.now -plus p1d`,
+		`user.objectId -le system.now`,
+		`device.deviceOSType -eq "Windows"`,
+		`Direct Reports for "62e19b97-8b3d-4d4a-a106-4ce66896a863"`,
+	} {
+		parsed, err := ParseMembershipRule(rule)
+		if err != nil {
+			t.Fatalf("ParseMembershipRule(%v) error = %v", rule, err)
+		}
+		if _, err := parsed.Evaluate(user); err == nil || !strings.Contains(err.Error(), "cannot be evaluated locally") {
+			t.Errorf("MembershipRule.Evaluate() of %v error = %v, want the rule cannot be evaluated locally", rule, err)
+		}
+	}
+
+	if _, err := (MembershipRule{}).Evaluate(user); err == nil {
+		t.Errorf("MembershipRule.Evaluate() of an empty rule error = nil, want error")
+	}
+}
+
+func TestMembershipRule_Filter(t *testing.T) {
+	rule, err := ParseMembershipRule(`user.department -eq "Sales" -or (user.extensionAttribute3 -eq "x" -and user.otherMails -any (_ -eq "a@b.c"))`)
+	if err != nil {
+		t.Fatalf("ParseMembershipRule() error = %v", err)
+	}
+	if got, want := strings.Join(rule.UserProperties(), ","), "department,onPremisesExtensionAttributes,otherMails"; got != want {
+		t.Errorf("MembershipRule.UserProperties() = %v, want %v", got, want)
+	}
+	users := Users{{ID: "1", Department: "Sales"}, {ID: "2", Department: "IT"}, {ID: "3", OtherMails: []string{"a@b.c"},
+		OnPremisesExtensionAttributes: &OnPremisesExtensionAttributes{ExtensionAttribute3: "x"}}}
+	got, err := rule.Filter(users)
+	if err != nil || len(got) != 2 || got[0].ID != "1" || got[1].ID != "3" {
+		t.Errorf("MembershipRule.Filter() = %v, error = %v, want the users 1 and 3", got, err)
+	}
+}
@@ -0,0 +1,214 @@
+package msgraph
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// maxNotificationBodySize limits the size of a notification batch read by the NotificationHandler.
+const maxNotificationBodySize = 10 << 20
+
+// defaultNotificationQueueSize is the number of batches waiting for dispatch if
+// NotificationHandler.QueueSize is not set.
+const defaultNotificationQueueSize = 100
+
+// NotificationHandler is a http.Handler that receives change notifications and lifecycle
+// notifications of subscriptions, see GraphClient.CreateSubscription. Use its URL as
+// NotificationURL and LifecycleNotificationURL of the Subscription.
+//
+// The handler answers the validation request of Microsoft Graph, verifies the ClientState of
+// every notification and acknowledges valid batches immediately with 202 Accepted, as
+// Microsoft Graph requires an answer within 3 seconds. Notifications with a wrong ClientState
+// are dropped and reported to OnError.
+//
+// The notifications are dispatched to the callbacks and the channel afterwards by a single
+// goroutine: callbacks are never called concurrently, and batches are dispatched in the order
+// they have been received, the notifications of a batch in the order of the batch. Batches
+// are queued until dispatched, a batch is rejected with 503 Service Unavailable if the queue
+// is full, hence delivered again by Microsoft Graph later on. Use Close to stop dispatching.
+//
+// Example:
+//
+//	handler := msgraph.NewNotificationHandler("secret client state")
+//	handler.OnNotification = func(n msgraph.ChangeNotification) { log.Printf("%v changed: %v", n.Resource, n.ChangeType) }
+//	http.Handle("/notifications", handler)
+//
+// See https://docs.microsoft.com/en-us/graph/webhooks#processing-the-change-notification
+type NotificationHandler struct {
+	// ClientState must match the ClientState of every notification, hence the ClientState of
+	// the Subscription. Notifications are not verified if empty.
+	ClientState string
+	// OnNotification is called for every change notification, may be nil.
+	OnNotification func(notification ChangeNotification)
+	// OnLifecycleNotification is called for every lifecycle notification, may be nil. If nil,
+	// lifecycle notifications are passed to OnNotification instead.
+	OnLifecycleNotification func(notification ChangeNotification)
+	// Notifications receives all change notifications and lifecycle notifications, may be nil.
+	// The channel must be read continuously, otherwise dispatching blocks.
+	Notifications chan<- ChangeNotification
+	// OnError is called for every request or notification that is rejected, may be nil.
+	OnError func(err error)
+	// ValidationTokenValidator validates the validation tokens of batches containing resource
+	// data, see Subscription.IncludeResourceData. Batches with invalid or missing tokens are
+	// rejected. Validation tokens are not validated if nil.
+	ValidationTokenValidator *ValidationTokenValidator
+	// QueueSize is the maximum number of batches waiting for dispatch, defaults to 100.
+	QueueSize int
+
+	mu     sync.Mutex
+	queue  chan []ChangeNotification
+	done   chan struct{}
+	closed bool
+}
+
+// NewNotificationHandler returns a new NotificationHandler that verifies the given client state.
+func NewNotificationHandler(clientState string) *NotificationHandler {
+	return &NotificationHandler{ClientState: clientState}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *NotificationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// validation request when creating a subscription, the token must be returned as plain text within 10 seconds
+	// see https://docs.microsoft.com/en-us/graph/webhooks#notification-endpoint-validation
+	if validationToken := r.URL.Query().Get("validationToken"); validationToken != "" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(validationToken))
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxNotificationBodySize))
+	if err != nil {
+		h.reportError(fmt.Errorf("cannot read notification body: %v", err))
+		http.Error(w, "cannot read body", http.StatusBadRequest)
+		return
+	}
+	var collection ChangeNotificationCollection
+	if err := json.Unmarshal(body, &collection); err != nil {
+		h.reportError(fmt.Errorf("cannot parse notifications: %v", err))
+		http.Error(w, "invalid notification body", http.StatusBadRequest)
+		return
+	}
+
+	if h.ValidationTokenValidator != nil && hasEncryptedContent(collection.Value) {
+		if err := h.ValidationTokenValidator.ValidateAll(collection.ValidationTokens); err != nil {
+			h.reportError(fmt.Errorf("notifications rejected: %v", err))
+			http.Error(w, "invalid validation tokens", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var notifications []ChangeNotification
+	for _, notification := range collection.Value {
+		if !h.verifyClientState(notification) {
+			h.reportError(fmt.Errorf("invalid clientState of notification for subscription %v, notification dropped", notification.SubscriptionID))
+			continue
+		}
+		notifications = append(notifications, notification)
+	}
+	if len(notifications) > 0 && !h.enqueue(notifications) {
+		h.reportError(fmt.Errorf("notification queue is full or closed, %v notifications rejected", len(notifications)))
+		http.Error(w, "notification queue is full", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Close stops accepting notifications and waits until all queued batches have been
+// dispatched. Batches received afterwards are rejected with 503 Service Unavailable.
+func (h *NotificationHandler) Close() {
+	h.mu.Lock()
+	if !h.closed {
+		h.closed = true
+		if h.queue != nil {
+			close(h.queue)
+		}
+	}
+	done := h.done
+	h.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+// enqueue queues the notifications for dispatch and starts the dispatching goroutine on first
+// use. Returns false if the queue is full or the handler is closed.
+func (h *NotificationHandler) enqueue(notifications []ChangeNotification) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return false
+	}
+	if h.queue == nil {
+		size := h.QueueSize
+		if size <= 0 {
+			size = defaultNotificationQueueSize
+		}
+		h.queue = make(chan []ChangeNotification, size)
+		h.done = make(chan struct{})
+		go h.work(h.queue, h.done)
+	}
+	select {
+	case h.queue <- notifications:
+		return true
+	default:
+		return false
+	}
+}
+
+// work dispatches the queued batches one after another until the queue is closed.
+func (h *NotificationHandler) work(queue <-chan []ChangeNotification, done chan<- struct{}) {
+	defer close(done)
+	for notifications := range queue {
+		h.dispatch(notifications)
+	}
+}
+
+// verifyClientState returns true if the ClientState of the notification matches h.ClientState.
+func (h *NotificationHandler) verifyClientState(notification ChangeNotification) bool {
+	if h.ClientState == "" {
+		return true
+	}
+	return subtle.ConstantTimeCompare([]byte(notification.ClientState), []byte(h.ClientState)) == 1
+}
+
+// hasEncryptedContent returns true if any of the notifications contains resource data.
+func hasEncryptedContent(notifications []ChangeNotification) bool {
+	for _, notification := range notifications {
+		if notification.EncryptedContent != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// dispatch passes the notifications to the callbacks and the channel.
+func (h *NotificationHandler) dispatch(notifications []ChangeNotification) {
+	for _, notification := range notifications {
+		if notification.IsLifecycleNotification() && h.OnLifecycleNotification != nil {
+			h.OnLifecycleNotification(notification)
+		} else if h.OnNotification != nil {
+			h.OnNotification(notification)
+		}
+		if h.Notifications != nil {
+			h.Notifications <- notification
+		}
+	}
+}
+
+// reportError passes the error to OnError, if set.
+func (h *NotificationHandler) reportError(err error) {
+	if h.OnError != nil {
+		h.OnError(err)
+	}
+}
@@ -0,0 +1,216 @@
+package msgraph
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNotificationHandler_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+		wantBody   string
+		wantIDs    []string
+		wantErrors int
+	}{
+		{
+			name:       "Validation request",
+			method:     http.MethodPost,
+			target:     "/notifications?validationToken=" + url.QueryEscape("Validation: token <1>"),
+			wantStatus: http.StatusOK,
+			wantBody:   "Validation: token <1>",
+		}, {
+			name:       "Wrong method",
+			method:     http.MethodGet,
+			target:     "/notifications",
+			wantStatus: http.StatusMethodNotAllowed,
+		}, {
+			name:       "Invalid JSON",
+			method:     http.MethodPost,
+			target:     "/notifications",
+			body:       `{"value": [`,
+			wantStatus: http.StatusBadRequest,
+			wantErrors: 1,
+		}, {
+			name:   "Batch with wrong clientState",
+			method: http.MethodPost,
+			target: "/notifications",
+			body: `{"value": [
+				{"id": "1", "subscriptionId": "s1", "clientState": "secret", "changeType": "updated", "resource": "Users/u1", "resourceData": {"id": "u1"}},
+				{"id": "2", "subscriptionId": "s1", "clientState": "wrong", "changeType": "updated", "resource": "Users/u2", "resourceData": {"id": "u2"}},
+				{"id": "3", "subscriptionId": "s1", "clientState": "secret", "lifecycleEvent": "reauthorizationRequired"}
+			]}`,
+			wantStatus: http.StatusAccepted,
+			wantIDs:    []string{"1", "3"},
+			wantErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifications := make(chan ChangeNotification, 10)
+			var errs int
+			handler := NewNotificationHandler("secret")
+			handler.Notifications = notifications
+			handler.OnError = func(err error) { errs++ }
+			defer handler.Close()
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("NotificationHandler.ServeHTTP() status = %v, want %v", rec.Code, tt.wantStatus)
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("NotificationHandler.ServeHTTP() body = %v, want %v", rec.Body.String(), tt.wantBody)
+			}
+			if errs != tt.wantErrors {
+				t.Errorf("NotificationHandler.OnError called %v times, want %v", errs, tt.wantErrors)
+			}
+			for _, wantID := range tt.wantIDs {
+				select {
+				case notification := <-notifications:
+					if notification.ID != wantID {
+						t.Errorf("NotificationHandler dispatched %v, want ID %v", notification, wantID)
+					}
+				case <-time.After(time.Second):
+					t.Fatalf("NotificationHandler did not dispatch notification %v", wantID)
+				}
+			}
+		})
+	}
+}
+
+func TestNotificationHandler_dispatchOrder(t *testing.T) {
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var dispatched []string
+	var running, maxRunning int
+	handler := NewNotificationHandler("secret")
+	handler.QueueSize = 3
+	handler.OnNotification = func(n ChangeNotification) {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+		if n.ID == "1" {
+			<-release // hold back the first batch, hence the following batches are queued
+		}
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		running--
+		dispatched = append(dispatched, n.ID)
+		mu.Unlock()
+	}
+	var rejected int
+	handler.OnError = func(err error) { rejected++ }
+
+	post := func(ids ...string) int {
+		var value []string
+		for _, id := range ids {
+			value = append(value, fmt.Sprintf(`{"id": %q, "subscriptionId": "s1", "clientState": "secret", "changeType": "updated", "resource": "Users/u%v"}`, id, id))
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/notifications", strings.NewReader(`{"value": [`+strings.Join(value, ",")+`]}`)))
+		return rec.Code
+	}
+	if code := post("1", "2"); code != http.StatusAccepted {
+		t.Fatalf("NotificationHandler.ServeHTTP() status = %v, want %v", code, http.StatusAccepted)
+	}
+	// wait until the first batch is dispatched, it leaves the queue
+	for deadline := time.Now().Add(time.Second); ; {
+		mu.Lock()
+		r := running
+		mu.Unlock()
+		if r == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("NotificationHandler did not dispatch the first batch")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	for _, batch := range [][]string{{"3"}, {"4", "5"}, {"6"}} {
+		if code := post(batch...); code != http.StatusAccepted {
+			t.Fatalf("NotificationHandler.ServeHTTP(%v) status = %v, want %v", batch, code, http.StatusAccepted)
+		}
+	}
+	if code := post("7"); code != http.StatusServiceUnavailable || rejected != 1 {
+		t.Errorf("NotificationHandler.ServeHTTP() with full queue status = %v, OnError called %v times, want %v and 1", code, rejected, http.StatusServiceUnavailable)
+	}
+
+	close(release)
+	handler.Close()
+	if want := []string{"1", "2", "3", "4", "5", "6"}; strings.Join(dispatched, ",") != strings.Join(want, ",") {
+		t.Errorf("NotificationHandler dispatched %v, want %v", dispatched, want)
+	}
+	if maxRunning != 1 {
+		t.Errorf("NotificationHandler ran %v callbacks concurrently, want 1", maxRunning)
+	}
+	if code := post("8"); code != http.StatusServiceUnavailable {
+		t.Errorf("NotificationHandler.ServeHTTP() after Close() status = %v, want %v", code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestNotificationHandler_Subscription(t *testing.T) {
+	if offlineServer == nil {
+		t.Skip("Skipping notification tests, Microsoft Graph cannot reach a local endpoint")
+	}
+	changes := make(chan ChangeNotification, 1)
+	lifecycle := make(chan ChangeNotification, 1)
+	handler := NewNotificationHandler("go-msgraph unit-test " + randomString(16))
+	handler.OnNotification = func(n ChangeNotification) { changes <- n }
+	handler.OnLifecycleNotification = func(n ChangeNotification) { lifecycle <- n }
+	endpoint := httptest.NewServer(handler)
+	defer endpoint.Close()
+	defer handler.Close()
+
+	subscription, err := graphClient.CreateSubscription(Subscription{
+		Resource:                 "users",
+		ChangeType:               ChangeTypeUpdated,
+		NotificationURL:          endpoint.URL,
+		LifecycleNotificationURL: endpoint.URL,
+		ClientState:              handler.ClientState,
+	})
+	if err != nil {
+		t.Fatalf("GraphClient.CreateSubscription() error = %v", err)
+	}
+	defer subscription.DeleteSubscription()
+
+	user, err := graphClient.GetUser(msGraphExistingUserPrincipalInGroup)
+	if err != nil {
+		t.Fatalf("GraphClient.GetUser() error = %v", err)
+	}
+	if err := offlineServer.Notify(subscription.ID, ChangeTypeUpdated, user.ID); err != nil {
+		t.Fatalf("msgraphtest.Server.Notify() error = %v", err)
+	}
+	select {
+	case n := <-changes:
+		if n.SubscriptionID != subscription.ID || n.ChangeType != ChangeTypeUpdated || n.ResourceData.ID != user.ID || n.IsLifecycleNotification() {
+			t.Errorf("NotificationHandler.OnNotification() = %v, want update of %v", n, user.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("NotificationHandler.OnNotification() not called")
+	}
+
+	if err := offlineServer.NotifyLifecycle(subscription.ID, LifecycleEventReauthorizationRequired); err != nil {
+		t.Fatalf("msgraphtest.Server.NotifyLifecycle() error = %v", err)
+	}
+	select {
+	case n := <-lifecycle:
+		if n.LifecycleEvent != LifecycleEventReauthorizationRequired || !n.IsLifecycleNotification() {
+			t.Errorf("NotificationHandler.OnLifecycleNotification() = %v, want %v", n, LifecycleEventReauthorizationRequired)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("NotificationHandler.OnLifecycleNotification() not called")
+	}
+}
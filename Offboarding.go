@@ -0,0 +1,252 @@
+package msgraph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Steps of an Offboarding, in the order they are performed.
+const (
+	OffboardingStepDisableAccount         = "disableAccount"
+	OffboardingStepRevokeSignInSessions   = "revokeSignInSessions"
+	OffboardingStepRemoveGroupMemberships = "removeGroupMemberships"
+	OffboardingStepRemoveLicenses         = "removeLicenses"
+	OffboardingStepRemoveManager          = "removeManager"
+	OffboardingStepSetAutomaticReplies    = "setAutomaticReplies"
+)
+
+// offboardingSteps are all steps of an Offboarding in the order they are performed.
+var offboardingSteps = []string{
+	OffboardingStepDisableAccount,
+	OffboardingStepRevokeSignInSessions,
+	OffboardingStepRemoveGroupMemberships,
+	OffboardingStepRemoveLicenses,
+	OffboardingStepRemoveManager,
+	OffboardingStepSetAutomaticReplies,
+}
+
+// Offboarding configures the steps of User.Offboard for a leaver. The account is disabled
+// first, then the sign-in sessions are revoked, the direct group memberships and the directly
+// assigned licenses are removed, the manager is reset and optionally automatic replies are
+// enabled for the mailbox.
+//
+// Every step is performed even if a previous step failed, all steps are idempotent. Hence a
+// partially failed offboarding can be resumed by offboarding again with the failed steps, see
+// OffboardingResult.FailedSteps.
+type Offboarding struct {
+	// Steps to perform, any of the OffboardingStep* constants. All steps are performed if empty,
+	// OffboardingStepSetAutomaticReplies only if AutomaticReplyMessage is set.
+	Steps []string
+	// AutomaticReplyMessage enables the automatic replies of the mailbox with this message for
+	// internal and external senders, e.g. "I have left the company, please contact ...".
+	AutomaticReplyMessage string
+	// DryRun plans all POST, PATCH and DELETE API-calls instead of sending them, see
+	// GraphClient.Plan. The current state of the user is still read to plan the steps.
+	DryRun bool
+}
+
+// OffboardingStepResult is the result of a step of an Offboarding. Steps that affect multiple
+// objects, e.g. OffboardingStepRemoveGroupMemberships, have a result per object.
+type OffboardingStepResult struct {
+	Step    string // one of the OffboardingStep* constants
+	Target  string // ID of the affected group or SKU, empty for steps that affect the user only
+	Skipped bool   // true if there was nothing to do, e.g. because the user has no manager
+	Err     error  // nil if the step succeeded or has been planned in dry-run mode
+}
+
+func (r OffboardingStepResult) String() string {
+	step := r.Step
+	if r.Target != "" {
+		step += " " + r.Target
+	}
+	switch {
+	case r.Err != nil:
+		return fmt.Sprintf("%v: failed: %v", step, r.Err)
+	case r.Skipped:
+		return fmt.Sprintf("%v: skipped", step)
+	}
+	return fmt.Sprintf("%v: ok", step)
+}
+
+// OffboardingResult contains the results of all steps of User.Offboard.
+type OffboardingResult struct {
+	UserID string
+	DryRun bool
+	Steps  []OffboardingStepResult
+}
+
+func (r OffboardingResult) String() string {
+	var steps = make([]string, len(r.Steps))
+	for i, step := range r.Steps {
+		steps[i] = step.String()
+	}
+	return fmt.Sprintf("OffboardingResult(UserID: \"%v\", DryRun: %v, Steps: [%v])", r.UserID, r.DryRun, strings.Join(steps, ", "))
+}
+
+// Failed returns the results of all failed steps.
+func (r OffboardingResult) Failed() []OffboardingStepResult {
+	var failed []OffboardingStepResult
+	for _, step := range r.Steps {
+		if step.Err != nil {
+			failed = append(failed, step)
+		}
+	}
+	return failed
+}
+
+// FailedSteps returns the distinct failed steps, e.g. to resume the offboarding with
+// Offboarding.Steps.
+func (r OffboardingResult) FailedSteps() []string {
+	var steps []string
+	for _, step := range r.Failed() {
+		if !containsString(steps, step.Step) {
+			steps = append(steps, step.Step)
+		}
+	}
+	return steps
+}
+
+// Err returns an error summarizing all failed steps, or nil if all steps succeeded.
+func (r OffboardingResult) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	var messages = make([]string, len(failed))
+	for i, step := range failed {
+		messages[i] = step.String()
+	}
+	return fmt.Errorf("offboarding of user %v failed in %v step(s): %v", r.UserID, len(failed), strings.Join(messages, "; "))
+}
+
+// Offboard performs the steps of the Offboarding for the user and returns the result of every
+// step, see Offboarding. Use OffboardingResult.Err to check whether all steps succeeded.
+// Only direct group memberships and directly assigned licenses can be removed, memberships
+// of dynamic groups and group-based licenses are reported as failed steps.
+func (u User) Offboard(ctx context.Context, o Offboarding) OffboardingResult {
+	result := OffboardingResult{UserID: u.ID, DryRun: o.DryRun}
+	if u.graphClient == nil {
+		result.Steps = append(result.Steps, OffboardingStepResult{Step: OffboardingStepDisableAccount, Err: ErrNotGraphClientSourced})
+		return result
+	}
+
+	steps := o.Steps
+	if len(steps) == 0 {
+		steps = offboardingSteps
+	}
+	for _, step := range steps {
+		if !containsString(offboardingSteps, step) {
+			result.Steps = append(result.Steps, OffboardingStepResult{Step: step, Err: fmt.Errorf("unknown offboarding step %q", step)})
+		}
+	}
+
+	updateOpts := []UpdateQueryOption{UpdateWithContext(ctx)}
+	deleteOpts := []DeleteQueryOption{DeleteWithContext(ctx)}
+	if o.DryRun {
+		updateOpts = append(updateOpts, UpdateWithDryRun())
+		deleteOpts = append(deleteOpts, DeleteWithDryRun())
+	}
+
+	// the current state of the user determines the licenses and the manager to remove
+	var current struct {
+		AssignedLicenses []AssignedLicense `json:"assignedLicenses"`
+		Manager          *struct {
+			ID string `json:"id"`
+		} `json:"manager"`
+	}
+	reqOpt := compileGetQueryOptions([]GetQueryOption{GetWithContext(ctx)})
+	reqOpt.queryValues.Set("$select", "id,assignedLicenses")
+	reqOpt.queryValues.Set("$expand", "manager($select=id)")
+	errCurrent := u.graphClient.makeGETAPICall(fmt.Sprintf("/users/%v", u.ID), reqOpt, &current)
+
+	for _, step := range offboardingSteps {
+		if !containsString(steps, step) {
+			continue
+		}
+		switch step {
+		case OffboardingStepDisableAccount:
+			err := u.PatchUser(NewUserPatch().SetAccountEnabled(false), updateOpts...)
+			result.Steps = append(result.Steps, OffboardingStepResult{Step: step, Err: err})
+		case OffboardingStepRevokeSignInSessions:
+			err := u.RevokeSignInSessions(updateOpts...)
+			result.Steps = append(result.Steps, OffboardingStepResult{Step: step, Err: err})
+		case OffboardingStepRemoveGroupMemberships:
+			groupIDs, err := u.listDirectMemberOfGroupIDs(ctx)
+			if err != nil {
+				result.Steps = append(result.Steps, OffboardingStepResult{Step: step, Err: err})
+				continue
+			}
+			if len(groupIDs) == 0 {
+				result.Steps = append(result.Steps, OffboardingStepResult{Step: step, Skipped: true})
+			}
+			for _, groupID := range groupIDs {
+				err := u.graphClient.removeGroupMember(groupID, u.ID, deleteOpts...)
+				result.Steps = append(result.Steps, OffboardingStepResult{Step: step, Target: groupID, Err: err})
+			}
+		case OffboardingStepRemoveLicenses:
+			if errCurrent != nil {
+				result.Steps = append(result.Steps, OffboardingStepResult{Step: step, Err: errCurrent})
+				continue
+			}
+			if len(current.AssignedLicenses) == 0 {
+				result.Steps = append(result.Steps, OffboardingStepResult{Step: step, Skipped: true})
+			}
+			for _, license := range current.AssignedLicenses {
+				_, err := u.AssignLicenses(nil, []string{license.SkuID}, updateOpts...)
+				result.Steps = append(result.Steps, OffboardingStepResult{Step: step, Target: license.SkuID, Err: err})
+			}
+		case OffboardingStepRemoveManager:
+			if errCurrent != nil {
+				result.Steps = append(result.Steps, OffboardingStepResult{Step: step, Err: errCurrent})
+				continue
+			}
+			if current.Manager == nil {
+				result.Steps = append(result.Steps, OffboardingStepResult{Step: step, Skipped: true})
+				continue
+			}
+			err := u.RemoveManager(deleteOpts...)
+			result.Steps = append(result.Steps, OffboardingStepResult{Step: step, Target: current.Manager.ID, Err: err})
+		case OffboardingStepSetAutomaticReplies:
+			if o.AutomaticReplyMessage == "" {
+				result.Steps = append(result.Steps, OffboardingStepResult{Step: step, Skipped: true})
+				continue
+			}
+			err := u.enableAutomaticReplies(o.AutomaticReplyMessage, updateOpts...)
+			result.Steps = append(result.Steps, OffboardingStepResult{Step: step, Err: err})
+		}
+	}
+	return result
+}
+
+// listDirectMemberOfGroupIDs returns the IDs of all groups the user is a direct member of.
+// Directory roles and administrative units are ignored.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/user-list-memberof
+func (u User) listDirectMemberOfGroupIDs(ctx context.Context) ([]string, error) {
+	resource := fmt.Sprintf("/users/%v/memberOf", u.ID)
+
+	var marsh struct {
+		Value []struct {
+			ODataType string `json:"@odata.type"`
+			ID        string `json:"id"`
+		} `json:"value"`
+	}
+	reqOpt := compileListQueryOptions([]ListQueryOption{ListWithContext(ctx), ListWithSelect("id")})
+	if err := u.graphClient.makeGETAPICall(resource, reqOpt, &marsh); err != nil {
+		return nil, err
+	}
+	var groupIDs []string
+	for _, object := range marsh.Value {
+		if object.ODataType == "#microsoft.graph.group" {
+			groupIDs = append(groupIDs, object.ID)
+		}
+	}
+	return groupIDs, nil
+}
+
+// enableAutomaticReplies enables the automatic replies of the mailbox of the user with the
+// message for internal and external senders.
+func (u User) enableAutomaticReplies(message string, opts ...UpdateQueryOption) error {
+	return u.UpdateMailboxSettings(NewMailboxSettingsPatch().EnableAutomaticReplies(message, message), opts...)
+}
@@ -0,0 +1,116 @@
+package msgraph
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/open-networks/go-msgraph/msgraphtest"
+)
+
+func TestUser_RevokeSignInSessions(t *testing.T) {
+	if err := (User{ID: "none"}).RevokeSignInSessions(); err != ErrNotGraphClientSourced {
+		t.Errorf("User.RevokeSignInSessions() error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+	testuser := createUnitTestUser(t)
+	defer testuser.DeleteUser()
+	if err := testuser.RevokeSignInSessions(); err != nil {
+		t.Errorf("User.RevokeSignInSessions() error = %v", err)
+	}
+}
+
+func TestUser_Offboard(t *testing.T) {
+	result := (User{ID: "none"}).Offboard(context.Background(), Offboarding{})
+	if len(result.Steps) != 1 || result.Steps[0].Err != ErrNotGraphClientSourced {
+		t.Errorf("User.Offboard() = %v, want %v", result, ErrNotGraphClientSourced)
+	}
+
+	leaver := createUnitTestUser(t)
+	defer leaver.DeleteUser()
+	manager := createUnitTestUser(t)
+	defer manager.DeleteUser()
+	if err := leaver.SetManager(manager.ID); err != nil {
+		t.Fatalf("User.SetManager() error = %v", err)
+	}
+	offboarding := Offboarding{}
+	if offlineServer != nil {
+		// group memberships cannot be added and mailboxes do not exist for unlicensed users
+		// created by the unit tests, hence these steps are only tested offline
+		group, err := graphClient.GetGroup(offlineServer.List("/groups")[0].ID())
+		if err != nil {
+			t.Fatalf("GraphClient.GetGroup() error = %v", err)
+		}
+		offlineServer.AddGroupMember(group.ID, leaver.ID)
+		offboarding.AutomaticReplyMessage = "I have left the company."
+
+		skus, _ := graphClient.ListSubscribedSkus()
+		leaver.PatchUser(NewUserPatch().SetUsageLocation("AT"))
+		if _, err := leaver.AssignLicenses([]AssignedLicense{{SkuID: skus[0].SkuID}}, nil); err != nil {
+			t.Fatalf("User.AssignLicenses() error = %v", err)
+		}
+	}
+
+	// dry-run plans the steps, but does not change the user
+	planned := len(graphClient.Plan())
+	dryRun := offboarding
+	dryRun.DryRun = true
+	result = leaver.Offboard(context.Background(), dryRun)
+	if err := result.Err(); err != nil {
+		t.Fatalf("User.Offboard() with DryRun error = %v", err)
+	}
+	if len(graphClient.Plan()) <= planned {
+		t.Errorf("User.Offboard() with DryRun did not plan any operations: %v", result)
+	}
+	if got, err := leaver.GetManager(); err != nil || got.ID != manager.ID {
+		t.Errorf("User.Offboard() with DryRun removed the manager: %v, error = %v", got, err)
+	}
+	graphClient.ResetPlan()
+
+	if offlineServer != nil {
+		// a failed step is reported, the other steps are still performed
+		offlineServer.InjectFault(msgraphtest.Fault{Method: http.MethodPost, Path: "/users/" + leaver.ID + "/revokeSignInSessions", StatusCode: http.StatusInternalServerError, Times: 1})
+		result = leaver.Offboard(context.Background(), offboarding)
+		if failed := result.FailedSteps(); len(failed) != 1 || failed[0] != OffboardingStepRevokeSignInSessions {
+			t.Fatalf("User.Offboard() with fault FailedSteps() = %v, want [%v]", failed, OffboardingStepRevokeSignInSessions)
+		}
+		if result.Err() == nil {
+			t.Errorf("OffboardingResult.Err() = nil, want error")
+		}
+		offboarding.Steps = result.FailedSteps()
+	}
+
+	result = leaver.Offboard(context.Background(), offboarding)
+	if err := result.Err(); err != nil {
+		t.Fatalf("User.Offboard() error = %v", err)
+	}
+	got, err := graphClient.GetUser(leaver.ID, GetWithSelect("id,accountEnabled,assignedLicenses"))
+	if err != nil {
+		t.Fatalf("GraphClient.GetUser() error = %v", err)
+	}
+	if got.AccountEnabled || len(got.AssignedLicenses) != 0 {
+		t.Errorf("User.Offboard() did not disable the account or remove the licenses: AccountEnabled = %v, AssignedLicenses = %v", got.AccountEnabled, got.AssignedLicenses)
+	}
+	if _, err := leaver.GetManager(); err == nil {
+		t.Errorf("User.Offboard() did not remove the manager")
+	}
+	if groups, err := got.GetMemberGroupsAsStrings(false); err != nil || len(groups) != 0 {
+		t.Errorf("User.Offboard() did not remove the group memberships: %v, error = %v", groups, err)
+	}
+	if offlineServer != nil {
+		settings := offlineServer.MailboxSettings(leaver.ID)
+		if replies, _ := settings["automaticRepliesSetting"].(map[string]interface{}); replies["status"] != "alwaysEnabled" {
+			t.Errorf("User.Offboard() did not enable automatic replies: %v", settings)
+		}
+	}
+
+	// offboarding again skips the steps that have nothing to do
+	result = leaver.Offboard(context.Background(), Offboarding{Steps: []string{OffboardingStepRemoveManager, OffboardingStepRemoveLicenses}})
+	for _, step := range result.Steps {
+		if !step.Skipped || step.Err != nil {
+			t.Errorf("User.Offboard() again = %v, want skipped", step)
+		}
+	}
+	if result := leaver.Offboard(context.Background(), Offboarding{Steps: []string{"unknown"}}); result.Err() == nil {
+		t.Errorf("User.Offboard() with unknown step error = nil, want error")
+	}
+}
@@ -0,0 +1,27 @@
+package msgraph
+
+import (
+	"fmt"
+)
+
+// OrgContact represents an organizational contact, hence a person outside the organization
+// managed in the directory, e.g. a member of a distribution group.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/orgcontact
+type OrgContact struct {
+	ID             string   `json:"id"`
+	DisplayName    string   `json:"displayName"`
+	GivenName      string   `json:"givenName"`
+	Surname        string   `json:"surname"`
+	Mail           string   `json:"mail"`
+	MailNickname   string   `json:"mailNickname"`
+	CompanyName    string   `json:"companyName"`
+	Department     string   `json:"department"`
+	JobTitle       string   `json:"jobTitle"`
+	ProxyAddresses []string `json:"proxyAddresses"`
+}
+
+func (o OrgContact) String() string {
+	return fmt.Sprintf("OrgContact(ID: \"%v\", DisplayName: \"%v\", Mail: \"%v\", CompanyName: \"%v\")",
+		o.ID, o.DisplayName, o.Mail, o.CompanyName)
+}
@@ -0,0 +1,102 @@
+package msgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// patch tracks the properties of a resource that have been set explicitly, hence sends exactly
+// those - including false, empty and null values. It is embedded by the typed patches like
+// UserPatch, which add the setters of the properties.
+type patch struct {
+	typeName   string   // name of the patched type, e.g. User
+	allowed    []string // json names of the properties that can be set
+	properties map[string]interface{}
+	err        error // first error of set or fail, returned by MarshalJSON
+}
+
+// newPatch returns an empty patch of the type with the given name that allows to set the
+// properties with the given json names.
+func newPatch(typeName string, allowed []string) patch {
+	return patch{typeName: typeName, allowed: allowed, properties: make(map[string]interface{})}
+}
+
+// set sets the property with the given json name to the value, or fails the patch if the
+// property cannot be set.
+func (p *patch) set(property string, value interface{}) {
+	if !containsString(p.allowed, property) {
+		p.fail(fmt.Errorf("unknown or read-only property %q of %v", property, p.typeName))
+		return
+	}
+	p.properties[property] = value
+}
+
+// fail fails the patch with the error, unless it has failed before.
+func (p *patch) fail(err error) {
+	if p.err == nil {
+		p.err = err
+	}
+}
+
+// IsSet returns true if the property with the given json name has been set or cleared.
+func (p *patch) IsSet(property string) bool {
+	_, ok := p.properties[property]
+	return ok
+}
+
+// Properties returns the sorted json names of all properties that have been set or cleared.
+func (p *patch) Properties() []string {
+	properties := make([]string, 0, len(p.properties))
+	for property := range p.properties {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+	return properties
+}
+
+func (p *patch) String() string {
+	data, err := p.MarshalJSON()
+	if err != nil {
+		return fmt.Sprintf("%vPatch(error: %v)", p.typeName, err)
+	}
+	return fmt.Sprintf("%vPatch(%s)", p.typeName, data)
+}
+
+// MarshalJSON implements the json.Marshaler interface and returns exactly the set properties.
+func (p *patch) MarshalJSON() ([]byte, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return json.Marshal(p.properties)
+}
+
+// jsonPropertyNames returns the json names of the exported fields of the struct type t,
+// except the excluded ones.
+func jsonPropertyNames(t reflect.Type, exclude ...string) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" { // unexported
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" || containsString(exclude, name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// containsString returns true if the list contains the value.
+func containsString(list []string, value string) bool {
+	for _, element := range list {
+		if element == value {
+			return true
+		}
+	}
+	return false
+}
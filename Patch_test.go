@@ -0,0 +1,29 @@
+package msgraph
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPatch_String(t *testing.T) {
+	tests := []struct {
+		patch          fmt.Stringer
+		want           string
+		wantProperties []string
+	}{
+		{patch: NewUserPatch().SetJobTitle("Engineer").Clear("department"), want: `UserPatch({"department":null,"jobTitle":"Engineer"})`},
+		{patch: NewUserPatch().Set("jobtitel", "x"), want: `UserPatch(error: unknown or read-only property "jobtitel" of User)`},
+		{patch: NewMailboxSettingsPatch().SetTimeZone("UTC"), want: `MailboxSettingsPatch({"timeZone":"UTC"})`},
+	}
+	for _, tt := range tests {
+		if got := tt.patch.String(); got != tt.want {
+			t.Errorf("String() = %v, want %v", got, tt.want)
+		}
+	}
+
+	patch := NewUserPatch().SetSurname("Doe").SetDisplayName("Jane Doe")
+	if got := strings.Join(patch.Properties(), ","); got != "displayName,surname" || !patch.IsSet("surname") || patch.IsSet("givenName") {
+		t.Errorf("UserPatch.Properties() = %v, want displayName,surname", got)
+	}
+}
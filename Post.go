@@ -0,0 +1,78 @@
+package msgraph
+
+import (
+	"fmt"
+	"time"
+)
+
+// Post represents a message in a ConversationThread, see ConversationThread.ListPosts. New
+// posts are sent with Group.CreateConversation, ConversationThread.Reply and Post.Reply.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/post
+type Post struct {
+	ID                   string      `json:"id"`
+	Body                 ItemBody    `json:"body"`
+	From                 Recipient   `json:"from"`   // read-only, the group or user the post was sent as
+	Sender               Recipient   `json:"sender"` // read-only, the user that sent the post
+	ReceivedDateTime     time.Time   `json:"receivedDateTime"`
+	CreatedDateTime      time.Time   `json:"createdDateTime"`
+	LastModifiedDateTime time.Time   `json:"lastModifiedDateTime"`
+	HasAttachments       bool        `json:"hasAttachments"`
+	ConversationID       string      `json:"conversationId"`
+	ConversationThreadID string      `json:"conversationThreadId"`
+	NewParticipants      []Recipient `json:"newParticipants"` // recipients added to the thread by the post
+	Attachments          Attachments `json:"attachments"`     // only used for new posts, see Post.ListAttachments
+
+	groupID     string       // the ID of the group of the post, part of the resource path of its API-calls
+	threadID    string       // the ID of the thread of the post, part of the resource path of its API-calls
+	graphClient *GraphClient // the graphClient that called the post
+}
+
+func (p Post) String() string {
+	return fmt.Sprintf("Post(ID: \"%v\", From: \"%v\", ReceivedDateTime: \"%v\", Body: %v, HasAttachments: %v, ConversationThreadID: \"%v\", DirectAPIConnection: %v)",
+		p.ID, p.From, p.ReceivedDateTime, p.Body, p.HasAttachments, p.ConversationThreadID, p.graphClient != nil)
+}
+
+// setThread sets the graphClient and the IDs of the group and the thread of the post.
+func (p *Post) setThread(gC *GraphClient, groupID, threadID string) {
+	p.graphClient = gC
+	p.groupID = groupID
+	p.threadID = threadID
+}
+
+// requestBody returns the properties of the post that can be set when sending a new post.
+func (p Post) requestBody() interface{} {
+	return struct {
+		Body            ItemBody    `json:"body"`
+		NewParticipants []Recipient `json:"newParticipants,omitempty"`
+		Attachments     Attachments `json:"attachments,omitempty"`
+	}{p.Body, p.NewParticipants, p.Attachments}
+}
+
+// Reply adds the given post to the thread of this post as reply to it, see
+// ConversationThread.Reply.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/post-reply
+func (p Post) Reply(post Post, opts ...CreateQueryOption) error {
+	if p.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	return p.graphClient.replyToPost(fmt.Sprintf("/groups/%v/threads/%v/posts/%v/reply", p.groupID, p.threadID, p.ID), post, opts)
+}
+
+// ListAttachments returns the attachments of the post including their ContentBytes.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/post-list-attachments
+func (p Post) ListAttachments(opts ...ListQueryOption) (Attachments, error) {
+	if p.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/groups/%v/threads/%v/posts/%v/attachments", p.groupID, p.threadID, p.ID)
+
+	var marsh struct {
+		Attachments Attachments `json:"value"`
+	}
+	err := p.graphClient.makeGETAPICall(resource, compileListQueryOptions(opts), &marsh)
+	return marsh.Attachments, err
+}
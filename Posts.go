@@ -0,0 +1,24 @@
+package msgraph
+
+import (
+	"strings"
+)
+
+// Posts represents multiple Post-instances.
+type Posts []Post
+
+func (p Posts) String() string {
+	var posts = make([]string, len(p))
+	for i, post := range p {
+		posts[i] = post.String()
+	}
+	return "Posts(" + strings.Join(posts, " | ") + ")"
+}
+
+// setThread sets the graphClient and the IDs of the group and the thread in all posts.
+func (p Posts) setThread(gC *GraphClient, groupID, threadID string) Posts {
+	for i := range p {
+		p[i].setThread(gC, groupID, threadID)
+	}
+	return p
+}
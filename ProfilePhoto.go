@@ -0,0 +1,122 @@
+package msgraph
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// Sizes of profile photos, see User.GetPhotoOfSize and Group.GetPhotoOfSize. Only the sizes
+// up to the size of the uploaded photo are available, use User.ListPhotos to get them.
+const (
+	PhotoSize48x48   = "48x48"
+	PhotoSize64x64   = "64x64"
+	PhotoSize96x96   = "96x96"
+	PhotoSize120x120 = "120x120"
+	PhotoSize240x240 = "240x240"
+	PhotoSize360x360 = "360x360"
+	PhotoSize432x432 = "432x432"
+	PhotoSize504x504 = "504x504"
+	PhotoSize648x648 = "648x648"
+)
+
+// ProfilePhoto represents the metadata of a profile photo of a user or group.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/profilephoto
+type ProfilePhoto struct {
+	ID          string `json:"id"` // size of the photo, e.g. 240X240
+	Height      int    `json:"height"`
+	Width       int    `json:"width"`
+	ContentType string `json:"@odata.mediaContentType"` // e.g. image/jpeg
+	ETag        string `json:"@odata.mediaEtag"`        // changes whenever the photo is replaced
+}
+
+func (p ProfilePhoto) String() string {
+	return fmt.Sprintf("ProfilePhoto(ID: \"%v\", Height: %v, Width: %v, ContentType: \"%v\", ETag: \"%v\")",
+		p.ID, p.Height, p.Width, p.ContentType, p.ETag)
+}
+
+// Photo is the binary content of a profile photo, see User.GetPhoto.
+type Photo struct {
+	ContentType string // e.g. image/jpeg
+	Content     []byte
+}
+
+func (p Photo) String() string {
+	return fmt.Sprintf("Photo(ContentType: \"%v\", Size: %v bytes)", p.ContentType, len(p.Content))
+}
+
+// Reader returns a reader of the photo content, e.g. to serve it via http.ServeContent.
+func (p Photo) Reader() io.Reader {
+	return bytes.NewReader(p.Content)
+}
+
+// PhotoStream is the binary content of a profile photo streamed from Microsoft Graph, see
+// User.OpenPhoto. Read the content from it and close it afterwards.
+type PhotoStream struct {
+	ContentType   string // e.g. image/jpeg
+	ContentLength int64  // -1 if unknown
+	io.ReadCloser
+}
+
+func (p PhotoStream) String() string {
+	return fmt.Sprintf("PhotoStream(ContentType: \"%v\", ContentLength: %v)", p.ContentType, p.ContentLength)
+}
+
+// getPhoto returns the binary content of the photo of the given resource, e.g. /users/{id}/photo.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/profilephoto-get
+func (g *GraphClient) getPhoto(resource string, opts []GetQueryOption) (Photo, error) {
+	var raw rawResponse
+	if err := g.makeGETAPICall(resource+"/$value", compileGetQueryOptions(opts), &raw); err != nil {
+		return Photo{}, err
+	}
+	return Photo{ContentType: raw.contentType, Content: raw.body}, nil
+}
+
+// openPhoto returns the binary content of the photo of the given resource, e.g. /users/{id}/photo,
+// as stream without reading it into memory.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/profilephoto-get
+func (g *GraphClient) openPhoto(resource string, opts []GetQueryOption) (PhotoStream, error) {
+	var stream streamResponse
+	if err := g.makeGETAPICall(resource+"/$value", compileGetQueryOptions(opts), &stream); err != nil {
+		return PhotoStream{}, err
+	}
+	return PhotoStream{ContentType: stream.contentType, ContentLength: stream.contentLength, ReadCloser: stream.body}, nil
+}
+
+// listPhotos returns the metadata of all available sizes of the photo of the given resource,
+// e.g. /users/{id}/photos.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/profilephoto-get
+func (g *GraphClient) listPhotos(resource string, opts []ListQueryOption) (ProfilePhotos, error) {
+	var marsh struct {
+		Photos ProfilePhotos `json:"value"`
+	}
+	err := g.makeGETAPICall(resource, compileListQueryOptions(opts), &marsh)
+	return marsh.Photos, err
+}
+
+// setPhoto uploads the photo to the given resource, e.g. /users/{id}/photo. The content type
+// is detected from the photo if empty.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/profilephoto-update
+func (g *GraphClient) setPhoto(resource string, photo io.Reader, contentType string, opts []UpdateQueryOption) error {
+	content, err := ioutil.ReadAll(photo)
+	if err != nil {
+		return fmt.Errorf("cannot read photo: %v", err)
+	}
+	if len(content) == 0 {
+		return fmt.Errorf("photo must not be empty")
+	}
+	if contentType == "" {
+		contentType = http.DetectContentType(content)
+	}
+
+	body := &binaryBody{Reader: bytes.NewReader(content), contentType: contentType}
+	// Hint: API-call body does not return any data / no json object.
+	return g.makePUTAPICall(resource+"/$value", compileUpdateQueryOptions(opts), body, nil)
+}
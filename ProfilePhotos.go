@@ -0,0 +1,41 @@
+package msgraph
+
+import (
+	"strings"
+)
+
+// ProfilePhotos represents multiple ProfilePhoto-instances and provides funcs to work with them.
+type ProfilePhotos []ProfilePhoto
+
+func (p ProfilePhotos) String() string {
+	var photos = make([]string, len(p))
+	for i, photo := range p {
+		photos[i] = photo.String()
+	}
+	return "ProfilePhotos(" + strings.Join(photos, " | ") + ")"
+}
+
+// GetBySize returns the ProfilePhoto of the given size, e.g. PhotoSize240x240, compared
+// case-insensitively. Returns ErrFindProfilePhoto if there is none.
+func (p ProfilePhotos) GetBySize(size string) (ProfilePhoto, error) {
+	for _, photo := range p {
+		if strings.EqualFold(photo.ID, size) {
+			return photo, nil
+		}
+	}
+	return ProfilePhoto{}, ErrFindProfilePhoto
+}
+
+// Largest returns the ProfilePhoto with the largest width. Returns ErrFindProfilePhoto if there is none.
+func (p ProfilePhotos) Largest() (ProfilePhoto, error) {
+	if len(p) == 0 {
+		return ProfilePhoto{}, ErrFindProfilePhoto
+	}
+	largest := p[0]
+	for _, photo := range p[1:] {
+		if photo.Width > largest.Width {
+			largest = photo
+		}
+	}
+	return largest, nil
+}
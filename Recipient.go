@@ -0,0 +1,12 @@
+package msgraph
+
+// Recipient represents the sender or a recipient of a Post or ConversationThread.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/recipient
+type Recipient struct {
+	EmailAddress EmailAddress `json:"emailAddress"`
+}
+
+func (r Recipient) String() string {
+	return r.EmailAddress.String()
+}
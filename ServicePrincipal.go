@@ -0,0 +1,25 @@
+package msgraph
+
+import (
+	"fmt"
+)
+
+// ServicePrincipal represents an instance of an application in the tenant, e.g. a member or
+// owner of a group.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/serviceprincipal
+type ServicePrincipal struct {
+	ID                     string   `json:"id"`
+	AppID                  string   `json:"appId"`
+	AppDisplayName         string   `json:"appDisplayName"`
+	DisplayName            string   `json:"displayName"`
+	AccountEnabled         bool     `json:"accountEnabled"`
+	ServicePrincipalType   string   `json:"servicePrincipalType"` // e.g. "Application" or "ManagedIdentity"
+	ServicePrincipalNames  []string `json:"servicePrincipalNames"`
+	AppOwnerOrganizationID string   `json:"appOwnerOrganizationId"`
+}
+
+func (s ServicePrincipal) String() string {
+	return fmt.Sprintf("ServicePrincipal(ID: \"%v\", AppID: \"%v\", DisplayName: \"%v\", ServicePrincipalType: \"%v\")",
+		s.ID, s.AppID, s.DisplayName, s.ServicePrincipalType)
+}
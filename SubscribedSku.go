@@ -0,0 +1,68 @@
+package msgraph
+
+import (
+	"fmt"
+)
+
+// SubscribedSku represents a commercial subscription of the tenant, e.g. ENTERPRISEPACK for
+// Office 365 E3, including its prepaid and consumed license units.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/subscribedsku
+type SubscribedSku struct {
+	ID               string             `json:"id"`
+	SkuID            string             `json:"skuId"`
+	SkuPartNumber    string             `json:"skuPartNumber"`    // e.g. ENTERPRISEPACK
+	AppliesTo        string             `json:"appliesTo"`        // User or Company
+	CapabilityStatus string             `json:"capabilityStatus"` // Enabled, Warning, Suspended, Deleted or LockedOut
+	ConsumedUnits    int                `json:"consumedUnits"`    // number of assigned licenses
+	PrepaidUnits     LicenseUnitsDetail `json:"prepaidUnits"`
+	ServicePlans     []ServicePlanInfo  `json:"servicePlans"`
+}
+
+// LicenseUnitsDetail contains the number of prepaid license units of a SubscribedSku per state.
+type LicenseUnitsDetail struct {
+	Enabled   int `json:"enabled"`   // units that are active and can be assigned
+	Suspended int `json:"suspended"` // units that are suspended, e.g. because the subscription has been cancelled
+	Warning   int `json:"warning"`   // units in warning state, e.g. because the subscription has not been renewed
+	LockedOut int `json:"lockedOut"`
+}
+
+// ServicePlanInfo represents a service plan of a SKU, e.g. EXCHANGE_S_ENTERPRISE. Use the
+// ServicePlanID in AssignedLicense.DisabledPlans to disable the service plan for a user.
+type ServicePlanInfo struct {
+	ServicePlanID      string `json:"servicePlanId"`
+	ServicePlanName    string `json:"servicePlanName"`
+	ProvisioningStatus string `json:"provisioningStatus,omitempty"` // e.g. Success, Disabled, PendingInput
+	AppliesTo          string `json:"appliesTo"`                    // User or Company
+}
+
+func (s SubscribedSku) String() string {
+	return fmt.Sprintf("SubscribedSku(SkuID: \"%v\", SkuPartNumber: \"%v\", CapabilityStatus: \"%v\", ConsumedUnits: %v, PrepaidUnits: %v)",
+		s.SkuID, s.SkuPartNumber, s.CapabilityStatus, s.ConsumedUnits, s.PrepaidUnits.Enabled)
+}
+
+// AvailableUnits returns the number of enabled prepaid units that have not been assigned
+// yet. A negative number means the SKU is over-assigned by that many units.
+func (s SubscribedSku) AvailableUnits() int {
+	return s.PrepaidUnits.Enabled - s.ConsumedUnits
+}
+
+// IsOverAssigned returns true if more licenses of the SKU are assigned than enabled units
+// are prepaid, e.g. after a subscription has been reduced or has moved to the warning state.
+func (s SubscribedSku) IsOverAssigned() bool {
+	return s.AvailableUnits() < 0
+}
+
+// ListSubscribedSkus returns the commercial subscriptions of the tenant.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/subscribedsku-list
+func (g *GraphClient) ListSubscribedSkus(opts ...ListQueryOption) (SubscribedSkus, error) {
+	resource := "/subscribedSkus"
+
+	var marsh struct {
+		SubscribedSkus SubscribedSkus `json:"value"`
+	}
+	err := g.makeGETAPICall(resource, compileListQueryOptions(opts), &marsh)
+	return marsh.SubscribedSkus, err
+}
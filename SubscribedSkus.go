@@ -0,0 +1,50 @@
+package msgraph
+
+import (
+	"strings"
+)
+
+// SubscribedSkus represents multiple SubscribedSku-instances and provides funcs to work with them.
+type SubscribedSkus []SubscribedSku
+
+func (s SubscribedSkus) String() string {
+	var skus = make([]string, len(s))
+	for i, sku := range s {
+		skus[i] = sku.String()
+	}
+	return "SubscribedSkus(" + strings.Join(skus, " | ") + ")"
+}
+
+// GetBySkuPartNumber returns the SubscribedSku with the given SkuPartNumber, e.g. ENTERPRISEPACK,
+// compared case-insensitively. Returns ErrFindSubscribedSku if there is none.
+func (s SubscribedSkus) GetBySkuPartNumber(skuPartNumber string) (SubscribedSku, error) {
+	for _, sku := range s {
+		if strings.EqualFold(sku.SkuPartNumber, skuPartNumber) {
+			return sku, nil
+		}
+	}
+	return SubscribedSku{}, ErrFindSubscribedSku
+}
+
+// GetBySkuID returns the SubscribedSku with the given SkuID. Returns ErrFindSubscribedSku if there is none.
+func (s SubscribedSkus) GetBySkuID(skuID string) (SubscribedSku, error) {
+	for _, sku := range s {
+		if strings.EqualFold(sku.SkuID, skuID) {
+			return sku, nil
+		}
+	}
+	return SubscribedSku{}, ErrFindSubscribedSku
+}
+
+// OverAssigned returns all SKUs that have more licenses assigned than enabled units are
+// prepaid, see SubscribedSku.IsOverAssigned. Use SubscribedSku.AvailableUnits to get the
+// number of licenses that have to be removed.
+func (s SubscribedSkus) OverAssigned() SubscribedSkus {
+	var skus SubscribedSkus
+	for _, sku := range s {
+		if sku.IsOverAssigned() {
+			skus = append(skus, sku)
+		}
+	}
+	return skus
+}
@@ -0,0 +1,44 @@
+package msgraph
+
+import (
+	"testing"
+)
+
+func TestSubscribedSkus_OverAssigned(t *testing.T) {
+	skus := SubscribedSkus{
+		{SkuID: "1", SkuPartNumber: "ENTERPRISEPACK", ConsumedUnits: 10, PrepaidUnits: LicenseUnitsDetail{Enabled: 25}},
+		{SkuID: "2", SkuPartNumber: "POWER_BI_STANDARD", ConsumedUnits: 12, PrepaidUnits: LicenseUnitsDetail{Enabled: 10, Warning: 2}},
+		{SkuID: "3", SkuPartNumber: "FLOW_FREE", ConsumedUnits: 5, PrepaidUnits: LicenseUnitsDetail{Enabled: 5}},
+	}
+	tests := []struct {
+		name          string
+		skuPartNumber string
+		wantAvailable int
+		wantOver      bool
+	}{
+		{name: "Available units", skuPartNumber: "ENTERPRISEPACK", wantAvailable: 15},
+		{name: "Warning units do not count", skuPartNumber: "power_bi_standard", wantAvailable: -2, wantOver: true},
+		{name: "Fully assigned", skuPartNumber: "FLOW_FREE", wantAvailable: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sku, err := skus.GetBySkuPartNumber(tt.skuPartNumber)
+			if err != nil {
+				t.Fatalf("SubscribedSkus.GetBySkuPartNumber() error = %v", err)
+			}
+			if got := sku.AvailableUnits(); got != tt.wantAvailable {
+				t.Errorf("SubscribedSku.AvailableUnits() = %v, want %v", got, tt.wantAvailable)
+			}
+			if got := sku.IsOverAssigned(); got != tt.wantOver {
+				t.Errorf("SubscribedSku.IsOverAssigned() = %v, want %v", got, tt.wantOver)
+			}
+		})
+	}
+
+	if over := skus.OverAssigned(); len(over) != 1 || over[0].SkuID != "2" {
+		t.Errorf("SubscribedSkus.OverAssigned() = %v, want POWER_BI_STANDARD", over)
+	}
+	if _, err := skus.GetBySkuID("4"); err != ErrFindSubscribedSku {
+		t.Errorf("SubscribedSkus.GetBySkuID() error = %v, want %v", err, ErrFindSubscribedSku)
+	}
+}
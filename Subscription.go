@@ -0,0 +1,180 @@
+package msgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Change types of a Subscription, combine multiple with a comma, e.g. "created,updated"
+const (
+	ChangeTypeCreated = "created"
+	ChangeTypeUpdated = "updated"
+	ChangeTypeDeleted = "deleted"
+)
+
+// Subscription represents a change notification subscription, Microsoft Graph sends a
+// notification to NotificationURL whenever the Resource changes until ExpirationDateTime.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/subscription?view=graph-rest-1.0
+type Subscription struct {
+	ID                        string    `json:"id,omitempty"`
+	Resource                  string    `json:"resource,omitempty"`                 // e.g. users, groups, users/{id}/events or security/alerts?$filter=status eq 'newAlert'
+	ChangeType                string    `json:"changeType,omitempty"`               // comma separated list of ChangeTypeCreated, ChangeTypeUpdated, ChangeTypeDeleted
+	NotificationURL           string    `json:"notificationUrl,omitempty"`          // https URL that receives the notifications
+	LifecycleNotificationURL  string    `json:"lifecycleNotificationUrl,omitempty"` // https URL that receives lifecycle notifications, e.g. reauthorizationRequired
+	ClientState               string    `json:"clientState,omitempty"`              // secret sent along with every notification, max. 128 characters
+	ExpirationDateTime        time.Time `json:"expirationDateTime"`
+	IncludeResourceData       bool      `json:"includeResourceData,omitempty"`
+	EncryptionCertificate     string    `json:"encryptionCertificate,omitempty"`   // base64-encoded public key certificate, required if IncludeResourceData is true
+	EncryptionCertificateID   string    `json:"encryptionCertificateId,omitempty"` // identifies the certificate to decrypt resource data
+	LatestSupportedTLSVersion string    `json:"latestSupportedTlsVersion,omitempty"`
+	ApplicationID             string    `json:"applicationId,omitempty"` // read-only
+	CreatorID                 string    `json:"creatorId,omitempty"`     // read-only
+
+	graphClient *GraphClient // the graphClient that created or listed the subscription
+}
+
+func (s Subscription) String() string {
+	return fmt.Sprintf("Subscription(ID: \"%v\", Resource: \"%v\", ChangeType: \"%v\", NotificationURL: \"%v\", LifecycleNotificationURL: \"%v\", ExpirationDateTime: \"%v\", IncludeResourceData: \"%v\", DirectAPIConnection: %v)",
+		s.ID, s.Resource, s.ChangeType, s.NotificationURL, s.LifecycleNotificationURL, s.ExpirationDateTime, s.IncludeResourceData, s.graphClient != nil)
+}
+
+// setGraphClient sets the graphClient instance in this instance and all child-instances (if any)
+func (s *Subscription) setGraphClient(gC *GraphClient) {
+	s.graphClient = gC
+}
+
+// MaxSubscriptionLifetime returns the maximum lifetime Microsoft Graph accepts for a
+// Subscription of the given resource, e.g. "users" or "/users/{id}/events". Resources that
+// are not known return the shortest lifetime of the common resources.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/subscription?view=graph-rest-1.0#maximum-length-of-subscription-per-resource-type
+func MaxSubscriptionLifetime(resource string) time.Duration {
+	resource = strings.ToLower(strings.TrimPrefix(resource, "/"))
+	if idx := strings.Index(resource, "?"); idx >= 0 {
+		resource = resource[:idx]
+	}
+	switch {
+	case strings.HasPrefix(resource, "teams") || strings.HasPrefix(resource, "chats") || strings.HasPrefix(resource, "communications/presences") ||
+		strings.Contains(resource, "/chats") || strings.Contains(resource, "/presence"):
+		return 60 * time.Minute
+	case strings.HasPrefix(resource, "security/alerts"):
+		return 43200 * time.Minute
+	case strings.Contains(resource, "/events") || strings.Contains(resource, "/messages") || strings.Contains(resource, "/contacts") ||
+		strings.Contains(resource, "/mailfolders") || strings.Contains(resource, "/todo/"):
+		return 10080 * time.Minute
+	case strings.Contains(resource, "/drive") || strings.HasPrefix(resource, "drives") || strings.Contains(resource, "/lists"):
+		return 42300 * time.Minute
+	case strings.Contains(resource, "/conversations"):
+		return 4230 * time.Minute
+	case strings.HasPrefix(resource, "users") || strings.HasPrefix(resource, "groups"):
+		return 41760 * time.Minute
+	default:
+		return 4230 * time.Minute
+	}
+}
+
+// subscriptionExpirationMargin is subtracted from the maximum lifetime of a Subscription
+// to not exceed it due to clock skew or request latency.
+const subscriptionExpirationMargin = time.Minute
+
+// maxSubscriptionExpiration returns the latest ExpirationDateTime Microsoft Graph accepts
+// for a Subscription of the given resource.
+func maxSubscriptionExpiration(resource string) time.Time {
+	return time.Now().Add(MaxSubscriptionLifetime(resource) - subscriptionExpirationMargin)
+}
+
+// CreateSubscription creates a new Subscription. Resource, ChangeType and NotificationURL
+// are required. If ExpirationDateTime is not set, the maximum lifetime of the resource is
+// used, see MaxSubscriptionLifetime. Microsoft Graph validates the NotificationURL before
+// the subscription is created, see https://docs.microsoft.com/en-us/graph/webhooks#notification-endpoint-validation
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/subscription-post-subscriptions?view=graph-rest-1.0
+func (g *GraphClient) CreateSubscription(subscriptionInput Subscription, opts ...CreateQueryOption) (Subscription, error) {
+	subscription := Subscription{graphClient: g}
+	if subscriptionInput.Resource == "" || subscriptionInput.ChangeType == "" || subscriptionInput.NotificationURL == "" {
+		return subscription, fmt.Errorf("Resource, ChangeType and NotificationURL are required to create a subscription")
+	}
+	if subscriptionInput.ExpirationDateTime.IsZero() {
+		subscriptionInput.ExpirationDateTime = maxSubscriptionExpiration(subscriptionInput.Resource)
+	}
+	bodyBytes, err := json.Marshal(subscriptionInput)
+	if err != nil {
+		return subscription, err
+	}
+
+	reader := bytes.NewReader(bodyBytes)
+	err = g.makePOSTAPICall("/subscriptions", compileCreateQueryOptions(opts), reader, &subscription)
+	return subscription, err
+}
+
+// ListSubscriptions returns all active subscriptions of the application.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/subscription-list?view=graph-rest-1.0
+func (g *GraphClient) ListSubscriptions(opts ...ListQueryOption) (Subscriptions, error) {
+	resource := "/subscriptions"
+	var marsh struct {
+		Subscriptions Subscriptions `json:"value"`
+	}
+	err := g.makeGETAPICall(resource, compileListQueryOptions(opts), &marsh)
+	marsh.Subscriptions.setGraphClient(g)
+	return marsh.Subscriptions, err
+}
+
+// GetSubscription returns the Subscription with the given ID.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/subscription-get?view=graph-rest-1.0
+func (g *GraphClient) GetSubscription(subscriptionID string, opts ...GetQueryOption) (Subscription, error) {
+	resource := fmt.Sprintf("/subscriptions/%v", subscriptionID)
+	subscription := Subscription{graphClient: g}
+	err := g.makeGETAPICall(resource, compileGetQueryOptions(opts), &subscription)
+	return subscription, err
+}
+
+// RenewSubscription extends the ExpirationDateTime of this subscription and returns the
+// renewed Subscription. If expirationDateTime is zero, the maximum lifetime of the resource
+// is used, see MaxSubscriptionLifetime. In dry-run the subscription is returned unchanged.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/subscription-update?view=graph-rest-1.0
+func (s Subscription) RenewSubscription(expirationDateTime time.Time, opts ...UpdateQueryOption) (Subscription, error) {
+	if s.graphClient == nil {
+		return s, ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/subscriptions/%v", s.ID)
+	if expirationDateTime.IsZero() {
+		expirationDateTime = maxSubscriptionExpiration(s.Resource)
+	}
+
+	bodyBytes, err := json.Marshal(struct {
+		ExpirationDateTime time.Time `json:"expirationDateTime"`
+	}{ExpirationDateTime: expirationDateTime})
+	if err != nil {
+		return s, err
+	}
+
+	reader := bytes.NewReader(bodyBytes)
+	reqParams := compileUpdateQueryOptions(opts)
+	// Hint: API-call body does not return any data / no json object.
+	err = s.graphClient.makePATCHAPICall(resource, reqParams, reader, nil)
+	if err != nil || s.graphClient.isDryRun(reqParams) {
+		return s, err
+	}
+	s.ExpirationDateTime = expirationDateTime
+	return s, nil
+}
+
+// DeleteSubscription deletes this subscription, hence no more notifications are sent.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/subscription-delete?view=graph-rest-1.0
+func (s Subscription) DeleteSubscription(opts ...DeleteQueryOption) error {
+	if s.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/subscriptions/%v", s.ID)
+
+	err := s.graphClient.makeDELETEAPICall(resource, compileDeleteQueryOptions(opts), nil)
+	return err
+}
@@ -0,0 +1,143 @@
+package msgraph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SubscriptionRenewer keeps subscriptions alive by renewing them before they expire. Each
+// subscription is renewed to the maximum lifetime of its resource, see MaxSubscriptionLifetime.
+//
+// Example:
+//
+//	renewer, err := msgraph.NewSubscriptionRenewer()
+//	renewer.OnError = func(s msgraph.Subscription, err error) { log.Printf("cannot renew %v: %v", s.ID, err) }
+//	renewer.Add(subscription)
+//	go renewer.Run(ctx)
+type SubscriptionRenewer struct {
+	// RenewBefore is the duration before the expiration of a subscription when it is renewed.
+	// If 0 or more than half of the maximum lifetime of the resource, half of it is used.
+	RenewBefore time.Duration
+	// CheckInterval is the interval Run checks for expiring subscriptions, defaults to one minute.
+	CheckInterval time.Duration
+	// OnRenewed is called with every renewed subscription, may be nil.
+	OnRenewed func(subscription Subscription)
+	// OnError is called if a subscription cannot be renewed, may be nil. The subscription is
+	// retried on the next check, use Remove to stop renewing it.
+	OnError func(subscription Subscription, err error)
+
+	mu            sync.Mutex
+	subscriptions Subscriptions
+}
+
+// NewSubscriptionRenewer returns a new SubscriptionRenewer that renews the given subscriptions.
+// All subscriptions must be sourced from a GraphClient, e.g. by CreateSubscription.
+func NewSubscriptionRenewer(subscriptions ...Subscription) (*SubscriptionRenewer, error) {
+	for _, subscription := range subscriptions {
+		if subscription.graphClient == nil {
+			return nil, ErrNotGraphClientSourced
+		}
+	}
+	return &SubscriptionRenewer{subscriptions: append(Subscriptions(nil), subscriptions...)}, nil
+}
+
+// Add adds the subscription to the renewer or replaces the one with the same ID. The
+// subscription must be sourced from a GraphClient, e.g. by CreateSubscription.
+func (r *SubscriptionRenewer) Add(subscription Subscription) error {
+	if subscription.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.subscriptions {
+		if r.subscriptions[i].ID == subscription.ID {
+			r.subscriptions[i] = subscription
+			return nil
+		}
+	}
+	r.subscriptions = append(r.subscriptions, subscription)
+	return nil
+}
+
+// Remove stops renewing the subscription with the given ID. The subscription is not deleted.
+func (r *SubscriptionRenewer) Remove(subscriptionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.subscriptions {
+		if r.subscriptions[i].ID == subscriptionID {
+			r.subscriptions = append(r.subscriptions[:i:i], r.subscriptions[i+1:]...)
+			return
+		}
+	}
+}
+
+// Subscriptions returns a copy of all subscriptions of the renewer with their current ExpirationDateTime.
+func (r *SubscriptionRenewer) Subscriptions() Subscriptions {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append(Subscriptions(nil), r.subscriptions...)
+}
+
+// renewBefore returns the duration before the expiration when the subscription is renewed.
+func (r *SubscriptionRenewer) renewBefore(subscription Subscription) time.Duration {
+	half := MaxSubscriptionLifetime(subscription.Resource) / 2
+	if r.RenewBefore <= 0 || r.RenewBefore > half {
+		return half
+	}
+	return r.RenewBefore
+}
+
+// RenewExpiring renews all subscriptions that expire within RenewBefore. Returns an error
+// listing all subscriptions that could not be renewed.
+func (r *SubscriptionRenewer) RenewExpiring(ctx context.Context) error {
+	var errs []string
+	for _, subscription := range r.Subscriptions() {
+		if time.Until(subscription.ExpirationDateTime) > r.renewBefore(subscription) {
+			continue
+		}
+		renewed, err := subscription.RenewSubscription(time.Time{}, UpdateWithContext(ctx))
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%v: %v", subscription.ID, err))
+			if r.OnError != nil {
+				r.OnError(subscription, err)
+			}
+			continue
+		}
+		r.mu.Lock()
+		for i := range r.subscriptions {
+			if r.subscriptions[i].ID == renewed.ID {
+				r.subscriptions[i] = renewed
+			}
+		}
+		r.mu.Unlock()
+		if r.OnRenewed != nil {
+			r.OnRenewed(renewed)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("cannot renew subscriptions: %v", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Run renews expiring subscriptions immediately and then every CheckInterval until the
+// context is cancelled. Errors are reported to OnError. Returns the error of the context.
+func (r *SubscriptionRenewer) Run(ctx context.Context) error {
+	interval := r.CheckInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		r.RenewExpiring(ctx) // errors are reported to OnError
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
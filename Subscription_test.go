@@ -0,0 +1,187 @@
+package msgraph
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// getTestNotificationURL returns msGraphNotificationURL or, when running offline, the URL of
+// a local endpoint that answers the subscription validation. Skips the test otherwise.
+func getTestNotificationURL(t *testing.T) string {
+	t.Helper()
+	if msGraphNotificationURL != "" {
+		return msGraphNotificationURL
+	}
+	if offlineServer == nil {
+		t.Skip("Skipping subscription tests due to missing 'MSGraphNotificationURL' value")
+	}
+	endpoint := httptest.NewServer(NewNotificationHandler(""))
+	t.Cleanup(endpoint.Close)
+	return endpoint.URL
+}
+
+func TestMaxSubscriptionLifetime(t *testing.T) {
+	tests := []struct {
+		resource string
+		want     time.Duration
+	}{
+		{resource: "users", want: 41760 * time.Minute},
+		{resource: "/groups", want: 41760 * time.Minute},
+		{resource: "groups/{id}/conversations", want: 4230 * time.Minute},
+		{resource: "/Groups/{id}/Conversations", want: 4230 * time.Minute},
+		{resource: "users/alice@contoso.com/events", want: 10080 * time.Minute},
+		{resource: "/me/mailFolders('inbox')/messages", want: 10080 * time.Minute},
+		{resource: "security/alerts?$filter=status eq 'newAlert'", want: 43200 * time.Minute},
+		{resource: "teams/{id}/channels/{id}/messages", want: 60 * time.Minute},
+		{resource: "communications/presences/{id}", want: 60 * time.Minute},
+		{resource: "me/drive/root", want: 42300 * time.Minute},
+		{resource: "communications/callRecords", want: 4230 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.resource, func(t *testing.T) {
+			if got := MaxSubscriptionLifetime(tt.resource); got != tt.want {
+				t.Errorf("MaxSubscriptionLifetime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGraphClient_CreateAndDeleteSubscription(t *testing.T) {
+	notificationURL := getTestNotificationURL(t)
+
+	if _, err := graphClient.CreateSubscription(Subscription{Resource: "users"}); err == nil {
+		t.Errorf("GraphClient.CreateSubscription() without ChangeType and NotificationURL error = nil, want error")
+	}
+
+	subscription, err := graphClient.CreateSubscription(Subscription{
+		Resource:        "users",
+		ChangeType:      ChangeTypeUpdated + "," + ChangeTypeDeleted,
+		NotificationURL: notificationURL,
+		ClientState:     "go-msgraph unit-test " + randomString(16),
+	})
+	if err != nil {
+		t.Fatalf("GraphClient.CreateSubscription() error = %v", err)
+	}
+	if subscription.ID == "" || subscription.graphClient == nil {
+		t.Errorf("GraphClient.CreateSubscription() = %v, want ID and graphClient set", subscription)
+	}
+	if maxExpiration := time.Now().Add(MaxSubscriptionLifetime("users")); subscription.ExpirationDateTime.After(maxExpiration) || subscription.ExpirationDateTime.Before(maxExpiration.Add(-time.Hour)) {
+		t.Errorf("GraphClient.CreateSubscription() ExpirationDateTime = %v, want close to the maximum lifetime %v", subscription.ExpirationDateTime, maxExpiration)
+	}
+
+	subscriptions, err := graphClient.ListSubscriptions()
+	if err != nil {
+		t.Errorf("GraphClient.ListSubscriptions() error = %v", err)
+	}
+	if len(subscriptions.GetByResource("/users")) == 0 {
+		t.Errorf("GraphClient.ListSubscriptions() = %v, want the created subscription", subscriptions)
+	}
+
+	expiration := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	planned, err := subscription.RenewSubscription(expiration, UpdateWithDryRun())
+	if err != nil {
+		t.Errorf("Subscription.RenewSubscription(UpdateWithDryRun()) error = %v", err)
+	}
+	if !planned.ExpirationDateTime.Equal(subscription.ExpirationDateTime) {
+		t.Errorf("Subscription.RenewSubscription(UpdateWithDryRun()) ExpirationDateTime = %v, want unchanged %v", planned.ExpirationDateTime, subscription.ExpirationDateTime)
+	}
+	renewed, err := subscription.RenewSubscription(expiration)
+	if err != nil {
+		t.Errorf("Subscription.RenewSubscription() error = %v", err)
+	}
+	got, err := graphClient.GetSubscription(subscription.ID)
+	if err != nil {
+		t.Errorf("GraphClient.GetSubscription() error = %v", err)
+	}
+	if !got.ExpirationDateTime.Equal(expiration) || !renewed.ExpirationDateTime.Equal(expiration) {
+		t.Errorf("Subscription.RenewSubscription() ExpirationDateTime = %v, got %v, want %v", renewed.ExpirationDateTime, got.ExpirationDateTime, expiration)
+	}
+
+	if err := subscription.DeleteSubscription(); err != nil {
+		t.Errorf("Subscription.DeleteSubscription() error = %v", err)
+	}
+	if _, err := graphClient.GetSubscription(subscription.ID); err == nil {
+		t.Errorf("GraphClient.GetSubscription() after DeleteSubscription() error = nil, want error")
+	}
+	if err := (Subscription{ID: "not-sourced"}).DeleteSubscription(); err != ErrNotGraphClientSourced {
+		t.Errorf("Subscription.DeleteSubscription() error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+}
+
+func TestSubscriptionRenewer(t *testing.T) {
+	notificationURL := getTestNotificationURL(t)
+
+	expiring, err := graphClient.CreateSubscription(Subscription{
+		Resource:           "groups",
+		ChangeType:         ChangeTypeUpdated,
+		NotificationURL:    notificationURL,
+		ExpirationDateTime: time.Now().Add(30 * time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("GraphClient.CreateSubscription() error = %v", err)
+	}
+	defer expiring.DeleteSubscription()
+	fresh, err := graphClient.CreateSubscription(Subscription{
+		Resource:        "users",
+		ChangeType:      ChangeTypeUpdated,
+		NotificationURL: notificationURL,
+	})
+	if err != nil {
+		t.Fatalf("GraphClient.CreateSubscription() error = %v", err)
+	}
+	defer fresh.DeleteSubscription()
+
+	if _, err := NewSubscriptionRenewer(expiring, Subscription{ID: "not-sourced"}); err != ErrNotGraphClientSourced {
+		t.Errorf("NewSubscriptionRenewer() error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+	renewer, err := NewSubscriptionRenewer(expiring)
+	if err != nil {
+		t.Fatalf("NewSubscriptionRenewer() error = %v", err)
+	}
+	renewer.RenewBefore = time.Hour
+	if err := renewer.Add(fresh); err != nil {
+		t.Fatalf("SubscriptionRenewer.Add() error = %v", err)
+	}
+	if err := renewer.Add(Subscription{ID: "not-sourced"}); err != ErrNotGraphClientSourced {
+		t.Errorf("SubscriptionRenewer.Add() error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+	var renewed []string
+	renewer.OnRenewed = func(subscription Subscription) { renewed = append(renewed, subscription.ID) }
+
+	if err := renewer.RenewExpiring(context.Background()); err != nil {
+		t.Fatalf("SubscriptionRenewer.RenewExpiring() error = %v", err)
+	}
+	if len(renewed) != 1 || renewed[0] != expiring.ID {
+		t.Errorf("SubscriptionRenewer.RenewExpiring() renewed %v, want only %v", renewed, expiring.ID)
+	}
+	got, err := graphClient.GetSubscription(expiring.ID)
+	if err != nil {
+		t.Fatalf("GraphClient.GetSubscription() error = %v", err)
+	}
+	if time.Until(got.ExpirationDateTime) < 24*time.Hour {
+		t.Errorf("SubscriptionRenewer.RenewExpiring() ExpirationDateTime = %v, want the maximum lifetime", got.ExpirationDateTime)
+	}
+
+	// unknown subscriptions are reported and retried
+	gone := expiring
+	gone.ID = "00000000-0000-0000-0000-000000000000"
+	gone.ExpirationDateTime = time.Now()
+	renewer.Add(gone)
+	var failed []string
+	renewer.OnError = func(subscription Subscription, err error) { failed = append(failed, subscription.ID) }
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	renewer.CheckInterval = 20 * time.Millisecond
+	if err := renewer.Run(ctx); err != context.DeadlineExceeded {
+		t.Errorf("SubscriptionRenewer.Run() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if len(failed) == 0 || failed[0] != gone.ID {
+		t.Errorf("SubscriptionRenewer.Run() OnError called with %v, want %v", failed, gone.ID)
+	}
+	renewer.Remove(gone.ID)
+	if subscriptions := renewer.Subscriptions(); len(subscriptions) != 2 {
+		t.Errorf("SubscriptionRenewer.Subscriptions() = %v, want 2", subscriptions)
+	}
+}
@@ -0,0 +1,36 @@
+package msgraph
+
+import (
+	"strings"
+)
+
+// Subscriptions represents multiple Subscription-instances and provides funcs to work with them.
+type Subscriptions []Subscription
+
+func (s Subscriptions) String() string {
+	var subscriptions = make([]string, len(s))
+	for i, subscription := range s {
+		subscriptions[i] = subscription.String()
+	}
+	return "Subscriptions(" + strings.Join(subscriptions, " | ") + ")"
+}
+
+// setGraphClient sets the GraphClient within that particular instance. Hence it's directly created by GraphClient
+func (s Subscriptions) setGraphClient(gC *GraphClient) Subscriptions {
+	for i := range s {
+		s[i].setGraphClient(gC)
+	}
+	return s
+}
+
+// GetByResource returns all subscriptions of the given resource, e.g. "users". Leading
+// slashes and the case are ignored.
+func (s Subscriptions) GetByResource(resource string) Subscriptions {
+	var subscriptions Subscriptions
+	for _, subscription := range s {
+		if strings.EqualFold(strings.TrimPrefix(subscription.Resource, "/"), strings.TrimPrefix(resource, "/")) {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	return subscriptions
+}
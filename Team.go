@@ -0,0 +1,182 @@
+package msgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Team represents the Microsoft Teams team of a Microsoft 365 group, see Group.CreateTeam.
+// The team has the same ID as its group, its members are the members of the group.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/team
+type Team struct {
+	ID                string                 `json:"id,omitempty"`          // read-only, the ID of the group
+	DisplayName       string                 `json:"displayName,omitempty"` // read-only, the display name of the group
+	Description       string                 `json:"description,omitempty"`
+	Visibility        string                 `json:"visibility,omitempty"` // read-only, the visibility of the group, e.g. GroupVisibilityPrivate
+	WebURL            string                 `json:"webUrl,omitempty"`     // read-only, link to the team in the Microsoft Teams client
+	IsArchived        bool                   `json:"isArchived,omitempty"` // read-only, archived teams are read-only
+	MemberSettings    *TeamMemberSettings    `json:"memberSettings,omitempty"`
+	GuestSettings     *TeamGuestSettings     `json:"guestSettings,omitempty"`
+	MessagingSettings *TeamMessagingSettings `json:"messagingSettings,omitempty"`
+
+	graphClient *GraphClient // the graphClient that called the team
+}
+
+// TeamMemberSettings are the permissions of members of a Team. Microsoft Graph allows all
+// of them by default.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/teammembersettings
+type TeamMemberSettings struct {
+	AllowCreateUpdateChannels         bool `json:"allowCreateUpdateChannels"`
+	AllowCreatePrivateChannels        bool `json:"allowCreatePrivateChannels"`
+	AllowDeleteChannels               bool `json:"allowDeleteChannels"`
+	AllowAddRemoveApps                bool `json:"allowAddRemoveApps"`
+	AllowCreateUpdateRemoveTabs       bool `json:"allowCreateUpdateRemoveTabs"`
+	AllowCreateUpdateRemoveConnectors bool `json:"allowCreateUpdateRemoveConnectors"`
+}
+
+// TeamGuestSettings are the permissions of guests of a Team. Microsoft Graph denies all of
+// them by default.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/teamguestsettings
+type TeamGuestSettings struct {
+	AllowCreateUpdateChannels bool `json:"allowCreateUpdateChannels"`
+	AllowDeleteChannels       bool `json:"allowDeleteChannels"`
+}
+
+// TeamMessagingSettings are the messaging permissions of a Team.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/teammessagingsettings
+type TeamMessagingSettings struct {
+	AllowUserEditMessages    bool `json:"allowUserEditMessages"`
+	AllowUserDeleteMessages  bool `json:"allowUserDeleteMessages"`
+	AllowOwnerDeleteMessages bool `json:"allowOwnerDeleteMessages"`
+	AllowTeamMentions        bool `json:"allowTeamMentions"`
+	AllowChannelMentions     bool `json:"allowChannelMentions"`
+}
+
+func (t Team) String() string {
+	return fmt.Sprintf("Team(ID: \"%v\", DisplayName: \"%v\", Description: \"%v\", Visibility: \"%v\", WebURL: \"%v\", IsArchived: %v, DirectAPIConnection: %v)",
+		t.ID, t.DisplayName, t.Description, t.Visibility, t.WebURL, t.IsArchived, t.graphClient != nil)
+}
+
+// setGraphClient sets the graphClient instance in this instance and all child-instances (if any)
+func (t *Team) setGraphClient(gC *GraphClient) {
+	t.graphClient = gC
+}
+
+// ListMembers returns the members of the team including their roles, see
+// ConversationMember.HasRole and ConversationMembers.Owners. Members and owners are managed
+// via the group of the team, e.g. with Group.AddMember and Group.AddOwner.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/team-list-members
+func (t Team) ListMembers(opts ...ListQueryOption) (ConversationMembers, error) {
+	if t.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	return t.graphClient.listConversationMembers(fmt.Sprintf("/teams/%v/members", t.ID), opts)
+}
+
+// ListChannels returns the channels of the team the application can access, including the
+// General channel every team has.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/channel-list
+func (t Team) ListChannels(opts ...ListQueryOption) (Channels, error) {
+	if t.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/teams/%v/channels", t.ID)
+
+	var marsh struct {
+		Channels Channels `json:"value"`
+	}
+	err := t.graphClient.makeGETAPICall(resource, compileListQueryOptions(opts), &marsh)
+	marsh.Channels.setTeam(t.graphClient, t.ID)
+	return marsh.Channels, err
+}
+
+// GetChannel returns the channel of the team with the given ID.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/channel-get
+func (t Team) GetChannel(channelID string, opts ...GetQueryOption) (Channel, error) {
+	if t.graphClient == nil {
+		return Channel{}, ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/teams/%v/channels/%v", t.ID, channelID)
+
+	var channel Channel
+	err := t.graphClient.makeGETAPICall(resource, compileGetQueryOptions(opts), &channel)
+	channel.setTeam(t.graphClient, t.ID)
+	return channel, err
+}
+
+// CreateChannel creates the channel in the team, DisplayName is required. MembershipType
+// defaults to ChannelMembershipTypeStandard, hence all members of the team are members of the
+// channel. Private and shared channels have their own members, the users with the given
+// ownerIDs are added as their owners. Microsoft Graph requires an owner if a private or
+// shared channel is created with application permissions.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/channel-post
+func (t Team) CreateChannel(channel Channel, ownerIDs []string, opts ...CreateQueryOption) (Channel, error) {
+	if t.graphClient == nil {
+		return Channel{}, ErrNotGraphClientSourced
+	}
+	if channel.DisplayName == "" {
+		return Channel{}, fmt.Errorf("the displayName of the channel is required")
+	}
+	if len(ownerIDs) > 0 && (channel.MembershipType == "" || channel.MembershipType == ChannelMembershipTypeStandard) {
+		return Channel{}, fmt.Errorf("owners can only be added to private and shared channels, the members of standard channels are the members of the team")
+	}
+	resource := fmt.Sprintf("/teams/%v/channels", t.ID)
+
+	post := map[string]interface{}{"displayName": channel.DisplayName}
+	if channel.Description != "" {
+		post["description"] = channel.Description
+	}
+	if channel.MembershipType != "" {
+		post["membershipType"] = channel.MembershipType
+	}
+	if len(ownerIDs) > 0 {
+		var members []interface{}
+		for _, ownerID := range ownerIDs {
+			members = append(members, t.graphClient.conversationMemberBody(ownerID, []string{TeamRoleOwner}))
+		}
+		post["members"] = members
+	}
+	bodyBytes, err := json.Marshal(post)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	reader := bytes.NewReader(bodyBytes)
+	var created Channel
+	err = t.graphClient.makePOSTAPICall(resource, compileCreateQueryOptions(opts), reader, &created)
+	created.setTeam(t.graphClient, t.ID)
+	return created, err
+}
+
+// listConversationMembers returns the members of the given resource, e.g. /teams/{id}/members.
+func (g *GraphClient) listConversationMembers(resource string, opts []ListQueryOption) (ConversationMembers, error) {
+	var marsh struct {
+		Members ConversationMembers `json:"value"`
+	}
+	err := g.makeGETAPICall(resource, compileListQueryOptions(opts), &marsh)
+	return marsh.Members, err
+}
+
+// conversationMemberBody returns the request body to add the user with the given ID and
+// roles to a team or channel.
+func (g *GraphClient) conversationMemberBody(userID string, roles []string) map[string]interface{} {
+	if roles == nil {
+		roles = []string{} // Microsoft Graph requires the roles, an empty list for members
+	}
+	return map[string]interface{}{
+		"@odata.type":     ODataTypeAadUserConversationMember,
+		"roles":           roles,
+		"user@odata.bind": g.resourceURL(fmt.Sprintf("users('%v')", userID)),
+	}
+}
@@ -0,0 +1,55 @@
+package msgraph
+
+import (
+	"fmt"
+	"time"
+)
+
+// Statuses of a TeamsAsyncOperation.
+const (
+	TeamsAsyncOperationStatusNotStarted = "notStarted"
+	TeamsAsyncOperationStatusInProgress = "inProgress"
+	TeamsAsyncOperationStatusSucceeded  = "succeeded"
+	TeamsAsyncOperationStatusFailed     = "failed"
+)
+
+// TeamsAsyncOperation represents a long-running operation of Microsoft Teams, e.g. the
+// creation of a team by Group.CreateTeam.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/teamsasyncoperation
+type TeamsAsyncOperation struct {
+	ID                     string          `json:"id"`
+	OperationType          string          `json:"operationType"` // e.g. createTeam
+	CreatedDateTime        time.Time       `json:"createdDateTime"`
+	LastActionDateTime     time.Time       `json:"lastActionDateTime"`
+	Status                 string          `json:"status"`        // one of the TeamsAsyncOperationStatus* constants
+	AttemptsCount          int             `json:"attemptsCount"` // number of times the operation was attempted before it succeeded or failed
+	TargetResourceID       string          `json:"targetResourceId"`
+	TargetResourceLocation string          `json:"targetResourceLocation"`
+	Error                  *OperationError `json:"error"` // only set if the operation failed
+}
+
+func (t TeamsAsyncOperation) String() string {
+	return fmt.Sprintf("TeamsAsyncOperation(ID: \"%v\", OperationType: \"%v\", Status: \"%v\", AttemptsCount: %v, TargetResourceID: \"%v\", Error: %v)",
+		t.ID, t.OperationType, t.Status, t.AttemptsCount, t.TargetResourceID, t.Error)
+}
+
+// Done returns true if the operation has succeeded or failed, hence it is not polled anymore.
+func (t TeamsAsyncOperation) Done() bool {
+	return t.Status == TeamsAsyncOperationStatusSucceeded || t.Status == TeamsAsyncOperationStatusFailed
+}
+
+// OperationError is the cause of a failed TeamsAsyncOperation.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/operationerror
+type OperationError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (o *OperationError) String() string {
+	if o == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("OperationError(Code: \"%v\", Message: \"%v\")", o.Code, o.Message)
+}
@@ -4,11 +4,17 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"strings"
 	"time"
 )
 
 // User represents a user from the ms graph API
+//
+// Note: Microsoft Graph returns only a default set of properties, use
+// GetWithSelect(UserSelectAllProperties) to retrieve all properties of the User.
+//
+// See https://docs.microsoft.com/en-us/graph/api/resources/user
 type User struct {
 	ID                string            `json:"id,omitempty"`
 	BusinessPhones    []string          `json:"businessPhones,omitempty"`
@@ -27,10 +33,90 @@ type User struct {
 	MailNickname      string            `json:"mailNickname,omitempty"`
 	PasswordProfile   PasswordProfile   `json:"passwordProfile,omitempty"`
 
+	AgeGroup                        string                         `json:"ageGroup,omitempty"`
+	City                            string                         `json:"city,omitempty"`
+	ConsentProvidedForMinor         string                         `json:"consentProvidedForMinor,omitempty"`
+	Country                         string                         `json:"country,omitempty"`
+	CreatedDateTime                 time.Time                      `json:"createdDateTime,omitempty"` // read-only
+	CreationType                    string                         `json:"creationType,omitempty"`    // read-only
+	DeletedDateTime                 time.Time                      `json:"deletedDateTime,omitempty"` // read-only
+	EmployeeHireDate                time.Time                      `json:"employeeHireDate,omitempty"`
+	EmployeeID                      string                         `json:"employeeId,omitempty"`
+	EmployeeOrgData                 *EmployeeOrgData               `json:"employeeOrgData,omitempty"`
+	EmployeeType                    string                         `json:"employeeType,omitempty"`
+	ExternalUserState               string                         `json:"externalUserState,omitempty"`               // read-only
+	ExternalUserStateChangeDateTime time.Time                      `json:"externalUserStateChangeDateTime,omitempty"` // read-only
+	FaxNumber                       string                         `json:"faxNumber,omitempty"`
+	Identities                      []ObjectIdentity               `json:"identities,omitempty"`
+	ImAddresses                     []string                       `json:"imAddresses,omitempty"` // read-only
+	IsResourceAccount               bool                           `json:"isResourceAccount,omitempty"`
+	LastPasswordChangeDateTime      time.Time                      `json:"lastPasswordChangeDateTime,omitempty"`  // read-only
+	LegalAgeGroupClassification     string                         `json:"legalAgeGroupClassification,omitempty"` // read-only
+	OfficeLocation                  string                         `json:"officeLocation,omitempty"`
+	OnPremisesDistinguishedName     string                         `json:"onPremisesDistinguishedName,omitempty"` // read-only
+	OnPremisesDomainName            string                         `json:"onPremisesDomainName,omitempty"`        // read-only
+	OnPremisesExtensionAttributes   *OnPremisesExtensionAttributes `json:"onPremisesExtensionAttributes,omitempty"`
+	OnPremisesImmutableID           string                         `json:"onPremisesImmutableId,omitempty"`
+	OnPremisesLastSyncDateTime      time.Time                      `json:"onPremisesLastSyncDateTime,omitempty"`   // read-only
+	OnPremisesSamAccountName        string                         `json:"onPremisesSamAccountName,omitempty"`     // read-only
+	OnPremisesSecurityIdentifier    string                         `json:"onPremisesSecurityIdentifier,omitempty"` // read-only
+	OnPremisesSyncEnabled           bool                           `json:"onPremisesSyncEnabled,omitempty"`        // read-only
+	OnPremisesUserPrincipalName     string                         `json:"onPremisesUserPrincipalName,omitempty"`  // read-only
+	OtherMails                      []string                       `json:"otherMails,omitempty"`
+	PasswordPolicies                string                         `json:"passwordPolicies,omitempty"`
+	PostalCode                      string                         `json:"postalCode,omitempty"`
+	PreferredDataLocation           string                         `json:"preferredDataLocation,omitempty"`
+	ProxyAddresses                  []string                       `json:"proxyAddresses,omitempty"` // read-only
+	ShowInAddressList               bool                           `json:"showInAddressList,omitempty"`
+	SignInSessionsValidFromDateTime time.Time                      `json:"signInSessionsValidFromDateTime,omitempty"` // read-only
+	State                           string                         `json:"state,omitempty"`
+	StreetAddress                   string                         `json:"streetAddress,omitempty"`
+	UsageLocation                   string                         `json:"usageLocation,omitempty"`
+	UserType                        string                         `json:"userType,omitempty"`
+
+	// ExtensionProperties contains the schema and directory extension properties of the user by
+	// their name, e.g. extkvbmkofy_costCenter. Only selected extensions are returned, see
+	// GetWithSchemaExtensions and User.GetSchemaExtension.
+	ExtensionProperties map[string]json.RawMessage `json:"-"`
+
 	activePhone string       // private cache for the active phone number
 	graphClient *GraphClient // the graphClient that called the user
 }
 
+// EmployeeOrgData represents the organization data of a User.
+type EmployeeOrgData struct {
+	CostCenter string `json:"costCenter,omitempty"`
+	Division   string `json:"division,omitempty"`
+}
+
+// ObjectIdentity represents an identity used to sign in to a User account, e.g. of a local
+// account of an Azure AD B2C tenant or of a federated identity provider.
+type ObjectIdentity struct {
+	SignInType       string `json:"signInType,omitempty"`
+	Issuer           string `json:"issuer,omitempty"`
+	IssuerAssignedID string `json:"issuerAssignedId,omitempty"`
+}
+
+// OnPremisesExtensionAttributes contains the extension attributes 1-15 of a User, synchronized
+// from the on-premises Active Directory or set for cloud-only users.
+type OnPremisesExtensionAttributes struct {
+	ExtensionAttribute1  string `json:"extensionAttribute1,omitempty"`
+	ExtensionAttribute2  string `json:"extensionAttribute2,omitempty"`
+	ExtensionAttribute3  string `json:"extensionAttribute3,omitempty"`
+	ExtensionAttribute4  string `json:"extensionAttribute4,omitempty"`
+	ExtensionAttribute5  string `json:"extensionAttribute5,omitempty"`
+	ExtensionAttribute6  string `json:"extensionAttribute6,omitempty"`
+	ExtensionAttribute7  string `json:"extensionAttribute7,omitempty"`
+	ExtensionAttribute8  string `json:"extensionAttribute8,omitempty"`
+	ExtensionAttribute9  string `json:"extensionAttribute9,omitempty"`
+	ExtensionAttribute10 string `json:"extensionAttribute10,omitempty"`
+	ExtensionAttribute11 string `json:"extensionAttribute11,omitempty"`
+	ExtensionAttribute12 string `json:"extensionAttribute12,omitempty"`
+	ExtensionAttribute13 string `json:"extensionAttribute13,omitempty"`
+	ExtensionAttribute14 string `json:"extensionAttribute14,omitempty"`
+	ExtensionAttribute15 string `json:"extensionAttribute15,omitempty"`
+}
+
 type AssignedLicense struct {
 	DisabledPlans []string `json:"disabledPlans,omitempty"`
 	SkuID         string   `json:"skuId,omitempty"`
@@ -42,6 +128,65 @@ type PasswordProfile struct {
 	Password                             string `json:"password,omitempty"`
 }
 
+// UserSelectAllProperties selects all properties of the User that can be read, e.g.
+// GetWithSelect(UserSelectAllProperties) or ListWithSelect(UserSelectAllProperties).
+var UserSelectAllProperties = strings.Join(jsonPropertyNames(reflect.TypeOf(User{}), "passwordProfile"), ",")
+
+// userDefaultSelect are the properties of the User Microsoft Graph returns by default.
+const userDefaultSelect = "id,businessPhones,displayName,givenName,jobTitle,mail,mobilePhone,officeLocation,preferredLanguage,surname,userPrincipalName"
+
+// userTimeProperties returns the time.Time properties of the User by their json name.
+func (u User) userTimeProperties() map[string]time.Time {
+	return map[string]time.Time{
+		"createdDateTime":                 u.CreatedDateTime,
+		"deletedDateTime":                 u.DeletedDateTime,
+		"employeeHireDate":                u.EmployeeHireDate,
+		"externalUserStateChangeDateTime": u.ExternalUserStateChangeDateTime,
+		"lastPasswordChangeDateTime":      u.LastPasswordChangeDateTime,
+		"onPremisesLastSyncDateTime":      u.OnPremisesLastSyncDateTime,
+		"signInSessionsValidFromDateTime": u.SignInSessionsValidFromDateTime,
+	}
+}
+
+// MarshalJSON implements the json.Marshaler interface. Unset time.Time properties are omitted,
+// as omitempty does not apply to structs - otherwise e.g. GraphClient.CreateUser would send
+// read-only properties like createdDateTime.
+func (u User) MarshalJSON() ([]byte, error) {
+	type user User // user has no methods, hence json.Marshal does not recurse into MarshalJSON
+	data, err := json.Marshal(user(u))
+	if err != nil {
+		return nil, err
+	}
+	var properties map[string]json.RawMessage
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return nil, err
+	}
+	for name, t := range u.userTimeProperties() {
+		if t.IsZero() {
+			delete(properties, name)
+		}
+	}
+	for name, value := range u.ExtensionProperties {
+		properties[name] = value
+	}
+	return json.Marshal(properties)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Schema and directory extension
+// properties are kept in ExtensionProperties.
+func (u *User) UnmarshalJSON(data []byte) error {
+	type user User // user has no methods, hence json.Unmarshal does not recurse into UnmarshalJSON
+	if err := json.Unmarshal(data, (*user)(u)); err != nil {
+		return err
+	}
+	extensions, err := extensionProperties(data)
+	if err != nil {
+		return err
+	}
+	u.ExtensionProperties = extensions
+	return nil
+}
+
 func (u User) String() string {
 	return fmt.Sprintf("User(ID: \"%v\", BusinessPhones: \"%v\", DisplayName: \"%v\", GivenName: \"%v\", "+
 		"JobTitle: \"%v\", Mail: \"%v\", MobilePhone: \"%v\", PreferredLanguage: \"%v\", Surname: \"%v\", "+
@@ -148,6 +293,7 @@ func (u User) GetFullName() string {
 
 // GetMemberGroupsAsStrings returns a list of all group IDs the user is a member of.
 // You can specify the securityGroupsEnabeled parameter to only return security group IDs.
+// Use CheckMemberGroups to check the membership in a few known groups.
 //
 // opts ...GetQueryOption - only msgraph.GetWithContext is supported.
 //
@@ -168,7 +314,8 @@ func (u User) PrettySimpleString() string {
 //
 // IMPORTANT: the user cannot be disabled (field AccountEnabled) this way, because the
 // default value of a boolean is false - and hence will not be posted via json - omitempty
-// is used. user func user.DisableAccount() instead.
+// is used. The same applies to clearing a property, e.g. JobTitle. Use user.PatchUser
+// with a UserPatch instead.
 //
 // Reference: https://developer.microsoft.com/en-us/graph/docs/api-reference/v1.0/api/user-update
 func (u User) UpdateUser(userInput User, opts ...UpdateQueryOption) error {
@@ -188,21 +335,24 @@ func (u User) UpdateUser(userInput User, opts ...UpdateQueryOption) error {
 	return err
 }
 
-// DisableAccount disables the User-Account, hence sets the AccountEnabled-field to false.
-// This function must be used instead of user.UpdateUser, because the AccountEnabled-field
-// with json "omitempty" will never be sent when false. Without omitempty, the user account would
-// always accidentially disabled upon an update of e.g. only "DisplayName"
+// PatchUser patches this user object with exactly the properties set in the UserPatch,
+// including false, empty and null values. Returns an error if the patch is empty or
+// contains an unknown or read-only property.
 //
-// Reference: https://developer.microsoft.com/en-us/graph/docs/api-reference/v1.0/api/user-update
-func (u User) DisableAccount(opts ...UpdateQueryOption) error {
+// Reference: https://docs.microsoft.com/en-us/graph/api/user-update
+func (u User) PatchUser(patch *UserPatch, opts ...UpdateQueryOption) error {
 	if u.graphClient == nil {
 		return ErrNotGraphClientSourced
 	}
+	if patch != nil && patch.err != nil {
+		return patch.err
+	}
+	if patch == nil || len(patch.properties) == 0 {
+		return fmt.Errorf("UserPatch is empty, nothing to update")
+	}
 	resource := fmt.Sprintf("/users/%v", u.ID)
 
-	bodyBytes, err := json.Marshal(struct {
-		AccountEnabled bool `json:"accountEnabled"`
-	}{AccountEnabled: false})
+	bodyBytes, err := json.Marshal(patch)
 	if err != nil {
 		return err
 	}
@@ -213,6 +363,36 @@ func (u User) DisableAccount(opts ...UpdateQueryOption) error {
 	return err
 }
 
+// DisableAccount disables the User-Account, hence sets the AccountEnabled-field to false.
+// This is a shorthand for user.PatchUser(NewUserPatch().SetAccountEnabled(false)).
+//
+// Reference: https://developer.microsoft.com/en-us/graph/docs/api-reference/v1.0/api/user-update
+func (u User) DisableAccount(opts ...UpdateQueryOption) error {
+	return u.PatchUser(NewUserPatch().SetAccountEnabled(false), opts...)
+}
+
+// RevokeSignInSessions invalidates all refresh tokens and session cookies issued to the user,
+// hence the user has to sign in again in all applications and browsers. Access tokens that
+// have already been issued stay valid until they expire, usually within one hour.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/user-revokesigninsessions
+func (u User) RevokeSignInSessions(opts ...UpdateQueryOption) error {
+	if u.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/users/%v/revokeSignInSessions", u.ID)
+
+	var marsh struct {
+		Value bool `json:"value"`
+	}
+	reqOpt := compileUpdateQueryOptions(opts)
+	err := u.graphClient.makePOSTAPICall(resource, reqOpt, nil, &marsh)
+	if err == nil && !marsh.Value && !u.graphClient.isDryRun(reqOpt) {
+		return fmt.Errorf("revoking the sign-in sessions of user %v was not successful", u.ID)
+	}
+	return err
+}
+
 // DeleteUser deletes this user instance at the Microsoft Azure AD. Use with caution.
 //
 // Reference: https://docs.microsoft.com/en-us/graph/api/user-delete
@@ -0,0 +1,166 @@
+package msgraph
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// UserPatch is a builder for the properties to update with User.PatchUser. Unlike a User
+// passed to User.UpdateUser, the UserPatch tracks which properties have been set explicitly
+// and sends exactly those - including false, empty and null values.
+//
+// Example:
+//
+//	patch := msgraph.NewUserPatch().SetAccountEnabled(false).SetDepartment("Sales").Clear("jobTitle")
+//	err := user.PatchUser(patch)
+type UserPatch struct {
+	patch
+}
+
+// NewUserPatch returns a new, empty UserPatch.
+func NewUserPatch() *UserPatch {
+	return &UserPatch{patch: newPatch("User", userProperties)}
+}
+
+// userProperties are the json names of all properties of a User that can be updated. The
+// assignedLicenses are updated with User.AssignLicense instead.
+var userProperties = jsonPropertyNames(reflect.TypeOf(User{}), "id", "assignedLicenses", "createdDateTime",
+	"creationType", "deletedDateTime", "externalUserState", "externalUserStateChangeDateTime", "imAddresses",
+	"lastPasswordChangeDateTime", "legalAgeGroupClassification", "onPremisesDistinguishedName",
+	"onPremisesDomainName", "onPremisesLastSyncDateTime", "onPremisesSamAccountName",
+	"onPremisesSecurityIdentifier", "onPremisesSyncEnabled", "onPremisesUserPrincipalName", "proxyAddresses",
+	"signInSessionsValidFromDateTime")
+
+// Set sets the property with the given json name, e.g. "jobTitle", to the value. A nil value
+// clears the property, see Clear. Setting a property that is not part of the User, e.g. due
+// to a typo, or a read-only property like proxyAddresses fails the UserPatch with an error.
+func (p *UserPatch) Set(property string, value interface{}) *UserPatch {
+	p.set(property, value)
+	return p
+}
+
+// SetExtension sets the schema or directory extension with the given ID, e.g. extkvbmkofy_costCenter,
+// to the value, e.g. a user-defined struct with json tags. A nil value clears the extension.
+func (p *UserPatch) SetExtension(extensionID string, value interface{}) *UserPatch {
+	if !isExtensionProperty(extensionID) {
+		p.fail(fmt.Errorf("%q is not the ID of a schema or directory extension", extensionID))
+		return p
+	}
+	p.properties[extensionID] = value
+	return p
+}
+
+// Clear sets the property with the given json name to null, e.g. Clear("mobilePhone").
+func (p *UserPatch) Clear(property string) *UserPatch {
+	return p.Set(property, nil)
+}
+
+// SetAccountEnabled sets accountEnabled, false disables the User.
+func (p *UserPatch) SetAccountEnabled(accountEnabled bool) *UserPatch {
+	return p.Set("accountEnabled", accountEnabled)
+}
+
+// SetDisplayName sets displayName.
+func (p *UserPatch) SetDisplayName(displayName string) *UserPatch {
+	return p.Set("displayName", displayName)
+}
+
+// SetGivenName sets givenName.
+func (p *UserPatch) SetGivenName(givenName string) *UserPatch {
+	return p.Set("givenName", givenName)
+}
+
+// SetSurname sets surname.
+func (p *UserPatch) SetSurname(surname string) *UserPatch {
+	return p.Set("surname", surname)
+}
+
+// SetJobTitle sets jobTitle.
+func (p *UserPatch) SetJobTitle(jobTitle string) *UserPatch {
+	return p.Set("jobTitle", jobTitle)
+}
+
+// SetDepartment sets department.
+func (p *UserPatch) SetDepartment(department string) *UserPatch {
+	return p.Set("department", department)
+}
+
+// SetCompanyName sets companyName.
+func (p *UserPatch) SetCompanyName(companyName string) *UserPatch {
+	return p.Set("companyName", companyName)
+}
+
+// SetOfficeLocation sets officeLocation.
+func (p *UserPatch) SetOfficeLocation(officeLocation string) *UserPatch {
+	return p.Set("officeLocation", officeLocation)
+}
+
+// SetMobilePhone sets mobilePhone.
+func (p *UserPatch) SetMobilePhone(mobilePhone string) *UserPatch {
+	return p.Set("mobilePhone", mobilePhone)
+}
+
+// SetBusinessPhones sets businessPhones, an empty slice removes all business phones.
+func (p *UserPatch) SetBusinessPhones(businessPhones []string) *UserPatch {
+	if businessPhones == nil {
+		businessPhones = []string{}
+	}
+	return p.Set("businessPhones", businessPhones)
+}
+
+// SetOtherMails sets otherMails, an empty slice removes all other mails.
+func (p *UserPatch) SetOtherMails(otherMails []string) *UserPatch {
+	if otherMails == nil {
+		otherMails = []string{}
+	}
+	return p.Set("otherMails", otherMails)
+}
+
+// SetUsageLocation sets usageLocation, a two letter country code (ISO 3166) that is
+// required before licenses can be assigned to the User.
+func (p *UserPatch) SetUsageLocation(usageLocation string) *UserPatch {
+	return p.Set("usageLocation", usageLocation)
+}
+
+// SetPreferredLanguage sets preferredLanguage, e.g. "en-US".
+func (p *UserPatch) SetPreferredLanguage(preferredLanguage string) *UserPatch {
+	return p.Set("preferredLanguage", preferredLanguage)
+}
+
+// SetEmployeeID sets employeeId.
+func (p *UserPatch) SetEmployeeID(employeeID string) *UserPatch {
+	return p.Set("employeeId", employeeID)
+}
+
+// SetEmployeeType sets employeeType, e.g. "Employee" or "Contractor".
+func (p *UserPatch) SetEmployeeType(employeeType string) *UserPatch {
+	return p.Set("employeeType", employeeType)
+}
+
+// SetEmployeeHireDate sets employeeHireDate.
+func (p *UserPatch) SetEmployeeHireDate(employeeHireDate time.Time) *UserPatch {
+	return p.Set("employeeHireDate", employeeHireDate.UTC())
+}
+
+// SetEmployeeOrgData sets employeeOrgData.
+func (p *UserPatch) SetEmployeeOrgData(employeeOrgData EmployeeOrgData) *UserPatch {
+	return p.Set("employeeOrgData", employeeOrgData)
+}
+
+// SetAddress sets streetAddress, postalCode, city, state and country at once.
+func (p *UserPatch) SetAddress(streetAddress, postalCode, city, state, country string) *UserPatch {
+	return p.Set("streetAddress", streetAddress).Set("postalCode", postalCode).Set("city", city).
+		Set("state", state).Set("country", country)
+}
+
+// SetOnPremisesExtensionAttributes sets onPremisesExtensionAttributes. Note, only the
+// attributes of cloud-only users can be updated.
+func (p *UserPatch) SetOnPremisesExtensionAttributes(attributes OnPremisesExtensionAttributes) *UserPatch {
+	return p.Set("onPremisesExtensionAttributes", attributes)
+}
+
+// SetPasswordProfile sets passwordProfile, e.g. to reset the password of the User.
+func (p *UserPatch) SetPasswordProfile(passwordProfile PasswordProfile) *UserPatch {
+	return p.Set("passwordProfile", passwordProfile)
+}
@@ -0,0 +1,90 @@
+package msgraph
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUserPatch_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   *UserPatch
+		want    string
+		wantErr bool
+	}{
+		{name: "Empty", patch: NewUserPatch(), want: `{}`},
+		{name: "False and empty values", patch: NewUserPatch().SetAccountEnabled(false).SetJobTitle("").SetBusinessPhones(nil),
+			want: `{"accountEnabled":false,"businessPhones":[],"jobTitle":""}`},
+		{name: "Null values", patch: NewUserPatch().Clear("mobilePhone").Clear("employeeHireDate"),
+			want: `{"employeeHireDate":null,"mobilePhone":null}`},
+		{name: "Overwritten value", patch: NewUserPatch().SetDepartment("Sales").Clear("department").SetDepartment("IT"),
+			want: `{"department":"IT"}`},
+		{name: "Nested values", patch: NewUserPatch().SetEmployeeHireDate(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)).
+			SetOnPremisesExtensionAttributes(OnPremisesExtensionAttributes{ExtensionAttribute1: "cost center 1"}),
+			want: `{"employeeHireDate":"2021-06-01T00:00:00Z","onPremisesExtensionAttributes":{"extensionAttribute1":"cost center 1"}}`},
+		{name: "Unknown property", patch: NewUserPatch().SetJobTitle("Technician").Set("jobtitle", "typo"), wantErr: true},
+		{name: "ID", patch: NewUserPatch().Set("id", "00000000-0000-0000-0000-000000000000"), wantErr: true},
+		{name: "Read-only date", patch: NewUserPatch().Set("createdDateTime", time.Now()), wantErr: true},
+		{name: "Read-only proxy addresses", patch: NewUserPatch().Set("proxyAddresses", []string{"SMTP:a@contoso.com"}), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.patch.MarshalJSON()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UserPatch.MarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("UserPatch.MarshalJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	patch := NewUserPatch().SetAddress("Main Street 1", "1010", "Vienna", "", "Austria")
+	if !patch.IsSet("state") || patch.IsSet("jobTitle") || len(patch.Properties()) != 5 {
+		t.Errorf("UserPatch.Properties() = %v, want the 5 address properties", patch.Properties())
+	}
+}
+
+func TestUser_PatchUser(t *testing.T) {
+	if err := (User{ID: "none"}).PatchUser(NewUserPatch().SetJobTitle("none")); err != ErrNotGraphClientSourced {
+		t.Errorf("User.PatchUser() error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+
+	testuser := createUnitTestUser(t)
+	defer testuser.DeleteUser()
+
+	if err := testuser.PatchUser(NewUserPatch()); err == nil {
+		t.Errorf("User.PatchUser() with empty patch error = nil, want error")
+	}
+	// the unknown property is reported instead of an empty patch
+	if err := testuser.PatchUser(NewUserPatch().Set("jobtitel", "x")); err == nil || !strings.Contains(err.Error(), `unknown or read-only property "jobtitel"`) {
+		t.Errorf("User.PatchUser() with unknown property error = %v, want the unknown property", err)
+	}
+
+	err := testuser.PatchUser(NewUserPatch().SetJobTitle("go-msgraph unit test").SetOfficeLocation("Vienna").SetUsageLocation("AT"))
+	if err != nil {
+		t.Fatalf("User.PatchUser() error = %v", err)
+	}
+	got, err := graphClient.GetUser(testuser.ID, GetWithSelect(UserSelectAllProperties))
+	if err != nil {
+		t.Fatalf("GraphClient.GetUser() error = %v", err)
+	}
+	if got.JobTitle != "go-msgraph unit test" || got.OfficeLocation != "Vienna" || got.UsageLocation != "AT" {
+		t.Errorf("User.PatchUser() did not set the properties: %v", got)
+	}
+
+	// clear the job title and disable the account, both impossible with User.UpdateUser
+	err = testuser.PatchUser(NewUserPatch().Clear("jobTitle").SetAccountEnabled(false))
+	if err != nil {
+		t.Fatalf("User.PatchUser() error = %v", err)
+	}
+	got, err = graphClient.GetUser(testuser.ID, GetWithSelect("id,jobTitle,officeLocation,accountEnabled"))
+	if err != nil {
+		t.Fatalf("GraphClient.GetUser() error = %v", err)
+	}
+	if got.JobTitle != "" || got.AccountEnabled || got.OfficeLocation != "Vienna" {
+		t.Errorf("User.PatchUser() did not clear jobTitle or disable the account: JobTitle = %q, AccountEnabled = %v, OfficeLocation = %q",
+			got.JobTitle, got.AccountEnabled, got.OfficeLocation)
+	}
+}
@@ -0,0 +1,127 @@
+package msgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ListAuthenticationMethods returns all authentication methods registered for the user,
+// grouped by their type.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/authentication-list-methods
+func (u User) ListAuthenticationMethods(opts ...ListQueryOption) (AuthenticationMethods, error) {
+	if u.graphClient == nil {
+		return AuthenticationMethods{}, ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/users/%v/authentication/methods", u.ID)
+
+	var marsh struct {
+		Methods AuthenticationMethods `json:"value"`
+	}
+	err := u.graphClient.makeGETAPICall(resource, compileListQueryOptions(opts), &marsh)
+	return marsh.Methods, err
+}
+
+// AddPhoneMethod registers the phone number for SMS or voice based MFA. The phoneNumber must
+// have the format "+{country code} {number}x{extension}", e.g. "+1 2065555555". The phoneType
+// is one of PhoneTypeMobile, PhoneTypeAlternateMobile or PhoneTypeOffice, every user can have
+// only one phone method per type.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/authentication-post-phonemethods
+func (u User) AddPhoneMethod(phoneNumber, phoneType string, opts ...CreateQueryOption) (PhoneAuthenticationMethod, error) {
+	if u.graphClient == nil {
+		return PhoneAuthenticationMethod{}, ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/users/%v/authentication/phoneMethods", u.ID)
+
+	bodyBytes, err := json.Marshal(PhoneAuthenticationMethod{PhoneNumber: phoneNumber, PhoneType: phoneType})
+	if err != nil {
+		return PhoneAuthenticationMethod{}, err
+	}
+
+	reader := bytes.NewReader(bodyBytes)
+	var method PhoneAuthenticationMethod
+	err = u.graphClient.makePOSTAPICall(resource, compileCreateQueryOptions(opts), reader, &method)
+	return method, err
+}
+
+// RemovePhoneMethod removes the phone method with the given ID, see AddPhoneMethod.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/phoneauthenticationmethod-delete
+func (u User) RemovePhoneMethod(methodID string, opts ...DeleteQueryOption) error {
+	return u.RemoveAuthenticationMethod(AuthenticationMethodTypePhone, methodID, opts...)
+}
+
+// RemoveAuthenticationMethod removes the authentication method with the given ID and type,
+// one of the AuthenticationMethodType* constants, e.g. to reset the MFA registration of the
+// user. The password cannot be removed and the default method can only be removed last.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/authenticationmethods-get-started
+func (u User) RemoveAuthenticationMethod(methodType, methodID string, opts ...DeleteQueryOption) error {
+	if u.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	if methodType == AuthenticationMethodTypePassword {
+		return fmt.Errorf("the password authentication method cannot be removed, use ResetPassword instead")
+	}
+	resource := fmt.Sprintf("/users/%v/authentication/%v/%v", u.ID, methodType, methodID)
+	return u.graphClient.makeDELETEAPICall(resource, compileDeleteQueryOptions(opts), nil)
+}
+
+// CreateTemporaryAccessPass creates a Temporary Access Pass for the user, e.g. to onboard a
+// user without password or after the MFA registration has been reset. The pass is valid from
+// startDateTime, or immediately if zero, for the lifetime, or the default lifetime of the
+// authentication methods policy if zero. The returned TemporaryAccessPass can only be
+// retrieved once, hence must be handed over to the user immediately.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/authentication-post-temporaryaccesspassmethods
+func (u User) CreateTemporaryAccessPass(startDateTime time.Time, lifetime time.Duration, isUsableOnce bool, opts ...CreateQueryOption) (TemporaryAccessPassAuthenticationMethod, error) {
+	if u.graphClient == nil {
+		return TemporaryAccessPassAuthenticationMethod{}, ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/users/%v/authentication/temporaryAccessPassMethods", u.ID)
+
+	post := map[string]interface{}{"isUsableOnce": isUsableOnce}
+	if !startDateTime.IsZero() {
+		post["startDateTime"] = startDateTime.UTC()
+	}
+	if lifetime > 0 {
+		post["lifetimeInMinutes"] = int(lifetime / time.Minute)
+	}
+	bodyBytes, err := json.Marshal(post)
+	if err != nil {
+		return TemporaryAccessPassAuthenticationMethod{}, err
+	}
+
+	reader := bytes.NewReader(bodyBytes)
+	var method TemporaryAccessPassAuthenticationMethod
+	err = u.graphClient.makePOSTAPICall(resource, compileCreateQueryOptions(opts), reader, &method)
+	return method, err
+}
+
+// ResetPassword resets the password of the user to newPassword, the user has to change it at
+// the next sign-in. If newPassword is empty, Microsoft Graph generates a password. The reset
+// is a long-running operation, hence the password may not be changed yet when ResetPassword
+// returns. Note, Microsoft Graph supports this API-call only with delegated permissions.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/authenticationmethod-resetpassword
+func (u User) ResetPassword(newPassword string, opts ...UpdateQueryOption) error {
+	if u.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/users/%v/authentication/methods/%v/resetPassword", u.ID, PasswordAuthenticationMethodID)
+
+	bodyBytes, err := json.Marshal(struct {
+		NewPassword string `json:"newPassword,omitempty"`
+	}{NewPassword: newPassword})
+	if err != nil {
+		return err
+	}
+
+	reader := bytes.NewReader(bodyBytes)
+	// Hint: API-call body does not return any data / no json object, the operation is accepted with 202.
+	return u.graphClient.makePOSTAPICall(resource, compileUpdateQueryOptions(opts), reader, nil)
+}
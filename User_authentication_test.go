@@ -0,0 +1,143 @@
+package msgraph
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestAuthenticationMethods_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    AuthenticationMethods
+		wantErr bool
+	}{
+		{name: "empty", data: `[]`, want: AuthenticationMethods{}},
+		{
+			name: "typed",
+			data: `[{"@odata.type": "#microsoft.graph.passwordAuthenticationMethod", "id": "28c10230-6103-485e-b985-444c60001490"},
+				{"@odata.type": "#microsoft.graph.phoneAuthenticationMethod", "id": "3179e48a-750b-4051-897c-87b9720928f7", "phoneNumber": "+1 2065555555", "phoneType": "mobile"},
+				{"@odata.type": "#microsoft.graph.fido2AuthenticationMethod", "id": "-2_GRUg2-HYz6_1YG4YRAQ2", "model": "YubiKey 5"},
+				{"@odata.type": "#microsoft.graph.emailAuthenticationMethod", "id": "3ddfcfc8-9383-446f-83cc-3ab9be4be18f", "emailAddress": "kim@contoso.com"}]`,
+			want: AuthenticationMethods{
+				Password: []PasswordAuthenticationMethod{{ID: PasswordAuthenticationMethodID}},
+				Phone:    []PhoneAuthenticationMethod{{ID: "3179e48a-750b-4051-897c-87b9720928f7", PhoneNumber: "+1 2065555555", PhoneType: PhoneTypeMobile}},
+				Fido2:    []Fido2AuthenticationMethod{{ID: "-2_GRUg2-HYz6_1YG4YRAQ2", Model: "YubiKey 5"}},
+				Email:    []EmailAuthenticationMethod{{ID: "3ddfcfc8-9383-446f-83cc-3ab9be4be18f", EmailAddress: "kim@contoso.com"}},
+			},
+		},
+		{
+			name: "unknown type",
+			data: `[{"@odata.type": "#microsoft.graph.windowsHelloForBusinessAuthenticationMethod", "id": "_jpuR-TGZtk6aQCLF3BQjA2"}]`,
+			want: AuthenticationMethods{Other: []json.RawMessage{json.RawMessage(`{"@odata.type": "#microsoft.graph.windowsHelloForBusinessAuthenticationMethod", "id": "_jpuR-TGZtk6aQCLF3BQjA2"}`)}},
+		},
+		{name: "invalid", data: `[{"@odata.type": "#microsoft.graph.phoneAuthenticationMethod", "phoneNumber": 1}]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got AuthenticationMethods
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AuthenticationMethods.UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.String() != tt.want.String() {
+				t.Errorf("AuthenticationMethods.UnmarshalJSON() = %v, want %v", got, tt.want)
+			}
+			if len(got.Phone) > 0 && got.Phone[0] != tt.want.Phone[0] {
+				t.Errorf("AuthenticationMethods.UnmarshalJSON() Phone = %v, want %v", got.Phone, tt.want.Phone)
+			}
+			if len(got.Email) > 0 && got.Email[0] != tt.want.Email[0] {
+				t.Errorf("AuthenticationMethods.UnmarshalJSON() Email = %v, want %v", got.Email, tt.want.Email)
+			}
+			if got.HasStrongMethod() != (len(tt.want.Phone)+len(tt.want.Fido2)+len(tt.want.Other) > 0) {
+				t.Errorf("AuthenticationMethods.HasStrongMethod() = %v", got.HasStrongMethod())
+			}
+		})
+	}
+}
+
+func TestUser_PhoneMethods(t *testing.T) {
+	if _, err := (User{ID: "none"}).ListAuthenticationMethods(); err != ErrNotGraphClientSourced {
+		t.Errorf("User.ListAuthenticationMethods() error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+	testuser := createUnitTestUser(t)
+	defer testuser.DeleteUser()
+
+	method, err := testuser.AddPhoneMethod("+1 2065555555", PhoneTypeMobile)
+	if err != nil {
+		t.Fatalf("User.AddPhoneMethod() error = %v", err)
+	}
+	if method.ID == "" || method.PhoneType != PhoneTypeMobile {
+		t.Errorf("User.AddPhoneMethod() = %v, want ID and phoneType %v", method, PhoneTypeMobile)
+	}
+	if _, err := testuser.AddPhoneMethod("2065555555", PhoneTypeOffice); err == nil {
+		t.Errorf("User.AddPhoneMethod() with invalid phone number error = nil, want error")
+	}
+
+	methods, err := testuser.ListAuthenticationMethods()
+	if err != nil {
+		t.Fatalf("User.ListAuthenticationMethods() error = %v", err)
+	}
+	if len(methods.Phone) != 1 || methods.Phone[0].ID != method.ID || len(methods.Password) != 1 || !methods.HasStrongMethod() {
+		t.Errorf("User.ListAuthenticationMethods() = %v, want the phone method %v and the password", methods, method)
+	}
+
+	if err := testuser.RemovePhoneMethod(method.ID); err != nil {
+		t.Fatalf("User.RemovePhoneMethod() error = %v", err)
+	}
+	if methods, err := testuser.ListAuthenticationMethods(); err != nil || methods.HasStrongMethod() {
+		t.Errorf("User.ListAuthenticationMethods() after remove = %v, error = %v", methods, err)
+	}
+	if err := testuser.RemoveAuthenticationMethod(AuthenticationMethodTypePassword, PasswordAuthenticationMethodID); err == nil {
+		t.Errorf("User.RemoveAuthenticationMethod() of the password error = nil, want error")
+	}
+}
+
+func TestUser_CreateTemporaryAccessPass(t *testing.T) {
+	if offlineServer == nil {
+		t.Skip("Temporary Access Passes require the authentication methods policy to enable them, only tested offline")
+	}
+	testuser := createUnitTestUser(t)
+	defer testuser.DeleteUser()
+
+	pass, err := testuser.CreateTemporaryAccessPass(time.Time{}, 2*time.Hour, true)
+	if err != nil {
+		t.Fatalf("User.CreateTemporaryAccessPass() error = %v", err)
+	}
+	if pass.TemporaryAccessPass == "" || pass.LifetimeInMinutes != 120 || !pass.IsUsableOnce || !pass.IsUsable {
+		t.Errorf("User.CreateTemporaryAccessPass() = %+v", pass)
+	}
+	if _, err := testuser.CreateTemporaryAccessPass(time.Time{}, 0, false); err == nil {
+		t.Errorf("User.CreateTemporaryAccessPass() twice error = nil, want error")
+	}
+	methods, err := testuser.ListAuthenticationMethods()
+	if err != nil || len(methods.TemporaryAccessPass) != 1 || methods.TemporaryAccessPass[0].TemporaryAccessPass != "" {
+		t.Errorf("User.ListAuthenticationMethods() = %v, error = %v, want the pass without its secret", methods, err)
+	}
+	if err := testuser.RemoveAuthenticationMethod(AuthenticationMethodTypeTemporaryAccessPass, pass.ID); err != nil {
+		t.Errorf("User.RemoveAuthenticationMethod() error = %v", err)
+	}
+}
+
+func TestUser_ResetPassword(t *testing.T) {
+	if offlineServer == nil {
+		t.Skip("password reset is only supported with delegated permissions, only tested offline")
+	}
+	if err := (User{ID: "none"}).ResetPassword(""); err != ErrNotGraphClientSourced {
+		t.Errorf("User.ResetPassword() error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+	testuser := createUnitTestUser(t)
+	defer testuser.DeleteUser()
+
+	if err := testuser.ResetPassword("xWwvJ]6NMw+bWH-d"); err != nil {
+		t.Fatalf("User.ResetPassword() error = %v", err)
+	}
+	got, err := graphClient.GetUser(testuser.ID, GetWithSelect("id,lastPasswordChangeDateTime"))
+	if err != nil || got.LastPasswordChangeDateTime.IsZero() {
+		t.Errorf("User.ResetPassword() did not change the password: %v, error = %v", got.LastPasswordChangeDateTime, err)
+	}
+}
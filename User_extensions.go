@@ -0,0 +1,84 @@
+package msgraph
+
+import (
+	"fmt"
+)
+
+// ListExtensions returns all open extensions of the user.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/opentypeextension-get
+func (u User) ListExtensions(opts ...ListQueryOption) (OpenExtensions, error) {
+	if u.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	return u.graphClient.listExtensions(fmt.Sprintf("/users/%v", u.ID), opts)
+}
+
+// GetExtension returns the open extension of the user with the given name, e.g.
+// com.contoso.costCenter. Use OpenExtension.Decode to map it to a user-defined struct.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/opentypeextension-get
+func (u User) GetExtension(extensionName string, opts ...GetQueryOption) (OpenExtension, error) {
+	if u.graphClient == nil {
+		return OpenExtension{}, ErrNotGraphClientSourced
+	}
+	return u.graphClient.getExtension(fmt.Sprintf("/users/%v", u.ID), extensionName, opts)
+}
+
+// CreateExtension creates the open extension for the user. Microsoft Graph responds with 409
+// if the user already has an extension with the same name, use UpdateExtension instead.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/opentypeextension-post-opentypeextension
+func (u User) CreateExtension(extension OpenExtension, opts ...CreateQueryOption) (OpenExtension, error) {
+	if u.graphClient == nil {
+		return OpenExtension{}, ErrNotGraphClientSourced
+	}
+	return u.graphClient.createExtension(fmt.Sprintf("/users/%v", u.ID), extension, opts)
+}
+
+// UpdateExtension replaces the properties of the open extension of the user, properties that
+// are not set are removed from the extension.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/opentypeextension-update
+func (u User) UpdateExtension(extension OpenExtension, opts ...UpdateQueryOption) error {
+	if u.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	return u.graphClient.updateExtension(fmt.Sprintf("/users/%v", u.ID), extension, opts)
+}
+
+// DeleteExtension deletes the open extension of the user with the given name.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/opentypeextension-delete
+func (u User) DeleteExtension(extensionName string, opts ...DeleteQueryOption) error {
+	if u.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	return u.graphClient.deleteExtension(fmt.Sprintf("/users/%v", u.ID), extensionName, opts)
+}
+
+// GetSchemaExtension maps the value of the schema or directory extension with the given ID,
+// e.g. extkvbmkofy_costCenter, to v, a pointer to a user-defined struct with json tags. The
+// extension must have been selected, e.g. with GetWithSchemaExtensions. Returns
+// ErrFindExtension if the user has no value for the extension.
+//
+// Example:
+//
+//	type CostCenter struct {
+//		Code string `json:"code"`
+//	}
+//	user, err := graphClient.GetUser("alice@contoso.com", msgraph.GetWithSchemaExtensions("extkvbmkofy_costCenter"))
+//	var costCenter CostCenter
+//	err = user.GetSchemaExtension("extkvbmkofy_costCenter", &costCenter)
+func (u User) GetSchemaExtension(extensionID string, v interface{}) error {
+	return decodeSchemaExtension(u.ExtensionProperties, extensionID, v)
+}
+
+// SetSchemaExtension sets the schema or directory extension with the given ID to v, e.g. a
+// user-defined struct with json tags. A nil v clears the extension, see UserPatch.SetExtension.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/extensibility-schema-groups
+func (u User) SetSchemaExtension(extensionID string, v interface{}, opts ...UpdateQueryOption) error {
+	return u.PatchUser(NewUserPatch().SetExtension(extensionID, v), opts...)
+}
@@ -0,0 +1,103 @@
+package msgraph
+
+import (
+	"testing"
+)
+
+func TestUser_OpenExtensions(t *testing.T) {
+	if _, err := (User{ID: "none"}).ListExtensions(); err != ErrNotGraphClientSourced {
+		t.Errorf("User.ListExtensions() error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+	testuser := createUnitTestUser(t)
+	defer testuser.DeleteUser()
+
+	extension := OpenExtension{ExtensionName: "com.contoso.costCenter", Properties: map[string]interface{}{"code": "CC-4711"}}
+	created, err := testuser.CreateExtension(extension)
+	if err != nil {
+		t.Fatalf("User.CreateExtension() error = %v", err)
+	}
+	if created.ExtensionName != extension.ExtensionName || created.Properties["code"] != "CC-4711" {
+		t.Errorf("User.CreateExtension() = %v, want %v", created, extension)
+	}
+	if _, err := testuser.CreateExtension(extension); err == nil {
+		t.Errorf("User.CreateExtension() twice error = nil, want error")
+	}
+
+	extension.Properties = map[string]interface{}{"code": "CC-0815", "validUntil": "2030-12-31"}
+	if err := testuser.UpdateExtension(extension); err != nil {
+		t.Fatalf("User.UpdateExtension() error = %v", err)
+	}
+	got, err := testuser.GetExtension(extension.ExtensionName)
+	if err != nil {
+		t.Fatalf("User.GetExtension() error = %v", err)
+	}
+	var costCenter struct {
+		Code       string `json:"code"`
+		ValidUntil string `json:"validUntil"`
+	}
+	if err := got.Decode(&costCenter); err != nil || costCenter.Code != "CC-0815" || costCenter.ValidUntil != "2030-12-31" {
+		t.Errorf("User.GetExtension() = %v decoded %+v, error = %v", got, costCenter, err)
+	}
+
+	extensions, err := testuser.ListExtensions()
+	if err != nil {
+		t.Fatalf("User.ListExtensions() error = %v", err)
+	}
+	if _, err := extensions.GetByName(extension.ExtensionName); err != nil {
+		t.Errorf("User.ListExtensions() = %v, want %v", extensions, extension.ExtensionName)
+	}
+	if err := testuser.DeleteExtension(extension.ExtensionName); err != nil {
+		t.Fatalf("User.DeleteExtension() error = %v", err)
+	}
+	if extensions, _ := testuser.ListExtensions(); len(extensions) != 0 {
+		t.Errorf("User.ListExtensions() after delete = %v, want none", extensions)
+	}
+}
+
+func TestUser_SchemaExtension(t *testing.T) {
+	if offlineServer == nil {
+		t.Skip("schema extensions must be registered for the tenant, only tested offline")
+	}
+	const extensionID = "extkvbmkofy_costCenter"
+	type costCenter struct {
+		Code  string `json:"code"`
+		Owner string `json:"owner,omitempty"`
+	}
+	testuser := createUnitTestUser(t)
+	defer testuser.DeleteUser()
+
+	if err := testuser.SetSchemaExtension("costCenter", costCenter{Code: "CC-4711"}); err == nil {
+		t.Errorf("User.SetSchemaExtension() with invalid ID error = nil, want error")
+	}
+	if err := testuser.SetSchemaExtension(extensionID, costCenter{Code: "CC-4711", Owner: "finance"}); err != nil {
+		t.Fatalf("User.SetSchemaExtension() error = %v", err)
+	}
+
+	got, err := graphClient.GetUser(testuser.ID, GetWithSchemaExtensions(extensionID))
+	if err != nil {
+		t.Fatalf("GraphClient.GetUser() error = %v", err)
+	}
+	var value costCenter
+	if err := got.GetSchemaExtension(extensionID, &value); err != nil || value.Code != "CC-4711" || value.Owner != "finance" {
+		t.Errorf("User.GetSchemaExtension() = %+v, error = %v", value, err)
+	}
+	if got.UserPrincipalName != testuser.UserPrincipalName {
+		t.Errorf("GraphClient.GetUser() with GetWithSchemaExtensions() did not select the default properties: %v", got)
+	}
+
+	users, err := graphClient.ListUsers(ListWithFilter("id eq '"+testuser.ID+"'"), ListWithSchemaExtensions(extensionID))
+	if err != nil || len(users) != 1 {
+		t.Fatalf("GraphClient.ListUsers() = %v, error = %v", users, err)
+	}
+	if err := users[0].GetSchemaExtension(extensionID, &value); err != nil || value.Code != "CC-4711" {
+		t.Errorf("User.GetSchemaExtension() of ListUsers() = %+v, error = %v", value, err)
+	}
+
+	if err := testuser.SetSchemaExtension(extensionID, nil); err != nil {
+		t.Fatalf("User.SetSchemaExtension() with nil error = %v", err)
+	}
+	got, _ = graphClient.GetUser(testuser.ID, GetWithSchemaExtensions(extensionID))
+	if err := got.GetSchemaExtension(extensionID, &value); err != ErrFindExtension {
+		t.Errorf("User.GetSchemaExtension() after clear error = %v, want %v", err, ErrFindExtension)
+	}
+}
@@ -0,0 +1,61 @@
+package msgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// AssignLicenses adds the licenses in add to the user and removes the licenses with the SkuIDs
+// in remove, within a single operation. Returns the updated user including its AssignedLicenses.
+// The UsageLocation of the user must be set before licenses can be assigned, see
+// UserPatch.SetUsageLocation.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/user-assignlicense
+func (u User) AssignLicenses(add []AssignedLicense, remove []string, opts ...UpdateQueryOption) (User, error) {
+	if u.graphClient == nil {
+		return User{}, ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/users/%v/assignLicense", u.ID)
+
+	// both properties are required by the API-call, even if empty
+	post := struct {
+		AddLicenses    []AssignedLicense `json:"addLicenses"`
+		RemoveLicenses []string          `json:"removeLicenses"`
+	}{AddLicenses: []AssignedLicense{}, RemoveLicenses: []string{}}
+	for _, license := range add {
+		if license.DisabledPlans == nil {
+			license.DisabledPlans = []string{}
+		}
+		post.AddLicenses = append(post.AddLicenses, license)
+	}
+	post.RemoveLicenses = append(post.RemoveLicenses, remove...)
+
+	bodyBytes, err := json.Marshal(post)
+	if err != nil {
+		return User{}, err
+	}
+
+	reader := bytes.NewReader(bodyBytes)
+	user := User{graphClient: u.graphClient}
+	err = u.graphClient.makePOSTAPICall(resource, compileUpdateQueryOptions(opts), reader, &user)
+	return user, err
+}
+
+// ListLicenseDetails returns the licenses assigned to the user.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/user-list-licensedetails
+func (u User) ListLicenseDetails(opts ...ListQueryOption) (LicenseDetailsList, error) {
+	if u.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/users/%v/licenseDetails", u.ID)
+
+	var marsh struct {
+		LicenseDetails LicenseDetailsList `json:"value"`
+	}
+	err := u.graphClient.makeGETAPICall(resource, compileListQueryOptions(opts), &marsh)
+	marsh.LicenseDetails.setGraphClient(u.graphClient)
+	return marsh.LicenseDetails, err
+}
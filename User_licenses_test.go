@@ -0,0 +1,87 @@
+package msgraph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUser_AssignLicenses(t *testing.T) {
+	if _, err := (User{ID: "none"}).AssignLicenses(nil, nil); err != ErrNotGraphClientSourced {
+		t.Errorf("User.AssignLicenses() error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+
+	skus, err := graphClient.ListSubscribedSkus()
+	if err != nil {
+		t.Fatalf("GraphClient.ListSubscribedSkus() error = %v", err)
+	}
+	var sku SubscribedSku
+	for _, s := range skus {
+		if s.AppliesTo == "User" && s.CapabilityStatus == "Enabled" && s.AvailableUnits() > 0 && len(s.ServicePlans) > 0 {
+			sku = s
+			break
+		}
+	}
+	if sku.SkuID == "" {
+		t.Skipf("Skipping TestUser_AssignLicenses, no SKU with available units in %v", skus)
+	}
+
+	testuser := createUnitTestUser(t)
+	defer testuser.DeleteUser()
+	if _, err := testuser.AssignLicenses([]AssignedLicense{{SkuID: sku.SkuID}}, nil); err == nil {
+		t.Errorf("User.AssignLicenses() without usageLocation error = nil, want error")
+	}
+	if err := testuser.PatchUser(NewUserPatch().SetUsageLocation("AT")); err != nil {
+		t.Fatalf("User.PatchUser() error = %v", err)
+	}
+
+	disabledPlan := sku.ServicePlans[0].ServicePlanID
+	updated, err := testuser.AssignLicenses([]AssignedLicense{{SkuID: sku.SkuID, DisabledPlans: []string{disabledPlan}}}, nil)
+	if err != nil {
+		t.Fatalf("User.AssignLicenses() error = %v", err)
+	}
+	if len(updated.AssignedLicenses) != 1 || updated.AssignedLicenses[0].SkuID != sku.SkuID || updated.graphClient == nil {
+		t.Errorf("User.AssignLicenses() = %v, want license %v", updated.AssignedLicenses, sku.SkuID)
+	}
+
+	details, err := testuser.ListLicenseDetails()
+	if err != nil {
+		t.Fatalf("User.ListLicenseDetails() error = %v", err)
+	}
+	if len(details) != 1 || details[0].SkuPartNumber != sku.SkuPartNumber || details[0].graphClient == nil {
+		t.Errorf("User.ListLicenseDetails() = %v, want %v", details, sku.SkuPartNumber)
+	} else {
+		if got, err := details.GetBySkuPartNumber(strings.ToLower(sku.SkuPartNumber)); err != nil || got.SkuID != sku.SkuID {
+			t.Errorf("LicenseDetailsList.GetBySkuPartNumber() = %v, error = %v, want %v", got, err, sku.SkuID)
+		}
+		if _, err := details.GetBySkuPartNumber("UNKNOWN"); err != ErrFindLicenseDetails {
+			t.Errorf("LicenseDetailsList.GetBySkuPartNumber() error = %v, want %v", err, ErrFindLicenseDetails)
+		}
+		for _, plan := range details[0].ServicePlans {
+			if plan.ServicePlanID == disabledPlan && plan.ProvisioningStatus != "Disabled" {
+				t.Errorf("User.ListLicenseDetails() ProvisioningStatus of disabled plan %v = %v, want Disabled", plan.ServicePlanName, plan.ProvisioningStatus)
+			}
+		}
+	}
+
+	if offlineServer != nil {
+		// consumed units are updated asynchronously by Azure AD, hence only checked offline
+		skus, err := graphClient.ListSubscribedSkus()
+		if err != nil {
+			t.Fatalf("GraphClient.ListSubscribedSkus() error = %v", err)
+		}
+		if got, _ := skus.GetBySkuID(sku.SkuID); got.ConsumedUnits != sku.ConsumedUnits+1 {
+			t.Errorf("SubscribedSku.ConsumedUnits after User.AssignLicenses() = %v, want %v", got.ConsumedUnits, sku.ConsumedUnits+1)
+		}
+		if over := skus.OverAssigned(); len(over) != 1 || over[0].SkuPartNumber != "POWER_BI_STANDARD" {
+			t.Errorf("SubscribedSkus.OverAssigned() = %v, want POWER_BI_STANDARD", over)
+		}
+	}
+
+	updated, err = testuser.AssignLicenses(nil, []string{sku.SkuID})
+	if err != nil {
+		t.Fatalf("User.AssignLicenses() removing license error = %v", err)
+	}
+	if len(updated.AssignedLicenses) != 0 {
+		t.Errorf("User.AssignLicenses() after removing = %v, want no licenses", updated.AssignedLicenses)
+	}
+}
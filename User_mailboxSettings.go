@@ -0,0 +1,47 @@
+package msgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// GetMailboxSettings returns the settings of the primary mailbox of the user, e.g. the
+// automatic replies, working hours and time zone.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/user-get-mailboxsettings
+func (u User) GetMailboxSettings(opts ...GetQueryOption) (MailboxSettings, error) {
+	if u.graphClient == nil {
+		return MailboxSettings{}, ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/users/%v/mailboxSettings", u.ID)
+
+	var settings MailboxSettings
+	err := u.graphClient.makeGETAPICall(resource, compileGetQueryOptions(opts), &settings)
+	return settings, err
+}
+
+// UpdateMailboxSettings updates exactly the mailbox settings set in the MailboxSettingsPatch,
+// including empty and null values. Returns an error if the patch is empty or contains an
+// unknown setting.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/user-update-mailboxsettings
+func (u User) UpdateMailboxSettings(patch *MailboxSettingsPatch, opts ...UpdateQueryOption) error {
+	if u.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	if patch == nil || len(patch.properties) == 0 && patch.err == nil {
+		return fmt.Errorf("MailboxSettingsPatch is empty, nothing to update")
+	}
+	resource := fmt.Sprintf("/users/%v/mailboxSettings", u.ID)
+
+	bodyBytes, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+
+	reader := bytes.NewReader(bodyBytes)
+	// Hint: API-call body does not return any data / no json object.
+	return u.graphClient.makePATCHAPICall(resource, compileUpdateQueryOptions(opts), reader, nil)
+}
@@ -0,0 +1,61 @@
+package msgraph
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUser_MailboxSettings(t *testing.T) {
+	if offlineServer == nil {
+		t.Skip("mailbox settings require a mailbox, unlicensed users created by the unit tests have none, only tested offline")
+	}
+	if _, err := (User{}).GetMailboxSettings(); err != ErrNotGraphClientSourced {
+		t.Errorf("User.GetMailboxSettings() error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+	testuser := createUnitTestUser(t)
+	defer testuser.DeleteUser()
+
+	settings, err := testuser.GetMailboxSettings()
+	if err != nil {
+		t.Fatalf("User.GetMailboxSettings() error = %v", err)
+	}
+	if settings.AutomaticRepliesSetting.Status != AutomaticRepliesDisabled || !settings.WorkingHours.IsWorkingDay(time.Monday) || settings.WorkingHours.IsWorkingDay(time.Sunday) {
+		t.Errorf("User.GetMailboxSettings() = %v, want disabled automatic replies and working days from monday to friday", settings)
+	}
+
+	if err := testuser.UpdateMailboxSettings(NewMailboxSettingsPatch()); err == nil {
+		t.Errorf("User.UpdateMailboxSettings() with empty patch error = nil, want error")
+	}
+	start := time.Now().Add(-time.Hour)
+	end := time.Now().AddDate(0, 0, 14)
+	if err := testuser.UpdateMailboxSettings(NewMailboxSettingsPatch().ScheduleAutomaticReplies(end, start, "", "")); err == nil {
+		t.Errorf("User.UpdateMailboxSettings() with end before start error = nil, want error")
+	}
+	patch := NewMailboxSettingsPatch().
+		ScheduleAutomaticReplies(start, end, "<p>I am on vacation.</p>", "").
+		SetTimeZone("Europe/Vienna").
+		SetLanguage("de-AT").
+		SetDateFormat("dd.MM.yyyy")
+	if err := testuser.UpdateMailboxSettings(patch); err != nil {
+		t.Fatalf("User.UpdateMailboxSettings() error = %v", err)
+	}
+
+	settings, err = testuser.GetMailboxSettings()
+	if err != nil {
+		t.Fatalf("User.GetMailboxSettings() error = %v", err)
+	}
+	replies := settings.AutomaticRepliesSetting
+	if replies.Status != AutomaticRepliesScheduled || replies.InternalReplyMessage != "<p>I am on vacation.</p>" || !replies.IsActive(time.Now()) {
+		t.Errorf("User.GetMailboxSettings() AutomaticRepliesSetting = %v, want active scheduled automatic replies", replies)
+	}
+	if settings.TimeZone != "Europe/Vienna" || settings.Language.Locale != "de-AT" || settings.DateFormat != "dd.MM.yyyy" || settings.TimeFormat == "" {
+		t.Errorf("User.GetMailboxSettings() = %v, want the updated and the unchanged settings", settings)
+	}
+
+	if err := testuser.UpdateMailboxSettings(NewMailboxSettingsPatch().DisableAutomaticReplies()); err != nil {
+		t.Fatalf("User.UpdateMailboxSettings() to disable automatic replies error = %v", err)
+	}
+	if settings, _ := testuser.GetMailboxSettings(); settings.AutomaticRepliesSetting.Status != AutomaticRepliesDisabled || settings.AutomaticRepliesSetting.InternalReplyMessage == "" {
+		t.Errorf("User.GetMailboxSettings() after disabling = %v, want disabled automatic replies with the message kept", settings.AutomaticRepliesSetting)
+	}
+}
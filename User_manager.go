@@ -0,0 +1,143 @@
+package msgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// GetManager returns the manager of the user. Returns an error if the user has no manager.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/user-list-manager
+func (u User) GetManager(opts ...GetQueryOption) (User, error) {
+	if u.graphClient == nil {
+		return User{}, ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/users/%v/manager", u.ID)
+	manager := User{graphClient: u.graphClient}
+	err := u.graphClient.makeGETAPICall(resource, compileGetQueryOptions(opts), &manager)
+	return manager, err
+}
+
+// SetManager assigns the user or organizational contact with the given ID as manager of the
+// user, replacing the current manager if any.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/user-post-manager
+func (u User) SetManager(managerID string, opts ...UpdateQueryOption) error {
+	if u.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	if managerID == "" {
+		return fmt.Errorf("managerID must not be empty")
+	}
+	if managerID == u.ID {
+		return fmt.Errorf("user %v cannot be its own manager", u.ID)
+	}
+	resource := fmt.Sprintf("/users/%v/manager/$ref", u.ID)
+
+	bodyBytes, err := json.Marshal(struct {
+		ODataID string `json:"@odata.id"`
+	}{ODataID: u.graphClient.directoryObjectURL(managerID)})
+	if err != nil {
+		return err
+	}
+
+	reader := bytes.NewReader(bodyBytes)
+	// Hint: API-call body does not return any data / no json object.
+	return u.graphClient.makePUTAPICall(resource, compileUpdateQueryOptions(opts), reader, nil)
+}
+
+// RemoveManager removes the manager of the user.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/user-delete-manager
+func (u User) RemoveManager(opts ...DeleteQueryOption) error {
+	if u.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/users/%v/manager/$ref", u.ID)
+	return u.graphClient.makeDELETEAPICall(resource, compileDeleteQueryOptions(opts), nil)
+}
+
+// ListDirectReports returns the users that have this user as their manager. Direct reports
+// may also be org contacts, which are filtered by Microsoft Graph with the type cast
+// /directReports/microsoft.graph.user.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/user-list-directreports
+func (u User) ListDirectReports(opts ...ListQueryOption) (Users, error) {
+	if u.graphClient == nil {
+		return Users{}, ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/users/%v/directReports/%v", u.ID, castSegment(ODataTypeUser))
+
+	var marsh struct {
+		Users Users `json:"value"`
+	}
+	err := u.graphClient.makeGETAPICall(resource, compileListQueryOptions(opts), &marsh)
+	marsh.Users.setGraphClient(u.graphClient)
+	return marsh.Users, err
+}
+
+// GetManagementChain returns the managers of the user, starting with the direct manager up to
+// the top of the hierarchy. The whole chain is retrieved with a single API-call using
+// $expand=manager($levels=max). The chain ends at the first manager that is already part of
+// it, hence a cyclic management hierarchy does not lead to an endless chain.
+//
+// opts ...GetQueryOption - only msgraph.GetWithContext is supported.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/user-list-manager#example-2-get-manager-chain-up-to-the-root-level
+func (u User) GetManagementChain(opts ...GetQueryOption) (Users, error) {
+	if u.graphClient == nil {
+		return Users{}, ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/users/%v", u.ID)
+
+	// $levels requires advanced query capabilities, hence $count and the ConsistencyLevel header
+	reqOpt := &listQueryOptions{
+		getQueryOptions: *compileGetQueryOptions(opts),
+		queryHeaders:    http.Header{},
+	}
+	reqOpt.queryHeaders.Set("ConsistencyLevel", "eventual")
+	reqOpt.queryValues.Set("$select", "id")
+	reqOpt.queryValues.Set("$expand", "manager($levels=max)")
+	reqOpt.queryValues.Set("$count", "true")
+
+	var root managementChainNode
+	if err := u.graphClient.makeGETAPICall(resource, reqOpt, &root); err != nil {
+		return Users{}, err
+	}
+
+	chain := Users{}
+	visited := map[string]bool{u.ID: true, root.ID: true}
+	for node := root.Manager; node != nil && !visited[node.ID]; node = node.Manager {
+		visited[node.ID] = true
+		manager := node.User
+		manager.setGraphClient(u.graphClient)
+		chain = append(chain, manager)
+	}
+	return chain, nil
+}
+
+// managementChainNode is a User with its expanded manager, see User.GetManagementChain.
+type managementChainNode struct {
+	User
+	Manager *managementChainNode `json:"manager"`
+}
+
+// UnmarshalJSON implements the json unmarshal to be used by the json-library. It is required
+// as the promoted User.UnmarshalJSON would skip the manager otherwise.
+func (n *managementChainNode) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &n.User); err != nil {
+		return err
+	}
+	var expanded struct {
+		Manager *managementChainNode `json:"manager"`
+	}
+	if err := json.Unmarshal(data, &expanded); err != nil {
+		return err
+	}
+	n.Manager = expanded.Manager
+	return nil
+}
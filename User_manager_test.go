@@ -0,0 +1,100 @@
+package msgraph
+
+import (
+	"testing"
+)
+
+func TestUser_Manager(t *testing.T) {
+	notGraphClientSourcedUser := User{ID: "none"}
+	if _, err := notGraphClientSourcedUser.GetManager(); err != ErrNotGraphClientSourced {
+		t.Errorf("User.GetManager() error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+	if err := notGraphClientSourcedUser.SetManager("manager"); err != ErrNotGraphClientSourced {
+		t.Errorf("User.SetManager() error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+	if _, err := notGraphClientSourcedUser.GetManagementChain(); err != ErrNotGraphClientSourced {
+		t.Errorf("User.GetManagementChain() error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+
+	employee := createUnitTestUser(t)
+	defer employee.DeleteUser()
+	teamLead := createUnitTestUser(t)
+	defer teamLead.DeleteUser()
+	ceo := createUnitTestUser(t)
+	defer ceo.DeleteUser()
+
+	if err := employee.SetManager(employee.ID); err == nil {
+		t.Errorf("User.SetManager() with own ID error = nil, want error")
+	}
+	if err := employee.SetManager(teamLead.ID); err != nil {
+		t.Fatalf("User.SetManager() error = %v", err)
+	}
+	if err := teamLead.SetManager(ceo.ID); err != nil {
+		t.Fatalf("User.SetManager() error = %v", err)
+	}
+
+	manager, err := employee.GetManager()
+	if err != nil {
+		t.Fatalf("User.GetManager() error = %v", err)
+	}
+	if manager.ID != teamLead.ID || manager.graphClient == nil {
+		t.Errorf("User.GetManager() = %v, want %v", manager, teamLead)
+	}
+
+	reports, err := teamLead.ListDirectReports()
+	if err != nil {
+		t.Fatalf("User.ListDirectReports() error = %v", err)
+	}
+	if len(reports) != 1 || reports[0].ID != employee.ID || reports[0].graphClient == nil {
+		t.Errorf("User.ListDirectReports() = %v, want %v", reports, employee)
+	}
+	if offlineServer != nil {
+		// org contacts are direct reports as well, but no users
+		contact := offlineServer.Add("/contacts", map[string]interface{}{"displayName": "partner", "mail": "partner@example.com"})
+		offlineServer.SetManager(contact.ID(), teamLead.ID)
+		reports, err := teamLead.ListDirectReports()
+		if err != nil {
+			t.Fatalf("User.ListDirectReports() with org contact error = %v", err)
+		}
+		if len(reports) != 1 || reports[0].ID != employee.ID {
+			t.Errorf("User.ListDirectReports() with org contact = %v, want only %v", reports, employee)
+		}
+	}
+
+	chain, err := employee.GetManagementChain()
+	if err != nil {
+		t.Fatalf("User.GetManagementChain() error = %v", err)
+	}
+	if len(chain) != 2 || chain[0].ID != teamLead.ID || chain[1].ID != ceo.ID {
+		t.Errorf("User.GetManagementChain() = %v, want [%v %v]", chain, teamLead, ceo)
+	}
+	for _, user := range chain {
+		if user.graphClient == nil {
+			t.Errorf("User.GetManagementChain() returned %v without GraphClient", user)
+		}
+	}
+
+	if offlineServer != nil {
+		// Azure AD refuses cyclic hierarchies in most cases, hence the cycle is only tested offline
+		offlineServer.SetManager(ceo.ID, employee.ID)
+		chain, err := teamLead.GetManagementChain()
+		if err != nil {
+			t.Fatalf("User.GetManagementChain() with cycle error = %v", err)
+		}
+		if len(chain) != 2 || chain[0].ID != ceo.ID || chain[1].ID != employee.ID {
+			t.Errorf("User.GetManagementChain() with cycle = %v, want [%v %v]", chain, ceo, employee)
+		}
+		offlineServer.SetManager(ceo.ID, "")
+	}
+
+	if err := employee.RemoveManager(); err != nil {
+		t.Fatalf("User.RemoveManager() error = %v", err)
+	}
+	if _, err := employee.GetManager(); err == nil {
+		t.Errorf("User.GetManager() after User.RemoveManager() error = nil, want error")
+	}
+	chain, err = employee.GetManagementChain()
+	if err != nil || len(chain) != 0 {
+		t.Errorf("User.GetManagementChain() after User.RemoveManager() = %v, error = %v, want empty chain", chain, err)
+	}
+}
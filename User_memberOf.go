@@ -0,0 +1,27 @@
+package msgraph
+
+import "fmt"
+
+// ListMemberOf returns the groups, directory roles and administrative units the user is a
+// direct member of, see DirectoryObject. Use DirectoryObjects.Groups to get the groups only.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/user-list-memberof
+func (u User) ListMemberOf(opts ...ListQueryOption) (DirectoryObjects, error) {
+	if u.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	return u.graphClient.listDirectoryObjects(fmt.Sprintf("/users/%v/memberOf", u.ID), opts)
+}
+
+// ListTransitiveMemberOf returns the groups, directory roles and administrative units the user
+// is a direct or nested member of, hence all groups the user inherits access from.
+// Supports optional OData query parameters https://docs.microsoft.com/en-us/graph/query-parameters
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/user-list-transitivememberof
+func (u User) ListTransitiveMemberOf(opts ...ListQueryOption) (DirectoryObjects, error) {
+	if u.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	return u.graphClient.listDirectoryObjects(fmt.Sprintf("/users/%v/transitiveMemberOf", u.ID), opts)
+}
@@ -0,0 +1,60 @@
+package msgraph
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+// sortedIDs returns the sorted IDs of the directory objects joined by commas.
+func sortedIDs(objects DirectoryObjects) string {
+	var ids = make([]string, len(objects))
+	for i, object := range objects {
+		ids[i] = object.ID
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+func TestUser_ListMemberOf(t *testing.T) {
+	if _, err := (User{}).ListMemberOf(); err != ErrNotGraphClientSourced {
+		t.Errorf("User.ListMemberOf() without GraphClient error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+	if _, err := (Group{}).ListTransitiveMemberOf(); err != ErrNotGraphClientSourced {
+		t.Errorf("Group.ListTransitiveMemberOf() without GraphClient error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+	user := createUnitTestUser(t)
+	defer user.DeleteUser()
+	outer, inner, other := createNestedUnitTestGroups(t, user)
+	defer outer.DeleteGroup()
+	defer inner.DeleteGroup()
+	defer other.DeleteGroup()
+
+	tests := []struct {
+		name string
+		list func() (DirectoryObjects, error)
+		want DirectoryObjects
+	}{
+		{name: "User.ListMemberOf", list: func() (DirectoryObjects, error) { return user.ListMemberOf() }, want: DirectoryObjects{{ID: inner.ID}}},
+		{name: "User.ListTransitiveMemberOf", list: func() (DirectoryObjects, error) { return user.ListTransitiveMemberOf() }, want: DirectoryObjects{{ID: inner.ID}, {ID: outer.ID}}},
+		{name: "Group.ListMemberOf", list: func() (DirectoryObjects, error) { return inner.ListMemberOf() }, want: DirectoryObjects{{ID: outer.ID}}},
+		{name: "Group.ListTransitiveMemberOf", list: func() (DirectoryObjects, error) { return inner.ListTransitiveMemberOf() }, want: DirectoryObjects{{ID: outer.ID}}},
+		{name: "Not nested", list: func() (DirectoryObjects, error) { return outer.ListMemberOf() }, want: DirectoryObjects{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.list()
+			if err != nil {
+				t.Fatalf("%v() error = %v", tt.name, err)
+			}
+			if sortedIDs(got) != sortedIDs(tt.want) {
+				t.Errorf("%v() = %v, want %v", tt.name, got, tt.want)
+			}
+			for _, group := range got {
+				if group.Group == nil || group.Group.graphClient == nil {
+					t.Errorf("%v() = %v, want GraphClient sourced groups", tt.name, group)
+				}
+			}
+		})
+	}
+}
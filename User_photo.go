@@ -0,0 +1,90 @@
+package msgraph
+
+import (
+	"fmt"
+	"io"
+)
+
+// GetPhoto returns the binary content of the profile photo of the user in the largest
+// available size, see Photo. Microsoft Graph responds with 404 if the user has no photo.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/profilephoto-get
+func (u User) GetPhoto(opts ...GetQueryOption) (Photo, error) {
+	if u.graphClient == nil {
+		return Photo{}, ErrNotGraphClientSourced
+	}
+	return u.graphClient.getPhoto(fmt.Sprintf("/users/%v/photo", u.ID), opts)
+}
+
+// GetPhotoOfSize returns the binary content of the profile photo of the user in the given
+// size, one of the PhotoSize* constants, e.g. PhotoSize96x96 for thumbnails.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/profilephoto-get
+func (u User) GetPhotoOfSize(size string, opts ...GetQueryOption) (Photo, error) {
+	if u.graphClient == nil {
+		return Photo{}, ErrNotGraphClientSourced
+	}
+	return u.graphClient.getPhoto(fmt.Sprintf("/users/%v/photos/%v", u.ID, size), opts)
+}
+
+// OpenPhoto returns the binary content of the profile photo of the user in the largest
+// available size as stream, without reading it into memory like User.GetPhoto. The caller
+// must close the PhotoStream. Reading the stream is not limited by the timeout of the default
+// http.Client, use GetWithContext to cancel it. Note, the Timeout of a http.Client configured
+// via WithHTTPClient includes reading the stream.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/profilephoto-get
+func (u User) OpenPhoto(opts ...GetQueryOption) (PhotoStream, error) {
+	if u.graphClient == nil {
+		return PhotoStream{}, ErrNotGraphClientSourced
+	}
+	return u.graphClient.openPhoto(fmt.Sprintf("/users/%v/photo", u.ID), opts)
+}
+
+// OpenPhotoOfSize returns the binary content of the profile photo of the user in the given
+// size as stream, see User.OpenPhoto and User.GetPhotoOfSize.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/profilephoto-get
+func (u User) OpenPhotoOfSize(size string, opts ...GetQueryOption) (PhotoStream, error) {
+	if u.graphClient == nil {
+		return PhotoStream{}, ErrNotGraphClientSourced
+	}
+	return u.graphClient.openPhoto(fmt.Sprintf("/users/%v/photos/%v", u.ID, size), opts)
+}
+
+// GetPhotoMetadata returns the metadata of the profile photo of the user in the largest
+// available size, e.g. to compare its ETag with a cached photo.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/profilephoto-get
+func (u User) GetPhotoMetadata(opts ...GetQueryOption) (ProfilePhoto, error) {
+	if u.graphClient == nil {
+		return ProfilePhoto{}, ErrNotGraphClientSourced
+	}
+	resource := fmt.Sprintf("/users/%v/photo", u.ID)
+
+	var photo ProfilePhoto
+	err := u.graphClient.makeGETAPICall(resource, compileGetQueryOptions(opts), &photo)
+	return photo, err
+}
+
+// ListPhotos returns the metadata of all available sizes of the profile photo of the user.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/profilephoto-get
+func (u User) ListPhotos(opts ...ListQueryOption) (ProfilePhotos, error) {
+	if u.graphClient == nil {
+		return nil, ErrNotGraphClientSourced
+	}
+	return u.graphClient.listPhotos(fmt.Sprintf("/users/%v/photos", u.ID), opts)
+}
+
+// SetPhoto uploads the profile photo of the user, replacing the current photo. The contentType,
+// e.g. image/jpeg, is detected from the photo if empty. Microsoft Graph accepts photos of up
+// to 4 MB and generates the smaller sizes from it.
+//
+// Reference: https://docs.microsoft.com/en-us/graph/api/profilephoto-update
+func (u User) SetPhoto(photo io.Reader, contentType string, opts ...UpdateQueryOption) error {
+	if u.graphClient == nil {
+		return ErrNotGraphClientSourced
+	}
+	return u.graphClient.setPhoto(fmt.Sprintf("/users/%v/photo", u.ID), photo, contentType, opts)
+}
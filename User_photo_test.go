@@ -0,0 +1,109 @@
+package msgraph
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+// createUnitTestPhoto returns a PNG image of the given size.
+func createUnitTestPhoto(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, x%height, color.RGBA{R: 0x2f, G: 0x6f, B: 0xb4, A: 0xff})
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestUser_Photo(t *testing.T) {
+	if _, err := (User{ID: "none"}).GetPhoto(); err != ErrNotGraphClientSourced {
+		t.Errorf("User.GetPhoto() error = %v, want %v", err, ErrNotGraphClientSourced)
+	}
+	if offlineServer == nil {
+		t.Skip("profile photos require a mailbox, unlicensed users created by the unit tests have none, only tested offline")
+	}
+	testuser := createUnitTestUser(t)
+	defer testuser.DeleteUser()
+
+	if _, err := testuser.GetPhoto(); err == nil {
+		t.Errorf("User.GetPhoto() without photo error = nil, want error")
+	}
+	if _, err := testuser.OpenPhoto(); !isStatusError(err, http.StatusNotFound, "") {
+		t.Errorf("User.OpenPhoto() without photo error = %v, want 404", err)
+	}
+	if err := testuser.SetPhoto(bytes.NewReader([]byte("no image")), "image/png"); err == nil {
+		t.Errorf("User.SetPhoto() with invalid image error = nil, want error")
+	}
+
+	content := createUnitTestPhoto(t, 100, 100)
+	graphClient.ResetPlan()
+	if err := testuser.SetPhoto(bytes.NewReader(content), "", UpdateWithDryRun()); err != nil {
+		t.Fatalf("User.SetPhoto() with dry-run error = %v", err)
+	}
+	if plan := graphClient.Plan(); len(plan) != 1 || plan[0].Body != nil {
+		t.Errorf("User.SetPhoto() with dry-run Plan() = %v, want one operation without body", plan)
+	}
+	graphClient.ResetPlan()
+	if err := testuser.SetPhoto(bytes.NewReader(content), ""); err != nil {
+		t.Fatalf("User.SetPhoto() error = %v", err)
+	}
+
+	photo, err := testuser.GetPhoto()
+	if err != nil {
+		t.Fatalf("User.GetPhoto() error = %v", err)
+	}
+	if photo.ContentType != "image/png" || !bytes.Equal(photo.Content, content) {
+		t.Errorf("User.GetPhoto() = %v, want the uploaded image/png", photo)
+	}
+	if got, _ := ioutil.ReadAll(photo.Reader()); !bytes.Equal(got, content) {
+		t.Errorf("Photo.Reader() returned %v bytes, want %v", len(got), len(content))
+	}
+	stream, err := testuser.OpenPhoto()
+	if err != nil {
+		t.Fatalf("User.OpenPhoto() error = %v", err)
+	}
+	got, err := ioutil.ReadAll(stream)
+	stream.Close()
+	if err != nil || stream.ContentType != "image/png" || stream.ContentLength != int64(len(content)) || !bytes.Equal(got, content) {
+		t.Errorf("User.OpenPhoto() = %v with %v bytes, error = %v, want the uploaded image/png", stream, len(got), err)
+	}
+	if _, err := testuser.OpenPhotoOfSize(PhotoSize240x240); err == nil {
+		t.Errorf("User.OpenPhotoOfSize(%v) larger than the photo error = nil, want error", PhotoSize240x240)
+	}
+	if _, err := testuser.GetPhotoOfSize(PhotoSize64x64); err != nil {
+		t.Errorf("User.GetPhotoOfSize(%v) error = %v", PhotoSize64x64, err)
+	}
+	if _, err := testuser.GetPhotoOfSize(PhotoSize240x240); err == nil {
+		t.Errorf("User.GetPhotoOfSize(%v) larger than the photo error = nil, want error", PhotoSize240x240)
+	}
+
+	metadata, err := testuser.GetPhotoMetadata()
+	if err != nil {
+		t.Fatalf("User.GetPhotoMetadata() error = %v", err)
+	}
+	if metadata.Width != 96 || metadata.ContentType != "image/png" || metadata.ETag == "" {
+		t.Errorf("User.GetPhotoMetadata() = %v, want the 96X96 image/png", metadata)
+	}
+	photos, err := testuser.ListPhotos()
+	if err != nil {
+		t.Fatalf("User.ListPhotos() error = %v", err)
+	}
+	if largest, err := photos.Largest(); err != nil || largest != metadata {
+		t.Errorf("ProfilePhotos.Largest() = %v, error = %v, want %v", largest, err, metadata)
+	}
+	if _, err := photos.GetBySize(PhotoSize48x48); err != nil || len(photos) != 3 {
+		t.Errorf("User.ListPhotos() = %v, want the sizes 48x48, 64x64 and 96x96", photos)
+	}
+	if _, err := photos.GetBySize(PhotoSize648x648); err != ErrFindProfilePhoto {
+		t.Errorf("ProfilePhotos.GetBySize(%v) error = %v, want %v", PhotoSize648x648, err, ErrFindProfilePhoto)
+	}
+}
@@ -1,6 +1,7 @@
 package msgraph
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
@@ -265,3 +266,31 @@ func TestUser_PrettySimpleString(t *testing.T) {
 		t.Errorf("user.GetFullName() should return \"%v\", but returns: \"%v\"", wanted, testuser.PrettySimpleString())
 	}
 }
+
+func TestUser_MarshalJSON(t *testing.T) {
+	created := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		user User
+		want string
+	}{
+		{name: "Unset times are omitted", user: User{DisplayName: "Alice"}, want: `{"displayName":"Alice","passwordProfile":{}}`},
+		{name: "Set times are kept", user: User{DisplayName: "Alice", CreatedDateTime: created},
+			want: `{"createdDateTime":"2021-06-01T10:00:00Z","displayName":"Alice","passwordProfile":{}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.user)
+			if err != nil {
+				t.Fatalf("User.MarshalJSON() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("User.MarshalJSON() = %s, want %s", got, tt.want)
+			}
+			var back User
+			if err := json.Unmarshal(got, &back); err != nil || !back.CreatedDateTime.Equal(tt.user.CreatedDateTime) {
+				t.Errorf("json.Unmarshal() = %v, error = %v", back.CreatedDateTime, err)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package msgraph
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 // Users represents multiple Users, used in JSON unmarshal
@@ -41,6 +42,26 @@ func (u Users) GetUserByMail(email string) (User, error) {
 	return User{}, ErrFindUser
 }
 
+// UnredeemedGuests returns all guests that have not redeemed their invitation and have been
+// invited before invitedBefore, or all of them if invitedBefore is zero. The invitation time is
+// the ExternalUserStateChangeDateTime, or the CreatedDateTime if the former is not set.
+func (u Users) UnredeemedGuests(invitedBefore time.Time) Users {
+	var guests Users
+	for _, user := range u {
+		if user.UserType != "Guest" || user.ExternalUserState != "PendingAcceptance" {
+			continue
+		}
+		invited := user.ExternalUserStateChangeDateTime
+		if invited.IsZero() {
+			invited = user.CreatedDateTime
+		}
+		if invitedBefore.IsZero() || invited.Before(invitedBefore) {
+			guests = append(guests, user)
+		}
+	}
+	return guests
+}
+
 func (u Users) String() string {
 	var strs = make([]string, len(u))
 	for i, user := range u {
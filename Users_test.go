@@ -3,6 +3,7 @@ package msgraph
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 var (
@@ -174,3 +175,35 @@ func TestUsers_Equal(t *testing.T) {
 		})
 	}
 }
+
+func TestUsers_UnredeemedGuests(t *testing.T) {
+	now := time.Now()
+	users := Users{
+		{ID: "member", UserType: "Member", CreatedDateTime: now.AddDate(-1, 0, 0)},
+		{ID: "accepted", UserType: "Guest", ExternalUserState: "Accepted", ExternalUserStateChangeDateTime: now.AddDate(-1, 0, 0)},
+		{ID: "old", UserType: "Guest", ExternalUserState: "PendingAcceptance", ExternalUserStateChangeDateTime: now.AddDate(0, -6, 0)},
+		{ID: "old-created", UserType: "Guest", ExternalUserState: "PendingAcceptance", CreatedDateTime: now.AddDate(0, -6, 0)},
+		{ID: "recent", UserType: "Guest", ExternalUserState: "PendingAcceptance", ExternalUserStateChangeDateTime: now.AddDate(0, 0, -1), CreatedDateTime: now.AddDate(-1, 0, 0)},
+	}
+	tests := []struct {
+		name          string
+		invitedBefore time.Time
+		want          []string
+	}{
+		{name: "all", want: []string{"old", "old-created", "recent"}},
+		{name: "older than 3 months", invitedBefore: now.AddDate(0, -3, 0), want: []string{"old", "old-created"}},
+		{name: "older than 1 year", invitedBefore: now.AddDate(-1, 0, 0), want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := users.UnredeemedGuests(tt.invitedBefore)
+			var ids []string
+			for _, user := range got {
+				ids = append(ids, user.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("Users.UnredeemedGuests() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
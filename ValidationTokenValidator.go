@@ -0,0 +1,241 @@
+package msgraph
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ValidationTokenSigningKeysURL is the JSON Web Key Set of the keys that sign the validation
+	// tokens of change notifications, see FetchSigningKeys.
+	ValidationTokenSigningKeysURL = "https://login.microsoftonline.com/common/discovery/v2.0/keys"
+	// ChangeNotificationPublisherID is the application ID of Microsoft Graph change notifications,
+	// the authorized party (azp / appid claim) of every validation token.
+	ChangeNotificationPublisherID = "0bf30f3b-4a52-48df-9a82-234910c4a086"
+)
+
+// validationTokenClockSkew is the clock skew tolerated when validating exp and nbf of a validation token.
+const validationTokenClockSkew = 5 * time.Minute
+
+// ValidationTokenValidator validates the JWT validation tokens Microsoft Graph sends along
+// with change notifications of subscriptions with IncludeResourceData, see
+// ChangeNotificationCollection.ValidationTokens. A token is valid if it is signed with RS256 by
+// one of the signing keys, is not expired, is issued for one of the ApplicationIDs by one of the
+// TenantIDs and is authorized by Microsoft Graph change notifications.
+//
+// See https://docs.microsoft.com/en-us/graph/webhooks-with-resource-data#validation-tokens-in-the-change-notification
+type ValidationTokenValidator struct {
+	ApplicationIDs    []string // accepted audiences, hence the application IDs of the subscriptions
+	TenantIDs         []string // accepted tenants, the issuer must be https://sts.windows.net/{tenantID}/
+	AuthorizedParties []string // accepted azp or appid claims, defaults to ChangeNotificationPublisherID
+
+	mu          sync.RWMutex
+	signingKeys map[string]*rsa.PublicKey // signing keys by their key ID
+}
+
+// NewValidationTokenValidator returns a new ValidationTokenValidator for the application and
+// tenant with the given signing keys, e.g. retrieved by FetchSigningKeys.
+func NewValidationTokenValidator(applicationID, tenantID string, signingKeys map[string]*rsa.PublicKey) *ValidationTokenValidator {
+	v := &ValidationTokenValidator{
+		ApplicationIDs:    []string{applicationID},
+		TenantIDs:         []string{tenantID},
+		AuthorizedParties: []string{ChangeNotificationPublisherID},
+	}
+	v.SetSigningKeys(signingKeys)
+	return v
+}
+
+// SetSigningKeys replaces the signing keys of the validator, e.g. to follow key rotation.
+func (v *ValidationTokenValidator) SetSigningKeys(signingKeys map[string]*rsa.PublicKey) {
+	keys := make(map[string]*rsa.PublicKey, len(signingKeys))
+	for kid, key := range signingKeys {
+		keys[kid] = key
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.signingKeys = keys
+}
+
+// ValidateAll validates all tokens, see Validate. Returns an error if there are no tokens at all.
+func (v *ValidationTokenValidator) ValidateAll(tokens []string) error {
+	if len(tokens) == 0 {
+		return fmt.Errorf("no validation tokens")
+	}
+	for i, token := range tokens {
+		if err := v.Validate(token); err != nil {
+			return fmt.Errorf("validation token %v is invalid: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// Validate validates the signature and the claims of the JWT validation token.
+func (v *ValidationTokenValidator) Validate(token string) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("token is not a JWT")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+		X5t string `json:"x5t"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return fmt.Errorf("invalid header: %v", err)
+	}
+	if header.Alg != "RS256" {
+		return fmt.Errorf("unsupported signing algorithm %q", header.Alg)
+	}
+	kid := header.Kid
+	if kid == "" {
+		kid = header.X5t
+	}
+	v.mu.RLock()
+	key := v.signingKeys[kid]
+	v.mu.RUnlock()
+	if key == nil {
+		return fmt.Errorf("unknown signing key %q", kid)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %v", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return fmt.Errorf("invalid signature: %v", err)
+	}
+
+	var claims struct {
+		Aud   interface{} `json:"aud"` // string or array of strings
+		Iss   string      `json:"iss"`
+		Exp   int64       `json:"exp"`
+		Nbf   int64       `json:"nbf"`
+		Azp   string      `json:"azp"`
+		AppID string      `json:"appid"`
+	}
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return fmt.Errorf("invalid claims: %v", err)
+	}
+	now := time.Now()
+	if claims.Exp == 0 || now.Add(-validationTokenClockSkew).After(time.Unix(claims.Exp, 0)) {
+		return fmt.Errorf("token expired at %v", time.Unix(claims.Exp, 0))
+	}
+	if claims.Nbf != 0 && now.Add(validationTokenClockSkew).Before(time.Unix(claims.Nbf, 0)) {
+		return fmt.Errorf("token not valid before %v", time.Unix(claims.Nbf, 0))
+	}
+	var audiences []string
+	switch aud := claims.Aud.(type) {
+	case string:
+		audiences = []string{aud}
+	case []interface{}:
+		for _, a := range aud {
+			if s, ok := a.(string); ok {
+				audiences = append(audiences, s)
+			}
+		}
+	}
+	if !containsAny(v.ApplicationIDs, audiences...) {
+		return fmt.Errorf("audience %v is not accepted", audiences)
+	}
+	tenantID := strings.TrimSuffix(strings.TrimPrefix(claims.Iss, "https://sts.windows.net/"), "/")
+	if !strings.HasPrefix(claims.Iss, "https://sts.windows.net/") || !containsAny(v.TenantIDs, tenantID) {
+		return fmt.Errorf("issuer %q is not accepted", claims.Iss)
+	}
+	authorizedParties := v.AuthorizedParties
+	if len(authorizedParties) == 0 {
+		authorizedParties = []string{ChangeNotificationPublisherID}
+	}
+	if !containsAny(authorizedParties, claims.Azp, claims.AppID) {
+		return fmt.Errorf("authorized party %q is not accepted", claims.Azp+claims.AppID)
+	}
+	return nil
+}
+
+// decodeJWTPart base64url-decodes and json-unmarshals a part of a JWT.
+func decodeJWTPart(part string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// containsAny returns true if any of the values is a non-empty element of list, compared case-insensitively.
+func containsAny(list []string, values ...string) bool {
+	for _, value := range values {
+		for _, element := range list {
+			if value != "" && strings.EqualFold(element, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseSigningKeys parses the RSA keys of a JSON Web Key Set, e.g. served at
+// ValidationTokenSigningKeysURL, and returns them by their key ID.
+func ParseSigningKeys(jwks []byte) (map[string]*rsa.PublicKey, error) {
+	var keySet struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(jwks, &keySet); err != nil {
+		return nil, fmt.Errorf("cannot parse JSON Web Key Set: %v", err)
+	}
+	keys := make(map[string]*rsa.PublicKey)
+	for _, key := range keySet.Keys {
+		if key.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus of key %v: %v", key.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent of key %v: %v", key.Kid, err)
+		}
+		keys[key.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	return keys, nil
+}
+
+// FetchSigningKeys retrieves and parses the JSON Web Key Set at the given URL, e.g.
+// ValidationTokenSigningKeysURL, see ParseSigningKeys.
+func FetchSigningKeys(ctx context.Context, jwksURL string) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request error: %v", err)
+	}
+	httpClient := &http.Client{
+		Timeout: time.Second * 10,
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP response error: %v of http.Request: %v", err, req.URL)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("StatusCode is not OK: %v. Body: %v ", resp.StatusCode, string(body))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("HTTP response read error: %v of http.Request: %v", err, req.URL)
+	}
+	return ParseSigningKeys(body)
+}
@@ -0,0 +1,157 @@
+package msgraph
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// signTestToken returns a RS256 JWT with the given claims signed by the key.
+func signTestToken(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": kid})
+	payload, _ := json.Marshal(claims)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("rsa.SignPKCS1v15() error = %v", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestValidationTokenValidator_Validate(t *testing.T) {
+	signingKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	validator := NewValidationTokenValidator("app-id", "tenant-id", map[string]*rsa.PublicKey{"key-1": &signingKey.PublicKey})
+
+	validClaims := func() map[string]interface{} {
+		return map[string]interface{}{
+			"aud": "app-id",
+			"iss": "https://sts.windows.net/tenant-id/",
+			"exp": time.Now().Add(time.Hour).Unix(),
+			"nbf": time.Now().Add(-time.Minute).Unix(),
+			"azp": ChangeNotificationPublisherID,
+		}
+	}
+	withClaim := func(key string, value interface{}) map[string]interface{} {
+		claims := validClaims()
+		claims[key] = value
+		return claims
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "Valid", token: signTestToken(t, signingKey, "key-1", validClaims())},
+		{name: "Valid with audience array and appid", token: signTestToken(t, signingKey, "key-1", map[string]interface{}{
+			"aud": []string{"other", "app-id"}, "iss": "https://sts.windows.net/tenant-id/", "exp": time.Now().Add(time.Hour).Unix(), "appid": ChangeNotificationPublisherID,
+		})},
+		{name: "Unknown key", token: signTestToken(t, signingKey, "key-2", validClaims()), wantErr: true},
+		{name: "Wrong signature", token: signTestToken(t, otherKey, "key-1", validClaims()), wantErr: true},
+		{name: "Expired", token: signTestToken(t, signingKey, "key-1", withClaim("exp", time.Now().Add(-time.Hour).Unix())), wantErr: true},
+		{name: "Not yet valid", token: signTestToken(t, signingKey, "key-1", withClaim("nbf", time.Now().Add(time.Hour).Unix())), wantErr: true},
+		{name: "Wrong audience", token: signTestToken(t, signingKey, "key-1", withClaim("aud", "other-app")), wantErr: true},
+		{name: "Wrong tenant", token: signTestToken(t, signingKey, "key-1", withClaim("iss", "https://sts.windows.net/other-tenant/")), wantErr: true},
+		{name: "Wrong authorized party", token: signTestToken(t, signingKey, "key-1", withClaim("azp", "other-app")), wantErr: true},
+		{name: "Not a JWT", token: "not.a-jwt", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validator.Validate(tt.token); (err != nil) != tt.wantErr {
+				t.Errorf("ValidationTokenValidator.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := validator.ValidateAll(nil); err == nil {
+		t.Errorf("ValidationTokenValidator.ValidateAll() without tokens error = nil, want error")
+	}
+	validator.SetSigningKeys(map[string]*rsa.PublicKey{"key-2": &otherKey.PublicKey})
+	if err := validator.ValidateAll([]string{signTestToken(t, otherKey, "key-2", validClaims())}); err != nil {
+		t.Errorf("ValidationTokenValidator.ValidateAll() after SetSigningKeys() error = %v", err)
+	}
+}
+
+func TestFetchSigningKeys(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	jwks := fmt.Sprintf(`{"keys": [{"kty": "RSA", "use": "sig", "kid": "key-1", "n": %q, "e": %q}, {"kty": "EC", "kid": "ec-key"}]}`,
+		base64.RawURLEncoding.EncodeToString(key.N.Bytes()), base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(jwks))
+	}))
+	defer srv.Close()
+
+	keys, err := FetchSigningKeys(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("FetchSigningKeys() error = %v", err)
+	}
+	if len(keys) != 1 || keys["key-1"] == nil || keys["key-1"].N.Cmp(key.N) != 0 || keys["key-1"].E != key.E {
+		t.Errorf("FetchSigningKeys() = %v, want key-1", keys)
+	}
+	if _, err := ParseSigningKeys([]byte(`{"keys": [`)); err == nil {
+		t.Errorf("ParseSigningKeys() with invalid JSON error = nil, want error")
+	}
+}
+
+func TestNotificationHandler_ValidationTokens(t *testing.T) {
+	signingKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	handler := NewNotificationHandler("secret")
+	handler.ValidationTokenValidator = NewValidationTokenValidator("app-id", "tenant-id", map[string]*rsa.PublicKey{"key-1": &signingKey.PublicKey})
+	validToken := signTestToken(t, signingKey, "key-1", map[string]interface{}{
+		"aud": "app-id", "iss": "https://sts.windows.net/tenant-id/", "exp": time.Now().Add(time.Hour).Unix(), "azp": ChangeNotificationPublisherID,
+	})
+	batch := func(tokens ...string) string {
+		data, _ := json.Marshal(ChangeNotificationCollection{
+			Value:            []ChangeNotification{{ClientState: "secret", EncryptedContent: &ChangeNotificationEncryptedContent{Data: "data"}}},
+			ValidationTokens: tokens,
+		})
+		return string(data)
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{name: "Valid tokens", body: batch(validToken), wantStatus: http.StatusAccepted},
+		{name: "Missing tokens", body: batch(), wantStatus: http.StatusBadRequest},
+		{name: "Invalid token", body: batch(validToken, "invalid"), wantStatus: http.StatusBadRequest},
+		{name: "No resource data", body: `{"value": [{"clientState": "secret"}]}`, wantStatus: http.StatusAccepted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/notifications", strings.NewReader(tt.body)))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("NotificationHandler.ServeHTTP() status = %v, want %v", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -75,6 +75,20 @@ var (
 	ErrFindGroup = errors.New("unable to find group")
 	// ErrFindCalendar is returned on any func that tries to find a calendar with the given parameters that cannot be found
 	ErrFindCalendar = errors.New("unable to find calendar")
+	// ErrFindSubscribedSku is returned on any func that tries to find a subscribed SKU with the given parameters that cannot be found
+	ErrFindSubscribedSku = errors.New("unable to find subscribed SKU")
+	// ErrFindLicenseDetails is returned on any func that tries to find the license details of a user with the given parameters that cannot be found
+	ErrFindLicenseDetails = errors.New("unable to find license details")
+	// ErrFindProfilePhoto is returned on any func that tries to find a profile photo with the given parameters that cannot be found
+	ErrFindProfilePhoto = errors.New("unable to find profile photo")
+	// ErrFindExtension is returned on any func that tries to find an open or schema extension with the given parameters that cannot be found
+	ErrFindExtension = errors.New("unable to find extension")
+	// ErrFindDirectoryObject is returned on any func that tries to find a directory object with the given parameters that cannot be found
+	ErrFindDirectoryObject = errors.New("unable to find directory object")
+	// ErrFindConversationMember is returned on any func that tries to find a team or channel member with the given parameters that cannot be found
+	ErrFindConversationMember = errors.New("unable to find conversation member")
+	// ErrFindChannel is returned on any func that tries to find a channel with the given parameters that cannot be found
+	ErrFindChannel = errors.New("unable to find channel")
 	// ErrNotGraphClientSourced is returned if e.g. a ListMembers() is called but the Group has not been created by a graphClient query
 	ErrNotGraphClientSourced = errors.New("instance is not created from a GraphClient API-Call, cannot directly get further information")
 )
@@ -0,0 +1,90 @@
+package msgraphtest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"unicode/utf8"
+)
+
+// Cassette holds request/response pairs recorded by a Recorder and served by a Replayer.
+// It is stored as indented JSON, hence it can be reviewed and committed along with the tests.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single recorded request/response pair.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the recorded part of a http.Request.
+type RecordedRequest struct {
+	Method       string      `json:"method"`
+	URL          string      `json:"url"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"bodyEncoding,omitempty"` // "base64" if the body is binary, otherwise empty
+}
+
+// RecordedResponse is the recorded part of a http.Response.
+type RecordedResponse struct {
+	StatusCode   int         `json:"statusCode"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"bodyEncoding,omitempty"` // "base64" if the body is binary, otherwise empty
+}
+
+// LoadCassette reads a Cassette from the JSON file at the given path, see Cassette.Save.
+func LoadCassette(path string) (Cassette, error) {
+	var c Cassette
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return c, fmt.Errorf("cannot read cassette: %v", err)
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("cannot parse cassette %v: %v", path, err)
+	}
+	return c, nil
+}
+
+// Save writes the Cassette as indented JSON to the file at the given path.
+func (c Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// encodeBody returns the body as string and the encoding used: plain text for UTF-8, otherwise base64.
+func encodeBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), "base64"
+}
+
+// decodeBody reverses encodeBody.
+func decodeBody(body, encoding string) ([]byte, error) {
+	if encoding == "base64" {
+		return base64.StdEncoding.DecodeString(body)
+	}
+	return []byte(body), nil
+}
+
+// readBody reads and returns the body and replaces it with a new reader of the same content.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := ioutil.ReadAll(*body)
+	(*body).Close()
+	*body = ioutil.NopCloser(bytes.NewReader(data))
+	return data, err
+}
@@ -0,0 +1,102 @@
+package msgraphtest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fault describes an error or latency the Server injects into matching requests, see
+// Server.InjectFault. A Fault with a StatusCode responds with that error instead of serving
+// the request, a Fault with only a Delay serves the request regularly after the delay.
+type Fault struct {
+	Method     string        // HTTP method to match, empty matches all methods
+	Path       string        // prefix of the resource path to match without API version, e.g. /users. Empty matches all paths
+	StatusCode int           // status code to respond with, 0 serves the request regularly
+	RetryAfter time.Duration // value of the Retry-After header, only set if greater than 0
+	Delay      time.Duration // delay before the response is written, aborted if the client cancels the request
+	Times      int           // number of requests the Fault is applied to, 0 applies it until Server.ClearFaults is called
+
+	applied int // number of requests the Fault has been applied to
+}
+
+// TooManyRequests returns a Fault that throttles all requests to the given path with status
+// 429, like Microsoft Graph does when the request rate is too high.
+func TooManyRequests(path string, retryAfter time.Duration) Fault {
+	return Fault{Path: path, StatusCode: http.StatusTooManyRequests, RetryAfter: retryAfter}
+}
+
+// InternalServerError returns a Fault that responds with status 500 to all requests to the given path.
+func InternalServerError(path string) Fault {
+	return Fault{Path: path, StatusCode: http.StatusInternalServerError}
+}
+
+// SlowResponse returns a Fault that delays all responses to the given path.
+func SlowResponse(path string, delay time.Duration) Fault {
+	return Fault{Path: path, Delay: delay}
+}
+
+// InjectFault adds the Fault to the Server. If multiple Faults match a request, the one that
+// was injected first is applied.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f.applied = 0
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all Faults from the Server.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// matchFault returns the first Fault matching the request and counts it as applied. Faults
+// that have been applied often enough are removed. s.mu must be held.
+func (s *Server) matchFault(method, path string) *Fault {
+	for i, f := range s.faults {
+		if (f.Method != "" && f.Method != method) || !strings.HasPrefix(strings.ToLower(path), strings.ToLower(f.Path)) {
+			continue
+		}
+		f.applied++
+		if f.Times > 0 && f.applied >= f.Times {
+			s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+		}
+		return f
+	}
+	return nil
+}
+
+// apply applies the Fault to the request. Returns true if a response has been written,
+// hence the request must not be served anymore.
+func (f *Fault) apply(w http.ResponseWriter, r *http.Request) bool {
+	if f.Delay > 0 {
+		timer := time.NewTimer(f.Delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			return true
+		}
+	}
+	if f.StatusCode == 0 {
+		return false
+	}
+	if f.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(f.RetryAfter.Seconds())))
+	}
+	code := map[int]string{
+		http.StatusTooManyRequests:     "TooManyRequests",
+		http.StatusInternalServerError: "InternalServerError",
+		http.StatusServiceUnavailable:  "ServiceUnavailable",
+		http.StatusGatewayTimeout:      "GatewayTimeout",
+	}[f.StatusCode]
+	if code == "" {
+		code = strings.Replace(http.StatusText(f.StatusCode), " ", "", -1)
+	}
+	WriteError(w, f.StatusCode, code, fmt.Sprintf("Fault injected by msgraphtest: %v %v", f.StatusCode, http.StatusText(f.StatusCode)))
+	return true
+}
@@ -0,0 +1,455 @@
+package msgraphtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Object is a Microsoft Graph resource as it is stored and served by the Server, hence the
+// JSON representation of e.g. a user, a group or a calendar event.
+type Object map[string]interface{}
+
+// ID returns the id property of the Object.
+func (o Object) ID() string {
+	id, _ := o["id"].(string)
+	return id
+}
+
+// copy returns a deep copy of the Object.
+func (o Object) copy() Object {
+	return copyValue(map[string]interface{}(o)).(map[string]interface{})
+}
+
+// copyValue returns a deep copy of the given JSON value.
+func copyValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(val))
+		for k, e := range val {
+			c[k] = copyValue(e)
+		}
+		return c
+	case Object:
+		return copyValue(map[string]interface{}(val))
+	case []interface{}:
+		c := make([]interface{}, len(val))
+		for i, e := range val {
+			c[i] = copyValue(e)
+		}
+		return c
+	default:
+		return val
+	}
+}
+
+// toObject converts v to an Object by JSON-marshalling and unmarshalling it.
+func toObject(v interface{}) (Object, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var obj Object
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&obj); err != nil {
+		return nil, err
+	}
+	if obj == nil {
+		return nil, fmt.Errorf("%T is not a JSON object", v)
+	}
+	return obj, nil
+}
+
+// directoryCollections are the collections that are searched for directory objects, e.g.
+// when resolving group members.
+var directoryCollections = []string{"/users", "/groups", "/devices", "/servicePrincipals", "/contacts"}
+
+// odataTypes contains the @odata.type that is set on objects added to the collection.
+var odataTypes = map[string]string{
+	"/users":             "#microsoft.graph.user",
+	"/groups":            "#microsoft.graph.group",
+	"/devices":           "#microsoft.graph.device",
+	"/servicePrincipals": "#microsoft.graph.servicePrincipal",
+	"/contacts":          "#microsoft.graph.orgContact",
+}
+
+// Add stores a copy of v in the collection with the given path, e.g. /security/alerts or
+// /users/{id}/calendars, and returns a copy of the stored Object. v may be anything that
+// marshals to a JSON object, e.g. an Object, a map or a msgraph.User. An id is generated if
+// v has none.
+//
+// Panics if v cannot be marshalled to a JSON object.
+func (s *Server) Add(collection string, v interface{}) Object {
+	obj, err := toObject(v)
+	if err != nil {
+		panic(fmt.Sprintf("msgraphtest: cannot add %T to %v: %v", v, collection, err))
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.insert(s.normalizePath(collection), obj).copy()
+}
+
+// AddUser adds the given user to /users, see Add.
+func (s *Server) AddUser(v interface{}) Object {
+	return s.Add("/users", v)
+}
+
+// AddGroup adds the given group to /groups, see Add.
+func (s *Server) AddGroup(v interface{}) Object {
+	return s.Add("/groups", v)
+}
+
+// AddGroupMember adds the directory object, e.g. a user or a group, with the given ID as
+// direct member to the group with the given ID.
+func (s *Server) AddGroupMember(groupID, memberID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if group, ok := s.find("/groups", groupID); ok {
+		groupID = group.ID()
+	}
+	for _, id := range s.members[groupID] {
+		if id == memberID {
+			return
+		}
+	}
+	s.members[groupID] = append(s.members[groupID], memberID)
+}
+
+// GroupMembers returns the IDs of all direct members of the group with the given ID.
+func (s *Server) GroupMembers(groupID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if group, ok := s.find("/groups", groupID); ok {
+		groupID = group.ID()
+	}
+	return append([]string(nil), s.members[groupID]...)
+}
+
+// AddCalendar adds the given calendar to the user with the given ID or userPrincipalName, see Add.
+func (s *Server) AddCalendar(userID string, v interface{}) Object {
+	return s.Add("/users/"+userID+"/calendars", v)
+}
+
+// AddEvent adds the given event to the default calendar of the user with the given ID or
+// userPrincipalName, see Add. Events are served by /users/{id}/calendar/events and by
+// /users/{id}/calendar/calendarView if they overlap with the requested time range.
+func (s *Server) AddEvent(userID string, v interface{}) Object {
+	return s.Add("/users/"+userID+"/events", v)
+}
+
+// AddAlert adds the given security alert to /security/alerts, see Add.
+func (s *Server) AddAlert(v interface{}) Object {
+	return s.Add("/security/alerts", v)
+}
+
+// AddSecureScore adds the given secure score to /security/secureScores, see Add.
+func (s *Server) AddSecureScore(v interface{}) Object {
+	return s.Add("/security/secureScores", v)
+}
+
+// AddSecureScoreControlProfile adds the given control profile to /security/secureScoreControlProfiles, see Add.
+func (s *Server) AddSecureScoreControlProfile(v interface{}) Object {
+	return s.Add("/security/secureScoreControlProfiles", v)
+}
+
+// Get returns a copy of the Object with the given ID (or userPrincipalName) in the given collection.
+func (s *Server) Get(collection, id string) (Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.find(s.normalizePath(collection), id)
+	if !ok {
+		return nil, false
+	}
+	return obj.copy(), true
+}
+
+// List returns a copy of all Objects in the given collection.
+func (s *Server) List(collection string) []Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var objs []Object
+	for _, obj := range s.collections[s.normalizePath(collection)] {
+		objs = append(objs, obj.copy())
+	}
+	return objs
+}
+
+// newID returns a new unique ID in GUID format. s.mu must be held.
+func (s *Server) newID() string {
+	s.idCounter++
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", s.idCounter)
+}
+
+// insert stores the object in the collection and sets the defaults Microsoft Graph would set.
+// s.mu must be held.
+func (s *Server) insert(collection string, obj Object) Object {
+	if obj.ID() == "" {
+		obj["id"] = s.newID()
+	}
+	if odataType, ok := odataTypes[collection]; ok {
+		if _, ok := obj["@odata.type"]; !ok {
+			obj["@odata.type"] = odataType
+		}
+		if _, ok := obj["createdDateTime"]; !ok {
+			obj["createdDateTime"] = time.Now().UTC().Format(time.RFC3339)
+		}
+	}
+	delete(obj, "passwordProfile") // write-only, never returned by Microsoft Graph
+	s.collections[collection] = append(s.collections[collection], obj)
+	return obj
+}
+
+// find returns the object with the given ID in the collection. Users may also be found by
+// their userPrincipalName. s.mu must be held.
+func (s *Server) find(collection, id string) (Object, bool) {
+	idx := s.indexOf(collection, id)
+	if idx < 0 {
+		return nil, false
+	}
+	return s.collections[collection][idx], true
+}
+
+// indexOf returns the index of the object with the given ID in the collection or -1. s.mu must be held.
+func (s *Server) indexOf(collection, id string) int {
+	for i, obj := range s.collections[collection] {
+		if obj.ID() == id {
+			return i
+		}
+		if upn, ok := obj["userPrincipalName"].(string); ok && strings.EqualFold(upn, id) {
+			return i
+		}
+	}
+	return -1
+}
+
+// findDirectoryObject returns the user, group, device, service principal or org contact with
+// the given ID. s.mu must be held.
+func (s *Server) findDirectoryObject(id string) (Object, bool) {
+	for _, collection := range directoryCollections {
+		if obj, ok := s.find(collection, id); ok {
+			return obj, true
+		}
+	}
+	return nil, false
+}
+
+// memberOf returns the IDs of all groups the directory object with the given ID is a direct
+// member of, or with transitive a direct or nested member of. s.mu must be held.
+func (s *Server) memberOf(id string, transitive bool) []string {
+	var groupIDs []string
+	visited := map[string]bool{id: true}
+	queue := []string{id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, group := range s.collections["/groups"] {
+			if visited[group.ID()] {
+				continue
+			}
+			for _, memberID := range s.members[group.ID()] {
+				if memberID == current {
+					visited[group.ID()] = true
+					groupIDs = append(groupIDs, group.ID())
+					if transitive {
+						queue = append(queue, group.ID())
+					}
+					break
+				}
+			}
+		}
+	}
+	return groupIDs
+}
+
+// normalizePath replaces a userPrincipalName in the second segment of the path, e.g.
+// /users/alice@contoso.com/calendars, with the ID of the object. s.mu must be held.
+func (s *Server) normalizePath(path string) string {
+	segments := splitPath(path)
+	if len(segments) > 1 {
+		if obj, ok := s.find("/"+segments[0], segments[1]); ok {
+			segments[1] = obj.ID()
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// requiredProperties lists the properties Microsoft Graph requires when creating an object in the collection.
+var requiredProperties = map[string][]string{
+	"/users":  {"accountEnabled", "displayName", "mailNickname", "passwordProfile", "userPrincipalName"},
+	"/groups": {"displayName", "mailEnabled", "mailNickname", "securityEnabled"},
+}
+
+// serveStore serves the generic object store: paths with an odd number of segments are
+// collections (GET lists, POST creates), paths with an even number of segments are objects
+// within a collection (GET, PATCH, DELETE).
+func (s *Server) serveStore(w http.ResponseWriter, r *http.Request, path string, body []byte) {
+	s.mu.Lock()
+	segments := splitPath(s.normalizePath(path))
+	if len(segments) == 0 {
+		s.mu.Unlock()
+		WriteError(w, http.StatusBadRequest, "BadRequest", "Resource not found for the segment ''.")
+		return
+	}
+	// nested collections, e.g. /users/{id}/calendars, require the owning object to exist
+	collectionSegments := segments[:len(segments)-1+len(segments)%2]
+	if n := len(collectionSegments); n >= 3 {
+		ownerID := collectionSegments[n-2]
+		if _, ok := s.find("/"+strings.Join(collectionSegments[:n-2], "/"), ownerID); !ok {
+			s.mu.Unlock()
+			writeNotFound(w, ownerID)
+			return
+		}
+	}
+
+	if len(segments)%2 == 1 {
+		collection := "/" + strings.Join(segments, "/")
+		switch r.Method {
+		case http.MethodGet:
+			objs := s.collections[collection]
+			s.mu.Unlock()
+			s.writeCollection(w, r, objs)
+		case http.MethodPost:
+			obj, err := s.create(collection, body)
+			s.mu.Unlock()
+			if err != nil {
+				WriteError(w, http.StatusBadRequest, "Request_BadRequest", err.Error())
+				return
+			}
+			WriteJSON(w, http.StatusCreated, obj)
+		default:
+			s.mu.Unlock()
+			WriteError(w, http.StatusMethodNotAllowed, "Request_BadRequest", fmt.Sprintf("Method %v is not allowed on a collection.", r.Method))
+		}
+		return
+	}
+
+	collection := "/" + strings.Join(segments[:len(segments)-1], "/")
+	id := segments[len(segments)-1]
+	idx := s.indexOf(collection, id)
+	if idx < 0 {
+		s.mu.Unlock()
+		writeNotFound(w, id)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		obj := s.collections[collection][idx]
+		s.mu.Unlock()
+		s.writeObject(w, r, obj)
+	case http.MethodPatch:
+		err := s.update(s.collections[collection][idx], body)
+		s.mu.Unlock()
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "Request_BadRequest", err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		if isSoftDeleted(collection, s.collections[collection][idx]) {
+			s.softDelete(collection, idx)
+		} else {
+			s.remove(collection, idx)
+		}
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s.mu.Unlock()
+		WriteError(w, http.StatusMethodNotAllowed, "Request_BadRequest", fmt.Sprintf("Method %v is not allowed on an object.", r.Method))
+	}
+}
+
+// create validates the JSON body and inserts it into the collection. s.mu must be held.
+func (s *Server) create(collection string, body []byte) (Object, error) {
+	obj, err := decodeObject(body)
+	if err != nil {
+		return nil, err
+	}
+	for _, property := range requiredProperties[collection] {
+		if _, ok := obj[property]; !ok {
+			return nil, fmt.Errorf("Property '%v' is required when creating the object.", property)
+		}
+	}
+	if upn, ok := obj["userPrincipalName"].(string); ok && s.indexOf(collection, upn) >= 0 {
+		return nil, fmt.Errorf("Another object with the same value for property userPrincipalName already exists.")
+	}
+	return s.insert(collection, obj).copy(), nil
+}
+
+// update merges the JSON body into the object. s.mu must be held.
+func (s *Server) update(obj Object, body []byte) error {
+	patch, err := decodeObject(body)
+	if err != nil {
+		return err
+	}
+	delete(patch, "id")
+	delete(patch, "passwordProfile")
+	for key, value := range patch {
+		obj[key] = value
+	}
+	return nil
+}
+
+// remove deletes the object at index idx from the collection, including its group
+// memberships and child collections. s.mu must be held.
+func (s *Server) remove(collection string, idx int) {
+	id := s.collections[collection][idx].ID()
+	s.collections[collection] = append(s.collections[collection][:idx:idx], s.collections[collection][idx+1:]...)
+	delete(s.members, id)
+	delete(s.owners, id)
+	delete(s.managers, id)
+	delete(s.mailboxSettings, id)
+	delete(s.photos, id)
+	for userID, managerID := range s.managers {
+		if managerID == id {
+			delete(s.managers, userID)
+		}
+	}
+	for groupID, memberIDs := range s.members {
+		for i, memberID := range memberIDs {
+			if memberID == id {
+				s.members[groupID] = append(memberIDs[:i:i], memberIDs[i+1:]...)
+				break
+			}
+		}
+	}
+	for groupID, ownerIDs := range s.owners {
+		for i, ownerID := range ownerIDs {
+			if ownerID == id {
+				s.owners[groupID] = append(ownerIDs[:i:i], ownerIDs[i+1:]...)
+				break
+			}
+		}
+	}
+	// the team of a group is deleted along with the group
+	if collection == "/groups" {
+		if idx := s.indexOf("/teams", id); idx >= 0 {
+			s.remove("/teams", idx)
+		}
+	}
+	prefix := collection + "/" + id + "/"
+	for key := range s.collections {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.collections, key)
+		}
+	}
+}
+
+// decodeObject decodes the JSON request body to an Object.
+func decodeObject(body []byte) (Object, error) {
+	var obj Object
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&obj); err != nil || obj == nil {
+		return nil, fmt.Errorf("Invalid JSON in request body: %v", err)
+	}
+	return obj, nil
+}
+
+// writeNotFound writes the error Microsoft Graph responds with if an object does not exist.
+func writeNotFound(w http.ResponseWriter, id string) {
+	WriteError(w, http.StatusNotFound, "Request_ResourceNotFound", fmt.Sprintf("Resource '%v' does not exist or one of its queried reference-property objects are not present.", id))
+}
@@ -0,0 +1,318 @@
+package msgraphtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// redacted replaces secrets in recorded interactions.
+const redacted = "REDACTED"
+
+// DefaultPIIProperties are JSON properties that contain personally identifiable information
+// about users and can be used as Recorder.PIIProperties.
+var DefaultPIIProperties = []string{
+	"mail", "userPrincipalName", "displayName", "givenName", "surname", "mobilePhone",
+	"businessPhones", "otherMails", "proxyAddresses", "mailNickname", "employeeId",
+}
+
+// secretProperties are JSON and form properties whose values are always replaced by REDACTED.
+var secretProperties = map[string]bool{
+	"access_token":        true,
+	"refresh_token":       true,
+	"id_token":            true,
+	"client_secret":       true,
+	"client_assertion":    true,
+	"password":            true,
+	"newPassword":         true,
+	"currentPassword":     true,
+	"temporaryAccessPass": true,
+	"clientState":         true,
+}
+
+// secretHeaders are headers whose values are always replaced by REDACTED.
+var secretHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// Recorder is a http.RoundTripper that sends requests via Transport and records all
+// request/response pairs. Use it with msgraph.WithHTTPClient and save the recorded Cassette
+// afterwards to replay it with a Replayer:
+//
+//	recorder := msgraphtest.NewRecorder(nil)
+//	graphClient, err := msgraph.NewGraphClient(tenantID, applicationID, clientSecret, msgraph.WithHTTPClient(&http.Client{Transport: recorder}))
+//	// ... perform API-calls
+//	err = recorder.Save("testdata/listusers.json")
+//
+// Access tokens, client secrets, passwords, subscription client states and authorization
+// headers are always scrubbed.
+type Recorder struct {
+	// Transport performs the actual requests. http.DefaultTransport is used if nil.
+	Transport http.RoundTripper
+	// PIIProperties are the names of JSON properties whose values are replaced by
+	// placeholders, e.g. DefaultPIIProperties. The replaced values are also replaced wherever
+	// else they occur in the Cassette, e.g. a userPrincipalName in a URL. No personally
+	// identifiable information is scrubbed if empty.
+	PIIProperties []string
+
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+// NewRecorder returns a new Recorder that sends requests via the given transport, or
+// http.DefaultTransport if nil.
+func NewRecorder(transport http.RoundTripper) *Recorder {
+	return &Recorder{Transport: transport}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read request body: %v", err)
+	}
+	outReq := req.Clone(req.Context())
+	outReq.Body = req.Body
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(outReq)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read response body: %v", err)
+	}
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: scrubHeader(req.Header),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     scrubHeader(resp.Header),
+		},
+	}
+	interaction.Request.Body, interaction.Request.BodyEncoding = encodeBody(scrubBody(reqBody, req.Header.Get("Content-Type")))
+	interaction.Response.Body, interaction.Response.BodyEncoding = encodeBody(scrubBody(respBody, resp.Header.Get("Content-Type")))
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, interaction)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// Cassette returns all interactions recorded so far with personally identifiable information
+// scrubbed according to PIIProperties.
+func (r *Recorder) Cassette() Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := Cassette{Interactions: append([]Interaction(nil), r.interactions...)}
+	if len(r.PIIProperties) > 0 {
+		scrubPII(&c, r.PIIProperties)
+	}
+	return c
+}
+
+// Save writes the Cassette to the file at the given path, see Cassette.Save.
+func (r *Recorder) Save(path string) error {
+	return r.Cassette().Save(path)
+}
+
+// scrubHeader returns a copy of the header with all secret headers redacted.
+func scrubHeader(header http.Header) http.Header {
+	scrubbed := header.Clone()
+	for _, key := range secretHeaders {
+		if scrubbed.Get(key) != "" {
+			scrubbed.Set(key, redacted)
+		}
+	}
+	return scrubbed
+}
+
+// scrubBody redacts all secret properties of a JSON or form encoded body. Other bodies are
+// returned as they are.
+func scrubBody(body []byte, contentType string) []byte {
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return body
+		}
+		for key := range values {
+			if secretProperties[key] {
+				values.Set(key, redacted)
+			}
+		}
+		return []byte(values.Encode())
+	}
+
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return body
+	}
+	if !redactJSON(v) {
+		return body
+	}
+	scrubbed, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return scrubbed
+}
+
+// redactJSON replaces the values of all secret properties in the JSON value. Returns true if
+// anything has been replaced.
+func redactJSON(v interface{}) bool {
+	var changed bool
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for key, e := range val {
+			if _, isString := e.(string); secretProperties[key] && isString {
+				val[key] = redacted
+				changed = true
+				continue
+			}
+			changed = redactJSON(e) || changed
+		}
+	case []interface{}:
+		for _, e := range val {
+			changed = redactJSON(e) || changed
+		}
+	}
+	return changed
+}
+
+// scrubPII replaces the values of the given JSON properties everywhere in the Cassette, i.e.
+// in URLs, headers and bodies, by deterministic placeholders. E-mail addresses are replaced by
+// userN@example.com, all other values by propertyN.
+func scrubPII(c *Cassette, properties []string) {
+	isPII := make(map[string]bool, len(properties))
+	for _, property := range properties {
+		isPII[strings.ToLower(property)] = true
+	}
+
+	// collect all values in the order of their appearance, properties of an object in
+	// alphabetical order, to generate deterministic placeholders
+	replacements := make(map[string]string)
+	var counter int
+	collect := func(body, encoding string) {
+		if encoding != "" {
+			return
+		}
+		var v interface{}
+		if err := json.Unmarshal([]byte(body), &v); err != nil {
+			return
+		}
+		collectPII(v, isPII, func(property, value string) {
+			if idx := strings.Index(value, ":"); idx >= 0 && idx < strings.Index(value, "@") {
+				value = value[idx+1:] // e.g. SMTP:alice@contoso.com in proxyAddresses
+			}
+			if len(value) < 3 || replacements[value] != "" {
+				return
+			}
+			counter++
+			if strings.Contains(value, "@") {
+				replacements[value] = fmt.Sprintf("user%d@example.com", counter)
+			} else {
+				replacements[value] = fmt.Sprintf("%s%d", property, counter)
+			}
+		})
+	}
+	for _, interaction := range c.Interactions {
+		collect(interaction.Request.Body, interaction.Request.BodyEncoding)
+		collect(interaction.Response.Body, interaction.Response.BodyEncoding)
+	}
+
+	// replace longer values first, hence values containing other values are replaced as a whole
+	var oldnew []string
+	values := make([]string, 0, len(replacements))
+	for value := range replacements {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if len(values[i]) != len(values[j]) {
+			return len(values[i]) > len(values[j])
+		}
+		return values[i] < values[j]
+	})
+	for _, value := range values {
+		replacement := replacements[value]
+		oldnew = append(oldnew, value, replacement)
+		if escaped := url.QueryEscape(value); escaped != value {
+			oldnew = append(oldnew, escaped, url.QueryEscape(replacement))
+		}
+		if escaped := url.PathEscape(value); escaped != value {
+			oldnew = append(oldnew, escaped, url.PathEscape(replacement))
+		}
+	}
+	replacer := strings.NewReplacer(oldnew...)
+
+	for i := range c.Interactions {
+		interaction := &c.Interactions[i]
+		interaction.Request.URL = replacer.Replace(interaction.Request.URL)
+		interaction.Request.Header = replaceHeader(interaction.Request.Header, replacer)
+		interaction.Response.Header = replaceHeader(interaction.Response.Header, replacer)
+		if interaction.Request.BodyEncoding == "" {
+			interaction.Request.Body = replacer.Replace(interaction.Request.Body)
+		}
+		if interaction.Response.BodyEncoding == "" {
+			interaction.Response.Body = replacer.Replace(interaction.Response.Body)
+		}
+	}
+}
+
+// collectPII calls fn for every string value of a PII property in the JSON value. The
+// properties of an object are visited in alphabetical order.
+func collectPII(v interface{}, isPII map[string]bool, fn func(property, value string)) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for key := range val {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			e := val[key]
+			if !isPII[strings.ToLower(key)] {
+				collectPII(e, isPII, fn)
+				continue
+			}
+			switch pii := e.(type) {
+			case string:
+				fn(key, pii)
+			case []interface{}:
+				for _, element := range pii {
+					if s, ok := element.(string); ok {
+						fn(key, s)
+					}
+				}
+			}
+		}
+	case []interface{}:
+		for _, e := range val {
+			collectPII(e, isPII, fn)
+		}
+	}
+}
+
+// replaceHeader returns a copy of the header with all values replaced by the replacer.
+func replaceHeader(header http.Header, replacer *strings.Replacer) http.Header {
+	replaced := header.Clone()
+	for key, values := range replaced {
+		for i := range values {
+			values[i] = replacer.Replace(values[i])
+		}
+		replaced[key] = values
+	}
+	return replaced
+}
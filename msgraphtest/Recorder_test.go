@@ -0,0 +1,131 @@
+package msgraphtest
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	msgraph "github.com/open-networks/go-msgraph"
+)
+
+func TestRecorder_Replayer(t *testing.T) {
+	srv := NewServer()
+	srv.AddUser(map[string]interface{}{"displayName": "Alice Smith", "userPrincipalName": "alice@contoso.com", "mail": "alice@contoso.com"})
+	srv.AddUser(map[string]interface{}{"displayName": "Bob Jones", "userPrincipalName": "bob@contoso.com", "mail": "bob@contoso.com"})
+
+	recorder := NewRecorder(nil)
+	recorder.PIIProperties = DefaultPIIProperties
+	graphClient, err := msgraph.NewGraphClientWithCustomEndpoint(srv.TenantID, srv.ApplicationID, srv.ClientSecret, srv.URL, srv.URL, msgraph.WithHTTPClient(&http.Client{Transport: recorder}))
+	if err != nil {
+		srv.Close()
+		t.Fatalf("Cannot initialize a new GraphClient for %v: %v", srv.URL, err)
+	}
+	recordedUsers, err := graphClient.ListUsers()
+	if err != nil {
+		srv.Close()
+		t.Fatalf("GraphClient.ListUsers() error = %v", err)
+	}
+	if _, err := graphClient.GetUser("alice@contoso.com"); err != nil {
+		srv.Close()
+		t.Fatalf("GraphClient.GetUser() error = %v", err)
+	}
+	srv.Close() // replay must work without the server
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := recorder.Save(path); err != nil {
+		t.Fatalf("Recorder.Save() error = %v", err)
+	}
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("LoadCassette() error = %v", err)
+	}
+	if len(cassette.Interactions) != 3 {
+		t.Fatalf("Cassette.Interactions = %v, want 3 (token, list users, get user)", len(cassette.Interactions))
+	}
+	for _, interaction := range cassette.Interactions {
+		for _, secret := range []string{srv.ClientSecret, "msgraphtest-access-token", "contoso.com", "Alice Smith"} {
+			recorded := interaction.Request.URL + interaction.Request.Body + interaction.Response.Body + strings.Join(interaction.Request.Header.Values("Authorization"), "")
+			if strings.Contains(recorded, secret) {
+				t.Errorf("Interaction %v %v contains %q, should be scrubbed", interaction.Request.Method, interaction.Request.URL, secret)
+			}
+		}
+	}
+	if !strings.Contains(cassette.Interactions[2].Request.URL, "/users/user") {
+		t.Errorf("Cassette.Interactions[2].Request.URL = %v, want userPrincipalName replaced by placeholder", cassette.Interactions[2].Request.URL)
+	}
+
+	replayer := NewReplayer(cassette)
+	graphClient, err = msgraph.NewGraphClientWithCustomEndpoint(srv.TenantID, srv.ApplicationID, srv.ClientSecret, srv.URL, srv.URL, msgraph.WithHTTPClient(&http.Client{Transport: replayer}))
+	if err != nil {
+		t.Fatalf("Cannot initialize a new GraphClient with a Replayer: %v", err)
+	}
+	replayedUsers, err := graphClient.ListUsers()
+	if err != nil {
+		t.Fatalf("GraphClient.ListUsers() error = %v", err)
+	}
+	if len(replayedUsers) != len(recordedUsers) {
+		t.Errorf("GraphClient.ListUsers() replayed %v users, want %v", len(replayedUsers), len(recordedUsers))
+	}
+	for _, user := range replayedUsers {
+		if !strings.HasSuffix(user.UserPrincipalName, "@example.com") {
+			t.Errorf("Replayed User.UserPrincipalName = %v, want placeholder", user.UserPrincipalName)
+		}
+	}
+	if _, err := graphClient.GetUser(replayedUsers[0].UserPrincipalName); err != nil {
+		t.Errorf("GraphClient.GetUser() error = %v", err)
+	}
+	if unused := replayer.Unused(); len(unused) != 0 {
+		t.Errorf("Replayer.Unused() = %v, want none", unused)
+	}
+	if _, err := graphClient.GetUser(replayedUsers[0].UserPrincipalName); err == nil {
+		t.Errorf("GraphClient.GetUser() replayed an interaction twice, want error")
+	}
+}
+
+func TestRecorder_Cassette_deterministic(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	for i := 0; i < 5; i++ {
+		srv.AddUser(map[string]interface{}{
+			"displayName":       fmt.Sprintf("User %d", i),
+			"givenName":         fmt.Sprintf("Given %d", i),
+			"surname":           fmt.Sprintf("Surname %d", i),
+			"userPrincipalName": fmt.Sprintf("user.%d@contoso.com", i),
+			"mail":              fmt.Sprintf("mail.%d@contoso.com", i),
+		})
+	}
+
+	record := func() Cassette {
+		recorder := NewRecorder(nil)
+		recorder.PIIProperties = DefaultPIIProperties
+		graphClient, err := msgraph.NewGraphClientWithCustomEndpoint(srv.TenantID, srv.ApplicationID, srv.ClientSecret, srv.URL, srv.URL, msgraph.WithHTTPClient(&http.Client{Transport: recorder}))
+		if err != nil {
+			t.Fatalf("Cannot initialize a new GraphClient for %v: %v", srv.URL, err)
+		}
+		if _, err := graphClient.ListUsers(); err != nil {
+			t.Fatalf("GraphClient.ListUsers() error = %v", err)
+		}
+		return recorder.Cassette()
+	}
+	want := record()
+	for i := 0; i < 10; i++ {
+		got := record()
+		if len(got.Interactions) != len(want.Interactions) {
+			t.Fatalf("Recorder.Cassette() recorded %v interactions, want %v", len(got.Interactions), len(want.Interactions))
+		}
+		for j := range got.Interactions {
+			if got.Interactions[j].Response.Body != want.Interactions[j].Response.Body {
+				t.Fatalf("Recorder.Cassette() Interactions[%v].Response.Body = %v, want %v", j, got.Interactions[j].Response.Body, want.Interactions[j].Response.Body)
+			}
+		}
+	}
+}
+
+func TestRecorder_clientState(t *testing.T) {
+	body := scrubBody([]byte(`{"changeType":"created","clientState":"secretClientState","resource":"users"}`), "application/json")
+	if strings.Contains(string(body), "secretClientState") {
+		t.Errorf("scrubBody() = %s, want clientState redacted", body)
+	}
+}
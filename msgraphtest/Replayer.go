@@ -0,0 +1,131 @@
+package msgraphtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Replayer is a http.RoundTripper that serves the interactions of a Cassette instead of
+// sending requests, hence tests recorded once run without network access or credentials:
+//
+//	cassette, err := msgraphtest.LoadCassette("testdata/listusers.json")
+//	// ... handle error
+//	graphClient, err := msgraph.NewGraphClient(tenantID, applicationID, clientSecret, msgraph.WithHTTPClient(&http.Client{Transport: msgraphtest.NewReplayer(cassette)}))
+//
+// A request is answered with the first unused interaction with the same method, path and
+// query parameters; the host is ignored. Token responses are re-stamped to be valid at the
+// time of the replay.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayer returns a new Replayer serving the interactions of the given Cassette.
+func NewReplayer(c Cassette) *Replayer {
+	return &Replayer{
+		interactions: c.Interactions,
+		used:         make([]bool, len(c.Interactions)),
+	}
+}
+
+// RoundTrip implements http.RoundTripper. Returns an error if no unused interaction matches the request.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, interaction := range r.interactions {
+		if r.used[i] || !matchRequest(interaction.Request, req) {
+			continue
+		}
+		body, err := decodeBody(interaction.Response.Body, interaction.Response.BodyEncoding)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode recorded response body of %v %v: %v", interaction.Request.Method, interaction.Request.URL, err)
+		}
+		if strings.HasSuffix(req.URL.Path, "/oauth2/token") {
+			body = restampToken(body)
+		}
+		r.used[i] = true
+		header := interaction.Response.Header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		header.Del("Content-Length")
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("msgraphtest: no recorded interaction matches %v %v", req.Method, req.URL)
+}
+
+// Unused returns all interactions that have not been replayed yet, e.g. to verify that a test
+// performed all recorded requests.
+func (r *Replayer) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var unused []Interaction
+	for i, interaction := range r.interactions {
+		if !r.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+// matchRequest returns true if the recorded request has the same method, path and query
+// parameters as the request.
+func matchRequest(recorded RecordedRequest, req *http.Request) bool {
+	if recorded.Method != req.Method {
+		return false
+	}
+	recordedURL, err := url.Parse(recorded.URL)
+	if err != nil || recordedURL.Path != req.URL.Path {
+		return false
+	}
+	recordedQuery := recordedURL.Query()
+	query := req.URL.Query()
+	if len(recordedQuery) == 0 && len(query) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(recordedQuery, query)
+}
+
+// restampToken sets expires_on and not_before of a recorded token response relative to the
+// current time, hence the token is not rejected as expired. Other bodies are returned as they are.
+func restampToken(body []byte) []byte {
+	var token map[string]interface{}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return body
+	}
+	if _, ok := token["expires_on"]; !ok {
+		return body
+	}
+	now := time.Now()
+	token["expires_on"] = strconv.FormatInt(now.Add(DefaultTokenLifetime).Unix(), 10)
+	token["not_before"] = strconv.FormatInt(now.Add(-5*time.Minute).Unix(), 10)
+	token["expires_in"] = strconv.Itoa(int(DefaultTokenLifetime.Seconds()))
+	restamped, err := json.Marshal(token)
+	if err != nil {
+		return body
+	}
+	return restamped
+}
@@ -0,0 +1,278 @@
+// Package msgraphtest provides an in-memory fake of the Microsoft Graph API for testing
+// code that uses github.com/open-networks/go-msgraph without a real Azure AD tenant.
+//
+// A Server serves both the Azure AD token endpoint and the Microsoft Graph service root,
+// hence its URL is used as azureADAuthEndpoint and serviceRootEndpoint of the GraphClient:
+//
+//	srv := msgraphtest.NewServer()
+//	defer srv.Close()
+//	srv.AddUser(map[string]interface{}{"displayName": "Alice", "userPrincipalName": "alice@contoso.com"})
+//	graphClient, err := msgraph.NewGraphClientWithCustomEndpoint(srv.TenantID, srv.ApplicationID, srv.ClientSecret, srv.URL, srv.URL)
+//
+// The Server supports the basic OData query parameters $filter, $select, $search, $orderby,
+// $top and $count, splits large results into pages linked via @odata.nextLink and can inject
+// faults like throttling (429), server errors (500) or slow responses, see Fault.
+package msgraphtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTenantID is the tenant ID accepted by a new Server.
+	DefaultTenantID = "msgraphtest-tenant"
+	// DefaultApplicationID is the application ID accepted by a new Server.
+	DefaultApplicationID = "msgraphtest-application"
+	// DefaultClientSecret is the client secret accepted by a new Server.
+	DefaultClientSecret = "msgraphtest-client-secret"
+	// DefaultPageSize is the maximum number of objects a new Server returns per page.
+	DefaultPageSize = 100
+	// DefaultTokenLifetime is the lifetime of access tokens issued by a new Server.
+	DefaultTokenLifetime = time.Hour
+	// DefaultDomain is the verified domain of the tenant, e.g. of the mail addresses of groups
+	// and the userPrincipalName of invited guests.
+	DefaultDomain = "msgraphtest.onmicrosoft.com"
+
+	// apiVersion is the only Microsoft Graph API version served by the Server.
+	apiVersion = "v1.0"
+)
+
+// Server is a fake Microsoft Graph API backed by an in-memory store of Objects. Create it
+// with NewServer and seed it with Add, AddUser, AddGroup etc. All methods are safe for
+// concurrent use.
+type Server struct {
+	URL string // base URL of the server, e.g. http://127.0.0.1:51234 - use it as azureADAuthEndpoint and serviceRootEndpoint
+
+	TenantID      string // the tenant ID accepted by the token endpoint
+	ApplicationID string // the application ID accepted by the token endpoint
+	ClientSecret  string // the client secret accepted by the token endpoint
+
+	PageSize      int           // maximum number of objects per page, further objects are linked via @odata.nextLink
+	TokenLifetime time.Duration // lifetime of issued access tokens
+
+	server *httptest.Server
+
+	mu               sync.Mutex
+	collections      map[string][]Object         // all objects keyed by their collection path, e.g. /users or /users/{id}/calendars
+	members          map[string][]string         // member IDs keyed by group ID
+	owners           map[string][]string         // owner IDs keyed by group ID
+	managers         map[string]string           // manager ID keyed by user ID
+	mailboxSettings  map[string]Object           // mailbox settings keyed by user ID
+	photos           map[string]photo            // profile photos keyed by user or group ID
+	deletedRelations map[string]deletedRelations // relations of deleted users and groups keyed by their ID
+	tokens           map[string]bool             // access tokens issued by the token endpoint
+	faults           []*Fault                    // faults injected via InjectFault
+	routes           []route                     // routes registered via HandleFunc, checked before the built-in routes
+	requests         []Request                   // log of all received requests
+	idCounter        int                         // counter used to generate IDs
+}
+
+// Request is a request received by the Server, see Server.Requests.
+type Request struct {
+	Method string
+	Path   string // the resource path without API version, e.g. /users
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// NewServer starts and returns a new Server with an empty store. The caller should call
+// Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		TenantID:         DefaultTenantID,
+		ApplicationID:    DefaultApplicationID,
+		ClientSecret:     DefaultClientSecret,
+		PageSize:         DefaultPageSize,
+		TokenLifetime:    DefaultTokenLifetime,
+		collections:      make(map[string][]Object),
+		members:          make(map[string][]string),
+		owners:           make(map[string][]string),
+		managers:         make(map[string]string),
+		mailboxSettings:  make(map[string]Object),
+		photos:           make(map[string]photo),
+		deletedRelations: make(map[string]deletedRelations),
+		tokens:           make(map[string]bool),
+	}
+	s.server = httptest.NewServer(s)
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts down the server and blocks until all outstanding requests on this server have completed.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Requests returns a copy of all requests received by the Server so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// ResetRequests clears the log returned by Requests.
+func (s *Server) ResetRequests() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+// HandleFunc registers a custom handler for the given HTTP method and resource path pattern,
+// e.g. "/users/{id}/manager". Segments in curly braces match any value and can be read with
+// PathParam. Custom handlers take precedence over the built-in behaviour of the Server, hence
+// they can be used to fake endpoints that are not supported out of the box.
+func (s *Server) HandleFunc(method, pattern string, handler http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes = append(s.routes, route{method: method, pattern: splitPath(pattern), handler: handler})
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "BadRequest", fmt.Sprintf("cannot read request body: %v", err))
+		return
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	path := r.URL.Path
+	isAPICall := strings.HasPrefix(path, "/"+apiVersion+"/")
+	if isAPICall {
+		path = strings.TrimPrefix(path, "/"+apiVersion)
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: path, Query: r.URL.Query(), Header: r.Header.Clone(), Body: body})
+	fault := s.matchFault(r.Method, path)
+	s.mu.Unlock()
+
+	if fault != nil && fault.apply(w, r) {
+		return
+	}
+
+	if !isAPICall {
+		if segments := splitPath(path); r.Method == http.MethodPost && len(segments) == 3 && segments[1] == "oauth2" && segments[2] == "token" {
+			s.serveToken(w, r, segments[0])
+			return
+		}
+		WriteError(w, http.StatusNotFound, "BadRequest", fmt.Sprintf("Invalid version or unknown path: %v", r.URL.Path))
+		return
+	}
+
+	if !s.isAuthorized(r) {
+		WriteError(w, http.StatusUnauthorized, "InvalidAuthenticationToken", "Access token validation failure. Invalid audience.")
+		return
+	}
+
+	if handler, params := s.findRoute(r.Method, path); handler != nil {
+		handler(w, r.WithContext(context.WithValue(r.Context(), pathParamsKey{}, params)))
+		return
+	}
+	s.serveStore(w, r, path, body)
+}
+
+// serveToken implements the Azure AD v1 client credentials flow used by msgraph.GraphClient.
+func (s *Server) serveToken(w http.ResponseWriter, r *http.Request, tenantID string) {
+	if err := r.ParseForm(); err != nil {
+		writeTokenError(w, http.StatusBadRequest, "invalid_request", fmt.Sprintf("cannot parse form: %v", err))
+		return
+	}
+	switch {
+	case tenantID != s.TenantID:
+		writeTokenError(w, http.StatusBadRequest, "invalid_request", fmt.Sprintf("AADSTS90002: Tenant '%v' not found.", tenantID))
+		return
+	case r.PostForm.Get("grant_type") != "client_credentials":
+		writeTokenError(w, http.StatusBadRequest, "unsupported_grant_type", "AADSTS70003: The app requested an unsupported grant type.")
+		return
+	case r.PostForm.Get("client_id") != s.ApplicationID:
+		writeTokenError(w, http.StatusBadRequest, "unauthorized_client", fmt.Sprintf("AADSTS700016: Application with identifier '%v' was not found.", r.PostForm.Get("client_id")))
+		return
+	case r.PostForm.Get("client_secret") != s.ClientSecret:
+		writeTokenError(w, http.StatusUnauthorized, "invalid_client", "AADSTS7000215: Invalid client secret provided.")
+		return
+	case strings.TrimSuffix(r.PostForm.Get("resource"), "/") != s.URL:
+		writeTokenError(w, http.StatusBadRequest, "invalid_resource", fmt.Sprintf("AADSTS500011: The resource principal named %v was not found.", r.PostForm.Get("resource")))
+		return
+	}
+
+	s.mu.Lock()
+	s.idCounter++
+	accessToken := fmt.Sprintf("msgraphtest-access-token-%d", s.idCounter)
+	s.tokens[accessToken] = true
+	s.mu.Unlock()
+
+	now := time.Now()
+	WriteJSON(w, http.StatusOK, map[string]string{
+		"token_type":     "Bearer",
+		"expires_in":     strconv.Itoa(int(s.TokenLifetime.Seconds())),
+		"ext_expires_in": strconv.Itoa(int(s.TokenLifetime.Seconds())),
+		"expires_on":     strconv.FormatInt(now.Add(s.TokenLifetime).Unix(), 10),
+		"not_before":     strconv.FormatInt(now.Add(-5*time.Minute).Unix(), 10),
+		"resource":       r.PostForm.Get("resource"),
+		"access_token":   accessToken,
+	})
+}
+
+// isAuthorized returns true if the request carries an access token issued by the Server.
+func (s *Server) isAuthorized(r *http.Request) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokens[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]
+}
+
+// WriteJSON writes v as JSON response with the given status code. It can be used by handlers
+// registered with Server.HandleFunc.
+func WriteJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(v)
+}
+
+// WriteError writes an error response in the format used by Microsoft Graph. It can be used
+// by handlers registered with Server.HandleFunc.
+func WriteError(w http.ResponseWriter, statusCode int, code, message string) {
+	WriteJSON(w, statusCode, map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": message,
+			"innerError": map[string]string{
+				"date":       time.Now().UTC().Format(time.RFC3339),
+				"request-id": "msgraphtest",
+			},
+		},
+	})
+}
+
+// writeTokenError writes an error response in the format used by the Azure AD token endpoint.
+func writeTokenError(w http.ResponseWriter, statusCode int, code, description string) {
+	WriteJSON(w, statusCode, map[string]interface{}{
+		"error":             code,
+		"error_description": description,
+		"error_codes":       []int{},
+		"timestamp":         time.Now().UTC().Format("2006-01-02 15:04:05Z"),
+	})
+}
+
+// splitPath splits the given path into its non-empty segments.
+func splitPath(path string) []string {
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
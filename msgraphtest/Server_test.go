@@ -0,0 +1,359 @@
+package msgraphtest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	msgraph "github.com/open-networks/go-msgraph"
+)
+
+// newTestGraphClient starts a new Server and returns it together with a GraphClient connected to it.
+func newTestGraphClient(t *testing.T) (*Server, *msgraph.GraphClient) {
+	t.Helper()
+	srv := NewServer()
+	t.Cleanup(srv.Close)
+	graphClient, err := msgraph.NewGraphClientWithCustomEndpoint(srv.TenantID, srv.ApplicationID, srv.ClientSecret, srv.URL, srv.URL)
+	if err != nil {
+		t.Fatalf("Cannot initialize a new GraphClient for %v: %v", srv.URL, err)
+	}
+	return srv, graphClient
+}
+
+func TestServer_Token(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	tests := []struct {
+		name          string
+		tenantID      string
+		applicationID string
+		clientSecret  string
+		serviceRoot   string
+		wantErr       bool
+	}{
+		{
+			name:          "All correct",
+			tenantID:      srv.TenantID,
+			applicationID: srv.ApplicationID,
+			clientSecret:  srv.ClientSecret,
+			serviceRoot:   srv.URL,
+			wantErr:       false,
+		}, {
+			name:          "Wrong tenant ID",
+			tenantID:      "wrong tenant id",
+			applicationID: srv.ApplicationID,
+			clientSecret:  srv.ClientSecret,
+			serviceRoot:   srv.URL,
+			wantErr:       true,
+		}, {
+			name:          "Wrong application ID",
+			tenantID:      srv.TenantID,
+			applicationID: "wrong application id",
+			clientSecret:  srv.ClientSecret,
+			serviceRoot:   srv.URL,
+			wantErr:       true,
+		}, {
+			name:          "Wrong client secret",
+			tenantID:      srv.TenantID,
+			applicationID: srv.ApplicationID,
+			clientSecret:  "wrong client secret",
+			serviceRoot:   srv.URL,
+			wantErr:       true,
+		}, {
+			name:          "Wrong service root endpoint",
+			tenantID:      srv.TenantID,
+			applicationID: srv.ApplicationID,
+			clientSecret:  srv.ClientSecret,
+			serviceRoot:   msgraph.ServiceRootEndpointGlobal,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := msgraph.NewGraphClientWithCustomEndpoint(tt.tenantID, tt.applicationID, tt.clientSecret, srv.URL, tt.serviceRoot)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewGraphClientWithCustomEndpoint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServer_Unauthorized(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/v1.0/users")
+	if err != nil {
+		t.Fatalf("http.Get() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("StatusCode = %v, want %v", resp.StatusCode, http.StatusUnauthorized)
+	}
+}
+
+func TestServer_Paging(t *testing.T) {
+	srv, graphClient := newTestGraphClient(t)
+	srv.PageSize = 3
+	for i := 0; i < 10; i++ {
+		srv.AddUser(msgraph.User{DisplayName: fmt.Sprintf("User %d", i), UserPrincipalName: fmt.Sprintf("user%d@contoso.com", i)})
+	}
+
+	users, err := graphClient.ListUsers()
+	if err != nil {
+		t.Fatalf("GraphClient.ListUsers() error = %v", err)
+	}
+	if len(users) != 10 {
+		t.Errorf("GraphClient.ListUsers() len = %d, want 10", len(users))
+	}
+	var pages int
+	for _, req := range srv.Requests() {
+		if req.Path == "/users" {
+			pages++
+		}
+	}
+	if pages != 4 {
+		t.Errorf("Server received %d requests for /users, want 4 pages", pages)
+	}
+}
+
+func TestServer_FilterAndSelect(t *testing.T) {
+	srv, graphClient := newTestGraphClient(t)
+	srv.AddUser(msgraph.User{DisplayName: "Alice Smith", UserPrincipalName: "alice@contoso.com", Department: "Sales"})
+	srv.AddUser(msgraph.User{DisplayName: "Bob Rabbit", UserPrincipalName: "bob@contoso.com", Department: "Sales"})
+	srv.AddUser(msgraph.User{DisplayName: "Carol Jones", UserPrincipalName: "carol@contoso.com", Department: "IT"})
+
+	tests := []struct {
+		name      string
+		opts      []msgraph.ListQueryOption
+		wantUPNs  []string
+		wantEmpty bool // true if DisplayName is expected to be empty due to $select
+		wantErr   bool
+	}{
+		{
+			name:     "No options",
+			wantUPNs: []string{"alice@contoso.com", "bob@contoso.com", "carol@contoso.com"},
+		}, {
+			name:     "Filter eq",
+			opts:     []msgraph.ListQueryOption{msgraph.ListWithFilter("department eq 'sales'")},
+			wantUPNs: []string{"alice@contoso.com", "bob@contoso.com"},
+		}, {
+			name:     "Filter startswith and ne",
+			opts:     []msgraph.ListQueryOption{msgraph.ListWithFilter("startswith(displayName,'b') or department ne 'Sales'")},
+			wantUPNs: []string{"bob@contoso.com", "carol@contoso.com"},
+		}, {
+			name:      "Filter and select",
+			opts:      []msgraph.ListQueryOption{msgraph.ListWithFilter("department eq 'IT'"), msgraph.ListWithSelect("userPrincipalName")},
+			wantUPNs:  []string{"carol@contoso.com"},
+			wantEmpty: true,
+		}, {
+			name:     "Search",
+			opts:     []msgraph.ListQueryOption{msgraph.ListWithSearch(`"displayName:rabbit"`)},
+			wantUPNs: []string{"bob@contoso.com"},
+		}, {
+			name:    "Invalid filter",
+			opts:    []msgraph.ListQueryOption{msgraph.ListWithFilter("department equals 'IT'")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := graphClient.ListUsers(tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GraphClient.ListUsers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.wantUPNs) {
+				t.Fatalf("GraphClient.ListUsers() = %v, want %v", got, tt.wantUPNs)
+			}
+			for i, user := range got {
+				if user.UserPrincipalName != tt.wantUPNs[i] {
+					t.Errorf("GraphClient.ListUsers()[%d].UserPrincipalName = %v, want %v", i, user.UserPrincipalName, tt.wantUPNs[i])
+				}
+				if (user.DisplayName == "") != tt.wantEmpty {
+					t.Errorf("GraphClient.ListUsers()[%d].DisplayName = %q, want empty: %v", i, user.DisplayName, tt.wantEmpty)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_CreateUpdateDeleteUser(t *testing.T) {
+	srv, graphClient := newTestGraphClient(t)
+
+	created, err := graphClient.CreateUser(msgraph.User{
+		AccountEnabled:    true,
+		DisplayName:       "Alice Smith",
+		MailNickname:      "alice",
+		UserPrincipalName: "alice@contoso.com",
+		PasswordProfile:   msgraph.PasswordProfile{Password: "secret"},
+	})
+	if err != nil {
+		t.Fatalf("GraphClient.CreateUser() error = %v", err)
+	}
+	if created.ID == "" {
+		t.Errorf("GraphClient.CreateUser() returned user without ID: %v", created)
+	}
+	if _, err := graphClient.CreateUser(msgraph.User{DisplayName: "Missing required properties"}); err == nil {
+		t.Errorf("GraphClient.CreateUser() error = nil, want an error for missing required properties")
+	}
+
+	if err := created.UpdateUser(msgraph.User{JobTitle: "Technician"}); err != nil {
+		t.Fatalf("User.UpdateUser() error = %v", err)
+	}
+	if stored, _ := srv.Get("/users", created.ID); stored["jobTitle"] != "Technician" {
+		t.Errorf("User.UpdateUser() stored jobTitle = %v, want Technician", stored["jobTitle"])
+	}
+
+	if err := created.DeleteUser(); err != nil {
+		t.Fatalf("User.DeleteUser() error = %v", err)
+	}
+	if _, err := graphClient.GetUser(created.ID); err == nil {
+		t.Errorf("GraphClient.GetUser() error = nil after User.DeleteUser(), want not found")
+	}
+}
+
+func TestServer_GroupMembers(t *testing.T) {
+	srv, graphClient := newTestGraphClient(t)
+	alice := srv.AddUser(msgraph.User{DisplayName: "Alice", UserPrincipalName: "alice@contoso.com"})
+	inner := srv.AddGroup(map[string]interface{}{"displayName": "inner", "securityEnabled": true})
+	outer := srv.AddGroup(map[string]interface{}{"displayName": "outer", "securityEnabled": false})
+	srv.AddGroupMember(inner.ID(), alice.ID())
+	srv.AddGroupMember(outer.ID(), inner.ID())
+
+	group, err := graphClient.GetGroup(outer.ID())
+	if err != nil {
+		t.Fatalf("GraphClient.GetGroup() error = %v", err)
+	}
+	members, err := group.ListTransitiveMemberObjects()
+	if err != nil {
+		t.Fatalf("Group.ListTransitiveMemberObjects() error = %v", err)
+	}
+	if len(members) != 2 || len(members.Users()) != 1 || len(members.Groups()) != 1 {
+		t.Errorf("Group.ListTransitiveMemberObjects() = %v, want the user and the inner group", members)
+	}
+	if users, err := group.ListTransitiveMembers(); err != nil || len(users) != 1 {
+		t.Errorf("Group.ListTransitiveMembers() = %v, error = %v, want only the user", users, err)
+	}
+
+	// like Microsoft Graph, only type casts of transitive lists require advanced query capabilities
+	requests := srv.Requests()
+	authorization := requests[len(requests)-1].Header.Get("Authorization")
+	for resource, want := range map[string]int{
+		"/members/microsoft.graph.group":           http.StatusOK,
+		"/transitiveMembers/microsoft.graph.user":  http.StatusBadRequest,
+		"/transitiveMembers/microsoft.graph.group": http.StatusBadRequest,
+	} {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/v1.0/groups/"+outer.ID()+resource, nil)
+		req.Header.Set("Authorization", authorization)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("http.Get() error = %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("GET %v without advanced query StatusCode = %v, want %v", resource, resp.StatusCode, want)
+		}
+	}
+
+	user, err := graphClient.GetUser("alice@contoso.com")
+	if err != nil {
+		t.Fatalf("GraphClient.GetUser() error = %v", err)
+	}
+	tests := []struct {
+		securityEnabledOnly bool
+		want                int
+	}{
+		{securityEnabledOnly: false, want: 2},
+		{securityEnabledOnly: true, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("securityEnabledOnly=%v", tt.securityEnabledOnly), func(t *testing.T) {
+			got, err := user.GetMemberGroupsAsStrings(tt.securityEnabledOnly)
+			if err != nil {
+				t.Fatalf("User.GetMemberGroupsAsStrings() error = %v", err)
+			}
+			if len(got) != tt.want {
+				t.Errorf("User.GetMemberGroupsAsStrings() = %v, want %d groups", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_InjectFault(t *testing.T) {
+	srv, graphClient := newTestGraphClient(t)
+	srv.AddUser(msgraph.User{DisplayName: "Alice", UserPrincipalName: "alice@contoso.com"})
+
+	tests := []struct {
+		name     string
+		fault    Fault
+		opts     []msgraph.ListQueryOption
+		wantErr  string
+		wantNext bool // true if the next request is expected to succeed
+	}{
+		{
+			name:     "Throttling once",
+			fault:    Fault{Path: "/users", StatusCode: http.StatusTooManyRequests, RetryAfter: time.Second, Times: 1},
+			wantErr:  "429",
+			wantNext: true,
+		}, {
+			name:     "Server error for other path",
+			fault:    InternalServerError("/groups"),
+			wantNext: true,
+		}, {
+			name:     "Server error",
+			fault:    InternalServerError("/users"),
+			wantErr:  "500",
+			wantNext: false,
+		}, {
+			name:     "Slow response",
+			fault:    SlowResponse("/users", time.Second),
+			opts:     []msgraph.ListQueryOption{msgraph.ListWithContext(ctxWithTimeout(t, 50*time.Millisecond))},
+			wantErr:  "context deadline exceeded",
+			wantNext: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv.ClearFaults()
+			srv.InjectFault(tt.fault)
+			_, err := graphClient.ListUsers(tt.opts...)
+			if (err != nil) != (tt.wantErr != "") || (err != nil && !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("GraphClient.ListUsers() error = %v, want error containing %q", err, tt.wantErr)
+			}
+			_, err = graphClient.ListUsers(tt.opts...)
+			if (err == nil) != tt.wantNext {
+				t.Errorf("GraphClient.ListUsers() second call error = %v, want success: %v", err, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestServer_HandleFunc(t *testing.T) {
+	srv, graphClient := newTestGraphClient(t)
+	srv.HandleFunc(http.MethodGet, "/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		WriteJSON(w, http.StatusOK, map[string]string{"id": PathParam(r, "id"), "displayName": "custom"})
+	})
+
+	user, err := graphClient.GetUser("any-id")
+	if err != nil {
+		t.Fatalf("GraphClient.GetUser() error = %v", err)
+	}
+	if user.ID != "any-id" || user.DisplayName != "custom" {
+		t.Errorf("GraphClient.GetUser() = %v, want user served by custom handler", user)
+	}
+}
+
+// ctxWithTimeout returns a context that is cancelled after the timeout or at the end of the test.
+func ctxWithTimeout(t *testing.T, timeout time.Duration) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	t.Cleanup(cancel)
+	return ctx
+}
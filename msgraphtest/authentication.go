@@ -0,0 +1,227 @@
+package msgraphtest
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// passwordMethodID is the well-known ID of the password authentication method of every user.
+const passwordMethodID = "28c10230-6103-485e-b985-444c60001490"
+
+// phoneMethodIDs are the well-known IDs of the phone authentication methods per phoneType.
+var phoneMethodIDs = map[string]string{
+	"mobile":          "3179e48a-750b-4051-897c-87b9720928f7",
+	"alternateMobile": "b6332ec1-7057-4abe-9331-3d72feddfe41",
+	"office":          "e37fc753-ff3b-4958-9484-eaa9425c82bc",
+}
+
+// authenticationMethodTypes maps the collections below /users/{id}/authentication to the
+// @odata.type of their authentication methods.
+var authenticationMethodTypes = map[string]string{
+	"phoneMethods":                   "#microsoft.graph.phoneAuthenticationMethod",
+	"microsoftAuthenticatorMethods":  "#microsoft.graph.microsoftAuthenticatorAuthenticationMethod",
+	"fido2Methods":                   "#microsoft.graph.fido2AuthenticationMethod",
+	"emailMethods":                   "#microsoft.graph.emailAuthenticationMethod",
+	"softwareOathMethods":            "#microsoft.graph.softwareOathAuthenticationMethod",
+	"temporaryAccessPassMethods":     "#microsoft.graph.temporaryAccessPassAuthenticationMethod",
+	"windowsHelloForBusinessMethods": "#microsoft.graph.windowsHelloForBusinessAuthenticationMethod",
+}
+
+// phoneNumberPattern is the format Microsoft Graph requires for phone numbers, e.g. +1 2065555555x123.
+var phoneNumberPattern = regexp.MustCompile(`^\+\d{1,3} \d{4,}(x\d+)?$`)
+
+// AddAuthenticationMethod adds the given authentication method, e.g. a FIDO2 key, to the
+// user with the given ID or userPrincipalName. methodType is the collection of the method,
+// e.g. fido2Methods, its @odata.type is set accordingly.
+func (s *Server) AddAuthenticationMethod(userID, methodType string, v interface{}) Object {
+	obj, err := toObject(v)
+	if err != nil {
+		panic(fmt.Sprintf("msgraphtest: cannot add authentication method %T: %v", v, err))
+	}
+	obj["@odata.type"] = authenticationMethodTypes[methodType]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if user, ok := s.find("/users", userID); ok {
+		userID = user.ID()
+	}
+	return s.insert(authenticationMethodsCollection(userID), obj).copy()
+}
+
+// authenticationMethodsCollection returns the collection of all authentication methods of the user.
+func authenticationMethodsCollection(userID string) string {
+	return "/users/" + userID + "/authentication/methods"
+}
+
+// serveAuthenticationMethods serves all authentication methods of a user including the password.
+func (s *Server) serveAuthenticationMethods(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	user, ok := s.find("/users", PathParam(r, "id"))
+	var methods []Object
+	if ok {
+		createdDateTime, _ := user["createdDateTime"].(string)
+		methods = append(methods, Object{"@odata.type": "#microsoft.graph.passwordAuthenticationMethod", "id": passwordMethodID, "createdDateTime": createdDateTime})
+		methods = append(methods, s.collections[authenticationMethodsCollection(user.ID())]...)
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	s.writeCollection(w, r, methods)
+}
+
+// serveAddPhoneMethod registers a phone method, one per phoneType, like Microsoft Graph does.
+func (s *Server) serveAddPhoneMethod(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	method, err := decodeObject(body)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "badRequest", err.Error())
+		return
+	}
+	phoneNumber, _ := method["phoneNumber"].(string)
+	phoneType, _ := method["phoneType"].(string)
+	if !phoneNumberPattern.MatchString(phoneNumber) {
+		WriteError(w, http.StatusBadRequest, "badRequest", fmt.Sprintf("Invalid phone number '%v', the format must be +{country code} {number}x{extension}.", phoneNumber))
+		return
+	}
+	if phoneMethodIDs[phoneType] == "" {
+		WriteError(w, http.StatusBadRequest, "badRequest", fmt.Sprintf("Invalid phoneType '%v'.", phoneType))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.find("/users", PathParam(r, "id"))
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	collection := authenticationMethodsCollection(user.ID())
+	if s.indexOf(collection, phoneMethodIDs[phoneType]) >= 0 {
+		WriteError(w, http.StatusBadRequest, "badRequest", fmt.Sprintf("A phone method of type '%v' already exists.", phoneType))
+		return
+	}
+	obj := s.insert(collection, Object{
+		"@odata.type":    authenticationMethodTypes["phoneMethods"],
+		"id":             phoneMethodIDs[phoneType],
+		"phoneNumber":    phoneNumber,
+		"phoneType":      phoneType,
+		"smsSignInState": "notEnabled",
+	})
+	WriteJSON(w, http.StatusCreated, obj)
+}
+
+// serveCreateTemporaryAccessPass creates a Temporary Access Pass, one per user.
+func (s *Server) serveCreateTemporaryAccessPass(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	method, err := decodeObject(body)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "badRequest", err.Error())
+		return
+	}
+	now := time.Now().UTC()
+	start := now
+	if value, ok := method["startDateTime"].(string); ok {
+		if start, err = time.Parse(time.RFC3339Nano, value); err != nil {
+			WriteError(w, http.StatusBadRequest, "badRequest", fmt.Sprintf("Invalid startDateTime '%v'.", value))
+			return
+		}
+	}
+	lifetime := 60
+	if value, ok := method["lifetimeInMinutes"]; ok {
+		lifetime = unitsOf(value)
+	}
+	if lifetime < 10 || lifetime > 43200 {
+		WriteError(w, http.StatusBadRequest, "badRequest", "lifetimeInMinutes must be between 10 and 43200.")
+		return
+	}
+	isUsableOnce, _ := method["isUsableOnce"].(bool)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.find("/users", PathParam(r, "id"))
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	collection := authenticationMethodsCollection(user.ID())
+	for _, existing := range s.collections[collection] {
+		if existing["@odata.type"] == authenticationMethodTypes["temporaryAccessPassMethods"] {
+			WriteError(w, http.StatusBadRequest, "badRequest", "The user already has a Temporary Access Pass.")
+			return
+		}
+	}
+	pass := make([]byte, 6)
+	rand.Read(pass)
+	reason, isUsable := "EnabledByPolicy", true
+	if start.After(now) {
+		reason, isUsable = "NotYetValid", false
+	}
+	obj := s.insert(collection, Object{
+		"@odata.type":           authenticationMethodTypes["temporaryAccessPassMethods"],
+		"createdDateTime":       now.Format(time.RFC3339),
+		"startDateTime":         start.Format(time.RFC3339),
+		"lifetimeInMinutes":     lifetime,
+		"isUsableOnce":          isUsableOnce,
+		"isUsable":              isUsable,
+		"methodUsabilityReason": reason,
+	})
+	created := obj.copy()
+	created["temporaryAccessPass"] = base64.RawURLEncoding.EncodeToString(pass) // never returned again
+	WriteJSON(w, http.StatusCreated, created)
+}
+
+// serveRemoveAuthenticationMethod removes an authentication method of the given type.
+func (s *Server) serveRemoveAuthenticationMethod(w http.ResponseWriter, r *http.Request) {
+	odataType, ok := authenticationMethodTypes[PathParam(r, "type")]
+	if !ok {
+		WriteError(w, http.StatusBadRequest, "BadRequest", fmt.Sprintf("Resource not found for the segment '%v'.", PathParam(r, "type")))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.find("/users", PathParam(r, "id"))
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	collection := authenticationMethodsCollection(user.ID())
+	idx := s.indexOf(collection, PathParam(r, "methodId"))
+	if idx < 0 || s.collections[collection][idx]["@odata.type"] != odataType {
+		writeNotFound(w, PathParam(r, "methodId"))
+		return
+	}
+	s.collections[collection] = append(s.collections[collection][:idx:idx], s.collections[collection][idx+1:]...)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serveResetPassword accepts a password reset as long-running operation, like Microsoft Graph does.
+func (s *Server) serveResetPassword(w http.ResponseWriter, r *http.Request) {
+	if !strings.EqualFold(PathParam(r, "methodId"), passwordMethodID) {
+		writeNotFound(w, PathParam(r, "methodId"))
+		return
+	}
+
+	s.mu.Lock()
+	user, ok := s.find("/users", PathParam(r, "id"))
+	if ok {
+		user["lastPasswordChangeDateTime"] = time.Now().UTC().Format(time.RFC3339)
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("%v/%v/users/%v/authentication/operations/%v", s.URL, apiVersion, user.ID(), passwordMethodID))
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,278 @@
+package msgraphtest
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// maxPreviewLength is the maximum length of the preview of a conversation or thread.
+const maxPreviewLength = 255
+
+// conversationsCollection returns the collection of all conversations of the group.
+func conversationsCollection(groupID string) string {
+	return "/groups/" + groupID + "/conversations"
+}
+
+// threadsCollection returns the collection of all threads of the group, regardless of their conversation.
+func threadsCollection(groupID string) string {
+	return "/groups/" + groupID + "/threads"
+}
+
+// postsCollection returns the collection of all posts of the thread.
+func postsCollection(groupID, threadID string) string {
+	return threadsCollection(groupID) + "/" + threadID + "/posts"
+}
+
+// findUnifiedGroup returns the group of the request. Only Microsoft 365 groups have
+// conversations, the error response is written otherwise. s.mu must be held.
+func (s *Server) findUnifiedGroup(w http.ResponseWriter, r *http.Request) (Object, bool) {
+	group, ok := s.find("/groups", PathParam(r, "id"))
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return nil, false
+	}
+	if groupTypes, _ := group["groupTypes"].([]interface{}); !containsValue(groupTypes, "Unified") {
+		WriteError(w, http.StatusBadRequest, "ErrorInvalidGroup", "Conversations are only supported by Microsoft 365 groups.")
+		return nil, false
+	}
+	return group, true
+}
+
+// serveConversations serves all conversations of a Microsoft 365 group.
+func (s *Server) serveConversations(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	group, ok := s.findUnifiedGroup(w, r)
+	var conversations []Object
+	if ok {
+		conversations = s.collections[conversationsCollection(group.ID())]
+	}
+	s.mu.Unlock()
+
+	if ok {
+		s.writeCollection(w, r, conversations)
+	}
+}
+
+// serveThreads serves all threads of a Microsoft 365 group.
+func (s *Server) serveThreads(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	group, ok := s.findUnifiedGroup(w, r)
+	var threads []Object
+	if ok {
+		threads = s.collections[threadsCollection(group.ID())]
+	}
+	s.mu.Unlock()
+
+	if ok {
+		s.writeCollection(w, r, threads)
+	}
+}
+
+// serveConversationThreads serves the threads of a conversation, hence the threads whose
+// posts belong to the conversation.
+func (s *Server) serveConversationThreads(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	group, ok := s.findUnifiedGroup(w, r)
+	if !ok {
+		return
+	}
+	conversation, ok := s.find(conversationsCollection(group.ID()), PathParam(r, "conversationId"))
+	if !ok {
+		writeNotFound(w, PathParam(r, "conversationId"))
+		return
+	}
+	var threads []Object
+	for _, thread := range s.collections[threadsCollection(group.ID())] {
+		if s.conversationIDOfThread(group.ID(), thread.ID()) == conversation.ID() {
+			threads = append(threads, thread)
+		}
+	}
+	s.writeCollection(w, r, threads)
+}
+
+// conversationIDOfThread returns the ID of the conversation the thread belongs to. s.mu must be held.
+func (s *Server) conversationIDOfThread(groupID, threadID string) string {
+	for _, post := range s.collections[postsCollection(groupID, threadID)] {
+		conversationID, _ := post["conversationId"].(string)
+		return conversationID
+	}
+	return ""
+}
+
+// serveCreateConversation creates a conversation with its threads and their posts.
+func (s *Server) serveCreateConversation(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	conversation, err := decodeObject(body)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "ErrorInvalidRequest", err.Error())
+		return
+	}
+	topic, _ := conversation["topic"].(string)
+	threads, _ := conversation["threads"].([]interface{})
+	delete(conversation, "threads")
+	if topic == "" || len(threads) == 0 {
+		WriteError(w, http.StatusBadRequest, "ErrorInvalidRequest", "A conversation requires a topic and at least one thread with a post.")
+		return
+	}
+	var posts [][]Object
+	for _, t := range threads {
+		thread, _ := t.(map[string]interface{})
+		threadPosts, _ := thread["posts"].([]interface{})
+		if len(threadPosts) == 0 {
+			WriteError(w, http.StatusBadRequest, "ErrorInvalidRequest", "A conversation requires a topic and at least one thread with a post.")
+			return
+		}
+		posts = append(posts, nil)
+		for _, p := range threadPosts {
+			post, _ := p.(map[string]interface{})
+			if msg := validatePost(post); msg != "" {
+				WriteError(w, http.StatusBadRequest, "ErrorInvalidRequest", msg)
+				return
+			}
+			posts[len(posts)-1] = append(posts[len(posts)-1], post)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	group, ok := s.findUnifiedGroup(w, r)
+	if !ok {
+		return
+	}
+	created := s.insert(conversationsCollection(group.ID()), Object{"topic": topic, "hasAttachments": false, "uniqueSenders": []interface{}{}})
+	for _, threadPosts := range posts {
+		thread := s.insert(threadsCollection(group.ID()), Object{"topic": topic, "hasAttachments": false, "isLocked": false, "uniqueSenders": []interface{}{}})
+		for _, post := range threadPosts {
+			s.insertPost(group, created, thread, post)
+		}
+	}
+	WriteJSON(w, http.StatusCreated, created.copy())
+}
+
+// serveReplyThread adds the post of the request to a thread.
+func (s *Server) serveReplyThread(w http.ResponseWriter, r *http.Request) {
+	s.serveReply(w, r, false)
+}
+
+// serveReplyPost adds the post of the request to the thread of the post it replies to.
+func (s *Server) serveReplyPost(w http.ResponseWriter, r *http.Request) {
+	s.serveReply(w, r, true)
+}
+
+// serveReply adds the post of the request to a thread, Microsoft Graph accepts the reply
+// without returning the created post.
+func (s *Server) serveReply(w http.ResponseWriter, r *http.Request, toPost bool) {
+	body, _ := ioutil.ReadAll(r.Body)
+	reply, err := decodeObject(body)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "ErrorInvalidRequest", err.Error())
+		return
+	}
+	post, _ := reply["post"].(map[string]interface{})
+	if msg := validatePost(post); msg != "" {
+		WriteError(w, http.StatusBadRequest, "ErrorInvalidRequest", msg)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	group, ok := s.findUnifiedGroup(w, r)
+	if !ok {
+		return
+	}
+	thread, ok := s.find(threadsCollection(group.ID()), PathParam(r, "threadId"))
+	if !ok {
+		writeNotFound(w, PathParam(r, "threadId"))
+		return
+	}
+	if toPost && s.indexOf(postsCollection(group.ID(), thread.ID()), PathParam(r, "postId")) < 0 {
+		writeNotFound(w, PathParam(r, "postId"))
+		return
+	}
+	if isLocked, _ := thread["isLocked"].(bool); isLocked {
+		WriteError(w, http.StatusBadRequest, "ErrorInvalidRequest", "The thread is locked and cannot be replied to.")
+		return
+	}
+	conversation, _ := s.find(conversationsCollection(group.ID()), s.conversationIDOfThread(group.ID(), thread.ID()))
+	s.insertPost(group, conversation, thread, post)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// validatePost returns the error message if the post has no body or invalid attachments.
+func validatePost(post map[string]interface{}) string {
+	if post == nil {
+		return "The post is required."
+	}
+	if _, ok := post["body"].(map[string]interface{}); !ok {
+		return "The body of the post is required."
+	}
+	attachments, _ := post["attachments"].([]interface{})
+	for _, a := range attachments {
+		attachment, _ := a.(map[string]interface{})
+		name, _ := attachment["name"].(string)
+		contentBytes, _ := attachment["contentBytes"].(string)
+		if name == "" {
+			return "The name of an attachment is required."
+		}
+		if _, err := base64.StdEncoding.DecodeString(contentBytes); err != nil {
+			return fmt.Sprintf("The contentBytes of attachment '%v' are not base64 encoded.", name)
+		}
+	}
+	return ""
+}
+
+// insertPost stores the validated post and its attachments in the thread and updates the
+// summaries of the thread and the conversation, e.g. the preview. The group is the sender of
+// the post. s.mu must be held.
+func (s *Server) insertPost(group, conversation, thread Object, post map[string]interface{}) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	attachments, _ := post["attachments"].([]interface{})
+	delete(post, "attachments")
+	sender := map[string]interface{}{"emailAddress": map[string]interface{}{"name": group["displayName"], "address": group["mail"]}}
+
+	stored := Object(post)
+	delete(stored, "id")
+	stored["from"], stored["sender"] = sender, copyValue(sender)
+	stored["conversationId"], stored["conversationThreadId"] = conversation.ID(), thread.ID()
+	stored["createdDateTime"], stored["lastModifiedDateTime"], stored["receivedDateTime"] = now, now, now
+	stored["hasAttachments"] = len(attachments) > 0
+	created := s.insert(postsCollection(group.ID(), thread.ID()), stored)
+	for _, a := range attachments {
+		attachment := Object(a.(map[string]interface{}))
+		content, _ := base64.StdEncoding.DecodeString(attachment["contentBytes"].(string))
+		delete(attachment, "id")
+		if _, ok := attachment["@odata.type"]; !ok {
+			attachment["@odata.type"] = "#microsoft.graph.fileAttachment"
+		}
+		if _, ok := attachment["isInline"]; !ok {
+			attachment["isInline"] = false
+		}
+		attachment["size"] = len(content)
+		attachment["lastModifiedDateTime"] = now
+		s.insert(postsCollection(group.ID(), thread.ID())+"/"+created.ID()+"/attachments", attachment)
+	}
+
+	body, _ := post["body"].(map[string]interface{})
+	preview, _ := body["content"].(string)
+	if len(preview) > maxPreviewLength {
+		preview = preview[:maxPreviewLength]
+	}
+	displayName, _ := group["displayName"].(string)
+	for _, summary := range []Object{conversation, thread} {
+		if summary == nil {
+			continue
+		}
+		summary["lastDeliveredDateTime"] = now
+		summary["preview"] = preview
+		if len(attachments) > 0 {
+			summary["hasAttachments"] = true
+		}
+		if senders, _ := summary["uniqueSenders"].([]interface{}); !containsValue(senders, displayName) {
+			summary["uniqueSenders"] = append(senders, displayName)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package msgraphtest
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// deletedItemsCollection holds all deleted users and groups, like the recycle bin of Azure AD.
+const deletedItemsCollection = "/directory/deletedItems"
+
+// deletedItemTypes maps the type cast segments of /directory/deletedItems to their collection.
+var deletedItemTypes = map[string]string{
+	"microsoft.graph.user":  "/users",
+	"microsoft.graph.group": "/groups",
+}
+
+// deletedRelations are the group memberships and, for groups, the members of a deleted
+// user or group, they are restored with it.
+type deletedRelations struct {
+	memberOf []string
+	members  []string
+}
+
+// isSoftDeleted returns true if the object of the collection is moved to the deleted items
+// when it is deleted, hence for users and Microsoft 365 groups. Security groups are deleted
+// permanently right away.
+func isSoftDeleted(collection string, obj Object) bool {
+	if collection == "/groups" {
+		groupTypes, _ := obj["groupTypes"].([]interface{})
+		return containsValue(groupTypes, "Unified")
+	}
+	return collection == "/users"
+}
+
+// softDelete moves the user or group at index idx of the collection to the deleted items,
+// where it can be restored including its group memberships and members. s.mu must be held.
+func (s *Server) softDelete(collection string, idx int) {
+	obj := s.collections[collection][idx]
+	id := obj.ID()
+	s.deletedRelations[id] = deletedRelations{memberOf: s.memberOf(id, false), members: append([]string(nil), s.members[id]...)}
+	s.remove(collection, idx)
+	obj["deletedDateTime"] = time.Now().UTC().Format(time.RFC3339)
+	s.collections[deletedItemsCollection] = append(s.collections[deletedItemsCollection], obj)
+}
+
+// DeletedItems returns copies of all deleted users and groups.
+func (s *Server) DeletedItems() []Object {
+	return s.List(deletedItemsCollection)
+}
+
+// serveDeletedItem serves a deleted user or group, or all deleted users or groups for the
+// type casts microsoft.graph.user and microsoft.graph.group.
+func (s *Server) serveDeletedItem(w http.ResponseWriter, r *http.Request) {
+	if collection, ok := deletedItemTypes[strings.ToLower(PathParam(r, "id"))]; ok {
+		s.mu.Lock()
+		var items []Object
+		for _, obj := range s.collections[deletedItemsCollection] {
+			if obj["@odata.type"] == odataTypes[collection] {
+				items = append(items, obj)
+			}
+		}
+		s.mu.Unlock()
+		s.writeCollection(w, r, items)
+		return
+	}
+
+	s.mu.Lock()
+	obj, ok := s.find(deletedItemsCollection, PathParam(r, "id"))
+	s.mu.Unlock()
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	s.writeObject(w, r, obj)
+}
+
+// serveRestoreDeletedItem moves a deleted user or group back to its collection including
+// its group memberships and members.
+func (s *Server) serveRestoreDeletedItem(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	idx := s.indexOf(deletedItemsCollection, PathParam(r, "id"))
+	if idx < 0 {
+		s.mu.Unlock()
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	obj := s.collections[deletedItemsCollection][idx]
+	collection := "/users"
+	if obj["@odata.type"] == odataTypes["/groups"] {
+		collection = "/groups"
+	}
+	if upn, ok := obj["userPrincipalName"].(string); ok && s.indexOf(collection, upn) >= 0 {
+		s.mu.Unlock()
+		WriteError(w, http.StatusBadRequest, "Request_BadRequest", "Another object with the same value for property userPrincipalName already exists.")
+		return
+	}
+	s.collections[deletedItemsCollection] = append(s.collections[deletedItemsCollection][:idx:idx], s.collections[deletedItemsCollection][idx+1:]...)
+	delete(obj, "deletedDateTime")
+	s.collections[collection] = append(s.collections[collection], obj)
+	relations := s.deletedRelations[obj.ID()]
+	for _, groupID := range relations.memberOf {
+		if _, ok := s.find("/groups", groupID); ok {
+			s.members[groupID] = append(s.members[groupID], obj.ID())
+		}
+	}
+	if len(relations.members) > 0 {
+		s.members[obj.ID()] = relations.members
+	}
+	delete(s.deletedRelations, obj.ID())
+	restored := obj.copy()
+	s.mu.Unlock()
+
+	WriteJSON(w, http.StatusOK, restored)
+}
+
+// servePermanentlyDeleteItem removes a deleted user or group for good.
+func (s *Server) servePermanentlyDeleteItem(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	idx := s.indexOf(deletedItemsCollection, PathParam(r, "id"))
+	if idx >= 0 {
+		delete(s.deletedRelations, s.collections[deletedItemsCollection][idx].ID())
+		s.remove(deletedItemsCollection, idx)
+	}
+	s.mu.Unlock()
+
+	if idx < 0 {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,91 @@
+package msgraphtest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// openTypeExtension is the @odata.type of open extensions.
+const openTypeExtension = "#microsoft.graph.openTypeExtension"
+
+// findExtensionOwner returns the collection of the open extensions of the user or group of the
+// extension route. s.mu must be held.
+func (s *Server) findExtensionOwner(r *http.Request) (string, bool) {
+	collection := "/users"
+	if strings.HasPrefix(strings.TrimPrefix(r.URL.Path, "/"+apiVersion), "/groups/") {
+		collection = "/groups"
+	}
+	owner, ok := s.find(collection, PathParam(r, "id"))
+	if !ok {
+		return "", false
+	}
+	return collection + "/" + owner.ID() + "/extensions", true
+}
+
+// decodeExtension decodes an open extension from the request body, its id is the extensionName.
+func decodeExtension(w http.ResponseWriter, r *http.Request) (Object, bool) {
+	body, _ := ioutil.ReadAll(r.Body)
+	extension, err := decodeObject(body)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return nil, false
+	}
+	if odataType, _ := extension["@odata.type"].(string); strings.TrimPrefix(odataType, "#") != strings.TrimPrefix(openTypeExtension, "#") {
+		WriteError(w, http.StatusBadRequest, "BadRequest", fmt.Sprintf("Invalid @odata.type '%v', open extensions must be of type microsoft.graph.openTypeExtension.", odataType))
+		return nil, false
+	}
+	name, _ := extension["extensionName"].(string)
+	if name == "" {
+		WriteError(w, http.StatusBadRequest, "BadRequest", "The extensionName is required.")
+		return nil, false
+	}
+	extension["@odata.type"] = openTypeExtension
+	extension["id"] = name
+	return extension, true
+}
+
+// serveCreateExtension creates an open extension, the extensionName must be unique per owner.
+func (s *Server) serveCreateExtension(w http.ResponseWriter, r *http.Request) {
+	extension, ok := decodeExtension(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	collection, ok := s.findExtensionOwner(r)
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	if s.indexOf(collection, extension.ID()) >= 0 {
+		WriteError(w, http.StatusConflict, "NameAlreadyExists", fmt.Sprintf("An extension already exists with given id '%v'.", extension.ID()))
+		return
+	}
+	WriteJSON(w, http.StatusCreated, s.insert(collection, extension).copy())
+}
+
+// serveUpdateExtension replaces all properties of an open extension with the request body.
+func (s *Server) serveUpdateExtension(w http.ResponseWriter, r *http.Request) {
+	extension, ok := decodeExtension(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	collection, ok := s.findExtensionOwner(r)
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	idx := s.indexOf(collection, PathParam(r, "name"))
+	if idx < 0 || extension.ID() != PathParam(r, "name") {
+		writeNotFound(w, PathParam(r, "name"))
+		return
+	}
+	s.collections[collection][idx] = extension
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,404 @@
+package msgraphtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// errDynamicMembers is the message of the error response when adding members to a group with
+// dynamic membership.
+const errDynamicMembers = "Members of a group with dynamic membership cannot be added or removed, they are determined by the membershipRule."
+
+// maxBindsOnCreate is the maximum number of owners and members that can be bound when
+// creating a group.
+const maxBindsOnCreate = 20
+
+// AddGroupOwner adds the user or service principal with the given ID as owner of the group
+// with the given ID.
+func (s *Server) AddGroupOwner(groupID, ownerID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if group, ok := s.find("/groups", groupID); ok {
+		groupID = group.ID()
+	}
+	if !containsID(s.owners[groupID], ownerID) {
+		s.owners[groupID] = append(s.owners[groupID], ownerID)
+	}
+}
+
+// GroupOwners returns the IDs of all owners of the group with the given ID.
+func (s *Server) GroupOwners(groupID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if group, ok := s.find("/groups", groupID); ok {
+		groupID = group.ID()
+	}
+	return append([]string(nil), s.owners[groupID]...)
+}
+
+// isDynamic returns true if the members of the group are determined by its membership rule.
+func isDynamic(group Object) bool {
+	groupTypes, _ := group["groupTypes"].([]interface{})
+	return containsValue(groupTypes, "DynamicMembership")
+}
+
+// validateMembershipRule returns the message of the error response if the membership rule
+// properties of the group are inconsistent, or an empty string. The rule itself is not parsed.
+func validateMembershipRule(group Object) string {
+	rule, _ := group["membershipRule"].(string)
+	state, hasState := group["membershipRuleProcessingState"]
+	switch {
+	case isDynamic(group) && rule == "":
+		return "A membershipRule is required for groups with dynamic membership."
+	case !isDynamic(group) && rule != "":
+		return "A membershipRule can only be set for groups with the groupType 'DynamicMembership'."
+	case hasState && state != "On" && state != "Paused":
+		return fmt.Sprintf("Invalid value '%v' for membershipRuleProcessingState, 'On' or 'Paused' is required.", state)
+	}
+	return ""
+}
+
+// serveCreateGroup creates a group including the owners and members bound via
+// owners@odata.bind and members@odata.bind. Mail-enabled groups get a mail address.
+func (s *Server) serveCreateGroup(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	group, err := decodeObject(body)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Request_BadRequest", err.Error())
+		return
+	}
+	for _, property := range requiredProperties["/groups"] {
+		if _, ok := group[property]; !ok {
+			WriteError(w, http.StatusBadRequest, "Request_BadRequest", fmt.Sprintf("Property '%v' is required when creating the object.", property))
+			return
+		}
+	}
+	ownerIDs, ownersOK := bindIDs(group, "owners@odata.bind")
+	memberIDs, membersOK := bindIDs(group, "members@odata.bind")
+	if !ownersOK || !membersOK {
+		WriteError(w, http.StatusBadRequest, "Request_BadRequest", "Invalid value for the odata.bind annotation, an array of directory object URLs is required.")
+		return
+	}
+	if len(ownerIDs)+len(memberIDs) > maxBindsOnCreate {
+		WriteError(w, http.StatusBadRequest, "Request_BadRequest", fmt.Sprintf("At most %v owners and members can be added when creating a group.", maxBindsOnCreate))
+		return
+	}
+	if message := validateMembershipRule(group); message != "" {
+		WriteError(w, http.StatusBadRequest, "Request_BadRequest", message)
+		return
+	}
+	if isDynamic(group) {
+		if len(memberIDs) > 0 {
+			WriteError(w, http.StatusBadRequest, "Request_BadRequest", errDynamicMembers)
+			return
+		}
+		if _, ok := group["membershipRuleProcessingState"]; !ok {
+			group["membershipRuleProcessingState"] = "On"
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range append(append([]string(nil), ownerIDs...), memberIDs...) {
+		if _, ok := s.findDirectoryObject(id); !ok {
+			WriteError(w, http.StatusBadRequest, "Request_BadRequest", fmt.Sprintf("Invalid object identifier '%v'.", id))
+			return
+		}
+	}
+	if mailEnabled, _ := group["mailEnabled"].(bool); mailEnabled {
+		nickname, _ := group["mailNickname"].(string)
+		group["mail"] = nickname + "@" + DefaultDomain
+		group["proxyAddresses"] = []interface{}{"SMTP:" + nickname + "@" + DefaultDomain}
+	}
+	if groupTypes, _ := group["groupTypes"].([]interface{}); containsValue(groupTypes, "Unified") {
+		if _, ok := group["visibility"]; !ok {
+			group["visibility"] = "Public"
+		}
+	}
+	created := s.insert("/groups", group)
+	for _, id := range ownerIDs {
+		if !containsID(s.owners[created.ID()], id) {
+			s.owners[created.ID()] = append(s.owners[created.ID()], id)
+		}
+	}
+	for _, id := range memberIDs {
+		if !containsID(s.members[created.ID()], id) {
+			s.members[created.ID()] = append(s.members[created.ID()], id)
+		}
+	}
+	WriteJSON(w, http.StatusCreated, created.copy())
+}
+
+// bindIDs removes the odata.bind annotation from the object and returns the IDs of the
+// referenced directory objects. Returns false if the annotation is not an array of URLs.
+func bindIDs(obj Object, annotation string) ([]string, bool) {
+	value, ok := obj[annotation]
+	if !ok {
+		return nil, true
+	}
+	delete(obj, annotation)
+	refs, ok := value.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	ids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		url, _ := ref.(string)
+		segments := splitPath(url)
+		if len(segments) == 0 {
+			return nil, false
+		}
+		ids = append(ids, segments[len(segments)-1])
+	}
+	return ids, true
+}
+
+// containsID returns true if the IDs contain the ID.
+func containsID(ids []string, id string) bool {
+	for _, element := range ids {
+		if element == id {
+			return true
+		}
+	}
+	return false
+}
+
+// containsValue returns true if the JSON array contains the string, compared case-insensitively.
+func containsValue(values []interface{}, value string) bool {
+	for _, element := range values {
+		if s, ok := element.(string); ok && strings.EqualFold(s, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// groupRelation returns the name and the IDs keyed by group ID of the relation of the
+// route, hence members or owners. s.mu must be held.
+func (s *Server) groupRelation(r *http.Request) (string, map[string][]string) {
+	if strings.Contains(r.URL.Path, "/owners") {
+		return "owners", s.owners
+	}
+	return "members", s.members
+}
+
+// serveOwners serves the owners of a group, optionally of the type of a type cast.
+func (s *Server) serveOwners(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	group, ok := s.find("/groups", PathParam(r, "id"))
+	var owners []Object
+	if ok {
+		for _, ownerID := range s.owners[group.ID()] {
+			if owner, found := s.findDirectoryObject(ownerID); found {
+				owners = append(owners, owner)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	s.writeCastCollection(w, r, owners, false)
+}
+
+// serveAddReference adds the directory object referenced by @odata.id as member or owner of
+// a group. Like Microsoft Graph it responds with 400 if the object has been added before.
+func (s *Server) serveAddReference(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	ref, err := decodeObject(body)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Request_BadRequest", err.Error())
+		return
+	}
+	odataID, _ := ref["@odata.id"].(string)
+	segments := splitPath(odataID)
+	if len(segments) == 0 {
+		WriteError(w, http.StatusBadRequest, "Request_BadRequest", "The @odata.id of the reference is missing.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	group, ok := s.find("/groups", PathParam(r, "id"))
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	relation, ids := s.groupRelation(r)
+	if code, message := s.addToGroup(group.ID(), relation, ids, []string{segments[len(segments)-1]}); code != 0 {
+		WriteError(w, code, "Request_BadRequest", message)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serveRemoveOwner removes an owner from a group.
+func (s *Server) serveRemoveOwner(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	group, ok := s.find("/groups", PathParam(r, "id"))
+	removed := false
+	if ok {
+		ownerIDs := s.owners[group.ID()]
+		for i, ownerID := range ownerIDs {
+			if ownerID == PathParam(r, "ownerId") {
+				s.owners[group.ID()] = append(ownerIDs[:i:i], ownerIDs[i+1:]...)
+				removed = true
+				break
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	if !removed {
+		writeNotFound(w, PathParam(r, "ownerId"))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serveUpdateGroup adds the owners and members bound via owners@odata.bind and
+// members@odata.bind to a group, all or none, and updates the remaining properties in the
+// generic object store.
+func (s *Server) serveUpdateGroup(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	patch, err := decodeObject(body)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Request_BadRequest", err.Error())
+		return
+	}
+	ownerIDs, ownersOK := bindIDs(patch, "owners@odata.bind")
+	memberIDs, membersOK := bindIDs(patch, "members@odata.bind")
+	if !ownersOK || !membersOK {
+		WriteError(w, http.StatusBadRequest, "Request_BadRequest", "Invalid value for the odata.bind annotation, an array of directory object URLs is required.")
+		return
+	}
+	if len(ownerIDs)+len(memberIDs) > maxBindsOnCreate {
+		WriteError(w, http.StatusBadRequest, "Request_BadRequest", fmt.Sprintf("At most %v owners and members can be added in one request.", maxBindsOnCreate))
+		return
+	}
+
+	s.mu.Lock()
+	if group, ok := s.find("/groups", PathParam(r, "id")); ok {
+		updated := group.copy()
+		for name, value := range patch {
+			if value == nil {
+				delete(updated, name)
+			} else {
+				updated[name] = value
+			}
+		}
+		if message := validateMembershipRule(updated); message != "" {
+			s.mu.Unlock()
+			WriteError(w, http.StatusBadRequest, "Request_BadRequest", message)
+			return
+		}
+	}
+	s.mu.Unlock()
+
+	if len(ownerIDs)+len(memberIDs) > 0 {
+		s.mu.Lock()
+		group, ok := s.find("/groups", PathParam(r, "id"))
+		if !ok {
+			s.mu.Unlock()
+			writeNotFound(w, PathParam(r, "id"))
+			return
+		}
+		// validate all references first, hence a failed request changes nothing
+		owners := append([]string(nil), s.owners[group.ID()]...)
+		members := append([]string(nil), s.members[group.ID()]...)
+		code, message := s.addToGroup(group.ID(), "owners", s.owners, ownerIDs)
+		if code == 0 {
+			code, message = s.addToGroup(group.ID(), "members", s.members, memberIDs)
+		}
+		if code != 0 {
+			s.owners[group.ID()], s.members[group.ID()] = owners, members
+			s.mu.Unlock()
+			WriteError(w, code, "Request_BadRequest", message)
+			return
+		}
+		s.mu.Unlock()
+	}
+
+	body, _ = json.Marshal(patch)
+	s.serveStore(w, r, "/groups/"+PathParam(r, "id"), body)
+}
+
+// addToGroup adds the directory objects with the given IDs to the relation of the group,
+// hence its owners or members. Returns the status code and message of the error response
+// if an object does not exist, cannot be added or has been added before. s.mu must be held.
+func (s *Server) addToGroup(groupID, relation string, ids map[string][]string, objectIDs []string) (int, string) {
+	if group, ok := s.find("/groups", groupID); ok && relation == "members" && len(objectIDs) > 0 && isDynamic(group) {
+		return http.StatusBadRequest, errDynamicMembers
+	}
+	for _, objectID := range objectIDs {
+		obj, ok := s.findDirectoryObject(objectID)
+		if !ok {
+			return http.StatusNotFound, fmt.Sprintf("Resource '%v' does not exist or one of its queried reference-property objects are not present.", objectID)
+		}
+		if obj.ID() == groupID {
+			return http.StatusBadRequest, "A group cannot be a member or owner of itself."
+		}
+		if odataType := obj["@odata.type"]; relation == "owners" && odataType != odataTypes["/users"] && odataType != odataTypes["/servicePrincipals"] {
+			return http.StatusBadRequest, "Only users and service principals can be owners of a group."
+		}
+		if containsID(ids[groupID], obj.ID()) {
+			return http.StatusBadRequest, fmt.Sprintf("One or more added object references already exist for the following modified properties: '%v'.", relation)
+		}
+		ids[groupID] = append(ids[groupID], obj.ID())
+	}
+	return 0, ""
+}
+
+// castObjects returns the objects of the type given by the type cast path parameter, e.g.
+// microsoft.graph.user, or all objects if the route has no type cast. Like Microsoft Graph,
+// the @odata.type of the casted objects is omitted as it is given by the cast.
+func castObjects(r *http.Request, objs []Object) ([]Object, error) {
+	cast := PathParam(r, "type")
+	if cast == "" {
+		return objs, nil
+	}
+	odataType := ""
+	for _, t := range odataTypes {
+		if strings.EqualFold(strings.TrimPrefix(t, "#"), cast) {
+			odataType = t
+		}
+	}
+	if odataType == "" {
+		return nil, fmt.Errorf("Resource not found for the segment '%v'.", cast)
+	}
+	var casted []Object
+	for _, obj := range objs {
+		if obj["@odata.type"] == odataType {
+			obj = obj.copy()
+			delete(obj, "@odata.type")
+			casted = append(casted, obj)
+		}
+	}
+	return casted, nil
+}
+
+// writeCastCollection writes the objects of the type cast of the route as collection, see
+// castObjects. Like Microsoft Graph, type casts of transitive lists require advanced query
+// capabilities, type casts of direct lists do not.
+func (s *Server) writeCastCollection(w http.ResponseWriter, r *http.Request, objs []Object, transitive bool) {
+	if transitive && PathParam(r, "type") != "" && (queryValue(r, "$count") != "true" || r.Header.Get("ConsistencyLevel") != "eventual") {
+		WriteError(w, http.StatusBadRequest, "Request_UnsupportedQuery",
+			"Type casts of transitive lists are only supported with advanced query capabilities, add $count=true and the header ConsistencyLevel: eventual.")
+		return
+	}
+	objs, err := castObjects(r, objs)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Request_BadRequest", err.Error())
+		return
+	}
+	s.writeCollection(w, r, objs)
+}
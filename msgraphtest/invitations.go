@@ -0,0 +1,93 @@
+package msgraphtest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RedeemInvitation marks the invitation of the guest with the given ID or userPrincipalName as
+// redeemed, like Microsoft Graph does when the guest signs in the first time.
+func (s *Server) RedeemInvitation(userID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if user, ok := s.find("/users", userID); ok {
+		user["externalUserState"] = "Accepted"
+		user["externalUserStateChangeDateTime"] = time.Now().UTC().Format(time.RFC3339)
+	}
+}
+
+// serveInvitation creates a guest for the invited email address, or returns the existing
+// guest if the address has been invited before. No email is sent.
+func (s *Server) serveInvitation(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	invitation, err := decodeObject(body)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+	email, _ := invitation["invitedUserEmailAddress"].(string)
+	redirectURL, _ := invitation["inviteRedirectUrl"].(string)
+	if at := strings.Index(email, "@"); at <= 0 || at == len(email)-1 {
+		WriteError(w, http.StatusBadRequest, "BadRequest", fmt.Sprintf("The specified invitedUserEmailAddress '%v' is invalid.", email))
+		return
+	}
+	if u, err := url.Parse(redirectURL); err != nil || u.Scheme == "" || u.Host == "" {
+		WriteError(w, http.StatusBadRequest, "BadRequest", fmt.Sprintf("The specified inviteRedirectUrl '%v' is invalid.", redirectURL))
+		return
+	}
+	userType, _ := invitation["invitedUserType"].(string)
+	if userType == "" {
+		userType = "Guest"
+	}
+	displayName, _ := invitation["invitedUserDisplayName"].(string)
+	if displayName == "" {
+		displayName = email
+	}
+
+	s.mu.Lock()
+	var guest Object
+	for _, user := range s.collections["/users"] {
+		if mail, _ := user["mail"].(string); strings.EqualFold(mail, email) {
+			guest = user
+			break
+		}
+	}
+	if guest == nil {
+		now := time.Now().UTC().Format(time.RFC3339)
+		guest = s.insert("/users", Object{
+			"accountEnabled":                  true,
+			"displayName":                     displayName,
+			"mail":                            email,
+			"mailNickname":                    strings.Replace(email, "@", "_", 1) + "#EXT#",
+			"userPrincipalName":               strings.Replace(email, "@", "_", 1) + "#EXT#@" + DefaultDomain,
+			"userType":                        userType,
+			"creationType":                    "Invitation",
+			"externalUserState":               "PendingAcceptance",
+			"externalUserStateChangeDateTime": now,
+			"createdDateTime":                 now,
+		})
+	}
+	if reset, _ := invitation["resetRedemption"].(bool); reset {
+		guest["externalUserState"] = "PendingAcceptance"
+		guest["externalUserStateChangeDateTime"] = time.Now().UTC().Format(time.RFC3339)
+	}
+	guestID := guest.ID()
+	status, _ := guest["externalUserState"].(string)
+	if status == "Accepted" {
+		status = "Completed"
+	}
+	invitationID := s.newID() // invitations are not stored
+	s.mu.Unlock()
+
+	invitation["id"] = invitationID
+	invitation["invitedUserDisplayName"] = displayName
+	invitation["invitedUserType"] = userType
+	invitation["inviteRedeemUrl"] = fmt.Sprintf("%v/redeem?rd=%v", s.URL, url.QueryEscape(guestID))
+	invitation["status"] = status
+	invitation["invitedUser"] = Object{"id": guestID}
+	WriteJSON(w, http.StatusCreated, invitation)
+}
@@ -0,0 +1,149 @@
+package msgraphtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// AddSubscribedSku adds the given SKU to /subscribedSkus, see Add. The consumedUnits of the SKU
+// are maintained by the assignLicense endpoint.
+func (s *Server) AddSubscribedSku(v interface{}) Object {
+	return s.Add("/subscribedSkus", v)
+}
+
+// serveAssignLicense adds and removes licenses of a user like Microsoft Graph does, hence
+// requires the usageLocation of the user and available units of the SKUs to add.
+func (s *Server) serveAssignLicense(w http.ResponseWriter, r *http.Request) {
+	var post struct {
+		AddLicenses []struct {
+			DisabledPlans []string `json:"disabledPlans"`
+			SkuID         string   `json:"skuId"`
+		} `json:"addLicenses"`
+		RemoveLicenses []string `json:"removeLicenses"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&post); err != nil || post.AddLicenses == nil || post.RemoveLicenses == nil {
+		WriteError(w, http.StatusBadRequest, "Request_BadRequest", "The properties addLicenses and removeLicenses are required.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.find("/users", PathParam(r, "id"))
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	if usageLocation, _ := user["usageLocation"].(string); usageLocation == "" {
+		WriteError(w, http.StatusBadRequest, "Request_BadRequest", "License assignment cannot be done for user with invalid usage location.")
+		return
+	}
+
+	assigned, _ := user["assignedLicenses"].([]interface{})
+	indexOfLicense := func(skuID string) int {
+		for i, license := range assigned {
+			if id, _ := license.(map[string]interface{})["skuId"].(string); strings.EqualFold(id, skuID) {
+				return i
+			}
+		}
+		return -1
+	}
+	for _, skuID := range post.RemoveLicenses {
+		idx := indexOfLicense(skuID)
+		if idx < 0 {
+			WriteError(w, http.StatusBadRequest, "Request_BadRequest", fmt.Sprintf("User does not have a corresponding license %v.", skuID))
+			return
+		}
+		assigned = append(assigned[:idx:idx], assigned[idx+1:]...)
+		if sku, ok := s.findSku(skuID); ok {
+			sku["consumedUnits"] = unitsOf(sku["consumedUnits"]) - 1
+		}
+	}
+	for _, license := range post.AddLicenses {
+		sku, ok := s.findSku(license.SkuID)
+		if !ok {
+			WriteError(w, http.StatusBadRequest, "Request_BadRequest", fmt.Sprintf("License %v does not correspond to a valid company License.", license.SkuID))
+			return
+		}
+		disabledPlans := make([]interface{}, len(license.DisabledPlans))
+		for i, plan := range license.DisabledPlans {
+			disabledPlans[i] = plan
+		}
+		if idx := indexOfLicense(license.SkuID); idx >= 0 {
+			assigned[idx] = map[string]interface{}{"skuId": license.SkuID, "disabledPlans": disabledPlans}
+			continue
+		}
+		prepaid, _ := sku["prepaidUnits"].(map[string]interface{})
+		if unitsOf(sku["consumedUnits"]) >= unitsOf(prepaid["enabled"]) {
+			WriteError(w, http.StatusBadRequest, "Request_BadRequest", fmt.Sprintf("Subscription for license %v does not have any available licenses.", license.SkuID))
+			return
+		}
+		sku["consumedUnits"] = unitsOf(sku["consumedUnits"]) + 1
+		assigned = append(assigned, map[string]interface{}{"skuId": license.SkuID, "disabledPlans": disabledPlans})
+	}
+	if assigned == nil {
+		assigned = []interface{}{}
+	}
+	user["assignedLicenses"] = assigned
+	WriteJSON(w, http.StatusOK, user)
+}
+
+// serveLicenseDetails serves the licenses assigned to a user, including their service plans.
+func (s *Server) serveLicenseDetails(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	user, ok := s.find("/users", PathParam(r, "id"))
+	var details []Object
+	if ok {
+		assigned, _ := user["assignedLicenses"].([]interface{})
+		for _, license := range assigned {
+			skuID, _ := license.(map[string]interface{})["skuId"].(string)
+			disabledPlans, _ := license.(map[string]interface{})["disabledPlans"].([]interface{})
+			sku, found := s.findSku(skuID)
+			if !found {
+				continue
+			}
+			var servicePlans []interface{}
+			plans, _ := sku["servicePlans"].([]interface{})
+			for _, plan := range plans {
+				servicePlan := copyValue(plan).(map[string]interface{})
+				servicePlan["provisioningStatus"] = "Success"
+				for _, disabled := range disabledPlans {
+					if disabled == servicePlan["servicePlanId"] {
+						servicePlan["provisioningStatus"] = "Disabled"
+					}
+				}
+				servicePlans = append(servicePlans, servicePlan)
+			}
+			details = append(details, Object{
+				"id":            sku["skuPartNumber"],
+				"skuId":         sku["skuId"],
+				"skuPartNumber": sku["skuPartNumber"],
+				"servicePlans":  servicePlans,
+			})
+		}
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	s.writeCollection(w, r, details)
+}
+
+// findSku returns the subscribed SKU with the given skuId. s.mu must be held.
+func (s *Server) findSku(skuID string) (Object, bool) {
+	for _, sku := range s.collections["/subscribedSkus"] {
+		if id, _ := sku["skuId"].(string); strings.EqualFold(id, skuID) {
+			return sku, true
+		}
+	}
+	return nil, false
+}
+
+// unitsOf returns the given JSON number as int, 0 if it is not a number.
+func unitsOf(v interface{}) int {
+	f, _ := normalizeValue(v).(float64)
+	return int(f)
+}
@@ -0,0 +1,210 @@
+package msgraphtest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// SetManager sets the user or org contact with the given ID as manager of the user with the
+// given ID or userPrincipalName. An empty managerID removes the manager.
+func (s *Server) SetManager(userID, managerID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if user, ok := s.find("/users", userID); ok {
+		userID = user.ID()
+	}
+	if managerID == "" {
+		delete(s.managers, userID)
+		return
+	}
+	if manager, ok := s.findDirectoryObject(managerID); ok {
+		managerID = manager.ID()
+	}
+	s.managers[userID] = managerID
+}
+
+// Manager returns the ID of the manager of the user with the given ID or userPrincipalName,
+// or an empty string if the user has no manager.
+func (s *Server) Manager(userID string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if user, ok := s.find("/users", userID); ok {
+		userID = user.ID()
+	}
+	return s.managers[userID]
+}
+
+// serveUser serves a user and supports $expand=manager, including $levels. Without $expand
+// the user is served by the generic object store.
+func (s *Server) serveUser(w http.ResponseWriter, r *http.Request) {
+	expand := queryValue(r, "$expand")
+	if !strings.HasPrefix(strings.ToLower(expand), "manager") {
+		s.serveStore(w, r, "/users/"+PathParam(r, "id"), nil)
+		return
+	}
+	levels, err := parseExpandLevels(expand)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Request_UnsupportedQuery", err.Error())
+		return
+	}
+	if levels != 1 && (queryValue(r, "$count") != "true" || r.Header.Get("ConsistencyLevel") != "eventual") {
+		WriteError(w, http.StatusBadRequest, "Request_UnsupportedQuery",
+			"$levels is only supported with advanced query capabilities, add $count=true and the header ConsistencyLevel: eventual.")
+		return
+	}
+
+	s.mu.Lock()
+	user, ok := s.find("/users", PathParam(r, "id"))
+	var expanded Object
+	if ok {
+		expanded = selectProperties(user, queryValue(r, "$select"))
+		visited := map[string]bool{user.ID(): true}
+		parent := expanded
+		for id, level := user.ID(), 0; levels < 0 || level < levels; level++ {
+			manager, found := s.findDirectoryObject(s.managers[id])
+			if !found {
+				break
+			}
+			child := manager.copy()
+			parent["manager"] = child
+			// like a cyclic hierarchy in Azure AD, a repeated manager is returned once more, but not expanded again
+			if visited[manager.ID()] {
+				break
+			}
+			visited[manager.ID()] = true
+			parent, id = child, manager.ID()
+		}
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	WriteJSON(w, http.StatusOK, expanded)
+}
+
+// parseExpandLevels returns the $levels of $expand=manager($levels=n), -1 for max, 1 if not set.
+func parseExpandLevels(expand string) (int, error) {
+	idx := strings.Index(strings.ToLower(expand), "$levels=")
+	if idx < 0 {
+		return 1, nil
+	}
+	value := expand[idx+len("$levels="):]
+	if end := strings.IndexAny(value, ";)"); end >= 0 {
+		value = value[:end]
+	}
+	if strings.EqualFold(value, "max") {
+		return -1, nil
+	}
+	levels, err := strconv.Atoi(value)
+	if err != nil || levels < 1 {
+		return 0, fmt.Errorf("Invalid $levels value '%v'.", value)
+	}
+	return levels, nil
+}
+
+// serveManager serves the manager of a user.
+func (s *Server) serveManager(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	user, ok := s.find("/users", PathParam(r, "id"))
+	var manager Object
+	var found bool
+	if ok {
+		manager, found = s.findDirectoryObject(s.managers[user.ID()])
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	if !found {
+		writeNotFound(w, "manager")
+		return
+	}
+	s.writeObject(w, r, manager)
+}
+
+// serveSetManager assigns the directory object referenced by @odata.id as manager of a user.
+func (s *Server) serveSetManager(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	ref, err := decodeObject(body)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Request_BadRequest", err.Error())
+		return
+	}
+	odataID, _ := ref["@odata.id"].(string)
+	segments := splitPath(odataID)
+	if len(segments) == 0 {
+		WriteError(w, http.StatusBadRequest, "Request_BadRequest", "The @odata.id of the reference is missing.")
+		return
+	}
+	managerID := segments[len(segments)-1]
+
+	s.mu.Lock()
+	user, ok := s.find("/users", PathParam(r, "id"))
+	manager, found := s.findDirectoryObject(managerID)
+	if ok && found {
+		s.managers[user.ID()] = manager.ID()
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	if !found {
+		writeNotFound(w, managerID)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serveRemoveManager removes the manager of a user.
+func (s *Server) serveRemoveManager(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	user, ok := s.find("/users", PathParam(r, "id"))
+	var hasManager bool
+	if ok {
+		_, hasManager = s.managers[user.ID()]
+		delete(s.managers, user.ID())
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	if !hasManager {
+		writeNotFound(w, "manager")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serveDirectReports serves all users and org contacts that have the user as manager.
+func (s *Server) serveDirectReports(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	user, ok := s.find("/users", PathParam(r, "id"))
+	var reports []Object
+	if ok {
+		for _, collection := range []string{"/users", "/contacts"} {
+			for _, obj := range s.collections[collection] {
+				if s.managers[obj.ID()] == user.ID() {
+					reports = append(reports, obj)
+				}
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	s.writeCastCollection(w, r, reports, false)
+}
@@ -0,0 +1,574 @@
+package msgraphtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// writeCollection applies the OData query parameters $filter, $search, $orderby, $top,
+// $skiptoken, $count and $select of the request to the objects and writes the resulting page.
+func (s *Server) writeCollection(w http.ResponseWriter, r *http.Request, objs []Object) {
+	query := r.URL.Query()
+
+	var result []Object
+	filter, err := parseFilter(query.Get("$filter"))
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Request_UnsupportedQuery", fmt.Sprintf("Unsupported or invalid query filter clause specified: %v", err))
+		return
+	}
+	search := query.Get("$search")
+	if search != "" && r.Header.Get("ConsistencyLevel") != "eventual" {
+		WriteError(w, http.StatusBadRequest, "Request_UnsupportedQuery", "Request with $search query parameter only works through MSGraph with a special request header: 'ConsistencyLevel: eventual'")
+		return
+	}
+	for _, obj := range objs {
+		if filter(obj, nil) && matchSearch(search, obj) {
+			result = append(result, obj)
+		}
+	}
+	if orderBy := query.Get("$orderby"); orderBy != "" {
+		sortObjects(result, orderBy)
+	}
+
+	pageSize := s.PageSize
+	if top, err := strconv.Atoi(query.Get("$top")); err == nil && top > 0 && (top < pageSize || pageSize <= 0) {
+		pageSize = top
+	}
+	offset, _ := strconv.Atoi(query.Get("$skiptoken"))
+	if offset < 0 || offset > len(result) {
+		offset = len(result)
+	}
+	end := len(result)
+	if pageSize > 0 && offset+pageSize < end {
+		end = offset + pageSize
+	}
+
+	page := make([]Object, 0, end-offset)
+	for _, obj := range result[offset:end] {
+		page = append(page, selectProperties(obj, query.Get("$select")))
+	}
+	resp := map[string]interface{}{
+		"@odata.context": s.URL + "/" + apiVersion + "/$metadata#" + strings.TrimPrefix(r.URL.Path, "/"+apiVersion+"/"),
+		"value":          page,
+	}
+	if query.Get("$count") == "true" {
+		resp["@odata.count"] = len(result)
+	}
+	if end < len(result) {
+		query.Set("$skiptoken", strconv.Itoa(end))
+		resp["@odata.nextLink"] = s.URL + r.URL.Path + "?" + query.Encode()
+	}
+	WriteJSON(w, http.StatusOK, resp)
+}
+
+// writeObject applies the $select query parameter of the request to the object and writes it.
+func (s *Server) writeObject(w http.ResponseWriter, r *http.Request, obj Object) {
+	WriteJSON(w, http.StatusOK, selectProperties(obj, r.URL.Query().Get("$select")))
+}
+
+// selectProperties returns a copy of the object that only contains the comma separated
+// properties and the @odata.type. Returns a full copy if selectParam is empty.
+func selectProperties(obj Object, selectParam string) Object {
+	if selectParam == "" {
+		return obj.copy()
+	}
+	selected := make(Object)
+	for _, property := range strings.Split(selectParam, ",") {
+		property = strings.TrimSpace(property)
+		for key, value := range obj {
+			if strings.EqualFold(key, property) {
+				selected[key] = copyValue(value)
+			}
+		}
+	}
+	if odataType, ok := obj["@odata.type"]; ok {
+		selected["@odata.type"] = odataType
+	}
+	return selected
+}
+
+// searchClause matches a single clause of the $search query parameter, e.g. "displayName:alice".
+var searchClause = regexp.MustCompile(`"([^":]+):([^"]*)"`)
+
+// matchSearch returns true if the object matches the $search query parameter. Clauses are
+// combined with AND unless the query contains OR. A clause matches if the property contains
+// the search term case-insensitively.
+func matchSearch(search string, obj Object) bool {
+	if search == "" {
+		return true
+	}
+	matchAny := strings.Contains(search, " OR ")
+	for _, clause := range searchClause.FindAllStringSubmatch(search, -1) {
+		value, _ := lookupProperty(obj, clause[1]).(string)
+		matched := strings.Contains(strings.ToLower(value), strings.ToLower(clause[2]))
+		if matched && matchAny {
+			return true
+		}
+		if !matched && !matchAny {
+			return false
+		}
+	}
+	return !matchAny
+}
+
+// sortObjects sorts the objects by the comma separated properties of the $orderby query
+// parameter, each optionally followed by asc or desc.
+func sortObjects(objs []Object, orderBy string) {
+	clauses := strings.Split(orderBy, ",")
+	sort.SliceStable(objs, func(i, j int) bool {
+		for _, clause := range clauses {
+			fields := strings.Fields(clause)
+			if len(fields) == 0 {
+				continue
+			}
+			cmp := compareValues(lookupProperty(objs[i], fields[0]), lookupProperty(objs[j], fields[0]))
+			if cmp == 0 {
+				continue
+			}
+			if len(fields) > 1 && strings.EqualFold(fields[1], "desc") {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+}
+
+// lookupProperty returns the value of the property with the given path, e.g. displayName or
+// onPremisesExtensionAttributes/extensionAttribute1. Property names are compared case-insensitively.
+func lookupProperty(v interface{}, path string) interface{} {
+	for _, name := range strings.Split(path, "/") {
+		var obj map[string]interface{}
+		switch val := v.(type) {
+		case Object:
+			obj = val
+		case map[string]interface{}:
+			obj = val
+		default:
+			return nil
+		}
+		v = nil
+		for key, value := range obj {
+			if strings.EqualFold(key, name) {
+				v = value
+				break
+			}
+		}
+	}
+	return v
+}
+
+// normalizeValue converts JSON numbers to float64 so that values can be compared.
+func normalizeValue(v interface{}) interface{} {
+	if n, ok := v.(json.Number); ok {
+		f, _ := n.Float64()
+		return f
+	}
+	if n, ok := v.(int); ok {
+		return float64(n)
+	}
+	return v
+}
+
+// compareValues returns -1, 0 or 1 if a is less than, equal to or greater than b. Strings are
+// compared case-insensitively, null is less than any other value.
+func compareValues(a, b interface{}) int {
+	a, b = normalizeValue(a), normalizeValue(b)
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	switch av := a.(type) {
+	case string:
+		if bv, ok := b.(string); ok {
+			return strings.Compare(strings.ToLower(av), strings.ToLower(bv))
+		}
+	case float64:
+		if bv, ok := b.(float64); ok {
+			switch {
+			case av < bv:
+				return -1
+			case av > bv:
+				return 1
+			}
+			return 0
+		}
+	case bool:
+		if bv, ok := b.(bool); ok {
+			switch {
+			case av == bv:
+				return 0
+			case !av:
+				return -1
+			}
+			return 1
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// filterPredicate evaluates a parsed $filter expression for an object. vars holds the values
+// of lambda variables, e.g. c in groupTypes/any(c:c eq 'Unified').
+type filterPredicate func(obj Object, vars map[string]interface{}) bool
+
+// filterOperand returns the value of a literal or property path of a $filter expression.
+type filterOperand func(obj Object, vars map[string]interface{}) interface{}
+
+// parseFilter parses the $filter query parameter. Supported are the comparison operators eq,
+// ne, gt, ge, lt, le and in, the functions startswith, endswith and contains, the lambda
+// operators any and all, the logical operators and, or, not and parentheses.
+func parseFilter(filter string) (filterPredicate, error) {
+	if strings.TrimSpace(filter) == "" {
+		return func(Object, map[string]interface{}) bool { return true }, nil
+	}
+	tokens, err := tokenizeFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens}
+	predicate, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected token %q", p.tokens[p.pos].value)
+	}
+	return predicate, nil
+}
+
+// filterToken is a single token of a $filter expression.
+type filterToken struct {
+	value    string
+	isString bool // true for string literals, value is unquoted then
+}
+
+// tokenizeFilter splits a $filter expression into tokens.
+func tokenizeFilter(filter string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(filter); {
+		c := filter[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(' || c == ')' || c == ',' || c == ':':
+			tokens = append(tokens, filterToken{value: string(c)})
+			i++
+		case c == '\'':
+			var sb strings.Builder
+			i++
+			for {
+				if i >= len(filter) {
+					return nil, fmt.Errorf("unterminated string literal")
+				}
+				if filter[i] == '\'' {
+					if i+1 < len(filter) && filter[i+1] == '\'' { // escaped quote
+						sb.WriteByte('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				sb.WriteByte(filter[i])
+				i++
+			}
+			tokens = append(tokens, filterToken{value: sb.String(), isString: true})
+		default:
+			start := i
+			for i < len(filter) && !strings.ContainsRune(" \t(),:'", rune(filter[i])) {
+				i++
+			}
+			// a date time literal contains colons, e.g. 2021-01-01T00:00:00Z
+			for i < len(filter) && filter[i] == ':' && i+1 < len(filter) && filter[i+1] >= '0' && filter[i+1] <= '9' && filter[start] >= '0' && filter[start] <= '9' {
+				i++
+				for i < len(filter) && !strings.ContainsRune(" \t(),:'", rune(filter[i])) {
+					i++
+				}
+			}
+			tokens = append(tokens, filterToken{value: filter[start:i]})
+		}
+	}
+	return tokens, nil
+}
+
+// filterParser is a recursive descent parser for $filter expressions.
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+// peek returns the current token value in lower case, or an empty string at the end.
+func (p *filterParser) peek() string {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].isString {
+		return ""
+	}
+	return strings.ToLower(p.tokens[p.pos].value)
+}
+
+// expect consumes the current token if it equals value, otherwise returns an error.
+func (p *filterParser) expect(value string) error {
+	if p.peek() != value {
+		if p.pos >= len(p.tokens) {
+			return fmt.Errorf("expected %q but reached end of expression", value)
+		}
+		return fmt.Errorf("expected %q but got %q", value, p.tokens[p.pos].value)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *filterParser) parseOr() (filterPredicate, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "or" {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(obj Object, vars map[string]interface{}) bool { return l(obj, vars) || right(obj, vars) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterPredicate, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "and" {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(obj Object, vars map[string]interface{}) bool { return l(obj, vars) && right(obj, vars) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (filterPredicate, error) {
+	if p.peek() == "not" {
+		p.pos++
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(obj Object, vars map[string]interface{}) bool { return !inner(obj, vars) }, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *filterParser) parsePrimary() (filterPredicate, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	switch name := p.peek(); {
+	case name == "(":
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	case name == "startswith" || name == "endswith" || name == "contains":
+		return p.parseFunction(name)
+	case strings.HasSuffix(name, "/any") || strings.HasSuffix(name, "/all"):
+		return p.parseLambda()
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	op := p.peek()
+	p.pos++
+	if op == "in" {
+		return p.parseIn(left)
+	}
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	var cmp func(int) bool
+	switch op {
+	case "eq":
+		cmp = func(c int) bool { return c == 0 }
+	case "ne":
+		cmp = func(c int) bool { return c != 0 }
+	case "gt":
+		cmp = func(c int) bool { return c > 0 }
+	case "ge":
+		cmp = func(c int) bool { return c >= 0 }
+	case "lt":
+		cmp = func(c int) bool { return c < 0 }
+	case "le":
+		cmp = func(c int) bool { return c <= 0 }
+	default:
+		return nil, fmt.Errorf("unsupported operator %q", op)
+	}
+	return func(obj Object, vars map[string]interface{}) bool {
+		return cmp(compareValues(left(obj, vars), right(obj, vars)))
+	}, nil
+}
+
+// parseFunction parses startswith(a,b), endswith(a,b) and contains(a,b).
+func (p *filterParser) parseFunction(name string) (filterPredicate, error) {
+	p.pos++
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(","); err != nil {
+		return nil, err
+	}
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	fn := map[string]func(string, string) bool{
+		"startswith": strings.HasPrefix,
+		"endswith":   strings.HasSuffix,
+		"contains":   strings.Contains,
+	}[name]
+	return func(obj Object, vars map[string]interface{}) bool {
+		l, lok := left(obj, vars).(string)
+		r, rok := right(obj, vars).(string)
+		return lok && rok && fn(strings.ToLower(l), strings.ToLower(r))
+	}, nil
+}
+
+// parseLambda parses collection/any(v:expression) and collection/all(v:expression).
+func (p *filterParser) parseLambda() (filterPredicate, error) {
+	token := p.tokens[p.pos].value
+	p.pos++
+	idx := strings.LastIndex(token, "/")
+	path, isAll := token[:idx], strings.EqualFold(token[idx+1:], "all")
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	variable := p.tokens[p.pos].value
+	p.pos++
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	inner, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	collection := p.pathOperand(path)
+	return func(obj Object, vars map[string]interface{}) bool {
+		values, _ := collection(obj, vars).([]interface{})
+		for _, value := range values {
+			lambdaVars := map[string]interface{}{variable: value}
+			for k, v := range vars {
+				if k != variable {
+					lambdaVars[k] = v
+				}
+			}
+			if matched := inner(obj, lambdaVars); matched != isAll {
+				return matched
+			}
+		}
+		return isAll
+	}, nil
+}
+
+// parseIn parses the list of the in operator, e.g. id in ('1', '2').
+func (p *filterParser) parseIn(left filterOperand) (filterPredicate, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var values []filterOperand
+	for {
+		value, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		if p.peek() != "," {
+			break
+		}
+		p.pos++
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return func(obj Object, vars map[string]interface{}) bool {
+		l := left(obj, vars)
+		for _, value := range values {
+			if compareValues(l, value(obj, vars)) == 0 {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// parseOperand parses a literal or a property path.
+func (p *filterParser) parseOperand() (filterOperand, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	token := p.tokens[p.pos]
+	p.pos++
+	if token.isString {
+		return func(Object, map[string]interface{}) interface{} { return token.value }, nil
+	}
+	switch strings.ToLower(token.value) {
+	case "(", ")", ",", ":":
+		return nil, fmt.Errorf("unexpected token %q", token.value)
+	case "null":
+		return func(Object, map[string]interface{}) interface{} { return nil }, nil
+	case "true", "false":
+		value := strings.EqualFold(token.value, "true")
+		return func(Object, map[string]interface{}) interface{} { return value }, nil
+	}
+	if c := token.value[0]; (c >= '0' && c <= '9') || c == '-' {
+		if f, err := strconv.ParseFloat(token.value, 64); err == nil {
+			return func(Object, map[string]interface{}) interface{} { return f }, nil
+		}
+		return func(Object, map[string]interface{}) interface{} { return token.value }, nil // e.g. date time or GUID
+	}
+	return p.pathOperand(token.value), nil
+}
+
+// pathOperand returns an operand that looks up the property path in the object or, if the
+// first segment is a lambda variable, in the value of the variable.
+func (p *filterParser) pathOperand(path string) filterOperand {
+	return func(obj Object, vars map[string]interface{}) interface{} {
+		segments := strings.SplitN(path, "/", 2)
+		if value, ok := vars[segments[0]]; ok {
+			if len(segments) == 1 {
+				return value
+			}
+			return lookupProperty(value, segments[1])
+		}
+		return lookupProperty(obj, path)
+	}
+}
@@ -0,0 +1,86 @@
+package msgraphtest
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func Test_parseFilter(t *testing.T) {
+	var obj Object
+	if err := json.Unmarshal([]byte(`{
+		"id": "1",
+		"displayName": "Alice Smith",
+		"accountEnabled": true,
+		"employeeId": null,
+		"age": 42,
+		"createdDateTime": "2021-06-01T10:00:00Z",
+		"groupTypes": ["Unified", "DynamicMembership"],
+		"onPremisesExtensionAttributes": {"extensionAttribute1": "cost-center-1"}
+	}`), &obj); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	tests := []struct {
+		filter  string
+		want    bool
+		wantErr bool
+	}{
+		{filter: "", want: true},
+		{filter: "displayName eq 'alice smith'", want: true},
+		{filter: "displayName eq 'Bob'", want: false},
+		{filter: "displayName ne 'Bob'", want: true},
+		{filter: "accountEnabled eq true", want: true},
+		{filter: "employeeId eq null", want: true},
+		{filter: "age gt 41 and age le 42", want: true},
+		{filter: "age lt 42", want: false},
+		{filter: "createdDateTime ge 2021-01-01T00:00:00Z", want: true},
+		{filter: "startswith(displayName,'ali')", want: true},
+		{filter: "endswith(displayName,'smith')", want: true},
+		{filter: "not startswith(displayName,'bob')", want: true},
+		{filter: "displayName eq 'Bob' or (accountEnabled eq true and age eq 42)", want: true},
+		{filter: "id in ('2', '1')", want: true},
+		{filter: "groupTypes/any(c:c eq 'Unified')", want: true},
+		{filter: "groupTypes/all(c:c eq 'Unified')", want: false},
+		{filter: "onPremisesExtensionAttributes/extensionAttribute1 eq 'cost-center-1'", want: true},
+		{filter: "displayName eq 'O''Brien'", want: false},
+		{filter: "displayName equals 'Alice'", wantErr: true},
+		{filter: "displayName eq 'Alice", wantErr: true},
+		{filter: "(displayName eq 'Alice'", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			predicate, err := parseFilter(tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := predicate(obj, nil); got != tt.want {
+				t.Errorf("parseFilter() predicate = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_matchSearch(t *testing.T) {
+	obj := Object{"displayName": "Alice Smith", "mail": "alice@contoso.com"}
+	tests := []struct {
+		search string
+		want   bool
+	}{
+		{search: "", want: true},
+		{search: `"displayName:smith"`, want: true},
+		{search: `"displayName:bob"`, want: false},
+		{search: `"displayName:alice" AND "mail:contoso"`, want: true},
+		{search: `"displayName:alice" AND "mail:fabrikam"`, want: false},
+		{search: `"displayName:bob" OR "mail:contoso"`, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.search, func(t *testing.T) {
+			if got := matchSearch(tt.search, obj); got != tt.want {
+				t.Errorf("matchSearch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package msgraphtest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"image"
+	_ "image/gif"  // register GIF for image.DecodeConfig
+	_ "image/jpeg" // register JPEG for image.DecodeConfig
+	_ "image/png"  // register PNG for image.DecodeConfig
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// photoSizes are the square sizes Microsoft Graph provides profile photos in.
+var photoSizes = []int{48, 64, 96, 120, 240, 360, 432, 504, 648}
+
+// photo is the profile photo of a user or group.
+type photo struct {
+	contentType   string
+	content       []byte
+	width, height int
+}
+
+// SetPhoto sets the profile photo of the user or group with the given ID or userPrincipalName.
+// The photo must be a GIF, JPEG or PNG image.
+func (s *Server) SetPhoto(id, contentType string, content []byte) {
+	p, err := newPhoto(contentType, content)
+	if err != nil {
+		panic(fmt.Sprintf("msgraphtest: cannot set photo of %v: %v", id, err))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if object, ok := s.findDirectoryObject(id); ok {
+		id = object.ID()
+	}
+	s.photos[id] = p
+}
+
+// Photo returns the content type and content of the profile photo of the user or group with
+// the given ID or userPrincipalName, ok is false if there is none.
+func (s *Server) Photo(id string) (contentType string, content []byte, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if object, found := s.findDirectoryObject(id); found {
+		id = object.ID()
+	}
+	p, ok := s.photos[id]
+	return p.contentType, append([]byte(nil), p.content...), ok
+}
+
+// newPhoto returns the photo if the content is a valid image.
+func newPhoto(contentType string, content []byte) (photo, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return photo{}, err
+	}
+	return photo{contentType: contentType, content: content, width: config.Width, height: config.Height}, nil
+}
+
+// metadata returns the profilePhoto of the given size, or of the largest available size if
+// size is empty. ok is false if the size is not available.
+func (p photo) metadata(size string) (Object, bool) {
+	available := p.sizes()
+	if size == "" {
+		if len(available) == 0 {
+			return Object{"id": "default", "width": p.width, "height": p.height, "@odata.mediaContentType": p.contentType, "@odata.mediaEtag": p.etag()}, true
+		}
+		side := available[len(available)-1]
+		size = fmt.Sprintf("%vx%v", side, side)
+	}
+	for _, side := range available {
+		if id := fmt.Sprintf("%vX%v", side, side); strings.EqualFold(size, id) {
+			return Object{"id": id, "width": side, "height": side, "@odata.mediaContentType": p.contentType, "@odata.mediaEtag": p.etag()}, true
+		}
+	}
+	return nil, false
+}
+
+// sizes returns all photoSizes not larger than the photo.
+func (p photo) sizes() []int {
+	var sizes []int
+	for _, side := range photoSizes {
+		if side <= p.width && side <= p.height {
+			sizes = append(sizes, side)
+		}
+	}
+	return sizes
+}
+
+// etag returns the @odata.mediaEtag of the photo, it changes whenever the photo is replaced.
+func (p photo) etag() string {
+	return fmt.Sprintf("W/\"%x\"", sha256.Sum256(p.content))
+}
+
+// findPhotoOwner returns the user or group of the photo route. s.mu must be held.
+func (s *Server) findPhotoOwner(r *http.Request) (Object, bool) {
+	collection := "/users"
+	if strings.HasPrefix(strings.TrimPrefix(r.URL.Path, "/"+apiVersion), "/groups/") {
+		collection = "/groups"
+	}
+	return s.find(collection, PathParam(r, "id"))
+}
+
+// lookupPhoto returns the photo of the user or group of the photo route and writes the error
+// response if there is none.
+func (s *Server) lookupPhoto(w http.ResponseWriter, r *http.Request) (photo, bool) {
+	s.mu.Lock()
+	owner, ok := s.findPhotoOwner(r)
+	var p photo
+	var hasPhoto bool
+	if ok {
+		p, hasPhoto = s.photos[owner.ID()]
+	}
+	s.mu.Unlock()
+
+	switch {
+	case !ok:
+		writeNotFound(w, PathParam(r, "id"))
+	case !hasPhoto:
+		WriteError(w, http.StatusNotFound, "ImageNotFound", "Exception of type 'Microsoft.Fast.Profile.Core.Exception.ImageNotFoundException' was thrown.")
+	}
+	return p, ok && hasPhoto
+}
+
+// servePhotoMetadata serves the profilePhoto of the largest or the given size.
+func (s *Server) servePhotoMetadata(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.lookupPhoto(w, r)
+	if !ok {
+		return
+	}
+	metadata, ok := p.metadata(PathParam(r, "size"))
+	if !ok {
+		WriteError(w, http.StatusNotFound, "ImageNotFound", fmt.Sprintf("The photo of size '%v' does not exist.", PathParam(r, "size")))
+		return
+	}
+	WriteJSON(w, http.StatusOK, metadata)
+}
+
+// serveListPhotos serves the profilePhotos of all available sizes.
+func (s *Server) serveListPhotos(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.lookupPhoto(w, r)
+	if !ok {
+		return
+	}
+	var photos []Object
+	for _, side := range p.sizes() {
+		metadata, _ := p.metadata(fmt.Sprintf("%vx%v", side, side))
+		photos = append(photos, metadata)
+	}
+	s.writeCollection(w, r, photos)
+}
+
+// servePhotoContent serves the binary content of the photo. Photos are not resized, hence the
+// content of all sizes is the uploaded photo.
+func (s *Server) servePhotoContent(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.lookupPhoto(w, r)
+	if !ok {
+		return
+	}
+	if size := PathParam(r, "size"); size != "" {
+		if _, ok := p.metadata(size); !ok {
+			WriteError(w, http.StatusNotFound, "ImageNotFound", fmt.Sprintf("The photo of size '%v' does not exist.", size))
+			return
+		}
+	}
+	w.Header().Set("Content-Type", p.contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(p.content)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(p.content)
+}
+
+// serveSetPhoto replaces the photo with the image of the request body.
+func (s *Server) serveSetPhoto(w http.ResponseWriter, r *http.Request) {
+	if r.ContentLength < 0 {
+		WriteError(w, http.StatusLengthRequired, "ErrorLengthRequired", "The Content-Length of the photo is required.")
+		return
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	contentType := r.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, "image/") {
+		WriteError(w, http.StatusBadRequest, "ErrorInvalidContentType", fmt.Sprintf("Invalid content type '%v' for a photo.", contentType))
+		return
+	}
+	if len(body) > 4*1024*1024 {
+		WriteError(w, http.StatusRequestEntityTooLarge, "ErrorRequestEntityTooLarge", "The photo must not exceed 4 MB.")
+		return
+	}
+	p, err := newPhoto(contentType, body)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "ImageProcessingFailed", fmt.Sprintf("The photo cannot be processed: %v", err))
+		return
+	}
+
+	s.mu.Lock()
+	owner, ok := s.findPhotoOwner(r)
+	if ok {
+		s.photos[owner.ID()] = p
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,475 @@
+package msgraphtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// route maps a HTTP method and resource path pattern to a handler, see Server.HandleFunc.
+type route struct {
+	method  string
+	pattern []string
+	handler http.HandlerFunc
+}
+
+// builtinRoute is a route for endpoints that are not served by the generic object store.
+type builtinRoute struct {
+	method  string
+	pattern string
+	handler func(s *Server, w http.ResponseWriter, r *http.Request)
+}
+
+// builtinRoutes lists all endpoints with special behaviour, hence everything that is not a
+// plain collection or object of the store.
+var builtinRoutes = []builtinRoute{
+	{http.MethodGet, "/groups/{id}/members", (*Server).serveMembers},
+	{http.MethodGet, "/groups/{id}/members/{type}", (*Server).serveMembers},
+	{http.MethodGet, "/groups/{id}/transitiveMembers", (*Server).serveTransitiveMembers},
+	{http.MethodGet, "/groups/{id}/transitiveMembers/{type}", (*Server).serveTransitiveMembers},
+	{http.MethodDelete, "/groups/{id}/members/{memberId}/$ref", (*Server).serveRemoveMember},
+	{http.MethodPost, "/groups", (*Server).serveCreateGroup},
+	{http.MethodPatch, "/groups/{id}", (*Server).serveUpdateGroup},
+	{http.MethodPost, "/groups/{id}/members/$ref", (*Server).serveAddReference},
+	{http.MethodGet, "/groups/{id}/owners", (*Server).serveOwners},
+	{http.MethodGet, "/groups/{id}/owners/{type}", (*Server).serveOwners},
+	{http.MethodPost, "/groups/{id}/owners/$ref", (*Server).serveAddReference},
+	{http.MethodDelete, "/groups/{id}/owners/{ownerId}/$ref", (*Server).serveRemoveOwner},
+	{http.MethodGet, "/users/{id}/memberOf", (*Server).serveMemberOf},
+	{http.MethodGet, "/users/{id}/memberOf/{type}", (*Server).serveMemberOf},
+	{http.MethodGet, "/users/{id}/transitiveMemberOf", (*Server).serveTransitiveMemberOf},
+	{http.MethodGet, "/users/{id}/transitiveMemberOf/{type}", (*Server).serveTransitiveMemberOf},
+	{http.MethodGet, "/groups/{id}/memberOf", (*Server).serveMemberOf},
+	{http.MethodGet, "/groups/{id}/memberOf/{type}", (*Server).serveMemberOf},
+	{http.MethodGet, "/groups/{id}/transitiveMemberOf", (*Server).serveTransitiveMemberOf},
+	{http.MethodGet, "/groups/{id}/transitiveMemberOf/{type}", (*Server).serveTransitiveMemberOf},
+	{http.MethodPost, "/directoryObjects/{id}/getMemberGroups", (*Server).serveGetMemberGroups},
+	{http.MethodPost, "/users/{id}/getMemberGroups", (*Server).serveGetMemberGroups},
+	{http.MethodPost, "/groups/{id}/getMemberGroups", (*Server).serveGetMemberGroups},
+	{http.MethodPost, "/directoryObjects/{id}/checkMemberGroups", (*Server).serveCheckMemberGroups},
+	{http.MethodPost, "/users/{id}/checkMemberGroups", (*Server).serveCheckMemberGroups},
+	{http.MethodPost, "/groups/{id}/checkMemberGroups", (*Server).serveCheckMemberGroups},
+	{http.MethodPost, "/directoryObjects/{id}/checkMemberObjects", (*Server).serveCheckMemberObjects},
+	{http.MethodPost, "/users/{id}/checkMemberObjects", (*Server).serveCheckMemberObjects},
+	{http.MethodPost, "/groups/{id}/checkMemberObjects", (*Server).serveCheckMemberObjects},
+	{http.MethodGet, "/users/{id}/calendar/calendarView", (*Server).serveCalendarView},
+	{http.MethodGet, "/users/{id}/calendar/events", (*Server).serveEvents},
+	{http.MethodGet, "/users/{id}/outlook/supportedTimeZones", (*Server).serveSupportedTimeZones},
+	{http.MethodGet, "/users/{id}", (*Server).serveUser},
+	{http.MethodGet, "/users/{id}/manager", (*Server).serveManager},
+	{http.MethodPut, "/users/{id}/manager/$ref", (*Server).serveSetManager},
+	{http.MethodDelete, "/users/{id}/manager/$ref", (*Server).serveRemoveManager},
+	{http.MethodGet, "/users/{id}/directReports", (*Server).serveDirectReports},
+	{http.MethodGet, "/users/{id}/directReports/{type}", (*Server).serveDirectReports},
+	{http.MethodPost, "/users/{id}/assignLicense", (*Server).serveAssignLicense},
+	{http.MethodGet, "/users/{id}/licenseDetails", (*Server).serveLicenseDetails},
+	{http.MethodPost, "/users/{id}/revokeSignInSessions", (*Server).serveRevokeSignInSessions},
+	{http.MethodGet, "/users/{id}/mailboxSettings", (*Server).serveMailboxSettings},
+	{http.MethodPatch, "/users/{id}/mailboxSettings", (*Server).serveUpdateMailboxSettings},
+	{http.MethodGet, "/users/{id}/authentication/methods", (*Server).serveAuthenticationMethods},
+	{http.MethodPost, "/users/{id}/authentication/phoneMethods", (*Server).serveAddPhoneMethod},
+	{http.MethodPost, "/users/{id}/authentication/temporaryAccessPassMethods", (*Server).serveCreateTemporaryAccessPass},
+	{http.MethodPost, "/users/{id}/authentication/methods/{methodId}/resetPassword", (*Server).serveResetPassword},
+	{http.MethodDelete, "/users/{id}/authentication/{type}/{methodId}", (*Server).serveRemoveAuthenticationMethod},
+	{http.MethodGet, "/users/{id}/photo", (*Server).servePhotoMetadata},
+	{http.MethodGet, "/users/{id}/photo/$value", (*Server).servePhotoContent},
+	{http.MethodPut, "/users/{id}/photo/$value", (*Server).serveSetPhoto},
+	{http.MethodGet, "/users/{id}/photos", (*Server).serveListPhotos},
+	{http.MethodGet, "/users/{id}/photos/{size}", (*Server).servePhotoMetadata},
+	{http.MethodGet, "/users/{id}/photos/{size}/$value", (*Server).servePhotoContent},
+	{http.MethodGet, "/groups/{id}/photo", (*Server).servePhotoMetadata},
+	{http.MethodGet, "/groups/{id}/photo/$value", (*Server).servePhotoContent},
+	{http.MethodPut, "/groups/{id}/photo/$value", (*Server).serveSetPhoto},
+	{http.MethodGet, "/groups/{id}/photos", (*Server).serveListPhotos},
+	{http.MethodGet, "/groups/{id}/photos/{size}", (*Server).servePhotoMetadata},
+	{http.MethodGet, "/groups/{id}/photos/{size}/$value", (*Server).servePhotoContent},
+	{http.MethodGet, "/groups/{id}/conversations", (*Server).serveConversations},
+	{http.MethodPost, "/groups/{id}/conversations", (*Server).serveCreateConversation},
+	{http.MethodGet, "/groups/{id}/conversations/{conversationId}/threads", (*Server).serveConversationThreads},
+	{http.MethodGet, "/groups/{id}/threads", (*Server).serveThreads},
+	{http.MethodPost, "/groups/{id}/threads/{threadId}/reply", (*Server).serveReplyThread},
+	{http.MethodPost, "/groups/{id}/threads/{threadId}/posts/{postId}/reply", (*Server).serveReplyPost},
+	{http.MethodPost, "/teams", (*Server).serveCreateTeam},
+	{http.MethodGet, "/teams/{id}/operations/{operationId}", (*Server).serveTeamsAsyncOperation},
+	{http.MethodGet, "/teams/{id}/members", (*Server).serveTeamMembers},
+	{http.MethodPost, "/teams/{id}/channels", (*Server).serveCreateChannel},
+	{http.MethodPatch, "/teams/{id}/channels/{channelId}", (*Server).serveUpdateChannel},
+	{http.MethodDelete, "/teams/{id}/channels/{channelId}", (*Server).serveDeleteChannel},
+	{http.MethodGet, "/teams/{id}/channels/{channelId}/members", (*Server).serveChannelMembers},
+	{http.MethodPost, "/teams/{id}/channels/{channelId}/members", (*Server).serveAddChannelMember},
+	{http.MethodPost, "/invitations", (*Server).serveInvitation},
+	{http.MethodPost, "/users/{id}/extensions", (*Server).serveCreateExtension},
+	{http.MethodPatch, "/users/{id}/extensions/{name}", (*Server).serveUpdateExtension},
+	{http.MethodPost, "/groups/{id}/extensions", (*Server).serveCreateExtension},
+	{http.MethodPatch, "/groups/{id}/extensions/{name}", (*Server).serveUpdateExtension},
+	{http.MethodGet, "/directory/deletedItems/{id}", (*Server).serveDeletedItem},
+	{http.MethodPost, "/directory/deletedItems/{id}/restore", (*Server).serveRestoreDeletedItem},
+	{http.MethodDelete, "/directory/deletedItems/{id}", (*Server).servePermanentlyDeleteItem},
+	{http.MethodPost, "/subscriptions", (*Server).serveCreateSubscription},
+	{http.MethodPatch, "/subscriptions/{id}", (*Server).serveUpdateSubscription},
+}
+
+// pathParamsKey is the context key for the path parameters of a matched route.
+type pathParamsKey struct{}
+
+// PathParam returns the value of the path segment named by the given parameter of the route
+// pattern, e.g. "id" for "/users/{id}/manager". Returns an empty string if there is none.
+func PathParam(r *http.Request, name string) string {
+	params, _ := r.Context().Value(pathParamsKey{}).(map[string]string)
+	return params[name]
+}
+
+// findRoute returns the handler and path parameters of the first custom or built-in route
+// that matches the given method and path, or a nil handler if there is none.
+func (s *Server) findRoute(method, path string) (http.HandlerFunc, map[string]string) {
+	segments := splitPath(path)
+
+	s.mu.Lock()
+	routes := append([]route(nil), s.routes...)
+	s.mu.Unlock()
+	for _, rt := range routes {
+		if params, ok := matchPattern(rt.pattern, segments); ok && rt.method == method {
+			return rt.handler, params
+		}
+	}
+
+	for _, rt := range builtinRoutes {
+		if params, ok := matchPattern(splitPath(rt.pattern), segments); ok && rt.method == method {
+			handler := rt.handler
+			return func(w http.ResponseWriter, r *http.Request) { handler(s, w, r) }, params
+		}
+	}
+	return nil, nil
+}
+
+// matchPattern matches the path segments against the pattern segments. Path segments are
+// compared case-insensitively, like Microsoft Graph does.
+func matchPattern(pattern, segments []string) (map[string]string, bool) {
+	if len(pattern) != len(segments) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, p := range pattern {
+		if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+			params[strings.Trim(p, "{}")] = segments[i]
+			continue
+		}
+		if !strings.EqualFold(p, segments[i]) {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// serveMembers serves the direct members of a group, optionally of the type of a type cast.
+func (s *Server) serveMembers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	group, ok := s.find("/groups", PathParam(r, "id"))
+	var members []Object
+	if ok {
+		for _, memberID := range s.members[group.ID()] {
+			if member, found := s.findDirectoryObject(memberID); found {
+				members = append(members, member)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	s.writeCastCollection(w, r, members, false)
+}
+
+// serveTransitiveMembers serves all direct and nested members of a group, optionally of the
+// type of a type cast.
+func (s *Server) serveTransitiveMembers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	group, ok := s.find("/groups", PathParam(r, "id"))
+	var members []Object
+	if ok {
+		visited := map[string]bool{group.ID(): true}
+		queue := append([]string(nil), s.members[group.ID()]...)
+		for len(queue) > 0 {
+			memberID := queue[0]
+			queue = queue[1:]
+			if visited[memberID] {
+				continue
+			}
+			visited[memberID] = true
+			if member, found := s.findDirectoryObject(memberID); found {
+				members = append(members, member)
+			}
+			queue = append(queue, s.members[memberID]...)
+		}
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	s.writeCastCollection(w, r, members, true)
+}
+
+// serveRemoveMember removes a direct member from a group.
+func (s *Server) serveRemoveMember(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	group, ok := s.find("/groups", PathParam(r, "id"))
+	removed, dynamic := false, ok && isDynamic(group)
+	if ok && !dynamic {
+		memberIDs := s.members[group.ID()]
+		for i, memberID := range memberIDs {
+			if memberID == PathParam(r, "memberId") {
+				s.members[group.ID()] = append(memberIDs[:i:i], memberIDs[i+1:]...)
+				removed = true
+				break
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	if dynamic {
+		WriteError(w, http.StatusBadRequest, "Request_BadRequest", errDynamicMembers)
+		return
+	}
+	if !removed {
+		writeNotFound(w, PathParam(r, "memberId"))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serveMemberOf serves the groups a user or group is a direct member of, optionally of the
+// type given by a type cast.
+func (s *Server) serveMemberOf(w http.ResponseWriter, r *http.Request) {
+	s.serveMemberGroups(w, r, false)
+}
+
+// serveTransitiveMemberOf serves the groups a user or group is a direct or nested member of,
+// optionally of the type given by a type cast.
+func (s *Server) serveTransitiveMemberOf(w http.ResponseWriter, r *http.Request) {
+	s.serveMemberGroups(w, r, true)
+}
+
+// serveMemberGroups serves the groups the directory object is a member of. Directory roles
+// and administrative units are not faked.
+func (s *Server) serveMemberGroups(w http.ResponseWriter, r *http.Request, transitive bool) {
+	s.mu.Lock()
+	object, ok := s.findDirectoryObject(PathParam(r, "id"))
+	var groups []Object
+	if ok {
+		for _, groupID := range s.memberOf(object.ID(), transitive) {
+			if group, found := s.find("/groups", groupID); found {
+				groups = append(groups, group)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	s.writeCastCollection(w, r, groups, transitive)
+}
+
+// serveGetMemberGroups serves the IDs of all groups the directory object is a direct or
+// nested member of.
+func (s *Server) serveGetMemberGroups(w http.ResponseWriter, r *http.Request) {
+	var post struct {
+		SecurityEnabledOnly bool `json:"securityEnabledOnly"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&post); err != nil {
+		WriteError(w, http.StatusBadRequest, "Request_BadRequest", fmt.Sprintf("cannot parse request body: %v", err))
+		return
+	}
+
+	s.mu.Lock()
+	object, ok := s.findDirectoryObject(PathParam(r, "id"))
+	var groupIDs []string
+	if ok {
+		for _, groupID := range s.memberOf(object.ID(), true) {
+			if group, found := s.find("/groups", groupID); found {
+				if enabled, _ := group["securityEnabled"].(bool); enabled || !post.SecurityEnabledOnly {
+					groupIDs = append(groupIDs, groupID)
+				}
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	if groupIDs == nil {
+		groupIDs = []string{}
+	}
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"value": groupIDs})
+}
+
+// maxCheckMemberIDs is the maximum number of IDs checkMemberGroups and checkMemberObjects
+// accept per request.
+const maxCheckMemberIDs = 20
+
+// serveCheckMemberGroups serves the IDs of the given groups the directory object is a direct or
+// transitive member of.
+func (s *Server) serveCheckMemberGroups(w http.ResponseWriter, r *http.Request) {
+	s.serveCheckMember(w, r, "groupIds")
+}
+
+// serveCheckMemberObjects serves the IDs of the given groups the directory object is a direct or
+// transitive member of. Directory roles and administrative units are not faked, hence only
+// groups are checked.
+func (s *Server) serveCheckMemberObjects(w http.ResponseWriter, r *http.Request) {
+	s.serveCheckMember(w, r, "ids")
+}
+
+// serveCheckMember serves the IDs of the request body property that are groups the directory
+// object is a direct or transitive member of.
+func (s *Server) serveCheckMember(w http.ResponseWriter, r *http.Request, property string) {
+	var post map[string][]string
+	if err := json.NewDecoder(r.Body).Decode(&post); err != nil {
+		WriteError(w, http.StatusBadRequest, "Request_BadRequest", fmt.Sprintf("cannot parse request body: %v", err))
+		return
+	}
+	ids, ok := post[property]
+	if !ok {
+		WriteError(w, http.StatusBadRequest, "Request_BadRequest", fmt.Sprintf("The parameter '%v' is required.", property))
+		return
+	}
+	if len(ids) > maxCheckMemberIDs {
+		WriteError(w, http.StatusBadRequest, "Request_BadRequest", fmt.Sprintf("At most %v IDs can be checked in one request.", maxCheckMemberIDs))
+		return
+	}
+
+	s.mu.Lock()
+	object, ok := s.findDirectoryObject(PathParam(r, "id"))
+	var memberOf []string
+	if ok {
+		memberOf = s.memberOf(object.ID(), true)
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	var value = []string{}
+	for _, id := range ids {
+		if containsID(memberOf, id) && !containsID(value, id) {
+			value = append(value, id)
+		}
+	}
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"value": value})
+}
+
+// serveCalendarView serves all events of the user that overlap with the time range given by
+// the query parameters startDateTime and endDateTime.
+func (s *Server) serveCalendarView(w http.ResponseWriter, r *http.Request) {
+	start, errStart := parseDateTime(queryValue(r, "startDateTime"), "UTC")
+	end, errEnd := parseDateTime(queryValue(r, "endDateTime"), "UTC")
+	if errStart != nil || errEnd != nil {
+		WriteError(w, http.StatusBadRequest, "ErrorInvalidParameter", "This request requires a time window specified by the query string parameters StartDateTime and EndDateTime.")
+		return
+	}
+
+	s.mu.Lock()
+	user, ok := s.find("/users", PathParam(r, "id"))
+	var events []Object
+	if ok {
+		for _, event := range s.collections["/users/"+user.ID()+"/events"] {
+			eventStart, errEventStart := parseEventDateTime(event["start"])
+			eventEnd, errEventEnd := parseEventDateTime(event["end"])
+			if errEventStart != nil || errEventEnd != nil || (eventStart.Before(end) && eventEnd.After(start)) {
+				events = append(events, event)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	s.writeCollection(w, r, events)
+}
+
+// serveEvents serves all events of the default calendar of the user.
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	user, ok := s.find("/users", PathParam(r, "id"))
+	var events []Object
+	if ok {
+		events = s.collections["/users/"+user.ID()+"/events"]
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	s.writeCollection(w, r, events)
+}
+
+// serveSupportedTimeZones serves a fixed subset of the time zones supported by Exchange Online.
+func (s *Server) serveSupportedTimeZones(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	_, ok := s.find("/users", PathParam(r, "id"))
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"value": supportedTimeZones})
+}
+
+// supportedTimeZones is served by serveSupportedTimeZones. Use the alias as originalStartTimeZone
+// and originalEndTimeZone of events.
+var supportedTimeZones = []map[string]string{
+	{"alias": "UTC", "displayName": "(UTC) Coordinated Universal Time"},
+	{"alias": "W. Europe Standard Time", "displayName": "(UTC+01:00) Amsterdam, Berlin, Bern, Rome, Stockholm, Vienna"},
+	{"alias": "Eastern Standard Time", "displayName": "(UTC-05:00) Eastern Time (US & Canada)"},
+	{"alias": "Pacific Standard Time", "displayName": "(UTC-08:00) Pacific Time (US & Canada)"},
+}
+
+// queryValue returns the value of the query parameter with the given name, compared
+// case-insensitively like Microsoft Graph does.
+func queryValue(r *http.Request, name string) string {
+	for key, values := range r.URL.Query() {
+		if strings.EqualFold(key, name) && len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// parseEventDateTime parses a dateTimeTimeZone value of an event, e.g. the start property.
+func parseEventDateTime(v interface{}) (time.Time, error) {
+	dateTimeTimeZone, _ := v.(map[string]interface{})
+	dateTime, _ := dateTimeTimeZone["dateTime"].(string)
+	timeZone, _ := dateTimeTimeZone["timeZone"].(string)
+	return parseDateTime(dateTime, timeZone)
+}
+
+// parseDateTime parses the given date and time without offset in the given IANA time zone.
+// Falls back to UTC if the time zone is unknown.
+func parseDateTime(dateTime, timeZone string) (time.Time, error) {
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	if t, err := time.Parse(time.RFC3339Nano, dateTime); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02T15:04:05.9999999", dateTime, loc)
+}
@@ -0,0 +1,221 @@
+package msgraphtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// subscriptionValidationTimeout is the time the notification endpoint has to answer the
+// validation request, like Microsoft Graph enforces.
+const subscriptionValidationTimeout = 10 * time.Second
+
+// serveCreateSubscription validates the subscription and its notification endpoints like
+// Microsoft Graph does, hence sends a validationToken to the notificationUrl and the
+// lifecycleNotificationUrl, and stores it in /subscriptions.
+func (s *Server) serveCreateSubscription(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	subscription, err := decodeObject(body)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		return
+	}
+	for _, property := range []string{"changeType", "notificationUrl", "resource", "expirationDateTime"} {
+		if value, _ := subscription[property].(string); value == "" {
+			WriteError(w, http.StatusBadRequest, "InvalidRequest", fmt.Sprintf("Property '%v' is required when creating a subscription.", property))
+			return
+		}
+	}
+	if err := validateExpiration(subscription); err != nil {
+		WriteError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		return
+	}
+	for _, property := range []string{"notificationUrl", "lifecycleNotificationUrl"} {
+		if endpoint, _ := subscription[property].(string); endpoint != "" {
+			if err := validateNotificationEndpoint(r.Context(), endpoint); err != nil {
+				WriteError(w, http.StatusBadRequest, "InvalidRequest", fmt.Sprintf("Subscription validation request failed. %v", err))
+				return
+			}
+		}
+	}
+
+	s.mu.Lock()
+	subscription["applicationId"] = s.ApplicationID
+	subscription["creatorId"] = s.ApplicationID
+	obj := s.insert("/subscriptions", subscription).copy()
+	s.mu.Unlock()
+	WriteJSON(w, http.StatusCreated, obj)
+}
+
+// serveUpdateSubscription renews the subscription with a new expirationDateTime and
+// responds with the updated subscription.
+func (s *Server) serveUpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	patch, err := decodeObject(body)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		return
+	}
+	if err := validateExpiration(patch); err != nil {
+		WriteError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	subscription, ok := s.find("/subscriptions", PathParam(r, "id"))
+	var obj Object
+	if ok {
+		subscription["expirationDateTime"] = patch["expirationDateTime"]
+		obj = subscription.copy()
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	WriteJSON(w, http.StatusOK, obj)
+}
+
+// validateExpiration returns an error if the expirationDateTime of the subscription is
+// missing or in the past.
+func validateExpiration(subscription Object) error {
+	value, _ := subscription["expirationDateTime"].(string)
+	expiration, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return fmt.Errorf("Invalid expirationDateTime '%v'.", value)
+	}
+	if !expiration.After(time.Now()) {
+		return fmt.Errorf("Subscription expiration can only be in the future.")
+	}
+	return nil
+}
+
+// validateNotificationEndpoint sends a validationToken to the endpoint, which must respond
+// with status 200 and the token as plain text body within subscriptionValidationTimeout.
+//
+// See https://docs.microsoft.com/en-us/graph/webhooks#notification-endpoint-validation
+func validateNotificationEndpoint(ctx context.Context, endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("Invalid notification url '%v'.", endpoint)
+	}
+	token := fmt.Sprintf("Validation: Testing client application reachability for subscription Request-Id: %v", time.Now().UnixNano())
+	query := u.Query()
+	query.Set("validationToken", token)
+	u.RawQuery = query.Encode()
+
+	ctx, cancel := context.WithTimeout(ctx, subscriptionValidationTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Notification endpoint must respond with 200 OK to validation request: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Notification endpoint must respond with 200 OK to validation request, got %v.", resp.StatusCode)
+	}
+	if string(body) != token {
+		return fmt.Errorf("Notification endpoint must respond with the validationToken as body.")
+	}
+	return nil
+}
+
+// Notify sends a change notification for the resource with the given ID to the
+// notificationUrl of the subscription with the given ID, like Microsoft Graph does if the
+// resource changes. changeType is e.g. "updated". Returns an error if the subscription does
+// not exist or the endpoint does not acknowledge the notification with a 2xx status.
+func (s *Server) Notify(subscriptionID, changeType, resourceID string) error {
+	s.mu.Lock()
+	subscription, ok := s.find("/subscriptions", subscriptionID)
+	var notification Object
+	if ok {
+		subscription = subscription.copy()
+		notification = s.newNotification(subscription)
+	}
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("msgraphtest: subscription %v does not exist", subscriptionID)
+	}
+
+	resource, _ := subscription["resource"].(string)
+	resource = strings.Trim(resource, "/")
+	if idx := strings.Index(resource, "?"); idx >= 0 {
+		resource = resource[:idx]
+	}
+	notification["changeType"] = changeType
+	notification["resource"] = resource + "/" + resourceID
+	notification["resourceData"] = map[string]interface{}{
+		"@odata.type": odataTypes["/"+resource],
+		"@odata.id":   resource + "/" + resourceID,
+		"id":          resourceID,
+	}
+	endpoint, _ := subscription["notificationUrl"].(string)
+	return postNotifications(endpoint, notification)
+}
+
+// NotifyLifecycle sends a lifecycle notification, e.g. "reauthorizationRequired", to the
+// lifecycleNotificationUrl of the subscription with the given ID, or to its notificationUrl
+// if there is none. Returns an error if the subscription does not exist or the endpoint does
+// not acknowledge the notification with a 2xx status.
+func (s *Server) NotifyLifecycle(subscriptionID, lifecycleEvent string) error {
+	s.mu.Lock()
+	subscription, ok := s.find("/subscriptions", subscriptionID)
+	var notification Object
+	if ok {
+		subscription = subscription.copy()
+		notification = s.newNotification(subscription)
+	}
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("msgraphtest: subscription %v does not exist", subscriptionID)
+	}
+
+	notification["lifecycleEvent"] = lifecycleEvent
+	endpoint, _ := subscription["lifecycleNotificationUrl"].(string)
+	if endpoint == "" {
+		endpoint, _ = subscription["notificationUrl"].(string)
+	}
+	return postNotifications(endpoint, notification)
+}
+
+// newNotification returns a notification with the properties common to change and lifecycle
+// notifications of the subscription. s.mu must be held.
+func (s *Server) newNotification(subscription Object) Object {
+	return Object{
+		"id":                             s.newID(),
+		"subscriptionId":                 subscription.ID(),
+		"subscriptionExpirationDateTime": subscription["expirationDateTime"],
+		"clientState":                    subscription["clientState"],
+		"tenantId":                       s.TenantID,
+	}
+}
+
+// postNotifications sends the notifications as a batch to the endpoint.
+func postNotifications(endpoint string, notifications ...Object) error {
+	body, err := json.Marshal(map[string]interface{}{"value": notifications})
+	if err != nil {
+		return err
+	}
+	resp, err := http.Post(endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("msgraphtest: cannot send notification: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("msgraphtest: notification endpoint responded with %v", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,415 @@
+package msgraphtest
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// generalChannelName is the display name of the channel every team has.
+const generalChannelName = "General"
+
+// teamsTemplateStandard is the binding of the standard template of new teams.
+const teamsTemplateStandard = "teamsTemplates('standard')"
+
+// entityKeyPattern matches a binding by entity key, e.g. groups('{id}') or users('{id}').
+var entityKeyPattern = regexp.MustCompile(`(groups|users)\('([^']+)'\)$`)
+
+// bindKey returns the key of the given entity set bound via annotation, e.g. the group ID of
+// group@odata.bind: "https://graph.microsoft.com/v1.0/groups('{id}')".
+func bindKey(obj map[string]interface{}, annotation, entitySet string) string {
+	bind, _ := obj[annotation].(string)
+	match := entityKeyPattern.FindStringSubmatch(bind)
+	if match == nil || match[1] != entitySet {
+		return ""
+	}
+	return match[2]
+}
+
+// channelsCollection returns the collection of all channels of the team.
+func channelsCollection(teamID string) string {
+	return "/teams/" + teamID + "/channels"
+}
+
+// newChannelID returns a new unique channel ID in the format Microsoft Teams uses. s.mu must be held.
+func (s *Server) newChannelID() string {
+	return "19:" + strings.Replace(s.newID(), "-", "", -1) + "@thread.tacv2"
+}
+
+// serveCreateTeam creates the team of a Microsoft 365 group asynchronously like Microsoft
+// Graph does: the response only contains the Location of the operation, which reports
+// inProgress when it is polled the first time and succeeded afterwards. Teams of groups that
+// are no Microsoft 365 groups fail.
+func (s *Server) serveCreateTeam(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	team, err := decodeObject(body)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+	groupID := bindKey(team, "group@odata.bind", "groups")
+	if template, _ := team["template@odata.bind"].(string); !strings.HasSuffix(template, teamsTemplateStandard) || groupID == "" {
+		WriteError(w, http.StatusBadRequest, "BadRequest", "A team requires template@odata.bind and group@odata.bind.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	group, ok := s.find("/groups", groupID)
+	if !ok {
+		writeNotFound(w, groupID)
+		return
+	}
+	if _, ok := s.find("/teams", group.ID()); ok {
+		WriteError(w, http.StatusConflict, "Conflict", "Team already exists.")
+		return
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	operation := Object{
+		"id":                     s.newID(),
+		"operationType":          "createTeam",
+		"createdDateTime":        now,
+		"lastActionDateTime":     now,
+		"attemptsCount":          1,
+		"status":                 "inProgress",
+		"targetResourceId":       group.ID(),
+		"targetResourceLocation": "/teams('" + group.ID() + "')",
+		"error":                  nil,
+	}
+	if groupTypes, _ := group["groupTypes"].([]interface{}); containsValue(groupTypes, "Unified") {
+		delete(team, "template@odata.bind")
+		delete(team, "group@odata.bind")
+		team["id"] = group.ID()
+		team["displayName"] = group["displayName"]
+		team["visibility"] = group["visibility"]
+		team["webUrl"] = "https://teams.microsoft.com/l/team/" + group.ID()
+		team["isArchived"] = false
+		s.insert("/teams", team)
+		s.insert(channelsCollection(group.ID()), Object{
+			"id":              s.newChannelID(),
+			"displayName":     generalChannelName,
+			"description":     team["description"],
+			"membershipType":  "standard",
+			"createdDateTime": now,
+		})
+	} else {
+		operation["status"] = "failed"
+		operation["error"] = map[string]interface{}{"code": "BadRequest", "message": "Teams can only be created for Microsoft 365 groups."}
+	}
+	s.insert("/teams/"+group.ID()+"/operations", operation)
+
+	w.Header().Set("Location", fmt.Sprintf("/teams('%v')/operations('%v')", group.ID(), operation.ID()))
+	w.Header().Set("Content-Location", fmt.Sprintf("/teams('%v')", group.ID()))
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// serveTeamsAsyncOperation serves the status of a teams async operation, an operation in
+// progress succeeds after it has been polled once.
+func (s *Server) serveTeamsAsyncOperation(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	operation, ok := s.find("/teams/"+PathParam(r, "id")+"/operations", PathParam(r, "operationId"))
+	var served Object
+	if ok {
+		served = operation.copy()
+		if operation["status"] == "inProgress" {
+			operation["status"] = "succeeded"
+			operation["lastActionDateTime"] = time.Now().UTC().Format(time.RFC3339)
+		}
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "operationId"))
+		return
+	}
+	s.writeObject(w, r, served)
+}
+
+// teamMember returns the conversation member of the user with the given roles. s.mu must be held.
+func teamMember(membershipID string, user Object, roles []interface{}) Object {
+	email, _ := user["mail"].(string)
+	if email == "" {
+		email, _ = user["userPrincipalName"].(string)
+	}
+	if userType, _ := user["userType"].(string); userType == "Guest" && !containsValue(roles, "guest") {
+		roles = append(roles, "guest")
+	}
+	return Object{
+		"@odata.type":                 "#microsoft.graph.aadUserConversationMember",
+		"id":                          membershipID,
+		"displayName":                 user["displayName"],
+		"roles":                       roles,
+		"userId":                      user.ID(),
+		"email":                       email,
+		"visibleHistoryStartDateTime": "0001-01-01T00:00:00Z",
+	}
+}
+
+// teamMembers returns the users that are owners or members of the group of the team as
+// conversation members. s.mu must be held.
+func (s *Server) teamMembers(teamID string) []Object {
+	var members []Object
+	var userIDs []string
+	for _, id := range append(append([]string(nil), s.owners[teamID]...), s.members[teamID]...) {
+		if !containsID(userIDs, id) {
+			userIDs = append(userIDs, id)
+		}
+	}
+	for _, userID := range userIDs {
+		user, ok := s.find("/users", userID)
+		if !ok {
+			continue
+		}
+		roles := []interface{}{}
+		if containsID(s.owners[teamID], userID) {
+			roles = append(roles, "owner")
+		}
+		membershipID := base64.RawURLEncoding.EncodeToString([]byte(teamID + "##" + userID))
+		members = append(members, teamMember(membershipID, user, roles))
+	}
+	return members
+}
+
+// serveTeamMembers serves the members of a team, hence the owners and members of its group.
+func (s *Server) serveTeamMembers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	team, ok := s.find("/teams", PathParam(r, "id"))
+	var members []Object
+	if ok {
+		members = s.teamMembers(team.ID())
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	s.writeCollection(w, r, members)
+}
+
+// findChannel returns the team and the channel of the request, the error response is written
+// if either does not exist. s.mu must be held.
+func (s *Server) findChannel(w http.ResponseWriter, r *http.Request) (Object, Object, bool) {
+	team, ok := s.find("/teams", PathParam(r, "id"))
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return nil, nil, false
+	}
+	channel, ok := s.find(channelsCollection(team.ID()), PathParam(r, "channelId"))
+	if !ok {
+		writeNotFound(w, PathParam(r, "channelId"))
+		return nil, nil, false
+	}
+	return team, channel, true
+}
+
+// channelNameExists returns true if another channel of the team has the display name,
+// compared case-insensitively. s.mu must be held.
+func (s *Server) channelNameExists(teamID, channelID, displayName string) bool {
+	for _, channel := range s.collections[channelsCollection(teamID)] {
+		if name, _ := channel["displayName"].(string); channel.ID() != channelID && strings.EqualFold(name, displayName) {
+			return true
+		}
+	}
+	return false
+}
+
+// serveCreateChannel creates a channel with its members. Private and shared channels require
+// an owner, standard channels cannot have members.
+func (s *Server) serveCreateChannel(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	channel, err := decodeObject(body)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+	displayName, _ := channel["displayName"].(string)
+	if displayName == "" {
+		WriteError(w, http.StatusBadRequest, "BadRequest", "The displayName of the channel is required.")
+		return
+	}
+	if _, ok := channel["membershipType"]; !ok {
+		channel["membershipType"] = "standard"
+	}
+	membershipType, _ := channel["membershipType"].(string)
+	if membershipType != "standard" && membershipType != "private" && membershipType != "shared" {
+		WriteError(w, http.StatusBadRequest, "BadRequest", fmt.Sprintf("Invalid membershipType '%v'.", membershipType))
+		return
+	}
+	members, _ := channel["members"].([]interface{})
+	delete(channel, "members")
+	if membershipType == "standard" && len(members) > 0 {
+		WriteError(w, http.StatusBadRequest, "BadRequest", "Members can only be added to private and shared channels.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	team, ok := s.find("/teams", PathParam(r, "id"))
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	if s.channelNameExists(team.ID(), "", displayName) {
+		WriteError(w, http.StatusBadRequest, "BadRequest", fmt.Sprintf("Channel name already existed, please use other name: %v", displayName))
+		return
+	}
+	var newMembers []Object
+	hasOwner := false
+	for _, m := range members {
+		member, _ := m.(map[string]interface{})
+		user, code, message := s.channelMemberUser(team, member)
+		if code != 0 {
+			WriteError(w, code, "BadRequest", message)
+			return
+		}
+		roles, _ := member["roles"].([]interface{})
+		hasOwner = hasOwner || containsValue(roles, "owner")
+		newMembers = append(newMembers, teamMember("", user, roles))
+	}
+	if membershipType != "standard" && !hasOwner {
+		WriteError(w, http.StatusBadRequest, "BadRequest", "An owner is required to create a private or shared channel with application permissions.")
+		return
+	}
+
+	channel["id"] = s.newChannelID()
+	channel["createdDateTime"] = time.Now().UTC().Format(time.RFC3339)
+	channel["webUrl"] = "https://teams.microsoft.com/l/channel/" + channel.ID()
+	created := s.insert(channelsCollection(team.ID()), channel)
+	for _, member := range newMembers {
+		delete(member, "id")
+		s.insert(channelsCollection(team.ID())+"/"+created.ID()+"/members", member)
+	}
+	WriteJSON(w, http.StatusCreated, created.copy())
+}
+
+// channelMemberUser returns the user bound via user@odata.bind of the member to add to a
+// channel, or the status code and message of the error response. Members of a channel must
+// be members of the team and have no roles except owner. s.mu must be held.
+func (s *Server) channelMemberUser(team Object, member map[string]interface{}) (Object, int, string) {
+	userID := bindKey(member, "user@odata.bind", "users")
+	user, ok := s.find("/users", userID)
+	if !ok {
+		return nil, http.StatusNotFound, fmt.Sprintf("User '%v' not found.", userID)
+	}
+	if !containsID(s.members[team.ID()], user.ID()) && !containsID(s.owners[team.ID()], user.ID()) {
+		return nil, http.StatusBadRequest, fmt.Sprintf("User '%v' is not a member of the team.", user.ID())
+	}
+	roles, _ := member["roles"].([]interface{})
+	for _, role := range roles {
+		if role != "owner" {
+			return nil, http.StatusBadRequest, fmt.Sprintf("Invalid role '%v'.", role)
+		}
+	}
+	return user, 0, ""
+}
+
+// serveUpdateChannel updates a channel, the General channel cannot be renamed.
+func (s *Server) serveUpdateChannel(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	patch, err := decodeObject(body)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	team, channel, ok := s.findChannel(w, r)
+	if !ok {
+		return
+	}
+	if displayName, ok := patch["displayName"].(string); ok {
+		if channel["displayName"] == generalChannelName && displayName != generalChannelName {
+			WriteError(w, http.StatusBadRequest, "BadRequest", "The General channel cannot be renamed.")
+			return
+		}
+		if s.channelNameExists(team.ID(), channel.ID(), displayName) {
+			WriteError(w, http.StatusBadRequest, "BadRequest", fmt.Sprintf("Channel name already existed, please use other name: %v", displayName))
+			return
+		}
+	}
+	for _, readOnly := range []string{"id", "membershipType", "email", "webUrl", "createdDateTime"} {
+		delete(patch, readOnly)
+	}
+	for key, value := range patch {
+		channel[key] = value
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serveDeleteChannel deletes a channel and its members, the General channel cannot be deleted.
+func (s *Server) serveDeleteChannel(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	team, channel, ok := s.findChannel(w, r)
+	if !ok {
+		return
+	}
+	if channel["displayName"] == generalChannelName {
+		WriteError(w, http.StatusBadRequest, "BadRequest", "The General channel cannot be deleted.")
+		return
+	}
+	s.remove(channelsCollection(team.ID()), s.indexOf(channelsCollection(team.ID()), channel.ID()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serveChannelMembers serves the members of a channel, the members of a standard channel are
+// the members of the team.
+func (s *Server) serveChannelMembers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	team, channel, ok := s.findChannel(w, r)
+	var members []Object
+	if ok {
+		if channel["membershipType"] == "standard" {
+			members = s.teamMembers(team.ID())
+		} else {
+			members = s.collections[channelsCollection(team.ID())+"/"+channel.ID()+"/members"]
+		}
+	}
+	s.mu.Unlock()
+
+	if ok {
+		s.writeCollection(w, r, members)
+	}
+}
+
+// serveAddChannelMember adds a member to a private or shared channel.
+func (s *Server) serveAddChannelMember(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	member, err := decodeObject(body)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	team, channel, ok := s.findChannel(w, r)
+	if !ok {
+		return
+	}
+	if channel["membershipType"] == "standard" {
+		WriteError(w, http.StatusBadRequest, "BadRequest", "Members can only be added to private and shared channels.")
+		return
+	}
+	user, code, message := s.channelMemberUser(team, member)
+	if code != 0 {
+		WriteError(w, code, "BadRequest", message)
+		return
+	}
+	collection := channelsCollection(team.ID()) + "/" + channel.ID() + "/members"
+	for _, existing := range s.collections[collection] {
+		if existing["userId"] == user.ID() {
+			WriteError(w, http.StatusConflict, "Conflict", fmt.Sprintf("User '%v' is already a member of the channel.", user.ID()))
+			return
+		}
+	}
+	roles, _ := member["roles"].([]interface{})
+	created := s.insert(collection, teamMember(s.newID(), user, roles))
+	WriteJSON(w, http.StatusCreated, created.copy())
+}
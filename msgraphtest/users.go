@@ -0,0 +1,159 @@
+package msgraphtest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// serveRevokeSignInSessions sets signInSessionsValidFromDateTime of a user to now.
+func (s *Server) serveRevokeSignInSessions(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	user, ok := s.find("/users", PathParam(r, "id"))
+	if ok {
+		user["signInSessionsValidFromDateTime"] = time.Now().UTC().Format(time.RFC3339)
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"value": true})
+}
+
+// MailboxSettings returns a copy of the mailbox settings of the user with the given ID or
+// userPrincipalName, nil if the user has none.
+func (s *Server) MailboxSettings(userID string) Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if user, ok := s.find("/users", userID); ok {
+		userID = user.ID()
+	}
+	if settings, ok := s.mailboxSettings[userID]; ok {
+		return settings.copy()
+	}
+	return nil
+}
+
+// serveMailboxSettings serves the mailbox settings of a user.
+func (s *Server) serveMailboxSettings(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	user, ok := s.find("/users", PathParam(r, "id"))
+	var settings Object
+	if ok {
+		settings = s.userMailboxSettings(user.ID()).copy()
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	s.writeObject(w, r, settings)
+}
+
+// serveUpdateMailboxSettings merges the JSON body into the mailbox settings of a user and
+// responds with the updated settings. Nested objects like automaticRepliesSetting are merged too.
+func (s *Server) serveUpdateMailboxSettings(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	patch, err := decodeObject(body)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "RequestBodyRead", err.Error())
+		return
+	}
+	if _, ok := patch["userPurpose"]; ok {
+		WriteError(w, http.StatusBadRequest, "ErrorInvalidProperty", "The property 'userPurpose' is read-only.")
+		return
+	}
+
+	s.mu.Lock()
+	user, ok := s.find("/users", PathParam(r, "id"))
+	var settings Object
+	if ok {
+		settings = s.userMailboxSettings(user.ID()).copy()
+		mergeObject(settings, patch)
+		if err := validateAutomaticReplies(settings); err != nil {
+			s.mu.Unlock()
+			WriteError(w, http.StatusBadRequest, "ErrorInvalidRequest", err.Error())
+			return
+		}
+		s.mailboxSettings[user.ID()] = settings
+		settings = settings.copy()
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w, PathParam(r, "id"))
+		return
+	}
+	WriteJSON(w, http.StatusOK, settings)
+}
+
+// userMailboxSettings returns the mailbox settings of the user with the given ID and creates
+// the defaults if there are none yet. s.mu must be held.
+func (s *Server) userMailboxSettings(userID string) Object {
+	settings, ok := s.mailboxSettings[userID]
+	if !ok {
+		settings = Object{
+			"timeZone":    "UTC",
+			"dateFormat":  "M/d/yyyy",
+			"timeFormat":  "h:mm tt",
+			"userPurpose": "user",
+			"language":    map[string]interface{}{"locale": "en-US", "displayName": "English (United States)"},
+			"automaticRepliesSetting": map[string]interface{}{
+				"status":               "disabled",
+				"externalAudience":     "all",
+				"internalReplyMessage": "",
+				"externalReplyMessage": "",
+			},
+			"workingHours": map[string]interface{}{
+				"daysOfWeek": []interface{}{"monday", "tuesday", "wednesday", "thursday", "friday"},
+				"startTime":  "08:00:00.0000000",
+				"endTime":    "17:00:00.0000000",
+				"timeZone":   map[string]interface{}{"name": "UTC"},
+			},
+		}
+		s.mailboxSettings[userID] = settings
+	}
+	return settings
+}
+
+// validateAutomaticReplies validates the automaticRepliesSetting of the mailbox settings,
+// scheduled automatic replies require a start before their end.
+func validateAutomaticReplies(settings Object) error {
+	replies, _ := settings["automaticRepliesSetting"].(map[string]interface{})
+	switch status, _ := replies["status"].(string); status {
+	case "disabled", "alwaysEnabled":
+		return nil
+	case "scheduled":
+		start, err := parseEventDateTime(replies["scheduledStartDateTime"])
+		if err != nil {
+			return fmt.Errorf("invalid scheduledStartDateTime: %v", err)
+		}
+		end, err := parseEventDateTime(replies["scheduledEndDateTime"])
+		if err != nil {
+			return fmt.Errorf("invalid scheduledEndDateTime: %v", err)
+		}
+		if !end.After(start) {
+			return fmt.Errorf("scheduledEndDateTime must be after scheduledStartDateTime")
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid automaticRepliesSetting status '%v'", status)
+	}
+}
+
+// mergeObject merges the patch into obj, nested objects are merged recursively.
+func mergeObject(obj, patch map[string]interface{}) {
+	for key, value := range patch {
+		nestedPatch, isObject := value.(map[string]interface{})
+		nested, hasObject := obj[key].(map[string]interface{})
+		if isObject && hasObject {
+			mergeObject(nested, nestedPatch)
+			continue
+		}
+		obj[key] = value
+	}
+}
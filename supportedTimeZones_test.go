@@ -3,6 +3,7 @@ package msgraph
 import (
 	"math/rand"
 	"testing"
+	"time"
 )
 
 func Test_supportedTimeZones_GetTimeZoneByAlias(t *testing.T) {
@@ -34,3 +35,21 @@ func Test_supportedTimeZones_GetTimeZoneByDisplayName(t *testing.T) {
 		t.Errorf("Tried to get a non existing timezone, expected an error, but got nil")
 	}
 }
+
+func Test_windowsTimeZones(t *testing.T) {
+	for windowsName, ianaName := range windowsTimeZones {
+		if _, err := time.LoadLocation(ianaName); err != nil {
+			t.Errorf("Cannot time.LoadLocation %v mapped from %v: %v", ianaName, windowsName, err)
+		}
+	}
+
+	defer func(loaded supportedTimeZones) { globalSupportedTimeZones = loaded }(globalSupportedTimeZones)
+	globalSupportedTimeZones = supportedTimeZones{}
+	location, err := mapTimeZoneStrings("W. Europe Standard Time")
+	if err != nil || location.String() != "Europe/Berlin" {
+		t.Errorf("mapTimeZoneStrings(\"W. Europe Standard Time\") = %v, %v, want Europe/Berlin", location, err)
+	}
+	if _, err := mapTimeZoneStrings("This is a non existing timezone"); err == nil {
+		t.Errorf("mapTimeZoneStrings() of a non existing timezone error = nil, want error")
+	}
+}